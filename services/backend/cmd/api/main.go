@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/H3nSte1n/recipe/internal/handler"
 	"github.com/H3nSte1n/recipe/internal/repository"
 	"github.com/H3nSte1n/recipe/internal/router"
@@ -9,6 +11,7 @@ import (
 	"github.com/H3nSte1n/recipe/pkg/config"
 	"github.com/H3nSte1n/recipe/pkg/crypto"
 	"github.com/H3nSte1n/recipe/pkg/database"
+	"github.com/H3nSte1n/recipe/pkg/email"
 	"github.com/H3nSte1n/recipe/pkg/storage"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -55,6 +58,11 @@ func main() {
 		logger.Fatal("Failed to initialize file store:", zap.Error(err))
 	}
 
+	mailTransport, err := email.NewTransport(&cfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize email transport:", zap.Error(err))
+	}
+
 	aiModelFactory := ai.NewModelFactory(&cfg, logger)
 
 	// Fail closed: secrets at rest (user AI API keys) require an encryption key.
@@ -65,11 +73,22 @@ func main() {
 
 	repos := repository.NewRepositories(db)
 
-	services := service.NewServices(repos, cfg, fileStore, logger, *aiModelFactory, cipher)
+	services := service.NewServices(repos, cfg, fileStore, mailTransport, logger, *aiModelFactory, cipher)
+
+	// Queued email is delivered in the background, with retries.
+	go services.EmailOutbox.RunSender(context.Background())
+	// Audit events older than audit.retention_days are purged in the background.
+	go services.AuditService.RunRetention(context.Background())
+	// Expired data export archives are deleted in the background.
+	go services.DataExportService.RunCleanup(context.Background())
+	// Trash older than trash.retention_days is purged in the background.
+	go services.TrashService.RunPurge(context.Background())
+	// Recurring shopping list templates add their staples in the background.
+	go services.TemplateService.RunScheduler(context.Background())
 
 	handlers := handler.NewHandlers(services, logger)
 
-	r := router.NewRouter(handlers, cfg, logger, repos.UserRepository)
+	r := router.NewRouter(handlers, cfg, logger, repos.UserRepository, services.APITokenService)
 	r.SetupRoutes()
 
 	logger.Info("Starting server on port " + cfg.App.Port)
@@ -0,0 +1,67 @@
+// Command import-products loads the product table that barcode scans are
+// looked up in from an Open Food Facts JSONL dump, gzipped or not:
+//
+//	import-products openfoodfacts-products.jsonl.gz
+//
+// It reads the same configuration as the API. Products already in the table
+// are replaced, so a newer dump can be imported over an older one.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/H3nSte1n/recipe/internal/repository"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/H3nSte1n/recipe/pkg/config"
+	"github.com/H3nSte1n/recipe/pkg/database"
+	"go.uber.org/zap"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: import-products <dump.jsonl[.gz]>")
+		os.Exit(2)
+	}
+
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = "development"
+	}
+
+	cfg, err := config.LoadConfig(env)
+	if err != nil {
+		log.Fatal("Cannot load config:", err)
+	}
+
+	if err := database.MigrateDB(&cfg); err != nil {
+		log.Fatal("Could not migrate database:", err)
+	}
+
+	logger, _ := zap.NewProduction()
+	defer func() { _ = logger.Sync() }()
+
+	db, err := database.NewPostgresConnection(&cfg)
+	if err != nil {
+		logger.Fatal("Failed to connect to database:", zap.Error(err))
+	}
+
+	dump, err := os.Open(os.Args[1])
+	if err != nil {
+		logger.Fatal("Failed to open dump:", zap.Error(err))
+	}
+	defer dump.Close()
+
+	repos := repository.NewRepositories(db)
+	// No AI model: categories come from the term dictionary alone.
+	categoryService := service.NewCategoryService(repos.CategoryRepository, nil, logger)
+	importer := service.NewProductImportService(repos.ProductRepository, categoryService, logger)
+
+	stats, err := importer.Import(context.Background(), dump)
+	if err != nil {
+		logger.Fatal("Failed to import products:", zap.Error(err), zap.Any("stats", stats))
+	}
+	logger.Info("Imported products", zap.Int("imported", stats.Imported), zap.Int("skipped", stats.Skipped))
+}
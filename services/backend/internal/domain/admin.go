@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// AdminUser is the administration view of an account. Unlike UserSummary it
+// includes the email address and account state.
+type AdminUser struct {
+	ID               string     `json:"id"`
+	Email            string     `json:"email"`
+	FirstName        string     `json:"first_name"`
+	LastName         string     `json:"last_name"`
+	Role             string     `json:"role"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	LockedUntil      *time.Time `json:"locked_until"`
+	AdminLockedAt    *time.Time `json:"admin_locked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// NewAdminUser projects user to its administration view.
+func NewAdminUser(user *User) AdminUser {
+	return AdminUser{
+		ID:               user.ID,
+		Email:            user.Email,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		Role:             user.Role,
+		EmailVerifiedAt:  user.EmailVerifiedAt,
+		TwoFactorEnabled: user.TwoFactorEnabled(),
+		LockedUntil:      user.LockedUntil,
+		AdminLockedAt:    user.AdminLockedAt,
+		CreatedAt:        user.CreatedAt,
+	}
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=member admin"`
+}
+
+type UpdateAIModelRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+type CreateStoreChainRequest struct {
+	Name    string         `json:"name" binding:"required,max=255"`
+	Country string         `json:"country" binding:"required,len=2"`
+	Layout  []StoreSection `json:"layout"`
+}
+
+type UpdateStoreChainRequest struct {
+	Name    *string        `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Country *string        `json:"country,omitempty" binding:"omitempty,len=2"`
+	Layout  []StoreSection `json:"layout,omitempty"`
+}
@@ -12,10 +12,14 @@ type UserAIConfig struct {
 	APIKey    string          `json:"-" gorm:"not null"` // Hide in JSON responses
 	IsDefault bool            `json:"is_default" gorm:"default:false"`
 	Settings  json.RawMessage `json:"settings" gorm:"type:jsonb;default:'{}'"`
-	CreatedAt time.Time       `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
-	User      *User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	AIModel   *AIModel        `json:"ai_model,omitempty" gorm:"foreignKey:AIModelID"`
+	// LastVerifiedAt is when the key last passed a provider check; LastError
+	// holds the outcome of the most recent failed check and is cleared on success.
+	LastVerifiedAt *time.Time `json:"last_verified_at"`
+	LastError      string     `json:"last_error,omitempty" gorm:"not null;default:''"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	User           *User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	AIModel        *AIModel   `json:"ai_model,omitempty" gorm:"foreignKey:AIModelID"`
 }
 
 type AIModel struct {
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AuditEvent is one entry in the append-only audit trail. ActorID is nil for
+// events no signed-in user caused.
+type AuditEvent struct {
+	ID         string       `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ActorID    *string      `json:"actor_id" gorm:"type:uuid"`
+	Action     string       `json:"action" gorm:"not null"`
+	TargetType string       `json:"target_type" gorm:"not null"`
+	TargetID   string       `json:"target_id" gorm:"not null;default:''"`
+	IPAddress  string       `json:"ip_address" gorm:"not null;default:''"`
+	UserAgent  string       `json:"user_agent" gorm:"not null;default:''"`
+	Changes    AuditChanges `json:"changes" gorm:"type:jsonb;serializer:json"`
+	CreatedAt  time.Time    `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// AuditChanges maps a field name to its value before and after the action.
+type AuditChanges map[string]AuditChange
+
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// AuditActor is who performed an audited action, and from where.
+type AuditActor struct {
+	UserID string
+	Client SessionClient
+}
+
+type auditClientKey struct{}
+
+// ContextWithAuditClient attaches the calling client to ctx so services can
+// stamp audit events without threading it through every method.
+func ContextWithAuditClient(ctx context.Context, client SessionClient) context.Context {
+	return context.WithValue(ctx, auditClientKey{}, client)
+}
+
+// AuditClientFromContext returns the client attached by
+// ContextWithAuditClient, or a zero value outside a request.
+func AuditClientFromContext(ctx context.Context) SessionClient {
+	client, _ := ctx.Value(auditClientKey{}).(SessionClient)
+	return client
+}
+
+// Audit target types.
+const (
+	AuditTargetUser         = "user"
+	AuditTargetSession      = "session"
+	AuditTargetAPIToken     = "api_token"
+	AuditTargetAIConfig     = "ai_config"
+	AuditTargetAIModel      = "ai_model"
+	AuditTargetRecipe       = "recipe"
+	AuditTargetShoppingList = "shopping_list"
+	AuditTargetStoreChain   = "store_chain"
+	AuditTargetStore        = "store"
+)
+
+// Audit actions for sign-in and account security. Failed sign-ins have no
+// actor; their target is the account that was tried.
+const (
+	AuditLoginSucceeded         = "auth.login_succeeded"
+	AuditLoginFailed            = "auth.login_failed"
+	AuditAccountLocked          = "auth.account_locked"
+	AuditTwoFactorFailed        = "auth.2fa_failed"
+	AuditTwoFactorEnabled       = "auth.2fa_enabled"
+	AuditTwoFactorDisabled      = "auth.2fa_disabled"
+	AuditPasswordResetRequested = "auth.password_reset_requested"
+	AuditPasswordReset          = "auth.password_reset"
+	AuditMagicLinkRequested     = "auth.magic_link_requested"
+	AuditSessionRevoked         = "auth.session_revoked"
+	AuditAPITokenCreated        = "auth.api_token_created"
+	AuditAPITokenRevoked        = "auth.api_token_revoked"
+	AuditAccountDeleted         = "user.deleted"
+	AuditDataExportRequested    = "user.data_export_requested"
+)
+
+// Audit actions for user data.
+const (
+	AuditAIConfigCreated          = "ai_config.created"
+	AuditAIConfigUpdated          = "ai_config.updated"
+	AuditAIConfigDeleted          = "ai_config.deleted"
+	AuditRecipeCreated            = "recipe.created"
+	AuditRecipeUpdated            = "recipe.updated"
+	AuditRecipeDeleted            = "recipe.deleted"
+	AuditRecipeRestored           = "recipe.restored"
+	AuditShoppingListCreated      = "shopping_list.created"
+	AuditShoppingListUpdated      = "shopping_list.updated"
+	AuditShoppingListDeleted      = "shopping_list.deleted"
+	AuditShoppingListRestored     = "shopping_list.restored"
+	AuditShoppingListShared       = "shopping_list.shared"
+	AuditShoppingListShareRevoked = "shopping_list.share_revoked"
+	AuditStoreCreated             = "store.created"
+	AuditStoreUpdated             = "store.updated"
+	AuditStoreDeleted             = "store.deleted"
+)
+
+// Audit actions taken through the administration API.
+const (
+	AuditAdminUserLocked        = "admin.user.locked"
+	AuditAdminUserUnlocked      = "admin.user.unlocked"
+	AuditAdminUserVerified      = "admin.user.verified"
+	AuditAdminUserRoleChanged   = "admin.user.role_changed"
+	AuditAdminUserDeleted       = "admin.user.deleted"
+	AuditAdminAIModelUpdated    = "admin.ai_model.updated"
+	AuditAdminStoreChainCreated = "admin.store_chain.created"
+	AuditAdminStoreChainUpdated = "admin.store_chain.updated"
+	AuditAdminStoreChainDeleted = "admin.store_chain.deleted"
+)
+
+// AuditEventFilter narrows an audit event listing. Zero fields match
+// everything; Before pages backwards through older events.
+type AuditEventFilter struct {
+	ActorID    string     `form:"actor_id"`
+	Action     string     `form:"action"`
+	TargetType string     `form:"target_type"`
+	TargetID   string     `form:"target_id"`
+	Before     *time.Time `form:"before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int        `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// ActivityFilter pages through a user's own activity, newest first.
+type ActivityFilter struct {
+	Before *time.Time `form:"before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit  int        `form:"limit" binding:"omitempty,min=1,max=200"`
+}
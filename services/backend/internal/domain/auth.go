@@ -47,3 +47,151 @@ type VerifyEmailRequest struct {
 type ResendVerificationRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
+
+// MagicLinkToken is a single-use, short-lived token that signs a user in
+// without a password. Mirrors PasswordResetToken, but only the hash of the
+// token is stored since it is a login credential.
+type MagicLinkToken struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string    `json:"user_id" gorm:"type:uuid"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type MagicLinkLoginRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Session is one signed-in device. It holds the hash of the device's current
+// refresh token; each refresh replaces the hash, so presenting an older token
+// for the same session is treated as theft and revokes the session.
+type Session struct {
+	ID                       string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID                   string     `json:"-" gorm:"type:uuid;not null"`
+	RefreshTokenHash         string     `json:"-" gorm:"not null"`
+	PreviousRefreshTokenHash string     `json:"-" gorm:"not null;default:''"` // rotated out by the last refresh
+	UserAgent                string     `json:"user_agent"`
+	IPAddress                string     `json:"ip_address"`
+	LastSeenAt               time.Time  `json:"last_seen_at"`
+	ExpiresAt                time.Time  `json:"expires_at"`
+	RevokedAt                *time.Time `json:"-"`
+	CreatedAt                time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	// Current marks the session the listing request was made with.
+	Current bool `json:"current" gorm:"-"`
+}
+
+// SessionClient describes the device a session is opened or refreshed from.
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenPair is a short-lived access token plus the refresh token that renews
+// it. ExpiresAt is when the access token expires.
+type TokenPair struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// RecoveryCode is a hashed one-time code that stands in for a TOTP code when
+// the authenticator is lost.
+type RecoveryCode struct {
+	ID        string     `json:"-" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string     `json:"-" gorm:"type:uuid;not null"`
+	CodeHash  string     `json:"-" gorm:"not null"`
+	UsedAt    *time.Time `json:"-"`
+	CreatedAt time.Time  `json:"-" gorm:"autoCreateTime"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// TOTPEnrollment is returned when enrollment starts. The secret is shown once
+// for manual entry; ProvisioningURI is what a QR code encodes.
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse lists freshly generated recovery codes. They are
+// stored hashed and cannot be shown again.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// DisableTOTPRequest re-authenticates the user with their password and a
+// current TOTP or recovery code.
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// UserIdentity links an account at an external identity provider to a user.
+type UserIdentity struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string    `json:"-" gorm:"type:uuid;not null"`
+	Provider  string    `json:"provider" gorm:"not null"`
+	Subject   string    `json:"-" gorm:"not null"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// OIDCLoginState is an in-flight external sign-in. Only the hashes of the
+// state parameter and the browser binding are stored; the nonce and PKCE
+// verifier never leave the server.
+type OIDCLoginState struct {
+	ID           string    `gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	StateHash    string    `gorm:"not null"`
+	BindingHash  string    `gorm:"not null"`
+	Provider     string    `gorm:"not null"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	UsedAt       *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// OIDCProvider is a sign-in option shown on the login page.
+type OIDCProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// OIDCStartResponse carries the provider URL to send the browser to and a
+// BrowserBinding secret the client keeps (e.g. in sessionStorage) and sends
+// back with the callback. It ties the sign-in to the browser that started it.
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	BrowserBinding   string `json:"browser_binding"`
+}
+
+type OIDCCallbackRequest struct {
+	Code           string `json:"code" binding:"required"`
+	State          string `json:"state" binding:"required"`
+	BrowserBinding string `json:"browser_binding" binding:"required"`
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultCategoryLocale is the locale every category has a name in.
+const DefaultCategoryLocale = "en"
+
+// GroceryCategory is a node of the category taxonomy. The top-level nodes are
+// the Category constants; subcategories refine one of them (PANTRY_SPICES
+// under PANTRY) and are what items are assigned where known.
+type GroceryCategory struct {
+	Code       Category          `json:"code" gorm:"primaryKey"`
+	ParentCode *Category         `json:"parent_code,omitempty"`
+	Position   int               `json:"position" gorm:"not null"`
+	Names      map[string]string `json:"names" gorm:"type:jsonb;serializer:json"`
+	// Name is Names in the requested locale; it is filled in when the tree is
+	// listed and not stored.
+	Name      string            `json:"name" gorm:"-"`
+	Children  []GroceryCategory `json:"children,omitempty" gorm:"-"`
+	CreatedAt time.Time         `json:"-" gorm:"autoCreateTime"`
+	UpdatedAt time.Time         `json:"-" gorm:"autoUpdateTime"`
+}
+
+func (GroceryCategory) TableName() string {
+	return "categories"
+}
+
+// LocalizedName returns the category's name in locale, falling back to the
+// default locale and then to its code.
+func (c *GroceryCategory) LocalizedName(locale string) string {
+	if name := c.Names[locale]; name != "" {
+		return name
+	}
+	if name := c.Names[DefaultCategoryLocale]; name != "" {
+		return name
+	}
+	return string(c.Code)
+}
+
+// CategoryTerm maps a normalized item name ("olive oil", "milch") to the
+// category it is assigned without asking an AI model. A spelling can be a
+// term in several locales, each with its own category.
+type CategoryTerm struct {
+	Term         string   `json:"term" gorm:"primaryKey"`
+	Locale       string   `json:"locale" gorm:"primaryKey"`
+	CategoryCode Category `json:"category_code" gorm:"not null"`
+}
+
+// CategoryTaxonomy is the loaded category tree, indexed for lookups.
+type CategoryTaxonomy struct {
+	// Categories is every category, parents before their children.
+	Categories []GroceryCategory
+	parents    map[Category]*Category
+}
+
+// NewCategoryTaxonomy indexes categories, which must list parents before
+// their children.
+func NewCategoryTaxonomy(categories []GroceryCategory) *CategoryTaxonomy {
+	t := &CategoryTaxonomy{
+		Categories: categories,
+		parents:    make(map[Category]*Category, len(categories)),
+	}
+	for _, c := range categories {
+		t.parents[c.Code] = c.ParentCode
+	}
+	return t
+}
+
+// Valid reports whether c is a category of the taxonomy.
+func (t *CategoryTaxonomy) Valid(c Category) bool {
+	_, ok := t.parents[c]
+	return ok
+}
+
+// Lineage returns c followed by its ancestors up to the top-level category.
+// An unknown category, or any category of a nil taxonomy, is its own lineage.
+func (t *CategoryTaxonomy) Lineage(c Category) []Category {
+	lineage := []Category{c}
+	if t == nil {
+		return lineage
+	}
+	for parent := t.parents[c]; parent != nil && len(lineage) <= len(t.parents); parent = t.parents[*parent] {
+		lineage = append(lineage, *parent)
+	}
+	return lineage
+}
+
+type categoryLanguagesKey struct{}
+
+// ContextWithCategoryLanguages attaches the caller's languages, most preferred
+// first, so item names are looked up in their dictionaries first without
+// threading them through every method.
+func ContextWithCategoryLanguages(ctx context.Context, languages []string) context.Context {
+	return context.WithValue(ctx, categoryLanguagesKey{}, languages)
+}
+
+// CategoryLanguagesFromContext returns the languages attached by
+// ContextWithCategoryLanguages, or nil outside a request.
+func CategoryLanguagesFromContext(ctx context.Context) []string {
+	languages, _ := ctx.Value(categoryLanguagesKey{}).([]string)
+	return languages
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Data export statuses. An export starts pending, becomes ready once its
+// archive is built (or failed), and expired once the archive is deleted.
+const (
+	DataExportPending = "pending"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+	DataExportExpired = "expired"
+)
+
+// DataExport is a user's request for a copy of their data. The archive is
+// only reachable through the signed link emailed to the account, so the file
+// name is never serialized.
+type DataExport struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID      string     `json:"-" gorm:"type:uuid;not null"`
+	Status      string     `json:"status" gorm:"not null;default:pending"`
+	FileName    string     `json:"-" gorm:"not null;default:''"`
+	SizeBytes   int64      `json:"size_bytes" gorm:"not null;default:0"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// AIConfigExport is the exported form of a UserAIConfig. It has no field for
+// the API key, so a key can never end up in an archive.
+type AIConfigExport struct {
+	ID             string          `json:"id"`
+	AIModelID      string          `json:"ai_model_id"`
+	IsDefault      bool            `json:"is_default"`
+	Settings       json.RawMessage `json:"settings"`
+	LastVerifiedAt *time.Time      `json:"last_verified_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// NewAIConfigExport copies the metadata of config, leaving out its key.
+func NewAIConfigExport(config UserAIConfig) AIConfigExport {
+	return AIConfigExport{
+		ID:             config.ID,
+		AIModelID:      config.AIModelID,
+		IsDefault:      config.IsDefault,
+		Settings:       config.Settings,
+		LastVerifiedAt: config.LastVerifiedAt,
+		CreatedAt:      config.CreatedAt,
+		UpdatedAt:      config.UpdatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// Outbox email statuses.
+const (
+	OutboxEmailPending = "pending"
+	OutboxEmailSent    = "sent"
+	OutboxEmailFailed  = "failed"
+)
+
+// OutboxEmail is a rendered email queued for the background sender.
+type OutboxEmail struct {
+	ID            string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	Recipient     string     `json:"recipient" gorm:"not null"`
+	Subject       string     `json:"subject" gorm:"not null"`
+	TextBody      string     `json:"-" gorm:"not null;default:''"`
+	HTMLBody      string     `json:"-" gorm:"column:html_body;not null;default:''"`
+	Status        string     `json:"status" gorm:"not null;default:pending"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error" gorm:"not null;default:''"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (OutboxEmail) TableName() string {
+	return "email_outbox"
+}
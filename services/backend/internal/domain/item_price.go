@@ -0,0 +1,89 @@
+package domain
+
+import "time"
+
+// ItemPrice is what a user pays for an item at a store chain: PriceCents for
+// Quantity Unit of it. UnitPriceCents is the same price per PerUnit, a
+// kilogram, litre or piece, or the unit itself when it is none of those, so
+// prices of different pack sizes compare.
+type ItemPrice struct {
+	ID             string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID         string    `json:"-" gorm:"type:uuid;not null"`
+	StoreChainID   string    `json:"store_chain_id" gorm:"type:uuid;not null"`
+	Name           string    `json:"name" gorm:"not null"`
+	ItemKey        string    `json:"-" gorm:"not null"`
+	Quantity       float64   `json:"quantity"`
+	Unit           string    `json:"unit"`
+	PriceCents     int64     `json:"price_cents"`
+	Currency       string    `json:"currency" gorm:"not null"`
+	UnitPriceCents float64   `json:"unit_price_cents"`
+	PerUnit        string    `json:"per_unit" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// SaveItemPriceRequest sets what an item costs at a store chain, replacing
+// the price it had there.
+type SaveItemPriceRequest struct {
+	StoreChainID string  `json:"store_chain_id" binding:"required"`
+	Name         string  `json:"name" binding:"required,max=255"`
+	Quantity     float64 `json:"quantity" binding:"gt=0"`
+	Unit         string  `json:"unit" binding:"max=50"`
+	PriceCents   int64   `json:"price_cents" binding:"gte=0"`
+	Currency     string  `json:"currency,omitempty" binding:"omitempty,len=3,alpha"`
+}
+
+// Bases of an item's cost.
+const (
+	// CostBasisPrice is the price the user set for the item.
+	CostBasisPrice = "price"
+	// CostBasisUnitPrice scales what the item recently cost per unit on
+	// shopping trips to the amount needed.
+	CostBasisUnitPrice = "unit_price"
+	// CostBasisPurchase assumes one purchase like the recent ones, for items
+	// bought in a unit the amount needed does not convert to.
+	CostBasisPurchase = "purchase"
+)
+
+// Reasons an item has no cost.
+const (
+	// CostMissingPrice: the item has no price and was not paid for on a
+	// trip, at the store chain if one was asked for.
+	CostMissingPrice = "no_price"
+	// CostMissingUnit: the item is priced per a unit its amount does not
+	// convert to, such as per kilogram for an amount in litres.
+	CostMissingUnit = "unit_mismatch"
+	// CostMissingCurrency: the item is priced in another currency than the
+	// cost is added up in.
+	CostMissingCurrency = "other_currency"
+)
+
+// Cost is what a list or recipe costs by the prices the user set, or for
+// items without one by what they recently paid on shopping trips. TotalCents
+// adds up the items that have a cost; the others are counted in MissingCount
+// and say why in their Missing instead of adding nothing.
+type Cost struct {
+	StoreChainID string  `json:"store_chain_id,omitempty"`
+	Currency     string  `json:"currency"`
+	TotalCents   int64   `json:"total_cents"`
+	MissingCount int     `json:"missing_count"`
+	Servings     float64 `json:"servings,omitempty"`
+	// PerServingCents is TotalCents per serving; like it, it leaves out
+	// items without a cost.
+	PerServingCents *int64     `json:"per_serving_cents,omitempty"`
+	Items           []ItemCost `json:"items"`
+}
+
+type ItemCost struct {
+	ListItemID     string  `json:"list_item_id,omitempty"`
+	IngredientID   string  `json:"ingredient_id,omitempty"`
+	Name           string  `json:"name"`
+	Amount         float64 `json:"amount"`
+	Unit           string  `json:"unit"`
+	CostCents      *int64  `json:"cost_cents"`
+	PriceID        string  `json:"price_id,omitempty"`
+	UnitPriceCents float64 `json:"unit_price_cents,omitempty"`
+	PerUnit        string  `json:"per_unit,omitempty"`
+	Basis          string  `json:"basis,omitempty"`
+	Missing        string  `json:"missing,omitempty"`
+}
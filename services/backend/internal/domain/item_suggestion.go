@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// ItemSuggestion is an item the user has put on lists before, with how they
+// usually put it there: its most recent category and unit and the amount
+// typically bought in that unit.
+type ItemSuggestion struct {
+	Name       string    `json:"name"`
+	Category   Category  `json:"category"`
+	Unit       string    `json:"unit,omitempty"`
+	Amount     float64   `json:"amount,omitempty"`
+	TimesUsed  int       `json:"times_used"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	// Together is how many past lists had the item along with the items it
+	// was suggested for. Only set for "bought together" suggestions.
+	Together int `json:"together,omitempty"`
+}
+
+type SuggestItemsQuery struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// ItemUsage is how many of the user's lists an item, by its lower-cased and
+// trimmed name, was on.
+type ItemUsage struct {
+	Key   string `json:"key" gorm:"column:item_key"`
+	Lists int    `json:"lists"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// Product is a packaged product known by its barcode, loaded from an Open
+// Food Facts dump.
+type Product struct {
+	Barcode   string           `json:"barcode" gorm:"primaryKey"`
+	Name      string           `json:"name" gorm:"not null"`
+	Brand     string           `json:"brand,omitempty"`
+	Quantity  string           `json:"quantity,omitempty"` // as printed on the pack, e.g. "400 g"
+	Category  Category         `json:"category" gorm:"not null"`
+	Nutrition ProductNutrition `json:"nutrition" gorm:"embedded"`
+	UpdatedAt time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ProductNutrition is per 100 g or 100 ml; values not known are nil.
+type ProductNutrition struct {
+	EnergyKcal    *float64 `json:"energy_kcal,omitempty"`
+	Fat           *float64 `json:"fat,omitempty"`
+	SaturatedFat  *float64 `json:"saturated_fat,omitempty"`
+	Carbohydrates *float64 `json:"carbohydrates,omitempty"`
+	Sugars        *float64 `json:"sugars,omitempty"`
+	Fiber         *float64 `json:"fiber,omitempty"`
+	Protein       *float64 `json:"protein,omitempty"`
+	Salt          *float64 `json:"salt,omitempty"`
+}
+
+// UserProduct is a product a user saved for a barcode, because the product
+// table does not know it or to have it added differently. Amount and Unit,
+// when set, are what a scan puts on the list.
+type UserProduct struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID    string    `json:"-" gorm:"type:uuid;not null"`
+	Barcode   string    `json:"barcode" gorm:"not null"`
+	Name      string    `json:"name" gorm:"not null"`
+	Brand     string    `json:"brand,omitempty"`
+	Quantity  string    `json:"quantity,omitempty"`
+	Category  Category  `json:"category" gorm:"not null"`
+	Amount    float64   `json:"amount,omitempty"`
+	Unit      string    `json:"unit,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ScannedProduct is what a barcode stands for, from the user's own products
+// or else the product table.
+type ScannedProduct struct {
+	Barcode   string            `json:"barcode"`
+	Name      string            `json:"name"`
+	Brand     string            `json:"brand,omitempty"`
+	Quantity  string            `json:"quantity,omitempty"`
+	Category  Category          `json:"category"`
+	Amount    float64           `json:"amount,omitempty"`
+	Unit      string            `json:"unit,omitempty"`
+	Nutrition *ProductNutrition `json:"nutrition,omitempty"`
+	// Saved is set when the product is one of the user's own.
+	Saved bool `json:"saved"`
+}
+
+type ScanBarcodeRequest struct {
+	Barcode string  `json:"barcode" binding:"required"`
+	Amount  float64 `json:"amount" binding:"omitempty,gt=0"`
+	Unit    string  `json:"unit"`
+	Notes   string  `json:"notes"`
+}
+
+type SaveProductRequest struct {
+	Barcode  string   `json:"barcode" binding:"required"`
+	Name     string   `json:"name" binding:"required,max=255"`
+	Brand    string   `json:"brand" binding:"max=255"`
+	Quantity string   `json:"quantity" binding:"max=100"`
+	Category Category `json:"category,omitempty"`
+	Amount   float64  `json:"amount" binding:"omitempty,gt=0"`
+	Unit     string   `json:"unit" binding:"max=50"`
+}
+
+// ProductImportStats counts what an import of a product dump did.
+type ProductImportStats struct {
+	Imported int `json:"imported"`
+	// Skipped are entries without a valid barcode or a name.
+	Skipped int `json:"skipped"`
+}
@@ -3,6 +3,8 @@ package domain
 import (
 	"mime/multipart"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Recipe struct {
@@ -13,7 +15,7 @@ type Recipe struct {
 	Notes        string              `json:"notes"`
 	Rating       float64             `json:"rating" gorm:"default:0"` // 0-5
 	ImageURL     string              `json:"image_url,omitempty" gorm:"type:varchar(255)"`
-	SourceType   string              `json:"source_type" gorm:"not null"` // URL, MANUAL, PDF, IMAGE
+	SourceType   string              `json:"source_type" gorm:"not null"` // URL, MANUAL, PDF, IMAGE, AI
 	Source       string              `json:"source,omitempty"`
 	IsPrivate    bool                `json:"is_private" gorm:"default:false"`
 	Servings     int                 `json:"servings" gorm:"not null"`
@@ -21,8 +23,10 @@ type Recipe struct {
 	CookTime     int                 `json:"cook_time"`
 	ShelfLife    int                 `json:"shelf_life"`
 	Status       string              `json:"status" gorm:"default:draft"`
+	VariantOfID  *string             `json:"variant_of_id,omitempty" gorm:"type:uuid"` // recipe this is a personal variant of
 	CreatedAt    time.Time           `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time           `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt      `json:"-"` // set while the recipe is in the trash
 	User         *User               `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	Ingredients  []RecipeIngredient  `json:"ingredients,omitempty" gorm:"foreignKey:RecipeID"`
 	Instructions []RecipeInstruction `json:"instructions,omitempty" gorm:"foreignKey:RecipeID"`
@@ -112,6 +116,14 @@ type SubRecipe struct {
 	Child         *Recipe `json:"child,omitempty" gorm:"foreignKey:ChildID"`
 }
 
+const (
+	SourceTypeURL    = "URL"
+	SourceTypeManual = "MANUAL"
+	SourceTypePDF    = "PDF"
+	SourceTypeImage  = "IMAGE"
+	SourceTypeAI     = "AI"
+)
+
 type NutritionDetailLevel string
 
 const (
@@ -123,7 +135,7 @@ const (
 type CreateRecipeRequest struct {
 	Title                string                `json:"title" binding:"required"`
 	Description          string                `json:"description"`
-	SourceType           string                `json:"source_type" binding:"required,oneof=URL MANUAL PDF IMAGE"`
+	SourceType           string                `json:"source_type" binding:"required,oneof=URL MANUAL PDF IMAGE"` // AI is set by generation only
 	SourceURL            string                `json:"source_url,omitempty"`
 	IsPrivate            bool                  `json:"is_private"`
 	Servings             int                   `json:"servings" binding:"required,min=1"`
@@ -166,3 +178,36 @@ type ImportImageRequest struct {
 	IsPrivate bool `json:"is_private"`
 	// Image file will be handled by multipart form data
 }
+
+// GenerateRecipeRequest asks the user's default AI model for a new recipe built
+// from the ingredients they have on hand.
+type GenerateRecipeRequest struct {
+	Ingredients        []string `json:"ingredients" binding:"required,min=1,max=50,dive,required,max=100"`
+	DietaryConstraints []string `json:"dietary_constraints" binding:"omitempty,max=20,dive,required,max=100"`
+	MaxTime            int      `json:"max_time" binding:"omitempty,min=1,max=1440"` // total prep + cook minutes
+	Servings           int      `json:"servings" binding:"required,min=1,max=100"`
+	Cuisine            string   `json:"cuisine" binding:"omitempty,max=50"`
+	IsPrivate          bool     `json:"is_private"`
+}
+
+const (
+	SubstituteSourceCurated = "curated"
+	SubstituteSourceAI      = "ai"
+)
+
+// IngredientSubstitute is one suggested replacement for a recipe ingredient.
+// Amount and Unit replace the original ingredient's amount and unit.
+type IngredientSubstitute struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+	Notes  string  `json:"notes,omitempty"`
+	Source string  `json:"source"` // curated or ai
+}
+
+type ApplySubstituteRequest struct {
+	Name   string  `json:"name" binding:"required,max=100"`
+	Amount float64 `json:"amount" binding:"min=0"`
+	Unit   string  `json:"unit" binding:"max=50"`
+	Notes  string  `json:"notes" binding:"max=500"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+const (
+	EditProposalPending  = "pending"
+	EditProposalAccepted = "accepted"
+	EditProposalRejected = "rejected"
+)
+
+// RecipeEditProposal is an AI-suggested revision of a recipe waiting for its
+// owner to accept or reject it. BaseUpdatedAt pins the recipe version the
+// proposal was made against so a stale proposal cannot overwrite later edits.
+type RecipeEditProposal struct {
+	ID            string              `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	RecipeID      string              `json:"recipe_id" gorm:"type:uuid;not null"`
+	UserID        string              `json:"user_id" gorm:"type:uuid;not null"`
+	Instruction   string              `json:"instruction" gorm:"not null"`
+	Revision      RecipeRevision      `json:"revision" gorm:"type:jsonb;serializer:json"`
+	Changes       []RecipeFieldChange `json:"changes" gorm:"type:jsonb;serializer:json"`
+	Status        string              `json:"status" gorm:"default:pending"` // pending, accepted, rejected
+	BaseUpdatedAt time.Time           `json:"base_updated_at" gorm:"not null"`
+	CreatedAt     time.Time           `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time           `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// RecipeRevision is the editable content of a recipe as proposed by the model.
+type RecipeRevision struct {
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Notes        string              `json:"notes"`
+	Servings     int                 `json:"servings"`
+	PrepTime     int                 `json:"prep_time"`
+	CookTime     int                 `json:"cook_time"`
+	Ingredients  []RecipeIngredient  `json:"ingredients"`
+	Instructions []RecipeInstruction `json:"instructions"`
+	Nutrition    *RecipeNutrition    `json:"nutrition,omitempty"`
+}
+
+// RecipeFieldChange is one entry of a proposal's diff. Before and After hold
+// the field's JSON value; list fields carry the whole list.
+type RecipeFieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type AIEditRecipeRequest struct {
+	Instruction string `json:"instruction" binding:"required,max=1000"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// RecipeTranslation holds a recipe's text in another language. Ingredients and
+// instructions are keyed by the IDs of the recipe rows they translate; amounts
+// are never stored here and always come from the recipe itself.
+type RecipeTranslation struct {
+	ID           string                  `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	RecipeID     string                  `json:"recipe_id" gorm:"type:uuid;not null"`
+	Language     string                  `json:"language" gorm:"not null"` // ISO 639-1 base language, e.g. "de"
+	Title        string                  `json:"title" gorm:"not null"`
+	Description  string                  `json:"description"`
+	Notes        string                  `json:"notes"`
+	Ingredients  []TranslatedIngredient  `json:"ingredients" gorm:"type:jsonb;serializer:json"`
+	Instructions []TranslatedInstruction `json:"instructions" gorm:"type:jsonb;serializer:json"`
+	CreatedAt    time.Time               `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time               `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type TranslatedIngredient struct {
+	IngredientID string `json:"ingredient_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Unit         string `json:"unit"`
+	Notes        string `json:"notes"`
+}
+
+type TranslatedInstruction struct {
+	InstructionID string `json:"instruction_id"`
+	Instruction   string `json:"instruction"`
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type ShoppingList struct {
 	ID           string             `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
@@ -8,28 +12,35 @@ type ShoppingList struct {
 	Name         string             `json:"name" gorm:"not null"`
 	Description  string             `json:"description"`
 	SortType     SortType           `json:"sort_type" gorm:"not null;default:'CATEGORY'"`
+	SortOrder    string             `json:"sort_order,omitempty" gorm:"not null;default:''"` // shown when no order is asked for, e.g. "checked,section,-name"
 	StoreChainID *string            `json:"store_chain_id,omitempty" gorm:"type:uuid"`
+	StoreID      *string            `json:"store_id,omitempty" gorm:"type:uuid"`
 	CreatedAt    time.Time          `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt     `json:"-"` // set while the list is in the trash
 	User         *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
 	StoreChain   *StoreChain        `json:"store_chain,omitempty" gorm:"foreignKey:StoreChainID"`
+	Store        *Store             `json:"store,omitempty" gorm:"foreignKey:StoreID"`
 	Items        []ShoppingListItem `json:"items,omitempty" gorm:"foreignKey:ListID"`
 }
 
 type ShoppingListItem struct {
-	ID        string        `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
-	ListID    string        `json:"list_id" gorm:"type:uuid;not null"`
-	RecipeID  *string       `json:"recipe_id,omitempty" gorm:"type:uuid"`
-	Name      string        `json:"name" gorm:"not null"`
-	Amount    float64       `json:"amount"`
-	Unit      string        `json:"unit"`
-	Category  Category      `json:"category" gorm:"not null"`
-	IsChecked bool          `json:"is_checked" gorm:"default:false"`
-	Notes     string        `json:"notes"`
-	CreatedAt time.Time     `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
-	List      *ShoppingList `json:"list,omitempty" gorm:"foreignKey:ListID"`
-	Recipe    *Recipe       `json:"recipe,omitempty" gorm:"foreignKey:RecipeID"`
+	ID         string         `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ListID     string         `json:"list_id" gorm:"type:uuid;not null"`
+	RecipeID   *string        `json:"recipe_id,omitempty" gorm:"type:uuid"`
+	Name       string         `json:"name" gorm:"not null"`
+	Amount     float64        `json:"amount"`
+	Unit       string         `json:"unit"`
+	Category   Category       `json:"category" gorm:"not null"`
+	IsChecked  bool           `json:"is_checked" gorm:"default:false"`
+	Notes      string         `json:"notes"`
+	Position   *int           `json:"position,omitempty"` // set once the user orders the list's items by hand
+	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt  gorm.DeletedAt `json:"-"`                     // set while the item is in the trash
+	ArchivedAt *time.Time     `json:"archived_at,omitempty"` // set once the item is cleared off its list checked
+	List       *ShoppingList  `json:"list,omitempty" gorm:"foreignKey:ListID"`
+	Recipe     *Recipe        `json:"recipe,omitempty" gorm:"foreignKey:RecipeID"`
 }
 
 type SortType string
@@ -39,6 +50,25 @@ const (
 	SortTypeStore    SortType = "STORE"
 )
 
+// Keys a list's items can be sorted by. Checked sorts unchecked items first,
+// section by where the list's store or chain has them, recipe groups the
+// items of each recipe in the order the recipes were added, and position
+// keeps the order the user put the items in, with items never placed last.
+const (
+	SortKeyChecked   = "checked"
+	SortKeySection   = "section"
+	SortKeyCategory  = "category"
+	SortKeyName      = "name"
+	SortKeyRecipe    = "recipe"
+	SortKeyAmount    = "amount"
+	SortKeyCreatedAt = "created_at"
+	SortKeyPosition  = "position"
+)
+
+var ItemSortKeys = []string{SortKeyChecked, SortKeySection, SortKeyCategory, SortKeyName, SortKeyRecipe, SortKeyAmount, SortKeyCreatedAt, SortKeyPosition}
+
+// Category is the code of a GroceryCategory. The constants are the top-level
+// categories; subcategory codes come from the categories table.
 type Category string
 
 const (
@@ -57,7 +87,9 @@ type CreateShoppingListRequest struct {
 	Name         string                    `json:"name" binding:"required"`
 	Description  string                    `json:"description"`
 	SortType     SortType                  `json:"sort_type" binding:"required,oneof=CATEGORY STORE"`
+	SortOrder    string                    `json:"sort_order,omitempty" binding:"max=200"`
 	StoreChainID string                    `json:"store_chain_id,omitempty"`
+	StoreID      string                    `json:"store_id,omitempty"`
 	Items        []ShoppingListItemRequest `json:"items,omitempty"`
 }
 
@@ -65,6 +97,11 @@ type UpdateShoppingListRequest struct {
 	Name        string   `json:"name" binding:"required"`
 	Description string   `json:"description"`
 	SortType    SortType `json:"sort_type" binding:"required,oneof=CATEGORY STORE"`
+	// SortOrder replaces the list's saved sort order; empty clears it.
+	SortOrder string `json:"sort_order,omitempty" binding:"max=200"`
+	// StoreID is the store the list is shopped at; check-offs teach that
+	// store's order. Empty clears it.
+	StoreID string `json:"store_id,omitempty"`
 }
 
 type UpdateShoppingListItemRequest struct {
@@ -0,0 +1,63 @@
+package domain
+
+// Operations a batch applies to a list's items.
+const (
+	// BatchOpUpdate replaces one item's fields, as updating the item does.
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+	// BatchOpCheck and BatchOpUncheck check or uncheck the items, or every
+	// item on the list when none are given.
+	BatchOpCheck   = "check"
+	BatchOpUncheck = "uncheck"
+	// BatchOpMove moves the items to TargetListID.
+	BatchOpMove = "move"
+	// BatchOpSetCategory puts the items in Category.
+	BatchOpSetCategory = "set_category"
+	// BatchOpCategorize categorizes the items again, asking the AI model
+	// first, or every item on the list when none are given.
+	BatchOpCategorize = "categorize"
+	// BatchOpReorder puts the items first on the list in the order given,
+	// followed by the list's other items in their current order.
+	BatchOpReorder = "reorder"
+)
+
+// Statuses of a batch operation.
+const (
+	BatchStatusApplied = "applied"
+	BatchStatusFailed  = "failed"
+	// BatchStatusRolledBack: the operation worked but a later one failed, so
+	// nothing the batch did was kept.
+	BatchStatusRolledBack = "rolled_back"
+	// BatchStatusSkipped: an earlier operation failed before this one ran.
+	BatchStatusSkipped = "skipped"
+)
+
+// BatchItemsRequest applies Operations to a list's items in order, all or
+// none of them.
+type BatchItemsRequest struct {
+	Operations []BatchItemOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+type BatchItemOperation struct {
+	Op           string                         `json:"op" binding:"required,oneof=update delete check uncheck move set_category categorize reorder"`
+	ItemIDs      []string                       `json:"item_ids,omitempty" binding:"max=500"`
+	Item         *UpdateShoppingListItemRequest `json:"item,omitempty" binding:"omitempty"`
+	TargetListID string                         `json:"target_list_id,omitempty"`
+	Category     Category                       `json:"category,omitempty"`
+}
+
+// BatchItemsResult says what became of each operation of a batch, in order.
+// List is the list after the batch when it was applied.
+type BatchItemsResult struct {
+	Applied bool              `json:"applied"`
+	Results []BatchItemResult `json:"results"`
+	List    *ShoppingList     `json:"list,omitempty"`
+}
+
+type BatchItemResult struct {
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	// ItemIDs are the items the operation applied to.
+	ItemIDs []string `json:"item_ids,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
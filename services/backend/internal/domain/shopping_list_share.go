@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// What a share link lets its holder do with the list.
+const (
+	ShareAccessView  = "view"
+	ShareAccessCheck = "check"
+)
+
+// ShoppingListShare is a link to a list for people without an account. The
+// link's token is signed for the share's ID; the share stays usable until it
+// is revoked or expires.
+type ShoppingListShare struct {
+	ID        string     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	ListID    string     `json:"list_id" gorm:"type:uuid;not null"`
+	UserID    string     `json:"-" gorm:"type:uuid;not null"`
+	Access    string     `json:"access" gorm:"not null"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+	// Token and URL are only filled in when the share is read by its owner.
+	Token string `json:"token,omitempty" gorm:"-"`
+	URL   string `json:"url,omitempty" gorm:"-"`
+}
+
+// Active reports whether the share's link still works at now.
+func (s *ShoppingListShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || now.Before(*s.ExpiresAt))
+}
+
+// CreateShareRequest shares a list, view-only unless Access says otherwise.
+type CreateShareRequest struct {
+	Access    string     `json:"access,omitempty" binding:"omitempty,oneof=view check"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ListSection is a part of the store and the list's items found there, in
+// the order they are picked up.
+type ListSection struct {
+	Name  string             `json:"name"`
+	Items []ShoppingListItem `json:"items"`
+}
+
+// SharedShoppingList is what a share link shows: the list, sorted for the
+// store, with nothing that identifies its owner.
+type SharedShoppingList struct {
+	Name      string        `json:"name"`
+	Access    string        `json:"access"`
+	ExpiresAt *time.Time    `json:"expires_at,omitempty"`
+	Sections  []ListSection `json:"sections"`
+}
+
+// Formats a shopping list can be exported in.
+const (
+	ExportFormatText     = "text"
+	ExportFormatMarkdown = "markdown"
+	ExportFormatHTML     = "html"
+)
+
+// ShoppingListExport is a rendered list, ready to download.
+type ShoppingListExport struct {
+	Filename    string
+	ContentType string
+	Body        []byte
+}
@@ -0,0 +1,93 @@
+package domain
+
+import "time"
+
+// How often a template's staples are added to its target list.
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// ShoppingListTemplate is a set of staple items. It can be added to any list
+// on demand and, with a Frequency, is added to TargetListID every Every days
+// or weeks, next at NextRunAt.
+type ShoppingListTemplate struct {
+	ID           string                     `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID       string                     `json:"-" gorm:"type:uuid;not null"`
+	Name         string                     `json:"name" gorm:"not null"`
+	TargetListID *string                    `json:"target_list_id,omitempty" gorm:"type:uuid"`
+	Frequency    string                     `json:"frequency,omitempty"`
+	Every        int                        `json:"every"`
+	NextRunAt    *time.Time                 `json:"next_run_at,omitempty"`
+	LastRunAt    *time.Time                 `json:"last_run_at,omitempty"`
+	CreatedAt    time.Time                  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time                  `json:"updated_at" gorm:"autoUpdateTime"`
+	Items        []ShoppingListTemplateItem `json:"items,omitempty" gorm:"foreignKey:TemplateID"`
+}
+
+// Period is the number of days between scheduled runs, or 0 for a template
+// that only runs on demand.
+func (t *ShoppingListTemplate) Period() int {
+	switch t.Frequency {
+	case FrequencyDaily:
+		return t.Every
+	case FrequencyWeekly:
+		return 7 * t.Every
+	}
+	return 0
+}
+
+// NextRunAfter returns the first run of the schedule starting at from that is
+// after now, so runs that were missed are skipped rather than caught up on.
+// It returns nil for a template that does not recur.
+func (t *ShoppingListTemplate) NextRunAfter(from time.Time, now time.Time) *time.Time {
+	period := t.Period()
+	if period <= 0 {
+		return nil
+	}
+	next := from
+	for !next.After(now) {
+		next = next.AddDate(0, 0, period)
+	}
+	return &next
+}
+
+type ShoppingListTemplateItem struct {
+	ID         string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	TemplateID string    `json:"-" gorm:"type:uuid;not null"`
+	Name       string    `json:"name" gorm:"not null"`
+	Amount     float64   `json:"amount"`
+	Unit       string    `json:"unit"`
+	Category   Category  `json:"category" gorm:"not null"`
+	Notes      string    `json:"notes"`
+	Position   int       `json:"-"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ShoppingListTemplateRequest creates or replaces a template. A Frequency
+// needs a TargetListID; the first run is at StartsAt, or right away.
+type ShoppingListTemplateRequest struct {
+	Name         string                `json:"name" binding:"required,max=255"`
+	TargetListID string                `json:"target_list_id,omitempty"`
+	Frequency    string                `json:"frequency,omitempty" binding:"omitempty,oneof=daily weekly"`
+	Every        int                   `json:"every,omitempty" binding:"omitempty,min=1,max=365"`
+	StartsAt     *time.Time            `json:"starts_at,omitempty"`
+	Items        []TemplateItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// TemplateItemRequest is a staple item; without a category it is
+// categorized like items added to a list.
+type TemplateItemRequest struct {
+	Name     string   `json:"name" binding:"required,max=255"`
+	Amount   float64  `json:"amount" binding:"gte=0"`
+	Unit     string   `json:"unit" binding:"max=50"`
+	Category Category `json:"category,omitempty"`
+	Notes    string   `json:"notes"`
+}
+
+// ApplyTemplateRequest adds a template's staples to ListID, or to the
+// template's target list without one.
+type ApplyTemplateRequest struct {
+	ListID string `json:"list_id,omitempty"`
+}
@@ -0,0 +1,103 @@
+package domain
+
+import "time"
+
+// DefaultTripCurrency is used for trips started without a currency.
+const DefaultTripCurrency = "EUR"
+
+// ShoppingTrip is one visit to a store to shop a list. It is open until
+// FinishedAt is set, when TotalCents and ItemCount are fixed.
+type ShoppingTrip struct {
+	ID         string             `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID     string             `json:"-" gorm:"type:uuid;not null"`
+	ListID     *string            `json:"list_id,omitempty" gorm:"type:uuid"`
+	StoreID    *string            `json:"store_id,omitempty" gorm:"type:uuid"`
+	StoreName  string             `json:"store_name"`
+	Currency   string             `json:"currency" gorm:"not null"`
+	TotalCents int64              `json:"total_cents"`
+	ItemCount  int                `json:"item_count"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time          `json:"updated_at" gorm:"autoUpdateTime"`
+	Items      []ShoppingTripItem `json:"items,omitempty" gorm:"foreignKey:TripID"`
+}
+
+// IsOpen reports whether items can still be recorded on the trip.
+func (t *ShoppingTrip) IsOpen() bool {
+	return t.FinishedAt == nil
+}
+
+// ShoppingTripItem is something bought on a trip: PriceCents is what was paid
+// for Quantity Unit of it.
+type ShoppingTripItem struct {
+	ID         string    `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	TripID     string    `json:"trip_id" gorm:"type:uuid;not null"`
+	ListItemID *string   `json:"list_item_id,omitempty" gorm:"type:uuid"`
+	Name       string    `json:"name" gorm:"not null"`
+	ItemKey    string    `json:"-" gorm:"not null"`
+	Category   Category  `json:"category" gorm:"not null"`
+	Quantity   float64   `json:"quantity"`
+	Unit       string    `json:"unit"`
+	PriceCents int64     `json:"price_cents"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// StartTripRequest starts a trip for a list. Without a store the list's own
+// store is used.
+type StartTripRequest struct {
+	ListID   string `json:"list_id" binding:"required"`
+	StoreID  string `json:"store_id,omitempty"`
+	Currency string `json:"currency,omitempty" binding:"omitempty,len=3,alpha"`
+}
+
+// RecordTripItemRequest records what was paid for an item. With ListItemID
+// it is the list's item, which is checked off; otherwise Name describes a
+// purchase that was not on the list. Recording a list item again replaces it.
+type RecordTripItemRequest struct {
+	ListItemID string   `json:"list_item_id,omitempty"`
+	Name       string   `json:"name,omitempty" binding:"max=255"`
+	Category   Category `json:"category,omitempty"`
+	Quantity   float64  `json:"quantity" binding:"gte=0"`
+	Unit       string   `json:"unit" binding:"max=50"`
+	PriceCents int64    `json:"price_cents" binding:"gte=0"`
+}
+
+// Ways spending can be grouped.
+const (
+	SpendingByMonth    = "month"
+	SpendingByCategory = "category"
+	SpendingByStore    = "store"
+)
+
+// SpendingFilter selects finished trips from From (inclusive) to To
+// (exclusive); zero times leave the range open.
+type SpendingFilter struct {
+	GroupBy string    `form:"group_by" binding:"required,oneof=month category store"`
+	From    time.Time `form:"from" time_format:"2006-01-02"`
+	To      time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// SpendingBucket is what was spent in one group, such as a month. Amounts in
+// different currencies are never added up, so a group spent in two
+// currencies has a bucket for each.
+type SpendingBucket struct {
+	Key        string `json:"key"`
+	Currency   string `json:"currency"`
+	TotalCents int64  `json:"total_cents"`
+	TripCount  int    `json:"trip_count"`
+	ItemCount  int    `json:"item_count"`
+}
+
+// PricePoint is one recorded purchase of an item on a finished trip.
+type PricePoint struct {
+	ItemKey    string    `json:"-"`
+	Name       string    `json:"name"`
+	Quantity   float64   `json:"quantity"`
+	Unit       string    `json:"unit"`
+	PriceCents int64     `json:"price_cents"`
+	Currency   string    `json:"currency"`
+	StoreName  string    `json:"store_name"`
+	PaidAt     time.Time `json:"paid_at"`
+}
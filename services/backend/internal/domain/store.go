@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// Store is a user's own shop. Its layout may start as a copy of a chain's and
+// is then edited independently, since a local branch rarely matches the
+// chain default.
+type Store struct {
+	ID           string         `json:"id" gorm:"primaryKey;type:uuid;default:uuid_generate_v4()"`
+	UserID       string         `json:"-" gorm:"type:uuid;not null"`
+	StoreChainID *string        `json:"store_chain_id,omitempty" gorm:"type:uuid"`
+	Name         string         `json:"name" gorm:"not null"`
+	Layout       []StoreSection `json:"layout" gorm:"type:jsonb;serializer:json"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Kinds of learned store positions.
+const (
+	StorePositionItem     = "item"
+	StorePositionCategory = "category"
+)
+
+// StorePosition is where in a store an item (by normalized name) or a
+// category tends to be picked up: 0 is the start of a trip and 1 the end. It
+// is a running mean over the last Observations check-offs.
+type StorePosition struct {
+	StoreID      string    `json:"-" gorm:"primaryKey;type:uuid"`
+	Kind         string    `json:"kind" gorm:"primaryKey"`
+	Key          string    `json:"key" gorm:"primaryKey"`
+	Position     float64   `json:"position" gorm:"not null"`
+	Observations int       `json:"observations" gorm:"not null"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// CreateStoreRequest creates a store. With a chain and no layout, the chain's
+// layout is copied.
+type CreateStoreRequest struct {
+	Name         string         `json:"name" binding:"required,max=255"`
+	StoreChainID string         `json:"store_chain_id,omitempty"`
+	Layout       []StoreSection `json:"layout,omitempty"`
+}
+
+type UpdateStoreRequest struct {
+	Name   *string        `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Layout []StoreSection `json:"layout,omitempty"`
+}
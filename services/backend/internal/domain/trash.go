@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Trash entry types.
+const (
+	TrashTypeRecipe           = "recipe"
+	TrashTypeShoppingList     = "shopping_list"
+	TrashTypeShoppingListItem = "shopping_list_item"
+)
+
+// TrashEntry is a deleted recipe, shopping list or shopping list item that
+// can still be restored until PurgeAt. ListID is set for items only.
+type TrashEntry struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ListID    string    `json:"list_id,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
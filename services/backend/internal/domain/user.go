@@ -21,6 +21,45 @@ type User struct {
 	// clients — that would leak lockout state to an unauthenticated caller.
 	FailedLoginAttempts int        `json:"-" gorm:"column:failed_login_attempts;not null;default:0"`
 	LockedUntil         *time.Time `json:"-" gorm:"column:locked_until"`
+
+	// TOTPSecret is the encrypted authenticator secret. It is set on enrollment
+	// but only enforced once TOTPEnabledAt is set by a confirmed code.
+	// TOTPLastStep is the last accepted time step, so a code cannot be replayed.
+	TOTPSecret    string     `json:"-" gorm:"column:totp_secret;not null;default:''"`
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at" gorm:"column:totp_enabled_at"`
+	TOTPLastStep  int64      `json:"-" gorm:"column:totp_last_step;not null;default:0"`
+
+	// Role is checked per request by middleware.RequireRole. AdminLockedAt is
+	// set when an administrator locks the account; it blocks sign-in until
+	// an administrator clears it.
+	Role          string     `json:"role" gorm:"not null;default:member"`
+	AdminLockedAt *time.Time `json:"-" gorm:"column:admin_locked_at"`
+}
+
+const (
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+// IsAdmin reports whether the user may use the administration API.
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsAdminLocked reports whether an administrator has locked the account.
+func (u *User) IsAdminLocked() bool {
+	return u.AdminLockedAt != nil
+}
+
+// TwoFactorEnabled reports whether login requires a second factor.
+func (u *User) TwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// HasPassword reports whether the user can sign in with a password. Accounts
+// created or claimed through a provider have none until they reset it.
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
 }
 
 // IsEmailVerified reports whether the user has completed email verification.
@@ -49,9 +88,14 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse carries either a token pair and the user or, when the account
+// has two-factor authentication, only a challenge token to redeem at
+// POST /auth/login/2fa together with a code.
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	TokenPair
+	User              *User  `json:"user,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
 }
 
 // Helper method to hash password
@@ -72,8 +72,50 @@ func IsLocked(err error) bool {
 	return false
 }
 
+// IsConflict reports whether err represents a write that lost to a concurrent
+// change, such as accepting a proposal for a recipe edited since.
+func IsConflict(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code == "CONFLICT"
+	}
+	return false
+}
+
+// IsInvalidInput reports whether err was raised with the INVALID_INPUT code,
+// i.e. the request was well-formed but its content was rejected.
+func IsInvalidInput(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code == "INVALID_INPUT"
+	}
+	return false
+}
+
+// IsInvalid2FACode reports whether err is a rejected TOTP or recovery code.
+func IsInvalid2FACode(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code == ErrInvalid2FACode.Code
+	}
+	return false
+}
+
+// IsAIProvider reports whether err is one of the ErrAI* provider failures, which
+// describe a problem with the caller's own key or provider account.
+func IsAIProvider(err error) bool {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		switch appErr.Code {
+		case ErrAIAuthFailed.Code, ErrAIQuotaExceeded.Code, ErrAIModelNotFound.Code, ErrAIUnavailable.Code:
+			return true
+		}
+	}
+	return false
+}
+
 // StatusCode maps an error to the HTTP status a handler should return for it. Known cases
-// (not-found, unauthorized/cross-tenant, account-locked) get their specific status; anything
+// (not-found, unauthorized/cross-tenant, account-locked, conflict, AI provider failures) get their specific status; anything
 // else — including raw GORM/driver errors that must never reach the client — falls back to 500
 // so callers know to log the real error and return a generic message instead of the error's own
 // text.
@@ -85,16 +127,42 @@ func StatusCode(err error) int {
 		return http.StatusForbidden
 	case IsLocked(err):
 		return http.StatusLocked
+	case IsConflict(err):
+		return http.StatusConflict
+	case IsAIProvider(err):
+		return aiProviderStatus(err)
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
+// aiProviderStatus distinguishes the ErrAI* codes: a rejected key or unknown
+// model is a problem with the submitted config, an exhausted quota is worth
+// retrying later, and an outage is the upstream's fault.
+func aiProviderStatus(err error) int {
+	var appErr *AppError
+	errors.As(err, &appErr)
+	switch appErr.Code {
+	case ErrAIQuotaExceeded.Code:
+		return http.StatusTooManyRequests
+	case ErrAIUnavailable.Code:
+		return http.StatusBadGateway
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
 var (
 	ErrNotFound         = &AppError{Code: "NOT_FOUND", Message: "resource not found"}
 	ErrUnauthorized     = &AppError{Code: "UNAUTHORIZED", Message: "unauthorized"}
 	ErrInternal         = &AppError{Code: "INTERNAL", Message: "internal error"}
 	ErrAccountLocked    = &AppError{Code: "LOCKED", Message: "account temporarily locked, try again later"}
+	ErrConflict         = &AppError{Code: "CONFLICT", Message: "resource was modified concurrently"}
+	ErrAIAuthFailed     = &AppError{Code: "AI_AUTH_FAILED", Message: "AI provider rejected the API key"}
+	ErrAIQuotaExceeded  = &AppError{Code: "AI_QUOTA_EXCEEDED", Message: "AI provider quota or rate limit exceeded"}
+	ErrAIModelNotFound  = &AppError{Code: "AI_MODEL_NOT_FOUND", Message: "AI provider does not offer this model"}
+	ErrAIUnavailable    = &AppError{Code: "AI_UNAVAILABLE", Message: "AI provider is unavailable"}
+	ErrInvalid2FACode   = &AppError{Code: "INVALID_2FA_CODE", Message: "invalid two-factor code"}
 	ErrTooManyRedirects = fmt.Errorf("too many redirects")
 	ErrInvalidURL       = fmt.Errorf("invalid URL")
 	ErrFetchFailed      = fmt.Errorf("failed to fetch content")
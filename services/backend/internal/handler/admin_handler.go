@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AdminHandler struct {
+	service service.AdminService
+	logger  *zap.Logger
+}
+
+func NewAdminHandler(service service.AdminService, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// auditActor identifies the calling administrator for the audit trail.
+func auditActor(c *gin.Context) domain.AuditActor {
+	return domain.AuditActor{
+		UserID: middleware.GetUserID(c),
+		Client: sessionClient(c),
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	users, err := h.service.ListUsers(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list users", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, err := h.service.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) LockUser(c *gin.Context) {
+	user, err := h.service.LockUser(c.Request.Context(), auditActor(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to lock user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	user, err := h.service.UnlockUser(c.Request.Context(), auditActor(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to unlock user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) VerifyUser(c *gin.Context) {
+	user, err := h.service.VerifyUser(c.Request.Context(), auditActor(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to verify user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	var req domain.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetUserRole(c.Request.Context(), auditActor(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to change user role")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	if err := h.service.DeleteUser(c.Request.Context(), auditActor(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to delete user")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user deleted"})
+}
+
+func (h *AdminHandler) ListAIModels(c *gin.Context) {
+	models, err := h.service.ListAIModels(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list AI models", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list AI models"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models)
+}
+
+func (h *AdminHandler) UpdateAIModel(c *gin.Context) {
+	var req domain.UpdateAIModelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	model, err := h.service.UpdateAIModel(c.Request.Context(), auditActor(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to update AI model")
+		return
+	}
+
+	c.JSON(http.StatusOK, model)
+}
+
+func (h *AdminHandler) CreateStoreChain(c *gin.Context) {
+	var req domain.CreateStoreChainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	chain, err := h.service.CreateStoreChain(c.Request.Context(), auditActor(c), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to create store chain")
+		return
+	}
+
+	c.JSON(http.StatusCreated, chain)
+}
+
+func (h *AdminHandler) UpdateStoreChain(c *gin.Context) {
+	var req domain.UpdateStoreChainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	chain, err := h.service.UpdateStoreChain(c.Request.Context(), auditActor(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to update store chain")
+		return
+	}
+
+	c.JSON(http.StatusOK, chain)
+}
+
+func (h *AdminHandler) DeleteStoreChain(c *gin.Context) {
+	if err := h.service.DeleteStoreChain(c.Request.Context(), auditActor(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to delete store chain")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "store chain deleted"})
+}
+
+func (h *AdminHandler) ListAuditEvents(c *gin.Context) {
+	var filter domain.AuditEventFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.service.ListAuditEvents(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to list audit events", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list audit events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *AdminHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case apperrors.IsNotFound(err):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case apperrors.IsInvalidInput(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"github.com/H3nSte1n/recipe/internal/domain"
 	apperrors "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/middleware"
@@ -23,7 +24,8 @@ func NewAIConfigHandler(aiConfigService service.AIConfigService, logger *zap.Log
 }
 
 // respondError maps a service error to its HTTP status and writes the response. Known errors
-// (not-found, cross-tenant/unauthorized) get their specific status and safe message; anything
+// (not-found, cross-tenant/unauthorized) get their specific status and safe message, and AI
+// provider failures also carry their code so clients can tell a bad key from a quota; anything
 // else — including raw GORM/Postgres driver errors, which must never reach the client — is
 // logged server-side with the real error and returns a generic fallback message.
 func (h *AIConfigHandler) respondError(c *gin.Context, err error, fallback string) {
@@ -33,6 +35,11 @@ func (h *AIConfigHandler) respondError(c *gin.Context, err error, fallback strin
 		c.JSON(status, gin.H{"error": fallback})
 		return
 	}
+	var appErr *apperrors.AppError
+	if apperrors.IsAIProvider(err) && errors.As(err, &appErr) {
+		c.JSON(status, gin.H{"error": appErr.Message, "code": appErr.Code})
+		return
+	}
 	c.JSON(status, gin.H{"error": err.Error()})
 }
 
@@ -142,3 +149,16 @@ func (h *AIConfigHandler) GetDefault(c *gin.Context) {
 
 	c.JSON(http.StatusOK, config)
 }
+
+func (h *AIConfigHandler) Test(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	configID := c.Param("id")
+
+	config, err := h.aiConfigService.Test(c.Request.Context(), userID, configID)
+	if err != nil {
+		h.respondError(c, err, "failed to test AI configuration")
+		return
+	}
+
+	c.JSON(http.StatusOK, config)
+}
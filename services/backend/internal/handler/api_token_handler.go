@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type APITokenHandler struct {
+	service service.APITokenService
+	logger  *zap.Logger
+}
+
+func NewAPITokenHandler(service service.APITokenService, logger *zap.Logger) *APITokenHandler {
+	return &APITokenHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *APITokenHandler) Create(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	var req domain.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.service.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		if apperrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to create API token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create API token"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+func (h *APITokenHandler) List(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	tokens, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("failed to list API tokens", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list API tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *APITokenHandler) Get(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	token, err := h.service.Get(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to get API token")
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+func (h *APITokenHandler) Update(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	var req domain.UpdateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, err := h.service.Update(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to update API token")
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+func (h *APITokenHandler) Revoke(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	if err := h.service.Revoke(c.Request.Context(), userID, c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to revoke API token")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API token revoked"})
+}
+
+func (h *APITokenHandler) respondError(c *gin.Context, err error, message string) {
+	if apperrors.IsNotFound(err) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API token not found"})
+		return
+	}
+	h.logger.Error(message, zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AuditHandler struct {
+	service service.AuditService
+	logger  *zap.Logger
+}
+
+func NewAuditHandler(service service.AuditService, logger *zap.Logger) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *AuditHandler) ListActivity(c *gin.Context) {
+	var filter domain.ActivityFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := h.service.ListActivity(c.Request.Context(), middleware.GetUserID(c), filter)
+	if err != nil {
+		h.logger.Error("failed to list account activity", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list account activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type CategoryHandler struct {
+	service service.CategoryService
+	logger  *zap.Logger
+}
+
+func NewCategoryHandler(service service.CategoryService, logger *zap.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// List returns the category tree, named in the caller's Accept-Language.
+func (h *CategoryHandler) List(c *gin.Context) {
+	categories, err := h.service.List(c.Request.Context(), acceptedLanguages(c.GetHeader("Accept-Language")))
+	if err != nil {
+		h.logger.Error("failed to list categories", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list categories"})
+		return
+	}
+
+	c.Header("Vary", "Accept-Language")
+	c.JSON(http.StatusOK, categories)
+}
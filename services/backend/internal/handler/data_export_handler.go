@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DataExportHandler struct {
+	service service.DataExportService
+	logger  *zap.Logger
+}
+
+func NewDataExportHandler(service service.DataExportService, logger *zap.Logger) *DataExportHandler {
+	return &DataExportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RequestExport answers 202: the archive is built in the background and its
+// link is emailed to the account.
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	export, err := h.service.RequestExport(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		if apperrors.IsConflict(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to request data export", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to request data export"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+func (h *DataExportHandler) GetLatest(c *gin.Context) {
+	export, err := h.service.GetLatest(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to get data export", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get data export"})
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
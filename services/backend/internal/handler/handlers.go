@@ -7,20 +7,46 @@ import (
 
 type Handlers struct {
 	UserHandler         *UserHandler
+	APITokenHandler     *APITokenHandler
 	ProfileHandler      *ProfileHandler
 	AIConfigHandler     *AIConfigHandler
 	RecipeHandler       *RecipeHandler
 	ShoppingListHandler *ShoppingListHandler
+	ShoppingTripHandler *ShoppingTripHandler
+	TemplateHandler     *ShoppingListTemplateHandler
+	ShareHandler        *ShoppingListShareHandler
+	SuggestionHandler   *ItemSuggestionHandler
+	ProductHandler      *ProductHandler
+	PriceHandler        *ItemPriceHandler
 	StoreChainHandler   *StoreChainHandler
+	StoreHandler        *StoreHandler
+	CategoryHandler     *CategoryHandler
+	AdminHandler        *AdminHandler
+	AuditHandler        *AuditHandler
+	DataExportHandler   *DataExportHandler
+	TrashHandler        *TrashHandler
 }
 
 func NewHandlers(services *service.Services, logger *zap.Logger) *Handlers {
 	return &Handlers{
 		UserHandler:         NewUserHandler(services.UserService),
+		APITokenHandler:     NewAPITokenHandler(services.APITokenService, logger),
 		ProfileHandler:      NewProfileHandler(services.ProfileService),
 		AIConfigHandler:     NewAIConfigHandler(services.AIConfigService, logger),
 		RecipeHandler:       NewRecipeHandler(services.RecipeService, logger),
 		ShoppingListHandler: NewShoppingListHandler(services.ShoppingListService, logger),
+		ShoppingTripHandler: NewShoppingTripHandler(services.ShoppingTripService, logger),
+		TemplateHandler:     NewShoppingListTemplateHandler(services.TemplateService, logger),
+		ShareHandler:        NewShoppingListShareHandler(services.ShareService, logger),
+		SuggestionHandler:   NewItemSuggestionHandler(services.SuggestionService, logger),
+		ProductHandler:      NewProductHandler(services.ProductService, logger),
+		PriceHandler:        NewItemPriceHandler(services.PriceService, logger),
 		StoreChainHandler:   NewStoreChainHandler(services.StoreChainService, logger),
+		StoreHandler:        NewStoreHandler(services.StoreService, logger),
+		CategoryHandler:     NewCategoryHandler(services.CategoryService, logger),
+		AdminHandler:        NewAdminHandler(services.AdminService, logger),
+		AuditHandler:        NewAuditHandler(services.AuditService, logger),
+		DataExportHandler:   NewDataExportHandler(services.DataExportService, logger),
+		TrashHandler:        NewTrashHandler(services.TrashService, logger),
 	}
 }
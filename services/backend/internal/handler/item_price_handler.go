@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ItemPriceHandler struct {
+	service service.ItemPriceService
+	logger  *zap.Logger
+}
+
+func NewItemPriceHandler(service service.ItemPriceService, logger *zap.Logger) *ItemPriceHandler {
+	return &ItemPriceHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (unknown price, chain, list or recipe,
+// someone else's recipe, rejected input) with their status and logs
+// anything else behind a generic message.
+func (h *ItemPriceHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if apperrors.IsInvalidInput(err) {
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *ItemPriceHandler) List(c *gin.Context) {
+	prices, err := h.service.List(c.Request.Context(), middleware.GetUserID(c), c.Query("store_chain_id"))
+	if err != nil {
+		h.respondError(c, err, "failed to list prices")
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+func (h *ItemPriceHandler) Save(c *gin.Context) {
+	var req domain.SaveItemPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	price, err := h.service.Save(c.Request.Context(), middleware.GetUserID(c), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to save price")
+		return
+	}
+
+	c.JSON(http.StatusOK, price)
+}
+
+func (h *ItemPriceHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to delete price")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "price deleted"})
+}
+
+func (h *ItemPriceHandler) ListCost(c *gin.Context) {
+	cost, err := h.service.ListCost(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), c.Query("store_chain_id"))
+	if err != nil {
+		h.respondError(c, err, "failed to cost list")
+		return
+	}
+
+	c.JSON(http.StatusOK, cost)
+}
+
+func (h *ItemPriceHandler) RecipeCost(c *gin.Context) {
+	servings, ok := parseServings(c.Query("servings"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "servings must be a positive number"})
+		return
+	}
+
+	cost, err := h.service.RecipeCost(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), servings, c.Query("store_chain_id"))
+	if err != nil {
+		h.respondError(c, err, "failed to cost recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, cost)
+}
+
+// parseServings reads an optional servings query parameter, zero when it is
+// not given. It is not ok unless it is a finite positive number.
+func parseServings(raw string) (float64, bool) {
+	if raw == "" {
+		return 0, true
+	}
+	servings, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(servings) || math.IsInf(servings, 0) || servings <= 0 {
+		return 0, false
+	}
+	return servings, true
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServings(t *testing.T) {
+	for raw, want := range map[string]float64{"": 0, "4": 4, "2.5": 2.5} {
+		servings, ok := parseServings(raw)
+		require.True(t, ok, raw)
+		require.Equal(t, want, servings, raw)
+	}
+	for _, raw := range []string{"NaN", "nan", "Inf", "+Inf", "-Inf", "infinity", "0", "-2", "1e400", "two"} {
+		_, ok := parseServings(raw)
+		require.False(t, ok, raw)
+	}
+}
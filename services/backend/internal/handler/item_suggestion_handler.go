@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ItemSuggestionHandler struct {
+	service service.ItemSuggestionService
+	logger  *zap.Logger
+}
+
+func NewItemSuggestionHandler(service service.ItemSuggestionService, logger *zap.Logger) *ItemSuggestionHandler {
+	return &ItemSuggestionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (not found, someone else's list, rejected
+// input) with their status and logs anything else behind a generic message.
+func (h *ItemSuggestionHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if apperrors.IsInvalidInput(err) {
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *ItemSuggestionHandler) Suggest(c *gin.Context) {
+	var query domain.SuggestItemsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestions, err := h.service.Suggest(c.Request.Context(), middleware.GetUserID(c), query.Q, query.Limit)
+	if err != nil {
+		h.respondError(c, err, "failed to suggest items")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+func (h *ItemSuggestionHandler) BoughtTogether(c *gin.Context) {
+	var query struct {
+		Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestions, err := h.service.BoughtTogether(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), query.Limit)
+	if err != nil {
+		h.respondError(c, err, "failed to suggest items")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ProductHandler struct {
+	service service.ProductService
+	logger  *zap.Logger
+}
+
+func NewProductHandler(service service.ProductService, logger *zap.Logger) *ProductHandler {
+	return &ProductHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (unknown barcode, someone else's list,
+// rejected input) with their status and logs anything else behind a generic
+// message.
+func (h *ProductHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if apperrors.IsInvalidInput(err) {
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *ProductHandler) Lookup(c *gin.Context) {
+	product, err := h.service.Lookup(c.Request.Context(), middleware.GetUserID(c), c.Param("barcode"))
+	if err != nil {
+		h.respondError(c, err, "failed to look up barcode")
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func (h *ProductHandler) AddToList(c *gin.Context) {
+	var req domain.ScanBarcodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.AddToList(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to add scanned item")
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *ProductHandler) ListSaved(c *gin.Context) {
+	products, err := h.service.ListSaved(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		h.respondError(c, err, "failed to list products")
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+func (h *ProductHandler) Save(c *gin.Context) {
+	var req domain.SaveProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.service.Save(c.Request.Context(), middleware.GetUserID(c), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to save product")
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func (h *ProductHandler) DeleteSaved(c *gin.Context) {
+	if err := h.service.DeleteSaved(c.Request.Context(), middleware.GetUserID(c), c.Param("barcode")); err != nil {
+		h.respondError(c, err, "failed to delete product")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
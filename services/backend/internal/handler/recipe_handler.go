@@ -15,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/zap"
+	"golang.org/x/text/language"
 )
 
 const (
@@ -180,6 +181,20 @@ func (h *RecipeHandler) Get(c *gin.Context) {
 		nutritionLevel = domain.NutritionDetailBase
 	}
 
+	if languages := acceptedLanguages(c.GetHeader("Accept-Language")); len(languages) > 0 {
+		recipe, lang, err := h.recipeService.GetTranslated(c.Request.Context(), userID, recipeID, nutritionLevel, languages)
+		if err != nil {
+			h.respondError(c, err, "failed to get recipe")
+			return
+		}
+		if lang != "" {
+			c.Header("Content-Language", lang)
+		}
+		c.Header("Vary", "Accept-Language")
+		c.JSON(http.StatusOK, recipe)
+		return
+	}
+
 	recipe, err := h.recipeService.GetByID(c.Request.Context(), userID, recipeID, nutritionLevel)
 	if err != nil {
 		h.respondError(c, err, "failed to get recipe")
@@ -189,6 +204,25 @@ func (h *RecipeHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, recipe)
 }
 
+// acceptedLanguages returns the language tags of an Accept-Language header in
+// order of preference, or nil when the header is absent or unparseable.
+func acceptedLanguages(header string) []string {
+	if header == "" {
+		return nil
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	languages := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag != language.Und {
+			languages = append(languages, tag.String())
+		}
+	}
+	return languages
+}
+
 func (h *RecipeHandler) ListMine(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
@@ -345,3 +379,138 @@ func (h *RecipeHandler) ParsePlainTextInstructions(c *gin.Context) {
 
 	c.JSON(http.StatusOK, instructions)
 }
+
+func (h *RecipeHandler) Generate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.GenerateRecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipe, err := h.recipeService.Generate(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, err, "failed to generate recipe")
+		return
+	}
+
+	c.JSON(http.StatusCreated, recipe)
+}
+
+func (h *RecipeHandler) SuggestSubstitutes(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	substitutes, err := h.recipeService.SuggestSubstitutes(c.Request.Context(), userID, c.Param("id"), c.Param("ingredientId"))
+	if err != nil {
+		h.respondError(c, err, "failed to suggest substitutes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"substitutes": substitutes})
+}
+
+func (h *RecipeHandler) ApplySubstitute(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.ApplySubstituteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant, err := h.recipeService.ApplySubstitute(c.Request.Context(), userID, c.Param("id"), c.Param("ingredientId"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to apply substitute")
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+func (h *RecipeHandler) Translate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	lang := c.Query("lang")
+	if _, err := language.Parse(lang); lang == "" || err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lang must be a valid language code"})
+		return
+	}
+
+	translation, err := h.recipeService.Translate(c.Request.Context(), userID, c.Param("id"), lang)
+	if err != nil {
+		h.respondError(c, err, "failed to translate recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *RecipeHandler) ProposeEdit(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req domain.AIEditRecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	proposal, err := h.recipeService.ProposeEdit(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to edit recipe")
+		return
+	}
+
+	c.JSON(http.StatusCreated, proposal)
+}
+
+func (h *RecipeHandler) AcceptEdit(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	recipe, err := h.recipeService.AcceptEdit(c.Request.Context(), userID, c.Param("id"), c.Param("proposalId"))
+	if err != nil {
+		h.respondError(c, err, "failed to accept edit")
+		return
+	}
+
+	c.JSON(http.StatusOK, recipe)
+}
+
+func (h *RecipeHandler) RejectEdit(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.recipeService.RejectEdit(c.Request.Context(), userID, c.Param("id"), c.Param("proposalId")); err != nil {
+		h.respondError(c, err, "failed to reject edit")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "edit rejected"})
+}
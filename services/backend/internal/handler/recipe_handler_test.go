@@ -73,6 +73,53 @@ func (m *mockRecipeService) ParsePlainTextInstructions(ctx context.Context, user
 	return v, args.Error(1)
 }
 
+func (m *mockRecipeService) Generate(ctx context.Context, userID string, req *domain.GenerateRecipeRequest) (*domain.Recipe, error) {
+	args := m.Called(ctx, userID, req)
+	v, _ := args.Get(0).(*domain.Recipe)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeService) SuggestSubstitutes(ctx context.Context, userID string, recipeID string, ingredientID string) ([]domain.IngredientSubstitute, error) {
+	args := m.Called(ctx, userID, recipeID, ingredientID)
+	v, _ := args.Get(0).([]domain.IngredientSubstitute)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeService) ApplySubstitute(ctx context.Context, userID string, recipeID string, ingredientID string, req *domain.ApplySubstituteRequest) (*domain.Recipe, error) {
+	args := m.Called(ctx, userID, recipeID, ingredientID, req)
+	v, _ := args.Get(0).(*domain.Recipe)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeService) Translate(ctx context.Context, userID string, recipeID string, language string) (*domain.RecipeTranslation, error) {
+	args := m.Called(ctx, userID, recipeID, language)
+	v, _ := args.Get(0).(*domain.RecipeTranslation)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeService) GetTranslated(ctx context.Context, userID string, recipeID string, nutritionLevel domain.NutritionDetailLevel, languages []string) (*domain.Recipe, string, error) {
+	args := m.Called(ctx, userID, recipeID, nutritionLevel, languages)
+	v, _ := args.Get(0).(*domain.Recipe)
+	return v, args.String(1), args.Error(2)
+}
+
+func (m *mockRecipeService) ProposeEdit(ctx context.Context, userID string, recipeID string, req *domain.AIEditRecipeRequest) (*domain.RecipeEditProposal, error) {
+	args := m.Called(ctx, userID, recipeID, req)
+	v, _ := args.Get(0).(*domain.RecipeEditProposal)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeService) AcceptEdit(ctx context.Context, userID string, recipeID string, proposalID string) (*domain.Recipe, error) {
+	args := m.Called(ctx, userID, recipeID, proposalID)
+	v, _ := args.Get(0).(*domain.Recipe)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeService) RejectEdit(ctx context.Context, userID string, recipeID string, proposalID string) error {
+	args := m.Called(ctx, userID, recipeID, proposalID)
+	return args.Error(0)
+}
+
 func TestRecipeHandler_Create(t *testing.T) {
 	userID := "550e8400-e29b-41d4-a716-446655440000"
 	createRecipeRequest := domain.CreateRecipeRequest{Description: "Foo", Title: "Foobar", IsPrivate: false, SourceType: "MANUAL", Servings: 1}
@@ -147,6 +194,15 @@ func TestRecipeHandler_Create(t *testing.T) {
 			expectedBodyContains: "error",
 			mockMethod:           func(m *mockRecipeService) {},
 		},
+		{
+			name:                 "returns 400 bad request when the client claims an AI source type",
+			body:                 []byte(`{"title":"Foobar","source_type":"AI","servings":1}`),
+			multipart:            false,
+			setUserID:            true,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "SourceType",
+			mockMethod:           func(m *mockRecipeService) {},
+		},
 		{
 			name:                 "returns 500 internal server error when service returns error",
 			body:                 jsonCreateRecipeRequest,
@@ -839,3 +895,416 @@ func TestRecipeHandler_ParsePlainTextInstructions(t *testing.T) {
 		})
 	}
 }
+
+func TestRecipeHandler_Generate(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	generateRequest := domain.GenerateRecipeRequest{Ingredients: []string{"rice", "eggs"}, DietaryConstraints: []string{"vegetarian"}, MaxTime: 30, Servings: 2, Cuisine: "Korean"}
+	recipe := domain.Recipe{ID: "1_foo", Title: "Egg fried rice", SourceType: domain.SourceTypeAI, Servings: 2}
+
+	tests := []struct {
+		name                 string
+		body                 []byte
+		setUserID            bool
+		expectedStatusCode   int
+		expectedBodyContains string
+		mockMethod           func(m *mockRecipeService)
+	}{
+		{
+			name:                 "returns 201 with generated recipe",
+			body:                 mustJson(t, generateRequest),
+			setUserID:            true,
+			expectedStatusCode:   http.StatusCreated,
+			expectedBodyContains: string(mustJson(t, recipe)),
+			mockMethod: func(m *mockRecipeService) {
+				m.On("Generate", mock.Anything, userID, mock.MatchedBy(func(req *domain.GenerateRecipeRequest) bool {
+					return len(req.Ingredients) == 2 && req.Servings == 2 && req.MaxTime == 30 && req.Cuisine == "Korean"
+				})).Return(&recipe, nil).Once()
+			},
+		},
+		{
+			name:                 "returns 400 when no ingredients are given",
+			body:                 []byte(`{"ingredients":[],"servings":2}`),
+			setUserID:            true,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "error",
+			mockMethod:           func(m *mockRecipeService) {},
+		},
+		{
+			name:                 "returns 400 when servings is missing",
+			body:                 []byte(`{"ingredients":["rice"]}`),
+			setUserID:            true,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "error",
+			mockMethod:           func(m *mockRecipeService) {},
+		},
+		{
+			name:                 "returns 401 unauthorized when user is not authenticated",
+			body:                 mustJson(t, generateRequest),
+			setUserID:            false,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedBodyContains: "unauthorized",
+			mockMethod:           func(m *mockRecipeService) {},
+		},
+		{
+			name:                 "returns 500 with generic message when generation fails",
+			body:                 mustJson(t, generateRequest),
+			setUserID:            true,
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedBodyContains: "failed to generate recipe",
+			mockMethod: func(m *mockRecipeService) {
+				m.On("Generate", mock.Anything, userID, mock.Anything).Return(nil, errors.New("provider exploded")).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockRecipeService)
+			tt.mockMethod(m)
+
+			handler := NewRecipeHandler(m, zap.NewNop())
+			router := gin.New()
+			router.POST("/api/v1/recipes/generate", func(ctx *gin.Context) {
+				if tt.setUserID {
+					ctx.Set("user_id", userID)
+				}
+				handler.Generate(ctx)
+			})
+
+			w := performRequest(router, http.MethodPost, "/api/v1/recipes/generate", tt.body)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBodyContains != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBodyContains)
+			}
+			assert.NotContains(t, w.Body.String(), "provider exploded")
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeHandler_SuggestSubstitutes(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	substitutes := []domain.IngredientSubstitute{{Name: "milk", Amount: 1, Unit: "cup", Source: domain.SubstituteSourceCurated}}
+
+	tests := []struct {
+		name                 string
+		expectedStatusCode   int
+		expectedBodyContains string
+		mockMethod           func(m *mockRecipeService)
+	}{
+		{
+			name:                 "returns 200 with substitutes",
+			expectedStatusCode:   http.StatusOK,
+			expectedBodyContains: `"substitutes":` + string(mustJson(t, substitutes)),
+			mockMethod: func(m *mockRecipeService) {
+				m.On("SuggestSubstitutes", mock.Anything, userID, "recipe-1", "ing-1").Return(substitutes, nil).Once()
+			},
+		},
+		{
+			name:                 "returns 404 when recipe or ingredient is not found",
+			expectedStatusCode:   http.StatusNotFound,
+			expectedBodyContains: "not found",
+			mockMethod: func(m *mockRecipeService) {
+				m.On("SuggestSubstitutes", mock.Anything, userID, "recipe-1", "ing-1").Return(nil, apperrors.ErrNotFound).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockRecipeService)
+			tt.mockMethod(m)
+
+			handler := NewRecipeHandler(m, zap.NewNop())
+			router := gin.New()
+			router.GET("/api/v1/recipes/:id/ingredients/:ingredientId/substitutes", func(ctx *gin.Context) {
+				ctx.Set("user_id", userID)
+				handler.SuggestSubstitutes(ctx)
+			})
+
+			w := performRequest(router, http.MethodGet, "/api/v1/recipes/recipe-1/ingredients/ing-1/substitutes", nil)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBodyContains)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeHandler_ApplySubstitute(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	variantOf := "recipe-1"
+	variant := domain.Recipe{ID: "recipe-2", UserID: userID, IsPrivate: true, VariantOfID: &variantOf}
+
+	tests := []struct {
+		name               string
+		body               []byte
+		expectedStatusCode int
+		mockMethod         func(m *mockRecipeService)
+	}{
+		{
+			name:               "returns 201 with the new variant",
+			body:               []byte(`{"name":"milk","amount":1,"unit":"cup","notes":"with lemon juice"}`),
+			expectedStatusCode: http.StatusCreated,
+			mockMethod: func(m *mockRecipeService) {
+				m.On("ApplySubstitute", mock.Anything, userID, "recipe-1", "ing-1", mock.MatchedBy(func(req *domain.ApplySubstituteRequest) bool {
+					return req.Name == "milk" && req.Amount == 1 && req.Unit == "cup"
+				})).Return(&variant, nil).Once()
+			},
+		},
+		{
+			name:               "returns 400 when name is missing",
+			body:               []byte(`{"amount":1}`),
+			expectedStatusCode: http.StatusBadRequest,
+			mockMethod:         func(m *mockRecipeService) {},
+		},
+		{
+			name:               "returns 400 when amount is negative",
+			body:               []byte(`{"name":"milk","amount":-1}`),
+			expectedStatusCode: http.StatusBadRequest,
+			mockMethod:         func(m *mockRecipeService) {},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockRecipeService)
+			tt.mockMethod(m)
+
+			handler := NewRecipeHandler(m, zap.NewNop())
+			router := gin.New()
+			router.POST("/api/v1/recipes/:id/ingredients/:ingredientId/substitutes/apply", func(ctx *gin.Context) {
+				ctx.Set("user_id", userID)
+				handler.ApplySubstitute(ctx)
+			})
+
+			w := performRequest(router, http.MethodPost, "/api/v1/recipes/recipe-1/ingredients/ing-1/substitutes/apply", tt.body)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeHandler_Get_AcceptLanguage(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	recipe := domain.Recipe{ID: "1_foo", Title: "Pfannkuchen"}
+
+	tests := []struct {
+		name                    string
+		acceptLanguage          string
+		appliedLanguage         string
+		expectedContentLanguage string
+	}{
+		{
+			name:                    "sets Content-Language when a translation is applied",
+			acceptLanguage:          "de-AT, de;q=0.9, en;q=0.5",
+			appliedLanguage:         "de",
+			expectedContentLanguage: "de",
+		},
+		{
+			name:                    "omits Content-Language when no translation matches",
+			acceptLanguage:          "fr",
+			appliedLanguage:         "",
+			expectedContentLanguage: "",
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockRecipeService)
+			m.On("GetTranslated", mock.Anything, userID, recipe.ID, domain.NutritionDetailBase, mock.MatchedBy(func(languages []string) bool {
+				return len(languages) > 0
+			})).Return(&recipe, tt.appliedLanguage, nil).Once()
+
+			handler := NewRecipeHandler(m, zap.NewNop())
+			router := gin.New()
+			router.GET("/api/v1/recipes/:id", func(ctx *gin.Context) {
+				ctx.Set("user_id", userID)
+				handler.Get(ctx)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/recipes/"+recipe.ID, nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedContentLanguage, w.Header().Get("Content-Language"))
+			assert.Contains(t, w.Body.String(), recipe.Title)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeHandler_Translate(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	translation := domain.RecipeTranslation{RecipeID: "recipe-1", Language: "de", Title: "Pfannkuchen"}
+
+	tests := []struct {
+		name               string
+		url                string
+		expectedStatusCode int
+		mockMethod         func(m *mockRecipeService)
+	}{
+		{
+			name:               "returns 200 with the stored translation",
+			url:                "/api/v1/recipes/recipe-1/translate?lang=de",
+			expectedStatusCode: http.StatusOK,
+			mockMethod: func(m *mockRecipeService) {
+				m.On("Translate", mock.Anything, userID, "recipe-1", "de").Return(&translation, nil).Once()
+			},
+		},
+		{
+			name:               "returns 400 when lang is missing",
+			url:                "/api/v1/recipes/recipe-1/translate",
+			expectedStatusCode: http.StatusBadRequest,
+			mockMethod:         func(m *mockRecipeService) {},
+		},
+		{
+			name:               "returns 400 when lang is not a language code",
+			url:                "/api/v1/recipes/recipe-1/translate?lang=not%20a%20language",
+			expectedStatusCode: http.StatusBadRequest,
+			mockMethod:         func(m *mockRecipeService) {},
+		},
+		{
+			name:               "returns 403 when translating someone else's public recipe",
+			url:                "/api/v1/recipes/recipe-1/translate?lang=de",
+			expectedStatusCode: http.StatusForbidden,
+			mockMethod: func(m *mockRecipeService) {
+				m.On("Translate", mock.Anything, userID, "recipe-1", "de").Return(nil, apperrors.ErrUnauthorized).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockRecipeService)
+			tt.mockMethod(m)
+
+			handler := NewRecipeHandler(m, zap.NewNop())
+			router := gin.New()
+			router.POST("/api/v1/recipes/:id/translate", func(ctx *gin.Context) {
+				ctx.Set("user_id", userID)
+				handler.Translate(ctx)
+			})
+
+			w := performRequest(router, http.MethodPost, tt.url, nil)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeHandler_ProposeEdit(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	proposal := domain.RecipeEditProposal{ID: "p-1", RecipeID: "recipe-1", Status: domain.EditProposalPending,
+		Changes: []domain.RecipeFieldChange{{Field: "title", Before: "Chili", After: "Veggie chili"}}}
+
+	tests := []struct {
+		name               string
+		body               []byte
+		expectedStatusCode int
+		mockMethod         func(m *mockRecipeService)
+	}{
+		{
+			name:               "returns 201 with the proposal",
+			body:               []byte(`{"instruction":"make it vegetarian"}`),
+			expectedStatusCode: http.StatusCreated,
+			mockMethod: func(m *mockRecipeService) {
+				m.On("ProposeEdit", mock.Anything, userID, "recipe-1", &domain.AIEditRecipeRequest{Instruction: "make it vegetarian"}).Return(&proposal, nil).Once()
+			},
+		},
+		{
+			name:               "returns 400 when instruction is missing",
+			body:               []byte(`{}`),
+			expectedStatusCode: http.StatusBadRequest,
+			mockMethod:         func(m *mockRecipeService) {},
+		},
+		{
+			name:               "returns 403 for someone else's recipe",
+			body:               []byte(`{"instruction":"make it vegetarian"}`),
+			expectedStatusCode: http.StatusForbidden,
+			mockMethod: func(m *mockRecipeService) {
+				m.On("ProposeEdit", mock.Anything, userID, "recipe-1", mock.Anything).Return(nil, apperrors.ErrUnauthorized).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockRecipeService)
+			tt.mockMethod(m)
+
+			handler := NewRecipeHandler(m, zap.NewNop())
+			router := gin.New()
+			router.POST("/api/v1/recipes/:id/ai-edit", func(ctx *gin.Context) {
+				ctx.Set("user_id", userID)
+				handler.ProposeEdit(ctx)
+			})
+
+			w := performRequest(router, http.MethodPost, "/api/v1/recipes/recipe-1/ai-edit", tt.body)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecipeHandler_AcceptEdit(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	recipe := domain.Recipe{ID: "recipe-1", Title: "Veggie chili"}
+
+	tests := []struct {
+		name                 string
+		expectedStatusCode   int
+		expectedBodyContains string
+		mockMethod           func(m *mockRecipeService)
+	}{
+		{
+			name:                 "returns 200 with the updated recipe",
+			expectedStatusCode:   http.StatusOK,
+			expectedBodyContains: "Veggie chili",
+			mockMethod: func(m *mockRecipeService) {
+				m.On("AcceptEdit", mock.Anything, userID, "recipe-1", "p-1").Return(&recipe, nil).Once()
+			},
+		},
+		{
+			name:                 "returns 409 when the recipe changed since the proposal",
+			expectedStatusCode:   http.StatusConflict,
+			expectedBodyContains: "recipe changed",
+			mockMethod: func(m *mockRecipeService) {
+				m.On("AcceptEdit", mock.Anything, userID, "recipe-1", "p-1").Return(nil, apperrors.ErrConflict.Wrap("recipe changed since the proposal was made")).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockRecipeService)
+			tt.mockMethod(m)
+
+			handler := NewRecipeHandler(m, zap.NewNop())
+			router := gin.New()
+			router.POST("/api/v1/recipes/:id/ai-edit/:proposalId/accept", func(ctx *gin.Context) {
+				ctx.Set("user_id", userID)
+				handler.AcceptEdit(ctx)
+			})
+
+			w := performRequest(router, http.MethodPost, "/api/v1/recipes/recipe-1/ai-edit/p-1/accept", nil)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBodyContains)
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/middleware"
 	"github.com/H3nSte1n/recipe/internal/service"
 	"github.com/gin-gonic/gin"
@@ -67,10 +71,9 @@ func (h *ShoppingListHandler) Get(c *gin.Context) {
 			return
 		}
 		list, err = h.service.GetSortedByStoreName(c.Request.Context(), userID, listID, storeName, country, sortDirection)
-	} else if sortBy != "" {
-		list, err = h.service.GetSorted(c.Request.Context(), userID, listID, sortBy, sortDirection)
 	} else {
-		list, err = h.service.GetByID(c.Request.Context(), userID, listID)
+		// Without sort_by the list comes in its saved sort order, if any.
+		list, err = h.service.GetSorted(c.Request.Context(), userID, listID, sortBy, sortDirection, acceptedLanguages(c.GetHeader("Accept-Language")))
 	}
 
 	if err != nil {
@@ -228,6 +231,107 @@ func (h *ShoppingListHandler) ToggleItem(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "item toggled successfully"})
 }
 
+func (h *ShoppingListHandler) ClearChecked(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	listID := c.Param("id")
+
+	cleared, err := h.service.ClearChecked(c.Request.Context(), userID, listID)
+	if err != nil {
+		if status := apperrors.StatusCode(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to clear checked items", zap.Error(err), zap.String("listID", listID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to clear checked items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cleared": cleared})
+}
+
+// BatchItems applies operations to the list's items all at once. When one
+// fails, nothing is kept and the per-operation results are written with the
+// failure's status.
+func (h *ShoppingListHandler) BatchItems(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	listID := c.Param("id")
+
+	var req domain.BatchItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.BatchItems(c.Request.Context(), userID, listID, &req)
+	if err != nil {
+		status := apperrors.StatusCode(err)
+		if apperrors.IsInvalidInput(err) {
+			status = http.StatusBadRequest
+		}
+		if status == http.StatusInternalServerError {
+			h.logger.Error("failed to apply item batch", zap.Error(err), zap.String("listID", listID))
+			c.JSON(status, gin.H{"error": "failed to apply item batch"})
+			return
+		}
+		if result == nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(status, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ShoppingListHandler) Export(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	listID := c.Param("id")
+
+	export, err := h.service.Export(c.Request.Context(), userID, listID, c.Query("format"), acceptedLanguages(c.GetHeader("Accept-Language")))
+	if err != nil {
+		if apperrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if status := apperrors.StatusCode(err); status != http.StatusInternalServerError {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to export shopping list", zap.Error(err), zap.String("listID", listID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export shopping list"})
+		return
+	}
+
+	writeExport(c, export)
+}
+
+// writeExport sends a rendered list: HTML is shown in the browser to print,
+// text and Markdown are downloaded. Item names are user input, so the HTML
+// may not load or run anything.
+func writeExport(c *gin.Context, export *domain.ShoppingListExport) {
+	disposition := "attachment"
+	if strings.HasPrefix(export.ContentType, "text/html") {
+		disposition = "inline"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, export.Filename))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	c.Data(http.StatusOK, export.ContentType, export.Body)
+}
+
 func (h *ShoppingListHandler) AddRecipe(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	if userID == "" {
@@ -258,16 +362,16 @@ func (h *ShoppingListHandler) SortByStore(c *gin.Context) {
 		return
 	}
 	listID := c.Param("id")
+	storeID := c.Query("store_id")
 	chainID := c.Query("chain_id")
 
-	if chainID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "chain_id query parameter is required"})
-		return
-	}
-
-	list, err := h.service.GetSortedForStore(c.Request.Context(), userID, listID, chainID)
+	list, err := h.service.GetSortedForStore(c.Request.Context(), userID, listID, storeID, chainID)
 	if err != nil {
-		h.logger.Error("failed to get sorted shopping list", zap.Error(err), zap.String("listID", listID), zap.String("chainID", chainID))
+		if apperrors.IsInvalidInput(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to get sorted shopping list", zap.Error(err), zap.String("listID", listID), zap.String("storeID", storeID), zap.String("chainID", chainID))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get sorted list"})
 		return
 	}
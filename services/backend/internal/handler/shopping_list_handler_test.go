@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -41,8 +42,8 @@ func (m *mockShoppingListService) GetByID(ctx context.Context, userID string, li
 	return v, args.Error(1)
 }
 
-func (m *mockShoppingListService) GetSorted(ctx context.Context, userID string, listID string, sortBy string, sortDirection string) (*domain.ShoppingList, error) {
-	args := m.Called(ctx, userID, listID, sortBy, sortDirection)
+func (m *mockShoppingListService) GetSorted(ctx context.Context, userID string, listID string, sortBy string, sortDirection string, languages []string) (*domain.ShoppingList, error) {
+	args := m.Called(ctx, userID, listID, sortBy, sortDirection, languages)
 	v, _ := args.Get(0).(*domain.ShoppingList)
 	return v, args.Error(1)
 }
@@ -79,13 +80,37 @@ func (m *mockShoppingListService) ToggleItem(ctx context.Context, userID string,
 	return args.Error(0)
 }
 
+func (m *mockShoppingListService) ClearChecked(ctx context.Context, userID string, listID string) (int64, error) {
+	args := m.Called(ctx, userID, listID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockShoppingListService) BatchItems(ctx context.Context, userID string, listID string, req *domain.BatchItemsRequest) (*domain.BatchItemsResult, error) {
+	args := m.Called(ctx, userID, listID, req)
+	v, _ := args.Get(0).(*domain.BatchItemsResult)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListService) GetSections(ctx context.Context, userID string, listID string, languages []string) (*domain.ShoppingList, []domain.ListSection, error) {
+	args := m.Called(ctx, userID, listID, languages)
+	v, _ := args.Get(0).(*domain.ShoppingList)
+	sections, _ := args.Get(1).([]domain.ListSection)
+	return v, sections, args.Error(2)
+}
+
+func (m *mockShoppingListService) Export(ctx context.Context, userID string, listID string, format string, languages []string) (*domain.ShoppingListExport, error) {
+	args := m.Called(ctx, userID, listID, format, languages)
+	v, _ := args.Get(0).(*domain.ShoppingListExport)
+	return v, args.Error(1)
+}
+
 func (m *mockShoppingListService) AddRecipeToList(ctx context.Context, userID string, listID string, req *domain.AddRecipeToListRequest) error {
 	args := m.Called(ctx, userID, listID, req)
 	return args.Error(0)
 }
 
-func (m *mockShoppingListService) GetSortedForStore(ctx context.Context, userID string, listID string, chainID string) (*domain.ShoppingList, error) {
-	args := m.Called(ctx, userID, listID, chainID)
+func (m *mockShoppingListService) GetSortedForStore(ctx context.Context, userID string, listID string, storeID string, chainID string) (*domain.ShoppingList, error) {
+	args := m.Called(ctx, userID, listID, storeID, chainID)
 	v, _ := args.Get(0).(*domain.ShoppingList)
 	return v, args.Error(1)
 }
@@ -211,17 +236,17 @@ func TestShoppingListHandler_Get(t *testing.T) {
 			expectedBodyContains: string(jsonShoppingList),
 			setUserID:            true,
 			mockMethod: func(m *mockShoppingListService) {
-				m.On("GetSorted", mock.Anything, userID, shoppingList.ID, "name", "asc").Return(&shoppingList, nil).Once()
+				m.On("GetSorted", mock.Anything, userID, shoppingList.ID, "name", "asc", []string(nil)).Return(&shoppingList, nil).Once()
 			},
 		},
 		{
-			name:                 "returns status 200 with shopping list sorted by default when no sort_by is attached and request is successfully",
+			name:                 "returns status 200 with shopping list in its saved sort order when no sort_by is attached and request is successfully",
 			url:                  fmt.Sprintf("/api/v1/shopping-lists/%v", shoppingList.ID),
 			expectedStatusCode:   http.StatusOK,
 			expectedBodyContains: string(jsonShoppingList),
 			setUserID:            true,
 			mockMethod: func(m *mockShoppingListService) {
-				m.On("GetByID", mock.Anything, userID, shoppingList.ID).Return(&shoppingList, nil).Once()
+				m.On("GetSorted", mock.Anything, userID, shoppingList.ID, "", "asc", []string(nil)).Return(&shoppingList, nil).Once()
 			},
 		},
 		{
@@ -239,7 +264,7 @@ func TestShoppingListHandler_Get(t *testing.T) {
 			expectedBodyContains: "shopping list not found",
 			setUserID:            true,
 			mockMethod: func(m *mockShoppingListService) {
-				m.On("GetByID", mock.Anything, userID, shoppingList.ID).Return(nil, errors.New("service error")).Once()
+				m.On("GetSorted", mock.Anything, userID, shoppingList.ID, "", "asc", []string(nil)).Return(nil, errors.New("service error")).Once()
 			},
 		},
 		{
@@ -891,16 +916,29 @@ func TestShoppingListHandler_SortByStore(t *testing.T) {
 			expectedStatusCode:   http.StatusOK,
 			expectedBodyContains: string(jsonSortedList),
 			mockMethod: func(m *mockShoppingListService) {
-				m.On("GetSortedForStore", mock.Anything, userID, listID, chainID).Return(&sortedList, nil).Once()
+				m.On("GetSortedForStore", mock.Anything, userID, listID, "", chainID).Return(&sortedList, nil).Once()
+			},
+		},
+		{
+			name:                 "returns 200 sorted for a user store when store_id is provided",
+			url:                  fmt.Sprintf("/api/v1/shopping-lists/%v/sort-by-store?store_id=store-1", listID),
+			setUserID:            true,
+			expectedStatusCode:   http.StatusOK,
+			expectedBodyContains: string(jsonSortedList),
+			mockMethod: func(m *mockShoppingListService) {
+				m.On("GetSortedForStore", mock.Anything, userID, listID, "store-1", "").Return(&sortedList, nil).Once()
 			},
 		},
 		{
-			name:                 "returns 400 when chain_id query param is missing",
+			name:                 "returns 400 when neither the request nor the list names a store",
 			url:                  fmt.Sprintf("/api/v1/shopping-lists/%v/sort-by-store", listID),
 			setUserID:            true,
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedBodyContains: "chain_id query parameter is required",
-			mockMethod:           func(m *mockShoppingListService) {},
+			expectedBodyContains: "store_id or chain_id is required",
+			mockMethod: func(m *mockShoppingListService) {
+				m.On("GetSortedForStore", mock.Anything, userID, listID, "", "").
+					Return(nil, apperrors.New("store_id or chain_id is required for a list without a store", "INVALID_INPUT")).Once()
+			},
 		},
 		{
 			name:                 "returns 401 unauthorized when user is not authenticated",
@@ -917,7 +955,7 @@ func TestShoppingListHandler_SortByStore(t *testing.T) {
 			expectedStatusCode:   http.StatusInternalServerError,
 			expectedBodyContains: "failed to get sorted list",
 			mockMethod: func(m *mockShoppingListService) {
-				m.On("GetSortedForStore", mock.Anything, userID, listID, chainID).Return(nil, errors.New("service error")).Once()
+				m.On("GetSortedForStore", mock.Anything, userID, listID, "", chainID).Return(nil, errors.New("service error")).Once()
 			},
 		},
 	}
@@ -947,3 +985,78 @@ func TestShoppingListHandler_SortByStore(t *testing.T) {
 		})
 	}
 }
+
+func TestShoppingListHandler_BatchItems(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	listID := "list-uuid-1234"
+	checkAll := &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{{Op: domain.BatchOpCheck}}}
+
+	tests := []struct {
+		name                 string
+		body                 []byte
+		expectedStatusCode   int
+		expectedBodyContains string
+		mockMethod           func(m *mockShoppingListService)
+	}{
+		{
+			name:                 "returns 200 with the results when the batch is applied",
+			body:                 mustJson(t, checkAll),
+			expectedStatusCode:   http.StatusOK,
+			expectedBodyContains: `"applied":true`,
+			mockMethod: func(m *mockShoppingListService) {
+				m.On("BatchItems", mock.Anything, userID, listID, checkAll).Return(&domain.BatchItemsResult{
+					Applied: true,
+					Results: []domain.BatchItemResult{{Op: domain.BatchOpCheck, Status: domain.BatchStatusApplied}},
+				}, nil).Once()
+			},
+		},
+		{
+			name:                 "returns 400 for unknown operations",
+			body:                 []byte(`{"operations":[{"op":"shuffle"}]}`),
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "error",
+			mockMethod:           func(m *mockShoppingListService) {},
+		},
+		{
+			name:                 "returns the failed operation's status with the results",
+			body:                 mustJson(t, checkAll),
+			expectedStatusCode:   http.StatusNotFound,
+			expectedBodyContains: `"status":"failed"`,
+			mockMethod: func(m *mockShoppingListService) {
+				m.On("BatchItems", mock.Anything, userID, listID, checkAll).Return(&domain.BatchItemsResult{
+					Results: []domain.BatchItemResult{{Op: domain.BatchOpCheck, Status: domain.BatchStatusFailed, Error: "item x is not on the list"}},
+				}, apperrors.ErrNotFound.Wrap("item x is not on the list")).Once()
+			},
+		},
+		{
+			name:                 "returns 500 without details when the service fails",
+			body:                 mustJson(t, checkAll),
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedBodyContains: "failed to apply item batch",
+			mockMethod: func(m *mockShoppingListService) {
+				m.On("BatchItems", mock.Anything, userID, listID, checkAll).Return(nil, errors.New("db down")).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockShoppingListService)
+			tt.mockMethod(m)
+
+			handler := NewShoppingListHandler(m, zap.NewNop())
+			router := gin.New()
+			router.POST("/api/v1/shopping-lists/:id/items/batch", func(ctx *gin.Context) {
+				ctx.Set("user_id", userID)
+				handler.BatchItems(ctx)
+			})
+
+			w := performRequest(router, http.MethodPost, fmt.Sprintf("/api/v1/shopping-lists/%v/items/batch", listID), tt.body)
+
+			require.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBodyContains)
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ShoppingListShareHandler struct {
+	service service.ShoppingListShareService
+	logger  *zap.Logger
+}
+
+func NewShoppingListShareHandler(service service.ShoppingListShareService, logger *zap.Logger) *ShoppingListShareHandler {
+	return &ShoppingListShareHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (not found, someone else's list, a
+// view-only link, rejected input) with their status and logs anything else
+// behind a generic message.
+func (h *ShoppingListShareHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if apperrors.IsInvalidInput(err) {
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *ShoppingListShareHandler) Create(c *gin.Context) {
+	var req domain.CreateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	share, err := h.service.Create(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to share shopping list")
+		return
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+func (h *ShoppingListShareHandler) List(c *gin.Context) {
+	shares, err := h.service.List(c.Request.Context(), middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to list shares")
+		return
+	}
+
+	c.JSON(http.StatusOK, shares)
+}
+
+func (h *ShoppingListShareHandler) Revoke(c *gin.Context) {
+	if err := h.service.Revoke(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), c.Param("shareId")); err != nil {
+		h.respondError(c, err, "failed to revoke share")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ShoppingListShareHandler) GetShared(c *gin.Context) {
+	list, err := h.service.GetShared(c.Request.Context(), c.Param("token"), acceptedLanguages(c.GetHeader("Accept-Language")))
+	if err != nil {
+		h.respondError(c, err, "failed to get shared shopping list")
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *ShoppingListShareHandler) ToggleSharedItem(c *gin.Context) {
+	var req struct {
+		Checked bool `json:"checked"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ToggleSharedItem(c.Request.Context(), c.Param("token"), c.Param("itemId"), req.Checked); err != nil {
+		h.respondError(c, err, "failed to toggle item")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "item toggled successfully"})
+}
+
+func (h *ShoppingListShareHandler) ExportShared(c *gin.Context) {
+	export, err := h.service.ExportShared(c.Request.Context(), c.Param("token"), c.Query("format"), acceptedLanguages(c.GetHeader("Accept-Language")))
+	if err != nil {
+		h.respondError(c, err, "failed to export shared shopping list")
+		return
+	}
+
+	writeExport(c, export)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ShoppingListTemplateHandler struct {
+	service service.ShoppingListTemplateService
+	logger  *zap.Logger
+}
+
+func NewShoppingListTemplateHandler(service service.ShoppingListTemplateService, logger *zap.Logger) *ShoppingListTemplateHandler {
+	return &ShoppingListTemplateHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (not found, someone else's template or
+// list, rejected input) with their status and logs anything else behind a
+// generic message.
+func (h *ShoppingListTemplateHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if apperrors.IsInvalidInput(err) {
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *ShoppingListTemplateHandler) List(c *gin.Context) {
+	templates, err := h.service.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		h.respondError(c, err, "failed to list templates")
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *ShoppingListTemplateHandler) Get(c *gin.Context) {
+	template, err := h.service.Get(c.Request.Context(), middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to get template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *ShoppingListTemplateHandler) Create(c *gin.Context) {
+	var req domain.ShoppingListTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.service.Create(c.Request.Context(), middleware.GetUserID(c), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to create template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *ShoppingListTemplateHandler) Update(c *gin.Context) {
+	var req domain.ShoppingListTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.service.Update(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to update template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *ShoppingListTemplateHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to delete template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "template deleted"})
+}
+
+func (h *ShoppingListTemplateHandler) Apply(c *gin.Context) {
+	var req domain.ApplyTemplateRequest
+	// The body is optional: without one the template's target list is used.
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	list, err := h.service.Apply(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), req.ListID)
+	if err != nil {
+		h.respondError(c, err, "failed to apply template")
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ShoppingTripHandler struct {
+	service service.ShoppingTripService
+	logger  *zap.Logger
+}
+
+func NewShoppingTripHandler(service service.ShoppingTripService, logger *zap.Logger) *ShoppingTripHandler {
+	return &ShoppingTripHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (not found, someone else's trip, a
+// finished trip, rejected input) with their status and logs anything else
+// behind a generic message.
+func (h *ShoppingTripHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if apperrors.IsInvalidInput(err) {
+		status = http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *ShoppingTripHandler) List(c *gin.Context) {
+	trips, err := h.service.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		h.respondError(c, err, "failed to list trips")
+		return
+	}
+
+	c.JSON(http.StatusOK, trips)
+}
+
+func (h *ShoppingTripHandler) Get(c *gin.Context) {
+	trip, err := h.service.Get(c.Request.Context(), middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to get trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+func (h *ShoppingTripHandler) Start(c *gin.Context) {
+	var req domain.StartTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trip, err := h.service.Start(c.Request.Context(), middleware.GetUserID(c), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to start trip")
+		return
+	}
+
+	c.JSON(http.StatusCreated, trip)
+}
+
+func (h *ShoppingTripHandler) RecordItem(c *gin.Context) {
+	var req domain.RecordTripItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.RecordItem(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to record trip item")
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *ShoppingTripHandler) RemoveItem(c *gin.Context) {
+	if err := h.service.RemoveItem(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), c.Param("itemId")); err != nil {
+		h.respondError(c, err, "failed to remove trip item")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "trip item removed"})
+}
+
+func (h *ShoppingTripHandler) Finish(c *gin.Context) {
+	trip, err := h.service.Finish(c.Request.Context(), middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to finish trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+func (h *ShoppingTripHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to delete trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "trip deleted"})
+}
+
+func (h *ShoppingTripHandler) Spending(c *gin.Context) {
+	var filter domain.SpendingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	buckets, err := h.service.Spending(c.Request.Context(), middleware.GetUserID(c), filter)
+	if err != nil {
+		h.respondError(c, err, "failed to get spending")
+		return
+	}
+
+	c.JSON(http.StatusOK, buckets)
+}
+
+func (h *ShoppingTripHandler) PriceHistory(c *gin.Context) {
+	prices, err := h.service.PriceHistory(c.Request.Context(), middleware.GetUserID(c), c.Query("name"))
+	if err != nil {
+		h.respondError(c, err, "failed to get price history")
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
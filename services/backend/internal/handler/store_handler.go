@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type StoreHandler struct {
+	service service.StoreService
+	logger  *zap.Logger
+}
+
+func NewStoreHandler(service service.StoreService, logger *zap.Logger) *StoreHandler {
+	return &StoreHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (not found, someone else's store) with
+// their status and logs anything else behind a generic message.
+func (h *StoreHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *StoreHandler) List(c *gin.Context) {
+	stores, err := h.service.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		h.respondError(c, err, "failed to list stores")
+		return
+	}
+
+	c.JSON(http.StatusOK, stores)
+}
+
+func (h *StoreHandler) Get(c *gin.Context) {
+	store, err := h.service.Get(c.Request.Context(), middleware.GetUserID(c), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err, "failed to get store")
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+func (h *StoreHandler) Create(c *gin.Context) {
+	var req domain.CreateStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	store, err := h.service.Create(c.Request.Context(), middleware.GetUserID(c), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to create store")
+		return
+	}
+
+	c.JSON(http.StatusCreated, store)
+}
+
+func (h *StoreHandler) Update(c *gin.Context) {
+	var req domain.UpdateStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	store, err := h.service.Update(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		h.respondError(c, err, "failed to update store")
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+func (h *StoreHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to delete store")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "store deleted"})
+}
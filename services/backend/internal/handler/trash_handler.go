@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/middleware"
+	"github.com/H3nSte1n/recipe/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type TrashHandler struct {
+	service service.TrashService
+	logger  *zap.Logger
+}
+
+func NewTrashHandler(service service.TrashService, logger *zap.Logger) *TrashHandler {
+	return &TrashHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondError writes known errors (not found, a list still in the trash)
+// with their status and logs anything else behind a generic message.
+func (h *TrashHandler) respondError(c *gin.Context, err error, fallback string) {
+	status := apperrors.StatusCode(err)
+	if status == http.StatusInternalServerError {
+		h.logger.Error(fallback, zap.Error(err))
+		c.JSON(status, gin.H{"error": fallback})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func (h *TrashHandler) List(c *gin.Context) {
+	entries, err := h.service.List(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		h.respondError(c, err, "failed to list trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *TrashHandler) RestoreRecipe(c *gin.Context) {
+	if err := h.service.RestoreRecipe(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to restore recipe")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "recipe restored"})
+}
+
+func (h *TrashHandler) RestoreShoppingList(c *gin.Context) {
+	if err := h.service.RestoreShoppingList(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		h.respondError(c, err, "failed to restore shopping list")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "shopping list restored"})
+}
+
+func (h *TrashHandler) RestoreShoppingListItem(c *gin.Context) {
+	if err := h.service.RestoreShoppingListItem(c.Request.Context(), middleware.GetUserID(c), c.Param("itemId")); err != nil {
+		h.respondError(c, err, "failed to restore shopping list item")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "item restored"})
+}
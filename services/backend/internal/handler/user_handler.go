@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/middleware"
 	"github.com/H3nSte1n/recipe/internal/service"
 	"github.com/gin-gonic/gin"
@@ -42,7 +43,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.userService.Login(c.Request.Context(), &req)
+	response, err := h.userService.Login(c.Request.Context(), &req, sessionClient(c))
 	if err != nil {
 		// Deliberately a single status/message for every failure mode (wrong password,
 		// nonexistent email, locked account): a distinct response for "locked" would let a
@@ -56,6 +57,220 @@ func (h *UserHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *UserHandler) CompleteTwoFactorLogin(c *gin.Context) {
+	var req domain.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.userService.CompleteTwoFactorLogin(c.Request.Context(), &req, sessionClient(c))
+	if err != nil {
+		// Same reasoning as Login: a wrong code, an expired challenge and a
+		// locked account are indistinguishable to the caller.
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) EnrollTOTP(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	enrollment, err := h.userService.EnrollTOTP(c.Request.Context(), userID)
+	if err != nil {
+		switch {
+		case apperrors.IsConflict(err):
+			c.JSON(http.StatusConflict, gin.H{"error": "two-factor authentication is already enabled"})
+		case apperrors.IsInvalidInput(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start two-factor enrollment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *UserHandler) ConfirmTOTP(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	var req domain.ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.userService.ConfirmTOTP(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		switch {
+		case apperrors.IsInvalid2FACode(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid two-factor code"})
+		case apperrors.IsNotFound(err):
+			c.JSON(http.StatusNotFound, gin.H{"error": "no two-factor enrollment in progress"})
+		case apperrors.IsConflict(err):
+			c.JSON(http.StatusConflict, gin.H{"error": "two-factor authentication is already enabled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to confirm two-factor authentication"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+func (h *UserHandler) DisableTOTP(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	var req domain.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.DisableTOTP(c.Request.Context(), userID, &req, sessionClient(c)); err != nil {
+		switch {
+		case apperrors.IsInvalid2FACode(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid password or two-factor code"})
+		case apperrors.IsNotFound(err):
+			c.JSON(http.StatusNotFound, gin.H{"error": "two-factor authentication is not enabled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to disable two-factor authentication"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+func (h *UserHandler) ListOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, h.userService.ListOIDCProviders())
+}
+
+func (h *UserHandler) StartOIDCLogin(c *gin.Context) {
+	response, err := h.userService.StartOIDCLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown identity provider"})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "identity provider is unavailable"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) CompleteOIDCLogin(c *gin.Context) {
+	var req domain.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.userService.CompleteOIDCLogin(c.Request.Context(), c.Param("provider"), &req, sessionClient(c))
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown identity provider"})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "external sign-in failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) RequestMagicLink(c *gin.Context) {
+	var req domain.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userService.RequestMagicLink(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send sign-in link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "if the email exists, a sign-in link will be sent",
+	})
+}
+
+func (h *UserHandler) MagicLinkLogin(c *gin.Context) {
+	var req domain.MagicLinkLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.userService.MagicLinkLogin(c.Request.Context(), &req, sessionClient(c))
+	if err != nil {
+		// An unknown, expired or used link and a locked account look the same
+		// to the caller.
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sessionClient describes the calling device for the session list.
+func sessionClient(c *gin.Context) domain.SessionClient {
+	return domain.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+func (h *UserHandler) Refresh(c *gin.Context) {
+	var req domain.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.userService.Refresh(c.Request.Context(), req.RefreshToken, sessionClient(c))
+	if err != nil {
+		// One response for every failure, like Login: an unknown, expired,
+		// revoked or reused token all look the same to the caller.
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	sessions, err := h.userService.ListSessions(c.Request.Context(), userID, middleware.GetSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	if err := h.userService.RevokeSession(c.Request.Context(), userID, c.Param("id")); err != nil {
+		if apperrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+}
+
 func (h *UserHandler) ForgotPassword(c *gin.Context) {
 	var req domain.ForgotPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -153,6 +368,11 @@ func (h *UserHandler) IsEmailVerified(ctx context.Context, userID string) (bool,
 	return h.userService.IsEmailVerified(ctx, userID)
 }
 
+// GetRole backs middleware.RequireRole the same way.
+func (h *UserHandler) GetRole(ctx context.Context, userID string) (string, error) {
+	return h.userService.GetRole(ctx, userID)
+}
+
 func (h *UserHandler) ListAll(c *gin.Context) {
 	users, err := h.userService.ListAll(c.Request.Context())
 	if err != nil {
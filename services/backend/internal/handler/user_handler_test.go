@@ -27,18 +27,87 @@ func (m *mockUserService) Register(ctx context.Context, req *domain.RegisterRequ
 	return user, args.Error(1)
 }
 
-func (m *mockUserService) Login(ctx context.Context, req *domain.LoginRequest) (*domain.LoginResponse, error) {
+func (m *mockUserService) Login(ctx context.Context, req *domain.LoginRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
 	args := m.Called(ctx, req)
 	loginResponse, _ := args.Get(0).(*domain.LoginResponse)
 	return loginResponse, args.Error(1)
 }
 
+func (m *mockUserService) Refresh(ctx context.Context, refreshToken string, client domain.SessionClient) (*domain.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	v, _ := args.Get(0).(*domain.TokenPair)
+	return v, args.Error(1)
+}
+
+func (m *mockUserService) ListSessions(ctx context.Context, userID string, currentSessionID string) ([]domain.Session, error) {
+	args := m.Called(ctx, userID, currentSessionID)
+	v, _ := args.Get(0).([]domain.Session)
+	return v, args.Error(1)
+}
+
+func (m *mockUserService) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *mockUserService) CompleteTwoFactorLogin(ctx context.Context, req *domain.TwoFactorLoginRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
+	args := m.Called(ctx, req)
+	v, _ := args.Get(0).(*domain.LoginResponse)
+	return v, args.Error(1)
+}
+
+func (m *mockUserService) EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPEnrollment, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).(*domain.TOTPEnrollment)
+	return v, args.Error(1)
+}
+
+func (m *mockUserService) ConfirmTOTP(ctx context.Context, userID string, code string) (*domain.RecoveryCodesResponse, error) {
+	args := m.Called(ctx, userID, code)
+	v, _ := args.Get(0).(*domain.RecoveryCodesResponse)
+	return v, args.Error(1)
+}
+
+func (m *mockUserService) DisableTOTP(ctx context.Context, userID string, req *domain.DisableTOTPRequest, client domain.SessionClient) error {
+	args := m.Called(ctx, userID, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) ListOIDCProviders() []domain.OIDCProvider {
+	args := m.Called()
+	v, _ := args.Get(0).([]domain.OIDCProvider)
+	return v
+}
+
+func (m *mockUserService) StartOIDCLogin(ctx context.Context, provider string) (*domain.OIDCStartResponse, error) {
+	args := m.Called(ctx, provider)
+	v, _ := args.Get(0).(*domain.OIDCStartResponse)
+	return v, args.Error(1)
+}
+
+func (m *mockUserService) CompleteOIDCLogin(ctx context.Context, provider string, req *domain.OIDCCallbackRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
+	args := m.Called(ctx, provider, req)
+	v, _ := args.Get(0).(*domain.LoginResponse)
+	return v, args.Error(1)
+}
+
 func (m *mockUserService) ValidateToken(token string) (*jwt.Token, error) {
 	args := m.Called(token)
 	t, _ := args.Get(0).(*jwt.Token)
 	return t, args.Error(1)
 }
 
+func (m *mockUserService) RequestMagicLink(ctx context.Context, req *domain.MagicLinkRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *mockUserService) MagicLinkLogin(ctx context.Context, req *domain.MagicLinkLoginRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
+	args := m.Called(ctx, req)
+	v, _ := args.Get(0).(*domain.LoginResponse)
+	return v, args.Error(1)
+}
+
 func (m *mockUserService) ForgotPassword(ctx context.Context, req *domain.ForgotPasswordRequest) error {
 	args := m.Called(ctx, req)
 	return args.Error(0)
@@ -75,6 +144,11 @@ func (m *mockUserService) IsEmailVerified(ctx context.Context, userID string) (b
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *mockUserService) GetRole(ctx context.Context, userID string) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
 func Test_UserHandler_Register(t *testing.T) {
 	registerRequest := domain.RegisterRequest{Email: "foo@bar.com", Password: "foo123asdasd", FirstName: "foo", LastName: "bar"}
 	jsonRequest, _ := json.Marshal(registerRequest)
@@ -172,8 +246,8 @@ func Test_UserHandler_Login(t *testing.T) {
 				m.On("Login", mock.Anything, mock.MatchedBy(func(req *domain.LoginRequest) bool {
 					return req.Email == loginRequest.Email && req.Password == loginRequest.Password
 				})).Return(&domain.LoginResponse{
-					Token: "fooBarToken",
-					User:  domain.User{},
+					TokenPair: domain.TokenPair{Token: "fooBarToken"},
+					User:      &domain.User{},
 				}, nil).Once()
 			},
 		},
@@ -242,6 +316,306 @@ func Test_UserHandler_Login(t *testing.T) {
 	}
 }
 
+func Test_UserHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name                 string
+		expectedStatusCode   int
+		body                 string
+		expectedBodyContains string
+		mockMethod           func(m *mockUserService)
+	}{
+		{
+			name:                 "refresh successfully",
+			expectedStatusCode:   http.StatusOK,
+			body:                 `{"refresh_token":"session-1.secret"}`,
+			expectedBodyContains: "session-1.next",
+			mockMethod: func(m *mockUserService) {
+				m.On("Refresh", mock.Anything, "session-1.secret").
+					Return(&domain.TokenPair{Token: "access", RefreshToken: "session-1.next"}, nil).Once()
+			},
+		},
+		{
+			name:               "missing refresh token",
+			expectedStatusCode: http.StatusBadRequest,
+			body:               `{}`,
+			mockMethod:         func(m *mockUserService) {},
+		},
+		{
+			name:                 "any service error maps to a generic 401",
+			expectedStatusCode:   http.StatusUnauthorized,
+			body:                 `{"refresh_token":"session-1.reused"}`,
+			expectedBodyContains: "invalid refresh token",
+			mockMethod: func(m *mockUserService) {
+				m.On("Refresh", mock.Anything, "session-1.reused").Return(nil, errors.New("db down")).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockUserService)
+			tt.mockMethod(m)
+
+			handler := NewUserHandler(m)
+			router := gin.New()
+			router.POST("/api/v1/auth/refresh", handler.Refresh)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBodyContains != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBodyContains)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_UserHandler_CompleteTwoFactorLogin(t *testing.T) {
+	tests := []struct {
+		name                 string
+		expectedStatusCode   int
+		body                 string
+		expectedBodyContains string
+		mockMethod           func(m *mockUserService)
+	}{
+		{
+			name:                 "valid code signs in",
+			expectedStatusCode:   http.StatusOK,
+			body:                 `{"challenge_token":"challenge","code":"123456"}`,
+			expectedBodyContains: "access",
+			mockMethod: func(m *mockUserService) {
+				m.On("CompleteTwoFactorLogin", mock.Anything, &domain.TwoFactorLoginRequest{ChallengeToken: "challenge", Code: "123456"}).
+					Return(&domain.LoginResponse{TokenPair: domain.TokenPair{Token: "access"}, User: &domain.User{}}, nil).Once()
+			},
+		},
+		{
+			name:               "missing code",
+			expectedStatusCode: http.StatusBadRequest,
+			body:               `{"challenge_token":"challenge"}`,
+			mockMethod:         func(m *mockUserService) {},
+		},
+		{
+			name:                 "wrong code maps to a generic 401",
+			expectedStatusCode:   http.StatusUnauthorized,
+			body:                 `{"challenge_token":"challenge","code":"000000"}`,
+			expectedBodyContains: "invalid credentials",
+			mockMethod: func(m *mockUserService) {
+				m.On("CompleteTwoFactorLogin", mock.Anything, mock.Anything).Return(nil, apperrors.ErrInvalid2FACode).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockUserService)
+			tt.mockMethod(m)
+
+			handler := NewUserHandler(m)
+			router := gin.New()
+			router.POST("/api/v1/auth/login/2fa", handler.CompleteTwoFactorLogin)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login/2fa", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBodyContains != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBodyContains)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_UserHandler_MagicLinkLogin(t *testing.T) {
+	tests := []struct {
+		name                 string
+		expectedStatusCode   int
+		body                 string
+		expectedBodyContains string
+		mockMethod           func(m *mockUserService)
+	}{
+		{
+			name:                 "valid link signs in",
+			expectedStatusCode:   http.StatusOK,
+			body:                 `{"token":"secret"}`,
+			expectedBodyContains: "access",
+			mockMethod: func(m *mockUserService) {
+				m.On("MagicLinkLogin", mock.Anything, &domain.MagicLinkLoginRequest{Token: "secret"}).
+					Return(&domain.LoginResponse{TokenPair: domain.TokenPair{Token: "access"}, User: &domain.User{}}, nil).Once()
+			},
+		},
+		{
+			name:               "missing token",
+			expectedStatusCode: http.StatusBadRequest,
+			body:               `{}`,
+			mockMethod:         func(m *mockUserService) {},
+		},
+		{
+			name:                 "used link maps to a generic 401",
+			expectedStatusCode:   http.StatusUnauthorized,
+			body:                 `{"token":"secret"}`,
+			expectedBodyContains: "invalid or expired link",
+			mockMethod: func(m *mockUserService) {
+				m.On("MagicLinkLogin", mock.Anything, mock.Anything).Return(nil, errors.New("invalid or expired link")).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockUserService)
+			tt.mockMethod(m)
+
+			handler := NewUserHandler(m)
+			router := gin.New()
+			router.POST("/api/v1/auth/magic-link/login", handler.MagicLinkLogin)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/magic-link/login", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			if tt.expectedBodyContains != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBodyContains)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_UserHandler_CompleteOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name               string
+		expectedStatusCode int
+		body               string
+		mockMethod         func(m *mockUserService)
+	}{
+		{
+			name:               "signs in",
+			expectedStatusCode: http.StatusOK,
+			body:               `{"code":"abc","state":"xyz","browser_binding":"b"}`,
+			mockMethod: func(m *mockUserService) {
+				m.On("CompleteOIDCLogin", mock.Anything, "google", &domain.OIDCCallbackRequest{Code: "abc", State: "xyz", BrowserBinding: "b"}).
+					Return(&domain.LoginResponse{TokenPair: domain.TokenPair{Token: "access"}, User: &domain.User{}}, nil).Once()
+			},
+		},
+		{
+			name:               "missing state",
+			expectedStatusCode: http.StatusBadRequest,
+			body:               `{"code":"abc"}`,
+			mockMethod:         func(m *mockUserService) {},
+		},
+		{
+			name:               "missing browser binding",
+			expectedStatusCode: http.StatusBadRequest,
+			body:               `{"code":"abc","state":"xyz"}`,
+			mockMethod:         func(m *mockUserService) {},
+		},
+		{
+			name:               "unknown provider",
+			expectedStatusCode: http.StatusNotFound,
+			body:               `{"code":"abc","state":"xyz","browser_binding":"b"}`,
+			mockMethod: func(m *mockUserService) {
+				m.On("CompleteOIDCLogin", mock.Anything, "google", mock.Anything).
+					Return(nil, apperrors.ErrNotFound.Wrap("unknown identity provider")).Once()
+			},
+		},
+		{
+			name:               "any other failure is a generic 401",
+			expectedStatusCode: http.StatusUnauthorized,
+			body:               `{"code":"abc","state":"xyz","browser_binding":"b"}`,
+			mockMethod: func(m *mockUserService) {
+				m.On("CompleteOIDCLogin", mock.Anything, "google", mock.Anything).Return(nil, errors.New("invalid state")).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockUserService)
+			tt.mockMethod(m)
+
+			handler := NewUserHandler(m)
+			router := gin.New()
+			router.POST("/api/v1/auth/oidc/:provider/callback", handler.CompleteOIDCLogin)
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/google/callback", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_UserHandler_ConfirmTOTP(t *testing.T) {
+	tests := []struct {
+		name               string
+		expectedStatusCode int
+		mockMethod         func(m *mockUserService)
+	}{
+		{
+			name:               "returns recovery codes",
+			expectedStatusCode: http.StatusOK,
+			mockMethod: func(m *mockUserService) {
+				m.On("ConfirmTOTP", mock.Anything, "user-1", "123456").
+					Return(&domain.RecoveryCodesResponse{RecoveryCodes: []string{"1a2b3-c4d5e"}}, nil).Once()
+			},
+		},
+		{
+			name:               "wrong code",
+			expectedStatusCode: http.StatusBadRequest,
+			mockMethod: func(m *mockUserService) {
+				m.On("ConfirmTOTP", mock.Anything, "user-1", "123456").Return(nil, apperrors.ErrInvalid2FACode).Once()
+			},
+		},
+		{
+			name:               "no enrollment started",
+			expectedStatusCode: http.StatusNotFound,
+			mockMethod: func(m *mockUserService) {
+				m.On("ConfirmTOTP", mock.Anything, "user-1", "123456").
+					Return(nil, apperrors.ErrNotFound.Wrap("no two-factor enrollment in progress")).Once()
+			},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockUserService)
+			tt.mockMethod(m)
+
+			handler := NewUserHandler(m)
+			router := gin.New()
+			router.POST("/api/v1/users/me/2fa/confirm", func(c *gin.Context) {
+				c.Set("user_id", "user-1")
+				handler.ConfirmTOTP(c)
+			})
+
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/users/me/2fa/confirm", strings.NewReader(`{"code":"123456"}`))
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			m.AssertExpectations(t)
+		})
+	}
+}
+
 func Test_UserHandler_ForgotPassword(t *testing.T) {
 	forgotPasswordRequest := domain.ForgotPasswordRequest{Email: "foo@bar.com"}
 	jsonRequest, _ := json.Marshal(forgotPasswordRequest)
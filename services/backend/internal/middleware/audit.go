@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditContext attaches the caller's IP address and user agent to the request
+// context, where service.AuditLog picks them up for audit events.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := domain.ContextWithAuditClient(c.Request.Context(), domain.SessionClient{
+			UserAgent: c.Request.UserAgent(),
+			IPAddress: c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(AuditContext())
+
+	var client domain.SessionClient
+	engine.GET("/", func(c *gin.Context) {
+		client = domain.AuditClientFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "198.51.100.4:1234"
+	req.Header.Set("User-Agent", "test-agent")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Equal(t, domain.SessionClient{UserAgent: "test-agent", IPAddress: "198.51.100.4"}, client)
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"errors"
+	"github.com/H3nSte1n/recipe/internal/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"net/http"
@@ -12,11 +13,20 @@ import (
 
 // TokenRevocationChecker looks up the newest revocation timestamp for a
 // user. Any JWT whose "iat" claim predates this timestamp must be rejected,
-// even if the token has not yet expired naturally. Satisfied by
+// even if the token has not yet expired naturally. It also reports whether
+// the session named by a token's "sid" claim is still active, so signing a
+// device out rejects that device's access tokens too. Satisfied by
 // repository.UserRepository; declared locally so this package doesn't need
 // to import the repository package.
 type TokenRevocationChecker interface {
 	GetTokenRevokedAt(ctx context.Context, userID string) (*time.Time, error)
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// APITokenVerifier authenticates personal access tokens, returning the owner
+// and the token's scopes. Satisfied by service.APITokenService.
+type APITokenVerifier interface {
+	VerifyAPIToken(ctx context.Context, token string) (userID string, scopes []string, err error)
 }
 
 type AuthMiddleware struct {
@@ -24,14 +34,16 @@ type AuthMiddleware struct {
 	issuer      string
 	audience    string
 	revocations TokenRevocationChecker
+	apiTokens   APITokenVerifier
 }
 
-func NewAuthMiddleware(secretKey, issuer, audience string, revocations TokenRevocationChecker) *AuthMiddleware {
+func NewAuthMiddleware(secretKey, issuer, audience string, revocations TokenRevocationChecker, apiTokens APITokenVerifier) *AuthMiddleware {
 	return &AuthMiddleware{
 		secretKey:   secretKey,
 		issuer:      issuer,
 		audience:    audience,
 		revocations: revocations,
+		apiTokens:   apiTokens,
 	}
 }
 
@@ -51,6 +63,11 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			return
 		}
 
+		if strings.HasPrefix(parts[1], domain.APITokenPrefix) {
+			m.authenticateAPIToken(c, parts[1])
+			return
+		}
+
 		token, err := m.validateToken(parts[1])
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
@@ -94,14 +111,56 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 			}
 		}
 
+		// Tokens issued before sessions existed carry no "sid"; they are still
+		// bounded by their expiry and the user-wide revocation above.
+		sessionID, _ := claims["sid"].(string)
+		if sessionID != "" && m.revocations != nil {
+			active, err := m.revocations.IsSessionActive(c.Request.Context(), sessionID)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "unable to verify token"})
+				c.Abort()
+				return
+			}
+			if !active {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+				c.Abort()
+				return
+			}
+		}
+
 		email, _ := claims["email"].(string)
 		c.Set("user_id", userID)
 		c.Set("email", email)
+		c.Set("session_id", sessionID)
 
 		c.Next()
 	}
 }
 
+// authenticateAPIToken handles requests carrying a personal access token.
+// The token's scopes are put in the context for RequireScope to enforce.
+func (m *AuthMiddleware) authenticateAPIToken(c *gin.Context, token string) {
+	if m.apiTokens == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		c.Abort()
+		return
+	}
+
+	userID, scopes, err := m.apiTokens.VerifyAPIToken(c.Request.Context(), token)
+	if err != nil || userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", userID)
+	c.Set("email", "")
+	c.Set("session_id", "")
+	c.Set(apiTokenScopesKey, scopes)
+
+	c.Next()
+}
+
 func issuedAtTime(claims jwt.MapClaims) (time.Time, bool) {
 	iat, err := claims.GetIssuedAt()
 	if err != nil || iat == nil {
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,16 +20,36 @@ const (
 )
 
 // stubRevocationChecker returns a fixed revocation timestamp for every user,
-// or none if revokedAt is nil.
+// or none if revokedAt is nil. Sessions listed in revokedSessions are
+// inactive; every other session is active.
 type stubRevocationChecker struct {
-	revokedAt *time.Time
-	err       error
+	revokedAt       *time.Time
+	err             error
+	revokedSessions map[string]bool
 }
 
 func (s *stubRevocationChecker) GetTokenRevokedAt(ctx context.Context, userID string) (*time.Time, error) {
 	return s.revokedAt, s.err
 }
 
+func (s *stubRevocationChecker) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return !s.revokedSessions[sessionID], s.err
+}
+
+// stubAPITokenVerifier accepts the tokens in its map, returning the owner
+// and scopes.
+type stubAPITokenVerifier struct {
+	tokens map[string][]string
+}
+
+func (s *stubAPITokenVerifier) VerifyAPIToken(ctx context.Context, token string) (string, []string, error) {
+	scopes, ok := s.tokens[token]
+	if !ok {
+		return "", nil, errors.New("invalid API token")
+	}
+	return "user-1", scopes, nil
+}
+
 func signToken(t *testing.T, claims jwt.MapClaims) string {
 	t.Helper()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -67,7 +88,7 @@ func performAuthRequest(m *AuthMiddleware, token string) *httptest.ResponseRecor
 }
 
 func TestAuthRequired_RejectsMissingUserIDClaim(t *testing.T) {
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil)
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil, nil)
 	claims := validClaims("", time.Now())
 	token := signToken(t, claims)
 
@@ -77,7 +98,7 @@ func TestAuthRequired_RejectsMissingUserIDClaim(t *testing.T) {
 }
 
 func TestAuthRequired_AcceptsValidToken(t *testing.T) {
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{})
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{}, nil)
 	token := signToken(t, validClaims("user-1", time.Now()))
 
 	w := performAuthRequest(m, token)
@@ -86,7 +107,7 @@ func TestAuthRequired_AcceptsValidToken(t *testing.T) {
 }
 
 func TestAuthRequired_RejectsWrongIssuer(t *testing.T) {
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil)
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil, nil)
 	claims := validClaims("user-1", time.Now())
 	claims["iss"] = "someone-else"
 	token := signToken(t, claims)
@@ -97,7 +118,7 @@ func TestAuthRequired_RejectsWrongIssuer(t *testing.T) {
 }
 
 func TestAuthRequired_RejectsWrongAudience(t *testing.T) {
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil)
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil, nil)
 	claims := validClaims("user-1", time.Now())
 	claims["aud"] = "someone-else-api"
 	token := signToken(t, claims)
@@ -108,7 +129,7 @@ func TestAuthRequired_RejectsWrongAudience(t *testing.T) {
 }
 
 func TestAuthRequired_RejectsNotYetValidToken(t *testing.T) {
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil)
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, nil, nil)
 	claims := validClaims("user-1", time.Now())
 	claims["nbf"] = time.Now().Add(time.Hour).Unix()
 	token := signToken(t, claims)
@@ -120,7 +141,7 @@ func TestAuthRequired_RejectsNotYetValidToken(t *testing.T) {
 
 func TestAuthRequired_RejectsTokenIssuedBeforeRevocation(t *testing.T) {
 	revokedAt := time.Now()
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{revokedAt: &revokedAt})
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{revokedAt: &revokedAt}, nil)
 	// Token issued before the revocation timestamp must be rejected even
 	// though it's otherwise well-formed and unexpired.
 	token := signToken(t, validClaims("user-1", revokedAt.Add(-time.Minute)))
@@ -132,7 +153,7 @@ func TestAuthRequired_RejectsTokenIssuedBeforeRevocation(t *testing.T) {
 
 func TestAuthRequired_AcceptsTokenIssuedAfterRevocation(t *testing.T) {
 	revokedAt := time.Now().Add(-time.Hour)
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{revokedAt: &revokedAt})
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{revokedAt: &revokedAt}, nil)
 	token := signToken(t, validClaims("user-1", time.Now()))
 
 	w := performAuthRequest(m, token)
@@ -141,10 +162,56 @@ func TestAuthRequired_AcceptsTokenIssuedAfterRevocation(t *testing.T) {
 }
 
 func TestAuthRequired_FailsClosedOnRevocationLookupError(t *testing.T) {
-	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{err: context.DeadlineExceeded})
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{err: context.DeadlineExceeded}, nil)
 	token := signToken(t, validClaims("user-1", time.Now()))
 
 	w := performAuthRequest(m, token)
 
 	require.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestAuthRequired_RejectsTokenOfRevokedSession(t *testing.T) {
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{revokedSessions: map[string]bool{"session-1": true}}, nil)
+	claims := validClaims("user-1", time.Now())
+	claims["sid"] = "session-1"
+
+	w := performAuthRequest(m, signToken(t, claims))
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthRequired_AcceptsTokenOfActiveSession(t *testing.T) {
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{revokedSessions: map[string]bool{"session-1": true}}, nil)
+	claims := validClaims("user-1", time.Now())
+	claims["sid"] = "session-2"
+
+	w := performAuthRequest(m, signToken(t, claims))
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthRequired_AcceptsAPIToken(t *testing.T) {
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{}, &stubAPITokenVerifier{
+		tokens: map[string][]string{"rcp_valid": {"recipes:read"}},
+	})
+
+	w := performAuthRequest(m, "rcp_valid")
+
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthRequired_RejectsUnknownAPIToken(t *testing.T) {
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{}, &stubAPITokenVerifier{})
+
+	w := performAuthRequest(m, "rcp_revoked")
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthRequired_RejectsAPITokenWithoutVerifier(t *testing.T) {
+	m := NewAuthMiddleware(testSecret, testIssuer, testAudience, &stubRevocationChecker{}, nil)
+
+	w := performAuthRequest(m, "rcp_valid")
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -17,3 +17,26 @@ func GetUserID(c *gin.Context) string {
 		return ""
 	}
 }
+
+// GetSessionID returns the session the request's access token was issued
+// under, or "" for tokens that predate sessions.
+func GetSessionID(c *gin.Context) string {
+	sessionID, _ := c.Get("session_id")
+	v, _ := sessionID.(string)
+	return v
+}
+
+// apiTokenScopesKey holds the scopes of the personal access token a request
+// was authenticated with. It is absent for login (JWT) requests.
+const apiTokenScopesKey = "api_token_scopes"
+
+// GetAPITokenScopes returns the scopes of the request's personal access token
+// and true, or false when the request was made with a login token.
+func GetAPITokenScopes(c *gin.Context) ([]string, bool) {
+	scopes, exists := c.Get(apiTokenScopesKey)
+	if !exists {
+		return nil, false
+	}
+	v, _ := scopes.([]string)
+	return v, true
+}
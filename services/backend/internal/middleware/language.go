@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/pkg/email"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// EmailLanguage attaches the caller's Accept-Language header to the request
+// context, where the email service picks the language of emails sent while
+// handling the request.
+func EmailLanguage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := email.ContextWithLanguage(c.Request.Context(), c.GetHeader("Accept-Language"))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// CategoryLanguage attaches the languages of the caller's Accept-Language
+// header to the request context, where item names are looked up in the term
+// dictionaries of those languages before the default one.
+func CategoryLanguage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tags, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+		if err == nil && len(tags) > 0 {
+			languages := make([]string, 0, len(tags))
+			for _, tag := range tags {
+				base, _ := tag.Base()
+				languages = append(languages, base.String())
+			}
+			ctx := domain.ContextWithCategoryLanguages(c.Request.Context(), languages)
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
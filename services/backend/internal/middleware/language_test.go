@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(CategoryLanguage())
+
+	var languages []string
+	engine.GET("/", func(c *gin.Context) {
+		languages = domain.CategoryLanguagesFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en;q=0.5, de-AT, fr;q=0.8")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, []string{"de", "fr", "en"}, languages)
+
+	languages = nil
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Nil(t, languages)
+}
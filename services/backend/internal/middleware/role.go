@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleChecker looks up a user's role. Like EmailVerificationChecker it is
+// satisfied by handler.UserHandler.
+type RoleChecker func(ctx context.Context, userID string) (string, error)
+
+// RequireRole only lets users holding one of roles through. The role is read
+// on every request rather than from the token, so a demotion takes effect
+// immediately. It must run after AuthRequired.
+func RequireRole(checker RoleChecker, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := GetUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Abort()
+			return
+		}
+
+		role, err := checker(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check role"})
+			c.Abort()
+			return
+		}
+		if !slices.Contains(roles, role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func performRoleRequest(checker RoleChecker, userID string) int {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set("user_id", userID)
+		}
+	})
+	engine.GET("/admin", RequireRole(checker, "admin"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	return w.Code
+}
+
+func TestRequireRole(t *testing.T) {
+	roles := map[string]string{"user-admin": "admin", "user-member": "member"}
+	checker := func(ctx context.Context, userID string) (string, error) {
+		return roles[userID], nil
+	}
+
+	require.Equal(t, http.StatusOK, performRoleRequest(checker, "user-admin"))
+	require.Equal(t, http.StatusForbidden, performRoleRequest(checker, "user-member"))
+	require.Equal(t, http.StatusUnauthorized, performRoleRequest(checker, ""))
+}
+
+func TestRequireRole_FailsClosedOnLookupError(t *testing.T) {
+	checker := func(ctx context.Context, userID string) (string, error) {
+		return "", errors.New("db down")
+	}
+
+	require.Equal(t, http.StatusInternalServerError, performRoleRequest(checker, "user-admin"))
+}
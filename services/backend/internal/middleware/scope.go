@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope limits requests made with a personal access token to tokens
+// scoped for resource: GET and HEAD need read access, anything else write.
+// Requests made with a login token are not restricted. It must run after
+// AuthRequired.
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, isAPIToken := GetAPITokenScopes(c)
+		if !isAPIToken {
+			c.Next()
+			return
+		}
+
+		access := domain.ScopeAccessWrite
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			access = domain.ScopeAccessRead
+		}
+		if !domain.ScopesAllow(scopes, resource, access) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API token lacks the " + resource + ":" + access + " scope"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SessionOnly rejects personal access tokens outright. It guards account
+// security routes (sessions, tokens, two-factor, account deletion) so a
+// leaked script token cannot be used to entrench itself or lock the owner
+// out.
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIToken := GetAPITokenScopes(c); isAPIToken {
+			c.JSON(http.StatusForbidden, gin.H{"error": "this endpoint requires signing in; API tokens are not accepted"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// performScopedRequest runs method against a route guarded by guard, with
+// the request authenticated by an API token holding scopes, or by a login
+// token when scopes is nil.
+func performScopedRequest(guard gin.HandlerFunc, method string, scopes []string) int {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		if scopes != nil {
+			c.Set(apiTokenScopesKey, scopes)
+		}
+	})
+	engine.Handle(method, "/recipes", guard, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(method, "/recipes", nil))
+	return w.Code
+}
+
+func TestRequireScope(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		scopes []string
+		want   int
+	}{
+		{"login token is not restricted", http.MethodPost, nil, http.StatusOK},
+		{"read scope allows GET", http.MethodGet, []string{"recipes:read"}, http.StatusOK},
+		{"read scope does not allow POST", http.MethodPost, []string{"recipes:read"}, http.StatusForbidden},
+		{"write scope implies read", http.MethodGet, []string{"recipes:write"}, http.StatusOK},
+		{"write scope allows DELETE", http.MethodDelete, []string{"recipes:write"}, http.StatusOK},
+		{"other resource's scope does not count", http.MethodGet, []string{"shopping_lists:write"}, http.StatusForbidden},
+		{"token without scopes", http.MethodGet, []string{}, http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want, performScopedRequest(RequireScope("recipes"), c.method, c.scopes))
+		})
+	}
+}
+
+func TestSessionOnly(t *testing.T) {
+	require.Equal(t, http.StatusOK, performScopedRequest(SessionOnly(), http.MethodGet, nil))
+	require.Equal(t, http.StatusForbidden, performScopedRequest(SessionOnly(), http.MethodGet, []string{"recipes:write"}))
+}
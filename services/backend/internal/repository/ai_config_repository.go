@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/H3nSte1n/recipe/internal/domain"
 	"gorm.io/gorm"
+	"time"
 )
 
 type AIConfigRepository interface {
@@ -15,6 +16,13 @@ type AIConfigRepository interface {
 	ListByUserID(ctx context.Context, userID string) ([]domain.UserAIConfig, error)
 	Delete(ctx context.Context, id string) error
 	GetAIModels(ctx context.Context) ([]domain.AIModel, error)
+	GetAIModelByID(ctx context.Context, id string) (*domain.AIModel, error)
+	// ListAllAIModels and GetAIModelIncludingInactive also return deactivated
+	// models, for administration.
+	ListAllAIModels(ctx context.Context) ([]domain.AIModel, error)
+	GetAIModelIncludingInactive(ctx context.Context, id string) (*domain.AIModel, error)
+	SetAIModelActive(ctx context.Context, id string, active bool) error
+	RecordVerification(ctx context.Context, id string, verifiedAt *time.Time, lastError string) error
 	GetDefaultConfig(ctx context.Context, userID string) (*domain.UserAIConfig, error)
 	SetDefault(ctx context.Context, userID, configID string) error
 	ClearDefaultByUserID(ctx context.Context, userID string, excludeIDs ...string) error
@@ -83,6 +91,52 @@ func (r *AIConfigRepositoryImpl) GetAIModels(ctx context.Context) ([]domain.AIMo
 	return models, err
 }
 
+func (r *AIConfigRepositoryImpl) GetAIModelByID(ctx context.Context, id string) (*domain.AIModel, error) {
+	var model domain.AIModel
+	if err := r.DB.WithContext(ctx).
+		Where("is_active = ?", true).
+		First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func (r *AIConfigRepositoryImpl) ListAllAIModels(ctx context.Context) ([]domain.AIModel, error) {
+	var models []domain.AIModel
+	err := r.DB.WithContext(ctx).
+		Order("provider, name").
+		Find(&models).Error
+	return models, err
+}
+
+func (r *AIConfigRepositoryImpl) GetAIModelIncludingInactive(ctx context.Context, id string) (*domain.AIModel, error) {
+	var model domain.AIModel
+	if err := r.DB.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+func (r *AIConfigRepositoryImpl) SetAIModelActive(ctx context.Context, id string, active bool) error {
+	return r.DB.WithContext(ctx).
+		Model(&domain.AIModel{}).
+		Where("id = ?", id).
+		Update("is_active", active).Error
+}
+
+// RecordVerification stores the outcome of a provider check. A nil verifiedAt
+// keeps the previous successful check time so a failure does not erase it.
+func (r *AIConfigRepositoryImpl) RecordVerification(ctx context.Context, id string, verifiedAt *time.Time, lastError string) error {
+	updates := map[string]interface{}{"last_error": lastError}
+	if verifiedAt != nil {
+		updates["last_verified_at"] = *verifiedAt
+	}
+	return r.DB.WithContext(ctx).
+		Model(&domain.UserAIConfig{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
 func (r *AIConfigRepositoryImpl) GetDefaultConfig(ctx context.Context, userID string) (*domain.UserAIConfig, error) {
 	var config domain.UserAIConfig
 	err := r.DB.WithContext(ctx).
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIConfigRepository_GetAIModelByID_NilOnMiss(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE ai_models (
+		id TEXT PRIMARY KEY, name TEXT NOT NULL, provider TEXT NOT NULL, model_version TEXT NOT NULL,
+		is_active BOOLEAN DEFAULT true, created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO ai_models (id, name, provider, model_version, is_active) VALUES
+		('m1', 'Claude', 'anthropic', 'claude-haiku-4-5', true),
+		('m2', 'Old GPT', 'openai', 'gpt-3.5', false)`).Error)
+
+	repo := NewAIConfigRepository(db)
+	ctx := context.Background()
+
+	model, err := repo.GetAIModelByID(ctx, "m1")
+	require.NoError(t, err)
+	require.Equal(t, "anthropic", model.Provider)
+
+	for _, id := range []string{"m2", "missing"} {
+		model, err := repo.GetAIModelByID(ctx, id)
+		require.True(t, apperrors.IsNotFound(err), id)
+		require.Nil(t, model, id)
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *domain.APIToken) error
+	// ListActive returns the user's unrevoked tokens, expired ones included so
+	// the owner can see what stopped working.
+	ListActive(ctx context.Context, userID string) ([]domain.APIToken, error)
+	CountActive(ctx context.Context, userID string, now time.Time) (int64, error)
+	GetByID(ctx context.Context, userID, id string) (*domain.APIToken, error)
+	// GetByHash finds a token by its hash. Tokens of accounts an administrator
+	// has locked are treated as not found.
+	GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error)
+	// UpdateName and Revoke report false when userID has no such unrevoked
+	// token.
+	UpdateName(ctx context.Context, userID, id, name string) (bool, error)
+	Revoke(ctx context.Context, userID, id string, revokedAt time.Time) (bool, error)
+	// TouchLastUsed sets last_used_at unless it was already set after
+	// notBefore, so a busy script does not write on every request.
+	TouchLastUsed(ctx context.Context, id string, usedAt, notBefore time.Time) error
+}
+
+type APITokenRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &APITokenRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *APITokenRepositoryImpl) Create(ctx context.Context, token *domain.APIToken) error {
+	return r.DB.WithContext(ctx).Create(token).Error
+}
+
+func (r *APITokenRepositoryImpl) ListActive(ctx context.Context, userID string) ([]domain.APIToken, error) {
+	var tokens []domain.APIToken
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *APITokenRepositoryImpl) CountActive(ctx context.Context, userID string, now time.Time) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&domain.APIToken{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *APITokenRepositoryImpl) GetByID(ctx context.Context, userID, id string) (*domain.APIToken, error) {
+	var token domain.APIToken
+	if err := r.DB.WithContext(ctx).First(&token, "id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *APITokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	var token domain.APIToken
+	if err := r.DB.WithContext(ctx).
+		Where("user_id NOT IN (SELECT id FROM users WHERE admin_locked_at IS NOT NULL)").
+		First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *APITokenRepositoryImpl) UpdateName(ctx context.Context, userID, id, name string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("name", name)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *APITokenRepositoryImpl) Revoke(ctx context.Context, userID, id string, revokedAt time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.APIToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *APITokenRepositoryImpl) TouchLastUsed(ctx context.Context, id string, usedAt, notBefore time.Time) error {
+	return r.DB.WithContext(ctx).Model(&domain.APIToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, notBefore).
+		Update("last_used_at", usedAt).Error
+}
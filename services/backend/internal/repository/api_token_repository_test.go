@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPITokenRepository_GetByHash_SkipsAdminLockedAccounts proves a locked
+// account's tokens stop authenticating without being revoked, so unlocking
+// the account brings them back.
+func TestAPITokenRepository_GetByHash_SkipsAdminLockedAccounts(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}))
+	require.NoError(t, db.Exec(`CREATE TABLE api_tokens (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, name TEXT NOT NULL, token_prefix TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE, scopes TEXT, expires_at DATETIME NOT NULL,
+		last_used_at DATETIME, revoked_at DATETIME, created_at DATETIME)`).Error)
+
+	users := NewUserRepository(db)
+	tokens := NewAPITokenRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, users.Create(ctx, &domain.User{ID: "u1", Email: "a@b.com", PasswordHash: "x"}))
+	require.NoError(t, tokens.Create(ctx, &domain.APIToken{
+		ID: "t1", UserID: "u1", Name: "script", TokenPrefix: "rcp_abc", TokenHash: "hash-1",
+		Scopes: []string{"recipes:read"}, ExpiresAt: time.Now().Add(time.Hour),
+	}))
+
+	token, err := tokens.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Equal(t, "u1", token.UserID)
+
+	lockedAt := time.Now()
+	require.NoError(t, users.SetAdminLock(ctx, "u1", &lockedAt))
+	_, err = tokens.GetByHash(ctx, "hash-1")
+	require.True(t, apperrors.IsNotFound(err), "a locked account's token must not authenticate")
+
+	require.NoError(t, users.SetAdminLock(ctx, "u1", nil))
+	_, err = tokens.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+}
+
+// TestUserRepository_RevokeUserAPITokens proves a password reset can revoke
+// every token of the account without touching anyone else's.
+func TestUserRepository_RevokeUserAPITokens(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.User{}))
+	require.NoError(t, db.Exec(`CREATE TABLE api_tokens (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, name TEXT NOT NULL, token_prefix TEXT NOT NULL,
+		token_hash TEXT NOT NULL UNIQUE, scopes TEXT, expires_at DATETIME NOT NULL,
+		last_used_at DATETIME, revoked_at DATETIME, created_at DATETIME)`).Error)
+
+	users := NewUserRepository(db)
+	tokens := NewAPITokenRepository(db)
+	ctx := context.Background()
+
+	for _, token := range []domain.APIToken{
+		{ID: "t1", UserID: "u1", TokenHash: "hash-1"},
+		{ID: "t2", UserID: "u1", TokenHash: "hash-2"},
+		{ID: "t3", UserID: "u2", TokenHash: "hash-3"},
+	} {
+		token.Name, token.TokenPrefix, token.ExpiresAt = "script", "rcp_abc", time.Now().Add(time.Hour)
+		require.NoError(t, tokens.Create(ctx, &token))
+	}
+
+	require.NoError(t, users.RevokeUserAPITokens(ctx, "u1", time.Now()))
+
+	active, err := tokens.ListActive(ctx, "u1")
+	require.NoError(t, err)
+	require.Empty(t, active)
+	token, err := tokens.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, token.RevokedAt)
+
+	active, err = tokens.ListActive(ctx, "u2")
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+}
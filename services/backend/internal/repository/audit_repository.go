@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+)
+
+// defaultAuditEventLimit is the page size when a listing does not ask for one.
+const defaultAuditEventLimit = 50
+
+type AuditRepository interface {
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	// List returns matching events, newest first.
+	List(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error)
+	// ListForUser returns events the user performed or that targeted their
+	// account, newest first.
+	ListForUser(ctx context.Context, userID string, filter domain.ActivityFilter) ([]domain.AuditEvent, error)
+	// DeleteBefore removes events older than cutoff and reports how many.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type AuditRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &AuditRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *AuditRepositoryImpl) Create(ctx context.Context, event *domain.AuditEvent) error {
+	return r.DB.WithContext(ctx).Create(event).Error
+}
+
+func (r *AuditRepositoryImpl) List(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error) {
+	query := r.DB.WithContext(ctx)
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Before != nil {
+		query = query.Where("created_at < ?", *filter.Before)
+	}
+
+	var events []domain.AuditEvent
+	err := query.Order("created_at DESC").Limit(auditEventLimit(filter.Limit)).Find(&events).Error
+	return events, err
+}
+
+func (r *AuditRepositoryImpl) ListForUser(ctx context.Context, userID string, filter domain.ActivityFilter) ([]domain.AuditEvent, error) {
+	query := r.DB.WithContext(ctx).
+		Where("actor_id = ? OR (target_type = ? AND target_id = ?)", userID, domain.AuditTargetUser, userID)
+	if filter.Before != nil {
+		query = query.Where("created_at < ?", *filter.Before)
+	}
+
+	var events []domain.AuditEvent
+	err := query.Order("created_at DESC").Limit(auditEventLimit(filter.Limit)).Find(&events).Error
+	return events, err
+}
+
+func (r *AuditRepositoryImpl) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&domain.AuditEvent{})
+	return result.RowsAffected, result.Error
+}
+
+func auditEventLimit(limit int) int {
+	if limit <= 0 {
+		return defaultAuditEventLimit
+	}
+	return limit
+}
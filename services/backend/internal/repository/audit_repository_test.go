@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuditRepository(t *testing.T) AuditRepository {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE audit_events (
+		id TEXT PRIMARY KEY, actor_id TEXT, action TEXT NOT NULL, target_type TEXT NOT NULL,
+		target_id TEXT NOT NULL DEFAULT '', ip_address TEXT NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '', changes TEXT, created_at DATETIME)`).Error)
+	return NewAuditRepository(db)
+}
+
+func TestAuditRepository_List_FiltersNewestFirst(t *testing.T) {
+	repo := newTestAuditRepository(t)
+	ctx := context.Background()
+	admin := "admin-1"
+	base := time.Now().Add(-time.Hour)
+
+	for i, e := range []domain.AuditEvent{
+		{ID: "e1", ActorID: &admin, Action: domain.AuditAdminUserLocked, TargetType: domain.AuditTargetUser, TargetID: "u1"},
+		{ID: "e2", ActorID: &admin, Action: domain.AuditAdminAIModelUpdated, TargetType: domain.AuditTargetAIModel, TargetID: "m1"},
+		{ID: "e3", ActorID: &admin, Action: domain.AuditAdminUserUnlocked, TargetType: domain.AuditTargetUser, TargetID: "u1",
+			Changes: domain.AuditChanges{"admin_locked_at": {From: "x", To: nil}}},
+	} {
+		e.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(ctx, &e))
+	}
+
+	events, err := repo.List(ctx, domain.AuditEventFilter{TargetType: domain.AuditTargetUser, TargetID: "u1"})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "e3", events[0].ID)
+	require.Equal(t, "e1", events[1].ID)
+	require.Contains(t, events[0].Changes, "admin_locked_at")
+
+	before := base.Add(time.Minute)
+	events, err = repo.List(ctx, domain.AuditEventFilter{Before: &before, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "e1", events[0].ID)
+}
+
+func TestAuditRepository_ListForUser(t *testing.T) {
+	repo := newTestAuditRepository(t)
+	ctx := context.Background()
+	user, admin := "user-1", "admin-1"
+	base := time.Now().Add(-time.Hour)
+
+	for i, e := range []domain.AuditEvent{
+		{ID: "own", ActorID: &user, Action: domain.AuditRecipeDeleted, TargetType: domain.AuditTargetRecipe, TargetID: "r1"},
+		{ID: "failed", Action: domain.AuditLoginFailed, TargetType: domain.AuditTargetUser, TargetID: user},
+		{ID: "by-admin", ActorID: &admin, Action: domain.AuditAdminUserLocked, TargetType: domain.AuditTargetUser, TargetID: user},
+		{ID: "other", ActorID: &admin, Action: domain.AuditAdminUserLocked, TargetType: domain.AuditTargetUser, TargetID: "user-2"},
+		// An AI model sharing the user's ID is not the user's account.
+		{ID: "same-id", ActorID: &admin, Action: domain.AuditAdminAIModelUpdated, TargetType: domain.AuditTargetAIModel, TargetID: user},
+	} {
+		e.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(ctx, &e))
+	}
+
+	events, err := repo.ListForUser(ctx, user, domain.ActivityFilter{})
+	require.NoError(t, err)
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	require.Equal(t, []string{"by-admin", "failed", "own"}, ids)
+
+	events, err = repo.ListForUser(ctx, user, domain.ActivityFilter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "by-admin", events[0].ID)
+}
+
+func TestAuditRepository_DeleteBefore(t *testing.T) {
+	repo := newTestAuditRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, e := range []domain.AuditEvent{
+		{ID: "old", Action: domain.AuditLoginFailed, TargetType: domain.AuditTargetUser, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "new", Action: domain.AuditLoginFailed, TargetType: domain.AuditTargetUser, CreatedAt: now},
+	} {
+		require.NoError(t, repo.Create(ctx, &e))
+	}
+
+	deleted, err := repo.DeleteBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, deleted)
+
+	events, err := repo.List(ctx, domain.AuditEventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "new", events[0].ID)
+}
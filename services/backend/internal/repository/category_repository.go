@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	// List returns every category, top-level ones first, each level in
+	// position order.
+	List(ctx context.Context) ([]domain.GroceryCategory, error)
+	// FindTerms returns the dictionary entries, in every locale, for whichever
+	// of terms it has.
+	FindTerms(ctx context.Context, terms []string) ([]domain.CategoryTerm, error)
+}
+
+type CategoryRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &CategoryRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *CategoryRepositoryImpl) List(ctx context.Context) ([]domain.GroceryCategory, error) {
+	var categories []domain.GroceryCategory
+	err := r.DB.WithContext(ctx).
+		Order("parent_code IS NOT NULL, position, code").
+		Find(&categories).Error
+	return categories, err
+}
+
+func (r *CategoryRepositoryImpl) FindTerms(ctx context.Context, terms []string) ([]domain.CategoryTerm, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var found []domain.CategoryTerm
+	err := r.DB.WithContext(ctx).Where("term IN ?", terms).Find(&found).Error
+	return found, err
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryRepository(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE categories (
+		code TEXT PRIMARY KEY, parent_code TEXT, position INTEGER NOT NULL DEFAULT 0,
+		names TEXT NOT NULL DEFAULT '{}', created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE category_terms (
+		term TEXT NOT NULL, locale TEXT NOT NULL, category_code TEXT NOT NULL,
+		PRIMARY KEY (term, locale))`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO categories (code, parent_code, position, names) VALUES
+		('PANTRY_SPICES', 'PANTRY', 2, '{"en":"Spices","de":"Gewürze"}'),
+		('PANTRY', NULL, 2, '{"en":"Pantry"}'),
+		('PANTRY_CANNED', 'PANTRY', 1, '{"en":"Canned goods"}'),
+		('PRODUCE', NULL, 1, '{"en":"Produce"}')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO category_terms (term, locale, category_code) VALUES
+		('cumin', 'en', 'PANTRY_SPICES'), ('kreuzkümmel', 'de', 'PANTRY_SPICES'),
+		('mais', 'de', 'PRODUCE'), ('mais', 'fr', 'PANTRY_CANNED')`).Error)
+	repo := NewCategoryRepository(db)
+	ctx := context.Background()
+
+	categories, err := repo.List(ctx)
+	require.NoError(t, err)
+	codes := make([]domain.Category, len(categories))
+	for i, c := range categories {
+		codes[i] = c.Code
+	}
+	require.Equal(t, []domain.Category{"PRODUCE", "PANTRY", "PANTRY_CANNED", "PANTRY_SPICES"}, codes)
+	require.Equal(t, "Gewürze", categories[3].Names["de"])
+	require.Equal(t, domain.CategoryPantry, *categories[3].ParentCode)
+
+	terms, err := repo.FindTerms(ctx, []string{"kreuzkümmel", "unknown"})
+	require.NoError(t, err)
+	require.Equal(t, []domain.CategoryTerm{{Term: "kreuzkümmel", Locale: "de", CategoryCode: "PANTRY_SPICES"}}, terms)
+
+	// A spelling that is a term in several locales comes back once per locale.
+	terms, err = repo.FindTerms(ctx, []string{"mais"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []domain.CategoryTerm{
+		{Term: "mais", Locale: "de", CategoryCode: "PRODUCE"},
+		{Term: "mais", Locale: "fr", CategoryCode: "PANTRY_CANNED"},
+	}, terms)
+
+	terms, err = repo.FindTerms(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, terms)
+}
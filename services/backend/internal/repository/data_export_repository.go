@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *domain.DataExport) error
+	// GetLatest returns the user's most recent export.
+	GetLatest(ctx context.Context, userID string) (*domain.DataExport, error)
+	MarkReady(ctx context.Context, id, fileName string, sizeBytes int64, completedAt, expiresAt time.Time) error
+	// MarkFailed also applies to a ready export, for when its link could not
+	// be delivered.
+	MarkFailed(ctx context.Context, id string, completedAt time.Time) error
+	// FailPendingBefore marks exports still pending since before cutoff as
+	// failed, e.g. ones whose build was cut short by a restart.
+	FailPendingBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	// ListExpired returns ready exports whose link expired before now.
+	ListExpired(ctx context.Context, now time.Time) ([]domain.DataExport, error)
+	MarkExpired(ctx context.Context, id string) error
+}
+
+type DataExportRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &DataExportRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *DataExportRepositoryImpl) Create(ctx context.Context, export *domain.DataExport) error {
+	return r.DB.WithContext(ctx).Create(export).Error
+}
+
+func (r *DataExportRepositoryImpl) GetLatest(ctx context.Context, userID string) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := r.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepositoryImpl) MarkReady(ctx context.Context, id, fileName string, sizeBytes int64, completedAt, expiresAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&domain.DataExport{}).
+		Where("id = ? AND status = ?", id, domain.DataExportPending).
+		Updates(map[string]interface{}{
+			"status":       domain.DataExportReady,
+			"file_name":    fileName,
+			"size_bytes":   sizeBytes,
+			"completed_at": completedAt,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+func (r *DataExportRepositoryImpl) MarkFailed(ctx context.Context, id string, completedAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&domain.DataExport{}).
+		Where("id = ? AND status IN ?", id, []string{domain.DataExportPending, domain.DataExportReady}).
+		Updates(map[string]interface{}{
+			"status":       domain.DataExportFailed,
+			"completed_at": completedAt,
+		}).Error
+}
+
+func (r *DataExportRepositoryImpl) FailPendingBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.DataExport{}).
+		Where("status = ? AND created_at < ?", domain.DataExportPending, cutoff).
+		Update("status", domain.DataExportFailed)
+	return result.RowsAffected, result.Error
+}
+
+func (r *DataExportRepositoryImpl) ListExpired(ctx context.Context, now time.Time) ([]domain.DataExport, error) {
+	var exports []domain.DataExport
+	err := r.DB.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", domain.DataExportReady, now).
+		Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepositoryImpl) MarkExpired(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Model(&domain.DataExport{}).
+		Where("id = ?", id).
+		Update("status", domain.DataExportExpired).Error
+}
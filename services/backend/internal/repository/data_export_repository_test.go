@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDataExportRepository(t *testing.T) DataExportRepository {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE data_exports (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, status TEXT NOT NULL DEFAULT 'pending',
+		file_name TEXT NOT NULL DEFAULT '', size_bytes INTEGER NOT NULL DEFAULT 0,
+		completed_at DATETIME, expires_at DATETIME, created_at DATETIME)`).Error)
+	return NewDataExportRepository(db)
+}
+
+func TestDataExportRepository_GetLatestAndMarkReady(t *testing.T) {
+	repo := newTestDataExportRepository(t)
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+
+	for i, e := range []domain.DataExport{
+		{ID: "old", UserID: "user-1", Status: domain.DataExportFailed},
+		{ID: "new", UserID: "user-1", Status: domain.DataExportPending},
+		{ID: "other", UserID: "user-2", Status: domain.DataExportPending},
+	} {
+		e.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(ctx, &e))
+	}
+
+	latest, err := repo.GetLatest(ctx, "user-1")
+	require.NoError(t, err)
+	require.Equal(t, "new", latest.ID)
+
+	now := time.Now()
+	require.NoError(t, repo.MarkReady(ctx, "new", "a.zip", 42, now, now.Add(time.Hour)))
+	latest, err = repo.GetLatest(ctx, "user-1")
+	require.NoError(t, err)
+	require.Equal(t, domain.DataExportReady, latest.Status)
+	require.Equal(t, "a.zip", latest.FileName)
+	require.EqualValues(t, 42, latest.SizeBytes)
+}
+
+func TestDataExportRepository_Cleanup(t *testing.T) {
+	repo := newTestDataExportRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, repo.Create(ctx, &domain.DataExport{ID: "stale", UserID: "u", Status: domain.DataExportPending, CreatedAt: now.Add(-2 * time.Hour)}))
+	require.NoError(t, repo.Create(ctx, &domain.DataExport{ID: "fresh", UserID: "u", Status: domain.DataExportPending, CreatedAt: now}))
+	require.NoError(t, repo.Create(ctx, &domain.DataExport{ID: "expired", UserID: "u", Status: domain.DataExportPending, CreatedAt: now.Add(-3 * time.Hour)}))
+	require.NoError(t, repo.MarkReady(ctx, "expired", "x.zip", 1, now.Add(-2*time.Hour), now.Add(-time.Minute)))
+
+	failed, err := repo.FailPendingBefore(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, failed)
+
+	expired, err := repo.ListExpired(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, "x.zip", expired[0].FileName)
+
+	require.NoError(t, repo.MarkExpired(ctx, "expired"))
+	expired, err = repo.ListExpired(ctx, now)
+	require.NoError(t, err)
+	require.Empty(t, expired)
+}
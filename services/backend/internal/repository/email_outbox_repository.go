@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmailOutboxRepository interface {
+	Create(ctx context.Context, email *domain.OutboxEmail) error
+	// ClaimDue returns up to limit pending emails due by now and pushes their
+	// next attempt back by lease, so a concurrent sender skips them while
+	// they are being delivered.
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]domain.OutboxEmail, error)
+	// MarkSent also clears the bodies, which may carry one-time links.
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	// MarkFailed gives up on the email and clears its bodies like MarkSent.
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string) error
+	// DeleteFinishedBefore deletes sent and failed emails created before
+	// cutoff.
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type EmailOutboxRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewEmailOutboxRepository(db *gorm.DB) EmailOutboxRepository {
+	return &EmailOutboxRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *EmailOutboxRepositoryImpl) Create(ctx context.Context, email *domain.OutboxEmail) error {
+	return r.DB.WithContext(ctx).Create(email).Error
+}
+
+func (r *EmailOutboxRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]domain.OutboxEmail, error) {
+	var emails []domain.OutboxEmail
+	err := r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.OutboxEmailPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&emails).Error; err != nil {
+			return err
+		}
+		if len(emails) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(emails))
+		for i := range emails {
+			ids[i] = emails[i].ID
+		}
+		return tx.Model(&domain.OutboxEmail{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return emails, err
+}
+
+func (r *EmailOutboxRepositoryImpl) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&domain.OutboxEmail{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     domain.OutboxEmailSent,
+			"sent_at":    sentAt,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"text_body":  "",
+			"html_body":  "",
+			"last_error": "",
+		}).Error
+}
+
+func (r *EmailOutboxRepositoryImpl) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.DB.WithContext(ctx).Model(&domain.OutboxEmail{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error
+}
+
+func (r *EmailOutboxRepositoryImpl) MarkFailed(ctx context.Context, id string, attempts int, lastError string) error {
+	return r.DB.WithContext(ctx).Model(&domain.OutboxEmail{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     domain.OutboxEmailFailed,
+			"attempts":   attempts,
+			"text_body":  "",
+			"html_body":  "",
+			"last_error": lastError,
+		}).Error
+}
+
+func (r *EmailOutboxRepositoryImpl) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).
+		Where("status <> ? AND created_at < ?", domain.OutboxEmailPending, cutoff).
+		Delete(&domain.OutboxEmail{})
+	return result.RowsAffected, result.Error
+}
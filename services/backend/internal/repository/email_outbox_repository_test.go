@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestEmailOutboxRepository(t *testing.T) (EmailOutboxRepository, *gorm.DB) {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE email_outbox (
+		id TEXT PRIMARY KEY, recipient TEXT NOT NULL, subject TEXT NOT NULL,
+		text_body TEXT NOT NULL DEFAULT '', html_body TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'pending', attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at DATETIME NOT NULL, last_error TEXT NOT NULL DEFAULT '',
+		sent_at DATETIME, created_at DATETIME)`).Error)
+	return NewEmailOutboxRepository(db), db
+}
+
+func TestEmailOutboxRepository_ClaimDue(t *testing.T) {
+	repo, _ := newTestEmailOutboxRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, e := range []domain.OutboxEmail{
+		{ID: "due", Recipient: "a@b.c", Subject: "s", Status: domain.OutboxEmailPending, NextAttemptAt: now.Add(-time.Minute)},
+		{ID: "later", Recipient: "a@b.c", Subject: "s", Status: domain.OutboxEmailPending, NextAttemptAt: now.Add(time.Minute)},
+		{ID: "failed", Recipient: "a@b.c", Subject: "s", Status: domain.OutboxEmailFailed, NextAttemptAt: now.Add(-time.Minute)},
+	} {
+		require.NoError(t, repo.Create(ctx, &e))
+	}
+
+	claimed, err := repo.ClaimDue(ctx, now, 10, 2*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "due", claimed[0].ID)
+
+	// The claimed email is leased, so a second sender does not pick it up.
+	claimed, err = repo.ClaimDue(ctx, now, 10, 2*time.Minute)
+	require.NoError(t, err)
+	require.Empty(t, claimed)
+
+	claimed, err = repo.ClaimDue(ctx, now.Add(3*time.Minute), 10, 2*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+}
+
+func TestEmailOutboxRepository_FinishingClearsBodies(t *testing.T) {
+	repo, db := newTestEmailOutboxRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, id := range []string{"e1", "e2"} {
+		require.NoError(t, repo.Create(ctx, &domain.OutboxEmail{
+			ID: id, Recipient: "a@b.c", Subject: "s", TextBody: "token", HTMLBody: "<p>token</p>",
+			Status: domain.OutboxEmailPending, Attempts: 1, NextAttemptAt: now, LastError: "timeout",
+		}))
+	}
+	require.NoError(t, repo.MarkSent(ctx, "e1", now))
+	require.NoError(t, repo.MarkFailed(ctx, "e2", 5, "mailbox unavailable"))
+
+	var sent domain.OutboxEmail
+	require.NoError(t, db.First(&sent, "id = ?", "e1").Error)
+	require.Equal(t, domain.OutboxEmailSent, sent.Status)
+	require.Equal(t, 2, sent.Attempts)
+	require.Empty(t, sent.TextBody)
+	require.Empty(t, sent.HTMLBody)
+	require.Empty(t, sent.LastError)
+	require.NotNil(t, sent.SentAt)
+
+	// A permanently failed email keeps no one-time links either.
+	var failed domain.OutboxEmail
+	require.NoError(t, db.First(&failed, "id = ?", "e2").Error)
+	require.Equal(t, domain.OutboxEmailFailed, failed.Status)
+	require.Empty(t, failed.TextBody)
+	require.Empty(t, failed.HTMLBody)
+	require.Equal(t, "mailbox unavailable", failed.LastError)
+}
+
+func TestEmailOutboxRepository_DeleteFinishedBefore(t *testing.T) {
+	repo, _ := newTestEmailOutboxRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+	old := now.Add(-8 * 24 * time.Hour)
+
+	for _, e := range []domain.OutboxEmail{
+		{ID: "old-sent", Status: domain.OutboxEmailSent, CreatedAt: old},
+		{ID: "old-failed", Status: domain.OutboxEmailFailed, CreatedAt: old},
+		{ID: "old-pending", Status: domain.OutboxEmailPending, CreatedAt: old},
+		{ID: "new-sent", Status: domain.OutboxEmailSent, CreatedAt: now},
+	} {
+		e.Recipient, e.Subject, e.NextAttemptAt = "a@b.c", "s", now
+		require.NoError(t, repo.Create(ctx, &e))
+	}
+
+	deleted, err := repo.DeleteFinishedBefore(ctx, now.Add(-7*24*time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, deleted)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ItemPriceRepository interface {
+	// List returns the user's prices by name, at storeChainID or, when it is
+	// empty, at every chain.
+	List(ctx context.Context, userID string, storeChainID string) ([]domain.ItemPrice, error)
+	// ListForItems returns the user's prices of items with any of itemKeys,
+	// at storeChainID or every chain as List does, most recently updated
+	// first.
+	ListForItems(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.ItemPrice, error)
+	// Save saves price, replacing the one the item had at its chain.
+	Save(ctx context.Context, price *domain.ItemPrice) error
+	Delete(ctx context.Context, userID string, id string) (bool, error)
+}
+
+type ItemPriceRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewItemPriceRepository(db *gorm.DB) ItemPriceRepository {
+	return &ItemPriceRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *ItemPriceRepositoryImpl) List(ctx context.Context, userID string, storeChainID string) ([]domain.ItemPrice, error) {
+	query := r.DB.WithContext(ctx).Where("user_id = ?", userID)
+	if storeChainID != "" {
+		query = query.Where("store_chain_id = ?", storeChainID)
+	}
+
+	var prices []domain.ItemPrice
+	err := query.Order("name ASC, updated_at DESC").Find(&prices).Error
+	return prices, err
+}
+
+func (r *ItemPriceRepositoryImpl) ListForItems(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.ItemPrice, error) {
+	if len(itemKeys) == 0 {
+		return nil, nil
+	}
+
+	query := r.DB.WithContext(ctx).Where("user_id = ? AND item_key IN ?", userID, itemKeys)
+	if storeChainID != "" {
+		query = query.Where("store_chain_id = ?", storeChainID)
+	}
+
+	var prices []domain.ItemPrice
+	err := query.Order("updated_at DESC, id").Find(&prices).Error
+	return prices, err
+}
+
+func (r *ItemPriceRepositoryImpl) Save(ctx context.Context, price *domain.ItemPrice) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "store_chain_id"}, {Name: "item_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "quantity", "unit", "price_cents", "currency", "unit_price_cents", "per_unit", "updated_at",
+		}),
+	}).Create(price).Error
+}
+
+func (r *ItemPriceRepositoryImpl) Delete(ctx context.Context, userID string, id string) (bool, error) {
+	result := r.DB.WithContext(ctx).
+		Where("user_id = ? AND id = ?", userID, id).
+		Delete(&domain.ItemPrice{})
+	return result.RowsAffected > 0, result.Error
+}
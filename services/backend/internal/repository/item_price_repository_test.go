@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestItemPriceRepository(t *testing.T) ItemPriceRepository {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE item_prices (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, store_chain_id TEXT NOT NULL, name TEXT NOT NULL,
+		item_key TEXT NOT NULL, quantity REAL NOT NULL, unit TEXT NOT NULL DEFAULT '', price_cents INTEGER NOT NULL,
+		currency TEXT NOT NULL, unit_price_cents REAL NOT NULL, per_unit TEXT NOT NULL,
+		created_at DATETIME, updated_at DATETIME, UNIQUE (user_id, store_chain_id, item_key))`).Error)
+	return NewItemPriceRepository(db)
+}
+
+func TestItemPriceRepository_SaveReplacesPriceAtChain(t *testing.T) {
+	repo := newTestItemPriceRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Save(ctx, &domain.ItemPrice{ID: "p1", UserID: "user-1", StoreChainID: "rewe", Name: "Flour", ItemKey: "flour", Quantity: 1, Unit: "kg", PriceCents: 99, Currency: "EUR", UnitPriceCents: 99, PerUnit: "kg"}))
+	require.NoError(t, repo.Save(ctx, &domain.ItemPrice{ID: "p2", UserID: "user-1", StoreChainID: "rewe", Name: "Flour", ItemKey: "flour", Quantity: 2, Unit: "kg", PriceCents: 179, Currency: "EUR", UnitPriceCents: 89.5, PerUnit: "kg"}))
+	require.NoError(t, repo.Save(ctx, &domain.ItemPrice{ID: "p3", UserID: "user-1", StoreChainID: "edeka", Name: "Flour", ItemKey: "flour", Quantity: 1, Unit: "kg", PriceCents: 119, Currency: "EUR", UnitPriceCents: 119, PerUnit: "kg"}))
+	require.NoError(t, repo.Save(ctx, &domain.ItemPrice{ID: "p4", UserID: "user-2", StoreChainID: "rewe", Name: "Flour", ItemKey: "flour", Quantity: 1, Unit: "kg", PriceCents: 50, Currency: "EUR", UnitPriceCents: 50, PerUnit: "kg"}))
+
+	prices, err := repo.List(ctx, "user-1", "rewe")
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, int64(179), prices[0].PriceCents)
+	require.Equal(t, 89.5, prices[0].UnitPriceCents)
+
+	prices, err = repo.ListForItems(ctx, "user-1", "", []string{"flour", "sugar"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+
+	prices, err = repo.ListForItems(ctx, "user-1", "edeka", []string{"flour"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, "p3", prices[0].ID)
+
+	deleted, err := repo.Delete(ctx, "user-2", "p3")
+	require.NoError(t, err)
+	require.False(t, deleted)
+	deleted, err = repo.Delete(ctx, "user-1", "p3")
+	require.NoError(t, err)
+	require.True(t, deleted)
+}
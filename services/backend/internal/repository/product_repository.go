@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductRepository interface {
+	GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error)
+	// Upsert inserts products, replacing those already there by barcode.
+	Upsert(ctx context.Context, products []domain.Product) error
+	GetSaved(ctx context.Context, userID string, barcode string) (*domain.UserProduct, error)
+	ListSaved(ctx context.Context, userID string) ([]domain.UserProduct, error)
+	// Save inserts the user's product, replacing the one they saved for the
+	// same barcode.
+	Save(ctx context.Context, product *domain.UserProduct) error
+	// DeleteSaved reports false when the user had no product saved for
+	// barcode.
+	DeleteSaved(ctx context.Context, userID string, barcode string) (bool, error)
+}
+
+type ProductRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewProductRepository(db *gorm.DB) ProductRepository {
+	return &ProductRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *ProductRepositoryImpl) GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+	var product domain.Product
+	if err := r.DB.WithContext(ctx).First(&product, "barcode = ?", barcode).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *ProductRepositoryImpl) Upsert(ctx context.Context, products []domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "barcode"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "brand", "quantity", "category", "energy_kcal", "fat", "saturated_fat",
+			"carbohydrates", "sugars", "fiber", "protein", "salt", "updated_at",
+		}),
+	}).Create(&products).Error
+}
+
+func (r *ProductRepositoryImpl) GetSaved(ctx context.Context, userID string, barcode string) (*domain.UserProduct, error) {
+	var product domain.UserProduct
+	if err := r.DB.WithContext(ctx).First(&product, "user_id = ? AND barcode = ?", userID, barcode).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *ProductRepositoryImpl) ListSaved(ctx context.Context, userID string) ([]domain.UserProduct, error) {
+	var products []domain.UserProduct
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&products).Error
+	return products, err
+}
+
+func (r *ProductRepositoryImpl) Save(ctx context.Context, product *domain.UserProduct) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "barcode"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "brand", "quantity", "category", "amount", "unit", "updated_at"}),
+	}).Create(product).Error
+}
+
+func (r *ProductRepositoryImpl) DeleteSaved(ctx context.Context, userID string, barcode string) (bool, error) {
+	result := r.DB.WithContext(ctx).
+		Where("user_id = ? AND barcode = ?", userID, barcode).
+		Delete(&domain.UserProduct{})
+	return result.RowsAffected > 0, result.Error
+}
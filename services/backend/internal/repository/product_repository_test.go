@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProductRepository(t *testing.T) ProductRepository {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE products (
+		barcode TEXT PRIMARY KEY, name TEXT NOT NULL, brand TEXT NOT NULL DEFAULT '', quantity TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL, energy_kcal REAL, fat REAL, saturated_fat REAL, carbohydrates REAL, sugars REAL,
+		fiber REAL, protein REAL, salt REAL, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE user_products (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, barcode TEXT NOT NULL, name TEXT NOT NULL,
+		brand TEXT NOT NULL DEFAULT '', quantity TEXT NOT NULL DEFAULT '', category TEXT NOT NULL,
+		amount REAL NOT NULL DEFAULT 0, unit TEXT NOT NULL DEFAULT '', created_at DATETIME, updated_at DATETIME,
+		UNIQUE (user_id, barcode))`).Error)
+	return NewProductRepository(db)
+}
+
+func TestProductRepository_UpsertReplacesByBarcode(t *testing.T) {
+	repo := newTestProductRepository(t)
+	ctx := context.Background()
+	kcal := 539.0
+
+	require.NoError(t, repo.Upsert(ctx, []domain.Product{
+		{Barcode: "3017620422003", Name: "Nutella", Category: domain.CategoryOther},
+		{Barcode: "96385074", Name: "Eggs", Category: domain.CategoryDairy},
+	}))
+	require.NoError(t, repo.Upsert(ctx, []domain.Product{
+		{Barcode: "3017620422003", Name: "Nutella", Brand: "Ferrero", Category: domain.CategoryPantry, Nutrition: domain.ProductNutrition{EnergyKcal: &kcal}},
+	}))
+
+	product, err := repo.GetByBarcode(ctx, "3017620422003")
+	require.NoError(t, err)
+	require.Equal(t, "Ferrero", product.Brand)
+	require.Equal(t, domain.CategoryPantry, product.Category)
+	require.Equal(t, kcal, *product.Nutrition.EnergyKcal)
+	require.Nil(t, product.Nutrition.Salt)
+}
+
+func TestProductRepository_SavedProducts(t *testing.T) {
+	repo := newTestProductRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Save(ctx, &domain.UserProduct{ID: "p1", UserID: "user-1", Barcode: "96385074", Name: "Eggs", Category: domain.CategoryDairy}))
+	require.NoError(t, repo.Save(ctx, &domain.UserProduct{ID: "p2", UserID: "user-1", Barcode: "96385074", Name: "Eggs (10)", Category: domain.CategoryDairy, Amount: 10}))
+	require.NoError(t, repo.Save(ctx, &domain.UserProduct{ID: "p3", UserID: "user-2", Barcode: "96385074", Name: "Theirs", Category: domain.CategoryDairy}))
+
+	saved, err := repo.ListSaved(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	require.Equal(t, "Eggs (10)", saved[0].Name)
+	require.Equal(t, 10.0, saved[0].Amount)
+
+	deleted, err := repo.DeleteSaved(ctx, "user-1", "96385074")
+	require.NoError(t, err)
+	require.True(t, deleted)
+	deleted, err = repo.DeleteSaved(ctx, "user-1", "96385074")
+	require.NoError(t, err)
+	require.False(t, deleted)
+
+	_, err = repo.GetSaved(ctx, "user-2", "96385074")
+	require.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+)
+
+type RecipeEditProposalRepository interface {
+	Create(ctx context.Context, proposal *domain.RecipeEditProposal) error
+	GetByID(ctx context.Context, id string) (*domain.RecipeEditProposal, error)
+	Resolve(ctx context.Context, id string, status string) (bool, error)
+	Reopen(ctx context.Context, id string) error
+}
+
+type RecipeEditProposalRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewRecipeEditProposalRepository(db *gorm.DB) RecipeEditProposalRepository {
+	return &RecipeEditProposalRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *RecipeEditProposalRepositoryImpl) Create(ctx context.Context, proposal *domain.RecipeEditProposal) error {
+	return r.DB.WithContext(ctx).Create(proposal).Error
+}
+
+func (r *RecipeEditProposalRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.RecipeEditProposal, error) {
+	var proposal domain.RecipeEditProposal
+	if err := r.DB.WithContext(ctx).First(&proposal, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// Resolve moves a pending proposal to status. It reports false when the
+// proposal was no longer pending, so two concurrent accepts cannot both win.
+func (r *RecipeEditProposalRepositoryImpl) Resolve(ctx context.Context, id string, status string) (bool, error) {
+	result := r.DB.WithContext(ctx).
+		Model(&domain.RecipeEditProposal{}).
+		Where("id = ? AND status = ?", id, domain.EditProposalPending).
+		Update("status", status)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Reopen puts a proposal back to pending after applying it failed.
+func (r *RecipeEditProposalRepositoryImpl) Reopen(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).
+		Model(&domain.RecipeEditProposal{}).
+		Where("id = ?", id).
+		Update("status", domain.EditProposalPending).Error
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/H3nSte1n/recipe/internal/domain"
 	"gorm.io/gorm"
 )
@@ -14,9 +16,26 @@ type RecipeRepository interface {
 	ListByUserID(ctx context.Context, userID string, includePrivate bool) ([]domain.Recipe, error)
 	ListPublic(ctx context.Context, page, pageSize int) ([]domain.Recipe, int64, error)
 	Exists(ctx context.Context, id string) (bool, error)
+	// ListTrashed returns the user's recipes in the trash with their
+	// ingredients, instructions and nutrition, most recently deleted first.
+	ListTrashed(ctx context.Context, userID string) ([]domain.Recipe, error)
+	GetTrashedByID(ctx context.Context, id string) (*domain.Recipe, error)
+	Restore(ctx context.Context, id string) error
+	// ListTrashedBefore returns recipes of any user deleted before cutoff.
+	ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]domain.Recipe, error)
+	// Purge permanently deletes a recipe in the trash along with its
+	// ingredients, instructions, nutrition and sub-recipe links.
+	Purge(ctx context.Context, id string) error
 	WithTypedTransaction(ctx context.Context, fn func(RecipeRepository) error) error
 }
 
+// liveSubRecipes hides links to sub-recipes that are in the trash. The link
+// itself is kept, so it reappears if the sub-recipe is restored, and is only
+// removed when the sub-recipe is purged.
+func liveSubRecipes(db *gorm.DB) *gorm.DB {
+	return db.Where("sub_recipes.child_id IN (SELECT id FROM recipes WHERE deleted_at IS NULL)")
+}
+
 type RecipeRepositoryImpl struct {
 	*BaseRepository
 }
@@ -52,7 +71,14 @@ func (r *RecipeRepositoryImpl) Update(ctx context.Context, recipe *domain.Recipe
 		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&domain.RecipeNutrition{}).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("parent_id = ?", recipe.ID).Delete(&domain.SubRecipe{}).Error; err != nil {
+		// Links to trashed sub-recipes are not part of the recipe as the caller
+		// sees it, so they survive the update; see liveSubRecipes.
+		if err := liveSubRecipes(tx.Where("parent_id = ?", recipe.ID)).Delete(&domain.SubRecipe{}).Error; err != nil {
+			return err
+		}
+		// Translations are keyed by the ingredient and instruction rows replaced
+		// below, so they are stale once the recipe changes.
+		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&domain.RecipeTranslation{}).Error; err != nil {
 			return err
 		}
 
@@ -115,7 +141,7 @@ func (r *RecipeRepositoryImpl) GetByID(ctx context.Context, id string, nutrition
 	query := r.DB.WithContext(ctx).
 		Preload("Ingredients").
 		Preload("Instructions").
-		Preload("SubRecipes").
+		Preload("SubRecipes", liveSubRecipes).
 		Preload("SubRecipes.Child").
 		Preload("SubRecipes.Child.Ingredients").
 		Preload("SubRecipes.Child.Instructions")
@@ -163,7 +189,7 @@ func (r *RecipeRepositoryImpl) ListByUserID(ctx context.Context, userID string,
 		}).
 		Preload("Nutrition").
 		Preload("SubRecipes", func(db *gorm.DB) *gorm.DB {
-			return db.Order("sub_recipes.id")
+			return liveSubRecipes(db).Order("sub_recipes.id")
 		}).
 		Preload("SubRecipes.Child", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id, title, servings")
@@ -204,7 +230,7 @@ func (r *RecipeRepositoryImpl) ListPublic(ctx context.Context, page, pageSize in
 		}).
 		Preload("Nutrition").
 		Preload("SubRecipes", func(db *gorm.DB) *gorm.DB {
-			return db.Order("sub_recipes.id")
+			return liveSubRecipes(db).Order("sub_recipes.id")
 		}).
 		Preload("SubRecipes.Child", func(db *gorm.DB) *gorm.DB {
 			return db.Select("id, title, servings")
@@ -239,3 +265,51 @@ func (r *RecipeRepositoryImpl) Exists(ctx context.Context, id string) (bool, err
 
 	return true, nil
 }
+
+func (r *RecipeRepositoryImpl) ListTrashed(ctx context.Context, userID string) ([]domain.Recipe, error) {
+	var recipes []domain.Recipe
+	err := r.DB.WithContext(ctx).Unscoped().
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recipe_ingredients.id")
+		}).
+		Preload("Instructions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recipe_instructions.step_number")
+		}).
+		Preload("Nutrition").
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&recipes).Error
+	return recipes, err
+}
+
+func (r *RecipeRepositoryImpl) GetTrashedByID(ctx context.Context, id string) (*domain.Recipe, error) {
+	var recipe domain.Recipe
+	if err := r.DB.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&recipe).Error; err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+func (r *RecipeRepositoryImpl) Restore(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Unscoped().Model(&domain.Recipe{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil).Error
+}
+
+func (r *RecipeRepositoryImpl) ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]domain.Recipe, error) {
+	var recipes []domain.Recipe
+	err := r.DB.WithContext(ctx).Unscoped().
+		Where("deleted_at < ?", cutoff).
+		Find(&recipes).Error
+	return recipes, err
+}
+
+func (r *RecipeRepositoryImpl) Purge(ctx context.Context, id string) error {
+	// Dependent rows, sub-recipe links in either direction included, go with
+	// the recipe through ON DELETE CASCADE.
+	return r.DB.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Delete(&domain.Recipe{}).Error
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestRecipeRepository(t *testing.T) (RecipeRepository, *gorm.DB) {
+	t.Helper()
+	db := openTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE recipes (
+			id TEXT PRIMARY KEY, user_id TEXT NOT NULL, title TEXT NOT NULL, description TEXT, notes TEXT,
+			rating REAL DEFAULT 0, image_url TEXT, source_type TEXT NOT NULL, source TEXT,
+			is_private BOOLEAN DEFAULT false, servings INTEGER NOT NULL, prep_time INTEGER, cook_time INTEGER,
+			shelf_life INTEGER, status TEXT DEFAULT 'draft', variant_of_id TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE recipe_ingredients (id TEXT PRIMARY KEY, recipe_id TEXT NOT NULL, name TEXT NOT NULL,
+			description TEXT NOT NULL, amount REAL, unit TEXT, notes TEXT)`,
+		`CREATE TABLE recipe_instructions (id TEXT PRIMARY KEY, recipe_id TEXT NOT NULL, step_number INTEGER NOT NULL,
+			instruction TEXT NOT NULL)`,
+		`CREATE TABLE recipe_nutrition (id TEXT PRIMARY KEY, recipe_id TEXT NOT NULL, calories REAL, per_serving BOOLEAN)`,
+		`CREATE TABLE sub_recipes (id TEXT PRIMARY KEY, parent_id TEXT NOT NULL, child_id TEXT NOT NULL,
+			serving_factor REAL DEFAULT 1)`,
+		`CREATE TABLE recipe_translations (id TEXT PRIMARY KEY, recipe_id TEXT NOT NULL)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return NewRecipeRepository(db), db
+}
+
+func TestRecipeRepository_TrashHidesSubRecipeLinksUntilRestored(t *testing.T) {
+	repo, db := newTestRecipeRepository(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"parent", "child"} {
+		require.NoError(t, repo.Create(ctx, &domain.Recipe{ID: id, UserID: "user-1", Title: id, SourceType: domain.SourceTypeManual, Servings: 2}))
+	}
+	require.NoError(t, db.Create(&domain.SubRecipe{ID: "link", ParentID: "parent", ChildID: "child", ServingFactor: 1}).Error)
+
+	require.NoError(t, repo.Delete(ctx, "child"))
+
+	_, err := repo.GetByID(ctx, "child", domain.NutritionDetailBase)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	parent, err := repo.GetByID(ctx, "parent", domain.NutritionDetailBase)
+	require.NoError(t, err)
+	require.Empty(t, parent.SubRecipes)
+
+	trashed, err := repo.ListTrashed(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	require.Equal(t, "child", trashed[0].ID)
+	require.True(t, trashed[0].DeletedAt.Valid)
+
+	// Editing the parent meanwhile keeps the hidden link.
+	parent.SubRecipes = nil
+	require.NoError(t, repo.Update(ctx, parent))
+
+	require.NoError(t, repo.Restore(ctx, "child"))
+	parent, err = repo.GetByID(ctx, "parent", domain.NutritionDetailBase)
+	require.NoError(t, err)
+	require.Len(t, parent.SubRecipes, 1)
+	require.Equal(t, "child", parent.SubRecipes[0].Child.ID)
+
+	trashed, err = repo.ListTrashed(ctx, "user-1")
+	require.NoError(t, err)
+	require.Empty(t, trashed)
+}
+
+func TestRecipeRepository_PurgeOnlyRemovesTrashedRecipes(t *testing.T) {
+	repo, db := newTestRecipeRepository(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"live", "old", "recent"} {
+		require.NoError(t, repo.Create(ctx, &domain.Recipe{ID: id, UserID: "user-1", Title: id, SourceType: domain.SourceTypeManual, Servings: 1}))
+	}
+	require.NoError(t, db.Exec(`UPDATE recipes SET deleted_at = ? WHERE id = 'old'`, time.Now().Add(-48*time.Hour)).Error)
+	require.NoError(t, repo.Delete(ctx, "recent"))
+
+	expired, err := repo.ListTrashedBefore(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, "old", expired[0].ID)
+
+	require.NoError(t, repo.Purge(ctx, "old"))
+	require.NoError(t, repo.Purge(ctx, "live"))
+
+	var remaining []string
+	require.NoError(t, db.Raw(`SELECT id FROM recipes ORDER BY id`).Scan(&remaining).Error)
+	require.Equal(t, []string{"live", "recent"}, remaining)
+}
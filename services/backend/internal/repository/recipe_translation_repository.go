@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecipeTranslationRepository interface {
+	Upsert(ctx context.Context, translation *domain.RecipeTranslation) error
+	ListByRecipeID(ctx context.Context, recipeID string, languages []string) ([]domain.RecipeTranslation, error)
+}
+
+type RecipeTranslationRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewRecipeTranslationRepository(db *gorm.DB) RecipeTranslationRepository {
+	return &RecipeTranslationRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// Upsert stores the translation, replacing any existing one for the same
+// recipe and language.
+func (r *RecipeTranslationRepositoryImpl) Upsert(ctx context.Context, translation *domain.RecipeTranslation) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "recipe_id"}, {Name: "language"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "description", "notes", "ingredients", "instructions", "updated_at"}),
+	}).Create(translation).Error
+}
+
+// ListByRecipeID returns the recipe's translations in any of the given
+// languages, in no particular order.
+func (r *RecipeTranslationRepositoryImpl) ListByRecipeID(ctx context.Context, recipeID string, languages []string) ([]domain.RecipeTranslation, error) {
+	var translations []domain.RecipeTranslation
+	if err := r.DB.WithContext(ctx).
+		Where("recipe_id = ? AND language IN ?", recipeID, languages).
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
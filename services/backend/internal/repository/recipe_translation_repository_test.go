@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecipeTranslationRepository_UpsertReplacesSameLanguage checks that
+// translating again into a language replaces the stored row instead of adding
+// a second one, while other languages are left alone.
+func TestRecipeTranslationRepository_UpsertReplacesSameLanguage(t *testing.T) {
+	db := openTestDB(t)
+	// Created by hand: the uuid_generate_v4() column default does not exist in
+	// sqlite, and the unique key is what the upsert relies on.
+	require.NoError(t, db.Exec(`CREATE TABLE recipe_translations (
+		id TEXT PRIMARY KEY, recipe_id TEXT NOT NULL, language TEXT NOT NULL,
+		title TEXT NOT NULL, description TEXT, notes TEXT, ingredients TEXT, instructions TEXT,
+		created_at DATETIME, updated_at DATETIME,
+		UNIQUE (recipe_id, language))`).Error)
+
+	repo := NewRecipeTranslationRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Upsert(ctx, &domain.RecipeTranslation{ID: "t1", RecipeID: "r1", Language: "de", Title: "Alt"}))
+	require.NoError(t, repo.Upsert(ctx, &domain.RecipeTranslation{ID: "t2", RecipeID: "r1", Language: "fr", Title: "Crêpes"}))
+	require.NoError(t, repo.Upsert(ctx, &domain.RecipeTranslation{
+		ID: "t3", RecipeID: "r1", Language: "de", Title: "Pfannkuchen",
+		Ingredients: []domain.TranslatedIngredient{{IngredientID: "i1", Name: "Mehl"}},
+	}))
+
+	got, err := repo.ListByRecipeID(ctx, "r1", []string{"de"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Pfannkuchen", got[0].Title)
+	assert.Equal(t, "Mehl", got[0].Ingredients[0].Name)
+
+	all, err := repo.ListByRecipeID(ctx, "r1", []string{"de", "fr"})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
@@ -3,21 +3,47 @@ package repository
 import "gorm.io/gorm"
 
 type Repositories struct {
-	UserRepository         UserRepository
-	ProfileRepository      ProfileRepository
-	AIConfigRepository     AIConfigRepository
-	RecipeRepository       RecipeRepository
-	ShoppingListRepository ShoppingListRepository
-	StoreChainRepository   StoreChainRepository
+	UserRepository                 UserRepository
+	APITokenRepository             APITokenRepository
+	ProfileRepository              ProfileRepository
+	AIConfigRepository             AIConfigRepository
+	RecipeRepository               RecipeRepository
+	RecipeTranslationRepository    RecipeTranslationRepository
+	RecipeEditProposalRepository   RecipeEditProposalRepository
+	ShoppingListRepository         ShoppingListRepository
+	ShoppingListTemplateRepository ShoppingListTemplateRepository
+	ShoppingListShareRepository    ShoppingListShareRepository
+	ShoppingTripRepository         ShoppingTripRepository
+	StoreChainRepository           StoreChainRepository
+	StoreRepository                StoreRepository
+	CategoryRepository             CategoryRepository
+	ProductRepository              ProductRepository
+	ItemPriceRepository            ItemPriceRepository
+	AuditRepository                AuditRepository
+	DataExportRepository           DataExportRepository
+	EmailOutboxRepository          EmailOutboxRepository
 }
 
 func NewRepositories(db *gorm.DB) *Repositories {
 	return &Repositories{
-		UserRepository:         NewUserRepository(db),
-		ProfileRepository:      NewProfileRepository(db),
-		AIConfigRepository:     NewAIConfigRepository(db),
-		RecipeRepository:       NewRecipeRepository(db),
-		ShoppingListRepository: NewShoppingListRepository(db),
-		StoreChainRepository:   NewStoreChainRepository(db),
+		UserRepository:                 NewUserRepository(db),
+		APITokenRepository:             NewAPITokenRepository(db),
+		ProfileRepository:              NewProfileRepository(db),
+		AIConfigRepository:             NewAIConfigRepository(db),
+		RecipeRepository:               NewRecipeRepository(db),
+		RecipeTranslationRepository:    NewRecipeTranslationRepository(db),
+		RecipeEditProposalRepository:   NewRecipeEditProposalRepository(db),
+		ShoppingListRepository:         NewShoppingListRepository(db),
+		ShoppingListTemplateRepository: NewShoppingListTemplateRepository(db),
+		ShoppingListShareRepository:    NewShoppingListShareRepository(db),
+		ShoppingTripRepository:         NewShoppingTripRepository(db),
+		StoreChainRepository:           NewStoreChainRepository(db),
+		StoreRepository:                NewStoreRepository(db),
+		CategoryRepository:             NewCategoryRepository(db),
+		ProductRepository:              NewProductRepository(db),
+		ItemPriceRepository:            NewItemPriceRepository(db),
+		AuditRepository:                NewAuditRepository(db),
+		DataExportRepository:           NewDataExportRepository(db),
+		EmailOutboxRepository:          NewEmailOutboxRepository(db),
 	}
 }
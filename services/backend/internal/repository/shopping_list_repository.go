@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
+
 	"github.com/H3nSte1n/recipe/internal/domain"
 	"gorm.io/gorm"
 )
@@ -14,8 +17,46 @@ type ShoppingListRepository interface {
 	Delete(ctx context.Context, id string) error
 	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingList, error)
 	AddItems(ctx context.Context, items []domain.ShoppingListItem) error
-	UpdateItem(ctx context.Context, item *domain.ShoppingListItem) error
+	// UpdateItemFields sets only the given columns of the item, so what
+	// others changed in its other columns meanwhile is kept. An item that was
+	// trashed or cleared in the meantime is not found rather than brought back.
+	UpdateItemFields(ctx context.Context, id string, fields map[string]interface{}) error
 	DeleteItem(ctx context.Context, id string) error
+	// ArchiveCheckedItems takes the list's checked items off it and returns
+	// how many went. Archived items are not in the trash; they are only read
+	// back as history.
+	ArchiveCheckedItems(ctx context.Context, listID string) (int64, error)
+	// ListTrashed returns the user's shopping lists in the trash with the
+	// items they were deleted with, most recently deleted first.
+	ListTrashed(ctx context.Context, userID string) ([]domain.ShoppingList, error)
+	// ListArchivedItems returns the items cleared off the user's lists, most
+	// recently cleared first.
+	ListArchivedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error)
+	// ListTrashedItems returns items in the trash from the user's lists that
+	// are not in the trash themselves; those come back with their list.
+	ListTrashedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error)
+	// ListItemHistory returns the items with one of keys, lower-cased and
+	// trimmed, put on the user's lists since since, newest first. Archived
+	// items and items and lists in the trash count; they were still shopped
+	// for.
+	ListItemHistory(ctx context.Context, userID string, since time.Time, keys []string) ([]domain.ShoppingListItem, error)
+	// RankItemHistory returns up to limit items put on the user's lists since
+	// since whose name contains q, best match first: names starting with q,
+	// then names with a word starting with q, then the rest. Within each,
+	// items on more lists and then more recently used come first.
+	RankItemHistory(ctx context.Context, userID string, since time.Time, q string, limit int) ([]domain.ItemUsage, error)
+	// ListBoughtTogether returns up to limit items that were on at least
+	// minLists of the user's lists since since along with an item with one of
+	// keys, leaving out listID and the keys themselves. Items on more such
+	// lists come first.
+	ListBoughtTogether(ctx context.Context, userID string, listID string, keys []string, since time.Time, minLists int, limit int) ([]domain.ItemUsage, error)
+	GetTrashedByID(ctx context.Context, id string) (*domain.ShoppingList, error)
+	GetTrashedItemByID(ctx context.Context, id string) (*domain.ShoppingListItem, error)
+	Restore(ctx context.Context, id string) error
+	RestoreItem(ctx context.Context, id string) error
+	// PurgeTrashedBefore permanently deletes lists and items of any user that
+	// were deleted before cutoff, and returns how many rows went.
+	PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error)
 	WithTypedTransaction(ctx context.Context, fn func(ShoppingListRepository) error) error
 }
 
@@ -39,8 +80,9 @@ func (r *ShoppingListRepositoryImpl) WithTypedTransaction(ctx context.Context, f
 func (r *ShoppingListRepositoryImpl) GetByID(ctx context.Context, listID string) (*domain.ShoppingList, error) {
 	var list domain.ShoppingList
 	if err := r.DB.WithContext(ctx).
-		Preload("Items").
+		Preload("Items", "archived_at IS NULL").
 		Preload("StoreChain").
+		Preload("Store").
 		First(&list, "id = ?", listID).Error; err != nil {
 		return nil, err
 	}
@@ -49,7 +91,7 @@ func (r *ShoppingListRepositoryImpl) GetByID(ctx context.Context, listID string)
 
 func (r *ShoppingListRepositoryImpl) GetItemByID(ctx context.Context, itemID string) (*domain.ShoppingListItem, error) {
 	var item domain.ShoppingListItem
-	if err := r.DB.WithContext(ctx).First(&item, "id = ?", itemID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&item, "id = ? AND archived_at IS NULL", itemID).Error; err != nil {
 		return nil, err
 	}
 	return &item, nil
@@ -70,8 +112,9 @@ func (r *ShoppingListRepositoryImpl) Delete(ctx context.Context, id string) erro
 func (r *ShoppingListRepositoryImpl) ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingList, error) {
 	var lists []domain.ShoppingList
 	if err := r.DB.WithContext(ctx).
-		Preload("Items").
+		Preload("Items", "archived_at IS NULL").
 		Preload("StoreChain").
+		Preload("Store").
 		Where("user_id = ?", userID).
 		Find(&lists).Error; err != nil {
 		return nil, err
@@ -83,10 +126,174 @@ func (r *ShoppingListRepositoryImpl) AddItems(ctx context.Context, items []domai
 	return r.DB.WithContext(ctx).Create(items).Error
 }
 
-func (r *ShoppingListRepositoryImpl) UpdateItem(ctx context.Context, item *domain.ShoppingListItem) error {
-	return r.DB.WithContext(ctx).Save(item).Error
+func (r *ShoppingListRepositoryImpl) UpdateItemFields(ctx context.Context, id string, fields map[string]interface{}) error {
+	result := r.DB.WithContext(ctx).Model(&domain.ShoppingListItem{}).
+		Where("id = ? AND archived_at IS NULL", id).
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (r *ShoppingListRepositoryImpl) DeleteItem(ctx context.Context, id string) error {
 	return r.DB.WithContext(ctx).Where("id = ?", id).Delete(&domain.ShoppingListItem{}).Error
 }
+
+func (r *ShoppingListRepositoryImpl) ArchiveCheckedItems(ctx context.Context, listID string) (int64, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.ShoppingListItem{}).
+		Where("list_id = ? AND is_checked AND archived_at IS NULL", listID).
+		Update("archived_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *ShoppingListRepositoryImpl) ListTrashed(ctx context.Context, userID string) ([]domain.ShoppingList, error) {
+	var lists []domain.ShoppingList
+	err := r.DB.WithContext(ctx).Unscoped().
+		Preload("Items", "archived_at IS NULL").
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&lists).Error
+	return lists, err
+}
+
+func (r *ShoppingListRepositoryImpl) ListArchivedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error) {
+	var items []domain.ShoppingListItem
+	err := r.DB.WithContext(ctx).Unscoped().
+		Joins("JOIN shopping_lists ON shopping_lists.id = shopping_list_items.list_id").
+		Where("shopping_lists.user_id = ? AND shopping_list_items.archived_at IS NOT NULL", userID).
+		Order("shopping_list_items.archived_at DESC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *ShoppingListRepositoryImpl) ListTrashedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error) {
+	var items []domain.ShoppingListItem
+	err := r.DB.WithContext(ctx).Unscoped().
+		Joins("JOIN shopping_lists ON shopping_lists.id = shopping_list_items.list_id").
+		Where("shopping_lists.user_id = ? AND shopping_lists.deleted_at IS NULL", userID).
+		Where("shopping_list_items.deleted_at IS NOT NULL").
+		Order("shopping_list_items.deleted_at DESC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *ShoppingListRepositoryImpl) ListItemHistory(ctx context.Context, userID string, since time.Time, keys []string) ([]domain.ShoppingListItem, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var items []domain.ShoppingListItem
+	err := r.DB.WithContext(ctx).Unscoped().
+		Joins("JOIN shopping_lists ON shopping_lists.id = shopping_list_items.list_id").
+		Where("shopping_lists.user_id = ? AND shopping_list_items.created_at >= ?", userID, since).
+		Where("lower(trim(shopping_list_items.name)) IN ?", keys).
+		Order("shopping_list_items.created_at DESC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *ShoppingListRepositoryImpl) RankItemHistory(ctx context.Context, userID string, since time.Time, q string, limit int) ([]domain.ItemUsage, error) {
+	q = escapeLike(q)
+	// Items are grouped in a subquery so the ORDER BY can use the group's
+	// columns in expressions, which Postgres does not allow for aliases.
+	var usage []domain.ItemUsage
+	err := r.DB.WithContext(ctx).Raw(`
+		SELECT item_key, lists FROM (
+			SELECT lower(trim(i.name)) AS item_key, COUNT(DISTINCT i.list_id) AS lists, MAX(i.created_at) AS last_used_at
+			FROM shopping_list_items i
+			JOIN shopping_lists l ON l.id = i.list_id
+			WHERE l.user_id = ? AND i.created_at >= ? AND lower(trim(i.name)) LIKE ? ESCAPE '\'
+			GROUP BY lower(trim(i.name))
+		) usage
+		ORDER BY CASE
+			WHEN item_key LIKE ? ESCAPE '\' THEN 0
+			WHEN item_key LIKE ? ESCAPE '\' OR item_key LIKE ? ESCAPE '\' THEN 1
+			ELSE 2
+		END, lists DESC, last_used_at DESC, item_key
+		LIMIT ?
+	`, userID, since, "%"+q+"%", q+"%", "% "+q+"%", "%-"+q+"%", limit).Scan(&usage).Error
+	return usage, err
+}
+
+func (r *ShoppingListRepositoryImpl) ListBoughtTogether(ctx context.Context, userID string, listID string, keys []string, since time.Time, minLists int, limit int) ([]domain.ItemUsage, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var usage []domain.ItemUsage
+	err := r.DB.WithContext(ctx).Raw(`
+		SELECT item_key, lists FROM (
+			SELECT lower(trim(i.name)) AS item_key, COUNT(DISTINCT i.list_id) AS lists, MAX(i.created_at) AS last_used_at
+			FROM shopping_list_items i
+			JOIN shopping_lists l ON l.id = i.list_id
+			WHERE l.user_id = ? AND i.created_at >= ? AND i.list_id <> ?
+				AND trim(i.name) <> '' AND lower(trim(i.name)) NOT IN ?
+				AND i.list_id IN (
+					SELECT s.list_id FROM shopping_list_items s
+					WHERE lower(trim(s.name)) IN ? AND s.created_at >= ?
+				)
+			GROUP BY lower(trim(i.name))
+			HAVING COUNT(DISTINCT i.list_id) >= ?
+		) together
+		ORDER BY lists DESC, last_used_at DESC, item_key
+		LIMIT ?
+	`, userID, since, listID, keys, keys, since, minLists, limit).Scan(&usage).Error
+	return usage, err
+}
+
+func (r *ShoppingListRepositoryImpl) GetTrashedByID(ctx context.Context, id string) (*domain.ShoppingList, error) {
+	var list domain.ShoppingList
+	if err := r.DB.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *ShoppingListRepositoryImpl) GetTrashedItemByID(ctx context.Context, id string) (*domain.ShoppingListItem, error) {
+	var item domain.ShoppingListItem
+	if err := r.DB.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *ShoppingListRepositoryImpl) Restore(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Unscoped().Model(&domain.ShoppingList{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil).Error
+}
+
+func (r *ShoppingListRepositoryImpl) RestoreItem(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Unscoped().Model(&domain.ShoppingListItem{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil).Error
+}
+
+func (r *ShoppingListRepositoryImpl) PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var purged int64
+	err := r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		items := tx.Unscoped().Where("deleted_at < ?", cutoff).Delete(&domain.ShoppingListItem{})
+		if items.Error != nil {
+			return items.Error
+		}
+		// Items still on a purged list go with it through ON DELETE CASCADE.
+		lists := tx.Unscoped().Where("deleted_at < ?", cutoff).Delete(&domain.ShoppingList{})
+		if lists.Error != nil {
+			return lists.Error
+		}
+		purged = items.RowsAffected + lists.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// escapeLike escapes the LIKE wildcards in s, for patterns using ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShoppingListRepository_ListItemHistory(t *testing.T) {
+	repo, db := newTestShoppingListRepository(t)
+	ctx := context.Background()
+
+	for _, list := range []domain.ShoppingList{
+		{ID: "old", UserID: "user-1", Name: "Old", SortType: domain.SortTypeCategory},
+		{ID: "binned", UserID: "user-1", Name: "Binned", SortType: domain.SortTypeCategory},
+		{ID: "theirs", UserID: "user-2", Name: "Theirs", SortType: domain.SortTypeCategory},
+	} {
+		require.NoError(t, repo.Create(ctx, &list))
+	}
+	require.NoError(t, repo.AddItems(ctx, []domain.ShoppingListItem{
+		{ID: "old-milk", ListID: "old", Name: "Milk", Category: domain.CategoryDairy},
+		{ID: "binned-eggs", ListID: "binned", Name: "Eggs", Category: domain.CategoryDairy},
+		{ID: "binned-bread", ListID: "binned", Name: " Bread", Category: domain.CategoryBakery, IsChecked: true},
+		{ID: "binned-milk", ListID: "binned", Name: "Milk", Category: domain.CategoryDairy},
+		{ID: "theirs-milk", ListID: "theirs", Name: "Milk", Category: domain.CategoryDairy},
+	}))
+	since := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Exec("UPDATE shopping_list_items SET created_at = ? WHERE id = ?", since.Add(-time.Hour), "old-milk").Error)
+	_, err := repo.ArchiveCheckedItems(ctx, "binned")
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, "binned"))
+
+	items, err := repo.ListItemHistory(ctx, "user-1", since, []string{"bread", "milk"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"binned-bread", "binned-milk"}, []string{items[0].ID, items[1].ID})
+	require.Len(t, items, 2)
+
+	items, err = repo.ListItemHistory(ctx, "user-1", since, nil)
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestShoppingListRepository_RankItemHistory(t *testing.T) {
+	repo, db := newTestShoppingListRepository(t)
+	ctx := context.Background()
+
+	for _, list := range []domain.ShoppingList{
+		{ID: "l1", UserID: "user-1", Name: "One", SortType: domain.SortTypeCategory},
+		{ID: "l2", UserID: "user-1", Name: "Two", SortType: domain.SortTypeCategory},
+		{ID: "l3", UserID: "user-1", Name: "Three", SortType: domain.SortTypeCategory},
+		{ID: "theirs", UserID: "user-2", Name: "Theirs", SortType: domain.SortTypeCategory},
+	} {
+		require.NoError(t, repo.Create(ctx, &list))
+	}
+	require.NoError(t, repo.AddItems(ctx, []domain.ShoppingListItem{
+		{ID: "1-milk", ListID: "l1", Name: "Milk", Category: domain.CategoryDairy},
+		{ID: "1-millet", ListID: "l1", Name: "Millet", Category: domain.CategoryPantry},
+		{ID: "2-milk", ListID: "l2", Name: "milk ", Category: domain.CategoryDairy},
+		{ID: "2-milk-again", ListID: "l2", Name: "Milk", Category: domain.CategoryDairy},
+		{ID: "2-oat-milk", ListID: "l2", Name: "Oat milk", Category: domain.CategoryBeverages},
+		{ID: "3-buttermilk", ListID: "l3", Name: "Buttermilk", Category: domain.CategoryDairy},
+		{ID: "3-buttermilk-2", ListID: "l2", Name: "Buttermilk", Category: domain.CategoryDairy},
+		{ID: "3-mildew", ListID: "l3", Name: "Mildew spray", Category: domain.CategoryHousehold},
+		{ID: "3-percent", ListID: "l3", Name: "100% juice", Category: domain.CategoryBeverages},
+		{ID: "theirs-milkshake", ListID: "theirs", Name: "Milkshake", Category: domain.CategoryBeverages},
+	}))
+	since := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Exec("UPDATE shopping_list_items SET created_at = ? WHERE id = ?", since.Add(-time.Hour), "3-mildew").Error)
+
+	usage, err := repo.RankItemHistory(ctx, "user-1", since, "mil", 10)
+	require.NoError(t, err)
+	// Starting with "mil" first, by lists; then a word starting with it; then
+	// containing it, even on more lists. Each list counts once.
+	require.Equal(t, []domain.ItemUsage{
+		{Key: "milk", Lists: 2}, {Key: "millet", Lists: 1}, {Key: "oat milk", Lists: 1}, {Key: "buttermilk", Lists: 2},
+	}, usage)
+
+	usage, err = repo.RankItemHistory(ctx, "user-1", since, "mil", 1)
+	require.NoError(t, err)
+	require.Equal(t, []domain.ItemUsage{{Key: "milk", Lists: 2}}, usage)
+
+	// Wildcards in q are matched literally.
+	usage, err = repo.RankItemHistory(ctx, "user-1", since, "0%", 10)
+	require.NoError(t, err)
+	require.Equal(t, []domain.ItemUsage{{Key: "100% juice", Lists: 1}}, usage)
+	usage, err = repo.RankItemHistory(ctx, "user-1", since, "m_lk", 10)
+	require.NoError(t, err)
+	require.Empty(t, usage)
+}
+
+func TestShoppingListRepository_ListBoughtTogether(t *testing.T) {
+	repo, _ := newTestShoppingListRepository(t)
+	ctx := context.Background()
+
+	for _, list := range []domain.ShoppingList{
+		{ID: "current", UserID: "user-1", Name: "Current", SortType: domain.SortTypeCategory},
+		{ID: "l1", UserID: "user-1", Name: "One", SortType: domain.SortTypeCategory},
+		{ID: "l2", UserID: "user-1", Name: "Two", SortType: domain.SortTypeCategory},
+		{ID: "l3", UserID: "user-1", Name: "Three", SortType: domain.SortTypeCategory},
+		{ID: "theirs", UserID: "user-2", Name: "Theirs", SortType: domain.SortTypeCategory},
+	} {
+		require.NoError(t, repo.Create(ctx, &list))
+	}
+	require.NoError(t, repo.AddItems(ctx, []domain.ShoppingListItem{
+		{ID: "c-pasta", ListID: "current", Name: "Pasta", Category: domain.CategoryPantry},
+		{ID: "c-basil", ListID: "current", Name: "Basil", Category: domain.CategoryProduce},
+		{ID: "3-pasta", ListID: "l3", Name: "Pasta", Category: domain.CategoryPantry},
+		{ID: "3-tomatoes", ListID: "l3", Name: "Tomatoes", Category: domain.CategoryProduce},
+		{ID: "3-parmesan", ListID: "l3", Name: "Parmesan", Category: domain.CategoryDairy},
+		{ID: "2-pasta", ListID: "l2", Name: "Pasta", Category: domain.CategoryPantry},
+		{ID: "2-tomatoes", ListID: "l2", Name: "Tomatoes", Category: domain.CategoryProduce},
+		{ID: "2-basil", ListID: "l2", Name: "Basil", Category: domain.CategoryProduce},
+		{ID: "1-tomatoes", ListID: "l1", Name: "Tomatoes", Category: domain.CategoryProduce},
+		{ID: "1-parmesan", ListID: "l1", Name: "Parmesan", Category: domain.CategoryDairy},
+		{ID: "t-pasta", ListID: "theirs", Name: "Pasta", Category: domain.CategoryPantry},
+		{ID: "t-parmesan", ListID: "theirs", Name: "Parmesan", Category: domain.CategoryDairy},
+	}))
+	since := time.Now().Add(-time.Hour)
+
+	// Parmesan was only once on a list with pasta or basil of the user's own;
+	// l1 shares nothing with the current list.
+	usage, err := repo.ListBoughtTogether(ctx, "user-1", "current", []string{"pasta", "basil"}, since, 2, 10)
+	require.NoError(t, err)
+	require.Equal(t, []domain.ItemUsage{{Key: "tomatoes", Lists: 2}}, usage)
+
+	usage, err = repo.ListBoughtTogether(ctx, "user-1", "current", []string{"pasta", "basil"}, since, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []domain.ItemUsage{{Key: "tomatoes", Lists: 2}}, usage)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestShoppingListRepository(t *testing.T) (ShoppingListRepository, *gorm.DB) {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE shopping_lists (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, name TEXT NOT NULL, description TEXT,
+		sort_type TEXT NOT NULL DEFAULT 'CATEGORY', sort_order TEXT NOT NULL DEFAULT '', store_chain_id TEXT, store_id TEXT,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE shopping_list_items (
+		id TEXT PRIMARY KEY, list_id TEXT NOT NULL, recipe_id TEXT, name TEXT NOT NULL, amount REAL, unit TEXT,
+		category TEXT NOT NULL, is_checked BOOLEAN DEFAULT false, notes TEXT, position INTEGER,
+		created_at DATETIME, updated_at DATETIME, deleted_at DATETIME, archived_at DATETIME)`).Error)
+	return NewShoppingListRepository(db), db
+}
+
+func TestShoppingListRepository_Trash(t *testing.T) {
+	repo, db := newTestShoppingListRepository(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"kept", "binned"} {
+		require.NoError(t, repo.Create(ctx, &domain.ShoppingList{ID: id, UserID: "user-1", Name: id, SortType: domain.SortTypeCategory}))
+		require.NoError(t, repo.AddItems(ctx, []domain.ShoppingListItem{
+			{ID: id + "-milk", ListID: id, Name: "Milk", Category: domain.CategoryDairy},
+			{ID: id + "-eggs", ListID: id, Name: "Eggs", Category: domain.CategoryDairy},
+		}))
+	}
+	require.NoError(t, repo.DeleteItem(ctx, "kept-milk"))
+	require.NoError(t, repo.DeleteItem(ctx, "binned-milk"))
+	require.NoError(t, repo.Delete(ctx, "binned"))
+
+	list, err := repo.GetByID(ctx, "kept")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	lists, err := repo.ListTrashed(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	require.Equal(t, "binned", lists[0].ID)
+
+	// binned-milk comes back with its list, so only kept-milk is listed.
+	items, err := repo.ListTrashedItems(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "kept-milk", items[0].ID)
+
+	require.NoError(t, repo.RestoreItem(ctx, "kept-milk"))
+	list, err = repo.GetByID(ctx, "kept")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+
+	require.NoError(t, db.Exec(`UPDATE shopping_lists SET deleted_at = ? WHERE id = 'binned'`, time.Now().Add(-48*time.Hour)).Error)
+	purged, err := repo.PurgeTrashedBefore(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, purged)
+	_, err = repo.GetTrashedByID(ctx, "binned")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestShoppingListRepository_ArchiveCheckedItems(t *testing.T) {
+	repo, db := newTestShoppingListRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &domain.ShoppingList{ID: "list-1", UserID: "user-1", Name: "Weekly", SortType: domain.SortTypeCategory}))
+	require.NoError(t, repo.AddItems(ctx, []domain.ShoppingListItem{
+		{ID: "milk", ListID: "list-1", Name: "Milk", Category: domain.CategoryDairy, IsChecked: true},
+		{ID: "eggs", ListID: "list-1", Name: "Eggs", Category: domain.CategoryDairy},
+		{ID: "bread", ListID: "list-1", Name: "Bread", Category: domain.CategoryBakery, IsChecked: true},
+	}))
+
+	cleared, err := repo.ArchiveCheckedItems(ctx, "list-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), cleared)
+
+	list, err := repo.GetByID(ctx, "list-1")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "eggs", list.Items[0].ID)
+	_, err = repo.GetItemByID(ctx, "milk")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// Cleared items stay out of the trash and its purge.
+	trashed, err := repo.ListTrashedItems(ctx, "user-1")
+	require.NoError(t, err)
+	require.Empty(t, trashed)
+	require.NoError(t, db.Exec(`UPDATE shopping_list_items SET archived_at = ? WHERE archived_at IS NOT NULL`, time.Now().Add(-400*24*time.Hour)).Error)
+	purged, err := repo.PurgeTrashedBefore(ctx, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, purged)
+
+	archived, err := repo.ListArchivedItems(ctx, "user-1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"milk", "bread"}, []string{archived[0].ID, archived[1].ID})
+	require.Len(t, archived, 2)
+
+	// Unchecking a cleared item from a stale view leaves it archived.
+	err = repo.UpdateItemFields(ctx, "milk", map[string]interface{}{"is_checked": false})
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	archived, err = repo.ListArchivedItems(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, archived, 2)
+}
+
+func TestShoppingListRepository_UpdateItemFields(t *testing.T) {
+	repo, db := newTestShoppingListRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &domain.ShoppingList{ID: "list-1", UserID: "user-1", Name: "Weekly", SortType: domain.SortTypeCategory}))
+	require.NoError(t, repo.AddItems(ctx, []domain.ShoppingListItem{{ID: "milk", ListID: "list-1", Name: "Milk", Category: domain.CategoryDairy}}))
+	// Someone renames the item after the batch read it.
+	require.NoError(t, db.Exec(`UPDATE shopping_list_items SET name = 'Oat milk' WHERE id = 'milk'`).Error)
+
+	require.NoError(t, repo.UpdateItemFields(ctx, "milk", map[string]interface{}{"is_checked": true}))
+
+	item, err := repo.GetItemByID(ctx, "milk")
+	require.NoError(t, err)
+	require.True(t, item.IsChecked)
+	require.Equal(t, "Oat milk", item.Name)
+	require.Equal(t, domain.CategoryDairy, item.Category)
+
+	// Toggling an item someone trashed meanwhile does not bring it back.
+	require.NoError(t, repo.DeleteItem(ctx, "milk"))
+	err = repo.UpdateItemFields(ctx, "milk", map[string]interface{}{"is_checked": false})
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = repo.GetItemByID(ctx, "milk")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ShoppingListShareRepository interface {
+	Create(ctx context.Context, share *domain.ShoppingListShare) error
+	GetByID(ctx context.Context, id string) (*domain.ShoppingListShare, error)
+	// ListByListID returns the list's shares, revoked ones included, newest
+	// first.
+	ListByListID(ctx context.Context, listID string) ([]domain.ShoppingListShare, error)
+	// ListByUserID returns the shares the user made of any list, revoked ones
+	// included, newest first.
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListShare, error)
+	// Revoke disables the share at revokedAt. It reports false when the share
+	// was already revoked.
+	Revoke(ctx context.Context, id string, revokedAt time.Time) (bool, error)
+}
+
+type ShoppingListShareRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewShoppingListShareRepository(db *gorm.DB) ShoppingListShareRepository {
+	return &ShoppingListShareRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *ShoppingListShareRepositoryImpl) Create(ctx context.Context, share *domain.ShoppingListShare) error {
+	return r.DB.WithContext(ctx).Create(share).Error
+}
+
+func (r *ShoppingListShareRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.ShoppingListShare, error) {
+	var share domain.ShoppingListShare
+	if err := r.DB.WithContext(ctx).First(&share, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *ShoppingListShareRepositoryImpl) ListByListID(ctx context.Context, listID string) ([]domain.ShoppingListShare, error) {
+	var shares []domain.ShoppingListShare
+	err := r.DB.WithContext(ctx).
+		Where("list_id = ?", listID).
+		Order("created_at DESC").
+		Find(&shares).Error
+	return shares, err
+}
+
+func (r *ShoppingListShareRepositoryImpl) ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListShare, error) {
+	var shares []domain.ShoppingListShare
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&shares).Error
+	return shares, err
+}
+
+func (r *ShoppingListShareRepositoryImpl) Revoke(ctx context.Context, id string, revokedAt time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).
+		Model(&domain.ShoppingListShare{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShoppingListShareRepository_RevokeOnce(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE shopping_list_shares (
+		id TEXT PRIMARY KEY, list_id TEXT NOT NULL, user_id TEXT NOT NULL, access TEXT NOT NULL,
+		expires_at DATETIME, revoked_at DATETIME, created_at DATETIME, updated_at DATETIME)`).Error)
+	repo := NewShoppingListShareRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &domain.ShoppingListShare{ID: "s1", ListID: "list-1", UserID: "user-1", Access: domain.ShareAccessView}))
+	require.NoError(t, repo.Create(ctx, &domain.ShoppingListShare{ID: "s2", ListID: "list-1", UserID: "user-1", Access: domain.ShareAccessCheck}))
+	require.NoError(t, repo.Create(ctx, &domain.ShoppingListShare{ID: "s3", ListID: "list-2", UserID: "user-1", Access: domain.ShareAccessView}))
+
+	now := time.Now().UTC()
+	revoked, err := repo.Revoke(ctx, "s1", now)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	revoked, err = repo.Revoke(ctx, "s1", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, revoked)
+
+	share, err := repo.GetByID(ctx, "s1")
+	require.NoError(t, err)
+	require.False(t, share.Active(now))
+
+	shares, err := repo.ListByListID(ctx, "list-1")
+	require.NoError(t, err)
+	require.Len(t, shares, 2)
+
+	shares, err = repo.ListByUserID(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, shares, 3)
+}
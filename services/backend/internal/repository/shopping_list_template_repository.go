@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ShoppingListTemplateRepository interface {
+	// Create saves the template together with its items.
+	Create(ctx context.Context, template *domain.ShoppingListTemplate) error
+	GetByID(ctx context.Context, id string) (*domain.ShoppingListTemplate, error)
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListTemplate, error)
+	// Replace saves the template and swaps its items for template.Items.
+	Replace(ctx context.Context, template *domain.ShoppingListTemplate) error
+	Delete(ctx context.Context, id string) error
+	// ListDue returns the recurring templates of any user whose next run is
+	// at or before now, with their items.
+	ListDue(ctx context.Context, now time.Time) ([]domain.ShoppingListTemplate, error)
+	// ClaimRun moves a template's run scheduled at scheduledAt to next. It
+	// reports false when the run was already claimed, by another instance or
+	// by an edit in the meantime.
+	ClaimRun(ctx context.Context, id string, scheduledAt time.Time, next *time.Time, ranAt time.Time) (bool, error)
+}
+
+type ShoppingListTemplateRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewShoppingListTemplateRepository(db *gorm.DB) ShoppingListTemplateRepository {
+	return &ShoppingListTemplateRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func preloadTemplateItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("position") })
+}
+
+func (r *ShoppingListTemplateRepositoryImpl) Create(ctx context.Context, template *domain.ShoppingListTemplate) error {
+	return r.DB.WithContext(ctx).Create(template).Error
+}
+
+func (r *ShoppingListTemplateRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.ShoppingListTemplate, error) {
+	var template domain.ShoppingListTemplate
+	if err := preloadTemplateItems(r.DB.WithContext(ctx)).First(&template, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *ShoppingListTemplateRepositoryImpl) ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListTemplate, error) {
+	var templates []domain.ShoppingListTemplate
+	err := preloadTemplateItems(r.DB.WithContext(ctx)).
+		Where("user_id = ?", userID).
+		Order("name").
+		Find(&templates).Error
+	return templates, err
+}
+
+func (r *ShoppingListTemplateRepositoryImpl) Replace(ctx context.Context, template *domain.ShoppingListTemplate) error {
+	return r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Omit("Items").Save(template).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", template.ID).Delete(&domain.ShoppingListTemplateItem{}).Error; err != nil {
+			return err
+		}
+		for i := range template.Items {
+			template.Items[i].TemplateID = template.ID
+		}
+		if len(template.Items) == 0 {
+			return nil
+		}
+		return tx.Create(&template.Items).Error
+	})
+}
+
+func (r *ShoppingListTemplateRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Delete(&domain.ShoppingListTemplate{}, "id = ?", id).Error
+}
+
+func (r *ShoppingListTemplateRepositoryImpl) ListDue(ctx context.Context, now time.Time) ([]domain.ShoppingListTemplate, error) {
+	var templates []domain.ShoppingListTemplate
+	err := preloadTemplateItems(r.DB.WithContext(ctx)).
+		Where("next_run_at <= ? AND frequency <> '' AND target_list_id IS NOT NULL", now).
+		Order("next_run_at").
+		Find(&templates).Error
+	return templates, err
+}
+
+func (r *ShoppingListTemplateRepositoryImpl) ClaimRun(ctx context.Context, id string, scheduledAt time.Time, next *time.Time, ranAt time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).
+		Model(&domain.ShoppingListTemplate{}).
+		Where("id = ? AND next_run_at = ?", id, scheduledAt).
+		Updates(map[string]interface{}{"next_run_at": next, "last_run_at": ranAt})
+	return result.RowsAffected > 0, result.Error
+}
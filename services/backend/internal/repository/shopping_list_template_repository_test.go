@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestShoppingListTemplateRepository(t *testing.T) ShoppingListTemplateRepository {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE shopping_list_templates (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, name TEXT NOT NULL, target_list_id TEXT,
+		frequency TEXT NOT NULL DEFAULT '', every INTEGER NOT NULL DEFAULT 1, next_run_at DATETIME, last_run_at DATETIME,
+		created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE shopping_list_template_items (
+		id TEXT PRIMARY KEY, template_id TEXT NOT NULL, name TEXT NOT NULL, amount REAL, unit TEXT,
+		category TEXT NOT NULL, notes TEXT, position INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME, updated_at DATETIME)`).Error)
+	return NewShoppingListTemplateRepository(db)
+}
+
+func TestShoppingListTemplateRepository_DueRunsAreClaimedOnce(t *testing.T) {
+	repo := newTestShoppingListTemplateRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	list := "list-1"
+
+	for _, template := range []domain.ShoppingListTemplate{
+		{ID: "due", UserID: "user-1", Name: "Weekly", TargetListID: &list, Frequency: domain.FrequencyWeekly, Every: 1, NextRunAt: &past,
+			Items: []domain.ShoppingListTemplateItem{{ID: "milk", Name: "Milk", Category: domain.CategoryDairy}}},
+		{ID: "later", UserID: "user-1", Name: "Later", TargetListID: &list, Frequency: domain.FrequencyDaily, Every: 3, NextRunAt: &future},
+		{ID: "manual", UserID: "user-1", Name: "Party", Every: 1},
+	} {
+		require.NoError(t, repo.Create(ctx, &template))
+	}
+
+	due, err := repo.ListDue(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, "due", due[0].ID)
+	require.Len(t, due[0].Items, 1)
+
+	next := due[0].NextRunAfter(*due[0].NextRunAt, now)
+	claimed, err := repo.ClaimRun(ctx, "due", *due[0].NextRunAt, next, now)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	// A second scheduler that saw the same run loses the claim.
+	claimed, err = repo.ClaimRun(ctx, "due", *due[0].NextRunAt, next, now)
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	due, err = repo.ListDue(ctx, now)
+	require.NoError(t, err)
+	require.Empty(t, due)
+}
+
+func TestShoppingListTemplateRepository_Replace(t *testing.T) {
+	repo := newTestShoppingListTemplateRepository(t)
+	ctx := context.Background()
+
+	template := &domain.ShoppingListTemplate{ID: "t1", UserID: "user-1", Name: "Basics", Every: 1, Items: []domain.ShoppingListTemplateItem{
+		{ID: "a", Name: "Milk", Category: domain.CategoryDairy},
+	}}
+	require.NoError(t, repo.Create(ctx, template))
+
+	template.Name = "Staples"
+	template.Items = []domain.ShoppingListTemplateItem{
+		{Name: "Bread", Category: domain.CategoryBakery, Position: 0},
+		{Name: "Eggs", Category: domain.CategoryDairy, Position: 1},
+	}
+	// sqlite has no uuid default, so give the new items their IDs here.
+	for i := range template.Items {
+		template.Items[i].ID = template.Items[i].Name
+	}
+	require.NoError(t, repo.Replace(ctx, template))
+
+	got, err := repo.GetByID(ctx, "t1")
+	require.NoError(t, err)
+	require.Equal(t, "Staples", got.Name)
+	require.Len(t, got.Items, 2)
+	require.Equal(t, "Bread", got.Items[0].Name)
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShoppingTripRepository interface {
+	Create(ctx context.Context, trip *domain.ShoppingTrip) error
+	// GetByID returns the trip with its items in the order they were recorded.
+	GetByID(ctx context.Context, id string) (*domain.ShoppingTrip, error)
+	GetOpenByListID(ctx context.Context, listID string) (*domain.ShoppingTrip, error)
+	// ListByUserID returns the user's trips without items, newest first.
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingTrip, error)
+	// ListWithItems returns all of the user's trips with their items, newest
+	// first.
+	ListWithItems(ctx context.Context, userID string) ([]domain.ShoppingTrip, error)
+	Delete(ctx context.Context, id string) error
+	// SaveItem records item, replacing what the trip already recorded for the
+	// same list item.
+	SaveItem(ctx context.Context, item *domain.ShoppingTripItem) error
+	DeleteItem(ctx context.Context, tripID string, itemID string) (bool, error)
+	// Finish fixes the trip's totals from its items and closes it. It reports
+	// false when the trip was not open.
+	Finish(ctx context.Context, tripID string, finishedAt time.Time) (bool, error)
+	// ListFinished returns the user's trips finished in [from, to) with their
+	// items; zero times leave the range open.
+	ListFinished(ctx context.Context, userID string, from time.Time, to time.Time) ([]domain.ShoppingTrip, error)
+	// ListPrices returns what the user paid for items with any of itemKeys on
+	// finished trips to stores of storeChainID, or to any store when it is
+	// empty, most recent first.
+	ListPrices(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.PricePoint, error)
+}
+
+type ShoppingTripRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewShoppingTripRepository(db *gorm.DB) ShoppingTripRepository {
+	return &ShoppingTripRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *ShoppingTripRepositoryImpl) Create(ctx context.Context, trip *domain.ShoppingTrip) error {
+	return r.DB.WithContext(ctx).Omit("Items").Create(trip).Error
+}
+
+func (r *ShoppingTripRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.ShoppingTrip, error) {
+	var trip domain.ShoppingTrip
+	err := r.DB.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		First(&trip, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trip, nil
+}
+
+func (r *ShoppingTripRepositoryImpl) GetOpenByListID(ctx context.Context, listID string) (*domain.ShoppingTrip, error) {
+	var trip domain.ShoppingTrip
+	err := r.DB.WithContext(ctx).
+		Where("list_id = ? AND finished_at IS NULL", listID).
+		First(&trip).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trip, nil
+}
+
+func (r *ShoppingTripRepositoryImpl) ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingTrip, error) {
+	var trips []domain.ShoppingTrip
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("started_at DESC").
+		Find(&trips).Error
+	return trips, err
+}
+
+func (r *ShoppingTripRepositoryImpl) ListWithItems(ctx context.Context, userID string) ([]domain.ShoppingTrip, error) {
+	var trips []domain.ShoppingTrip
+	err := r.DB.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		Where("user_id = ?", userID).
+		Order("started_at DESC").
+		Find(&trips).Error
+	return trips, err
+}
+
+func (r *ShoppingTripRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Delete(&domain.ShoppingTrip{}, "id = ?", id).Error
+}
+
+func (r *ShoppingTripRepositoryImpl) SaveItem(ctx context.Context, item *domain.ShoppingTripItem) error {
+	db := r.DB.WithContext(ctx)
+	if item.ListItemID != nil {
+		db = db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "trip_id"}, {Name: "list_item_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "item_key", "category", "quantity", "unit", "price_cents", "updated_at"}),
+		})
+	}
+	return db.Create(item).Error
+}
+
+func (r *ShoppingTripRepositoryImpl) DeleteItem(ctx context.Context, tripID string, itemID string) (bool, error) {
+	result := r.DB.WithContext(ctx).
+		Where("id = ? AND trip_id = ?", itemID, tripID).
+		Delete(&domain.ShoppingTripItem{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ShoppingTripRepositoryImpl) Finish(ctx context.Context, tripID string, finishedAt time.Time) (bool, error) {
+	items := r.DB.Model(&domain.ShoppingTripItem{}).Where("trip_id = ?", tripID)
+	result := r.DB.WithContext(ctx).
+		Model(&domain.ShoppingTrip{}).
+		Where("id = ? AND finished_at IS NULL", tripID).
+		Updates(map[string]interface{}{
+			"total_cents": items.Session(&gorm.Session{}).Select("COALESCE(SUM(price_cents), 0)"),
+			"item_count":  items.Session(&gorm.Session{}).Select("COUNT(*)"),
+			"finished_at": finishedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ShoppingTripRepositoryImpl) ListFinished(ctx context.Context, userID string, from time.Time, to time.Time) ([]domain.ShoppingTrip, error) {
+	db := r.DB.WithContext(ctx).
+		Preload("Items").
+		Where("user_id = ? AND finished_at IS NOT NULL", userID)
+	if !from.IsZero() {
+		db = db.Where("finished_at >= ?", from)
+	}
+	if !to.IsZero() {
+		db = db.Where("finished_at < ?", to)
+	}
+
+	var trips []domain.ShoppingTrip
+	err := db.Order("finished_at").Find(&trips).Error
+	return trips, err
+}
+
+func (r *ShoppingTripRepositoryImpl) ListPrices(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.PricePoint, error) {
+	if len(itemKeys) == 0 {
+		return nil, nil
+	}
+
+	var prices []domain.PricePoint
+	db := r.DB.WithContext(ctx).
+		Table("shopping_trip_items AS i").
+		Select("i.item_key, i.name, i.quantity, i.unit, i.price_cents, t.currency, t.store_name, t.finished_at AS paid_at").
+		Joins("JOIN shopping_trips t ON t.id = i.trip_id").
+		Where("t.user_id = ? AND t.finished_at IS NOT NULL AND i.item_key IN ?", userID, itemKeys)
+	if storeChainID != "" {
+		db = db.Joins("JOIN stores s ON s.id = t.store_id").Where("s.store_chain_id = ?", storeChainID)
+	}
+	err := db.Order("t.finished_at DESC, i.created_at DESC").Scan(&prices).Error
+	return prices, err
+}
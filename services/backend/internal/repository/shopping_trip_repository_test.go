@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestShoppingTripRepository(t *testing.T) (ShoppingTripRepository, *gorm.DB) {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE shopping_trips (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, list_id TEXT, store_id TEXT, store_name TEXT,
+		currency TEXT NOT NULL, total_cents INTEGER NOT NULL DEFAULT 0, item_count INTEGER NOT NULL DEFAULT 0,
+		started_at DATETIME, finished_at DATETIME, created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE shopping_trip_items (
+		id TEXT PRIMARY KEY, trip_id TEXT NOT NULL, list_item_id TEXT, name TEXT NOT NULL, item_key TEXT NOT NULL,
+		category TEXT NOT NULL, quantity REAL, unit TEXT, price_cents INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME, updated_at DATETIME, UNIQUE (trip_id, list_item_id))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE stores (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, store_chain_id TEXT, name TEXT NOT NULL,
+		layout TEXT NOT NULL DEFAULT '[]', created_at DATETIME, updated_at DATETIME)`).Error)
+	return NewShoppingTripRepository(db), db
+}
+
+func TestShoppingTripRepository_RecordAndFinish(t *testing.T) {
+	repo, _ := newTestShoppingTripRepository(t)
+	ctx := context.Background()
+	listItem := "item-1"
+
+	require.NoError(t, repo.Create(ctx, &domain.ShoppingTrip{ID: "t1", UserID: "user-1", Currency: "EUR", StartedAt: time.Now()}))
+	require.NoError(t, repo.SaveItem(ctx, &domain.ShoppingTripItem{ID: "i1", TripID: "t1", ListItemID: &listItem, Name: "Milk", ItemKey: "milk", Category: domain.CategoryDairy, PriceCents: 99}))
+	// Recording the list item again replaces the first price.
+	require.NoError(t, repo.SaveItem(ctx, &domain.ShoppingTripItem{ID: "i2", TripID: "t1", ListItemID: &listItem, Name: "Milk", ItemKey: "milk", Category: domain.CategoryDairy, PriceCents: 129}))
+	require.NoError(t, repo.SaveItem(ctx, &domain.ShoppingTripItem{ID: "i3", TripID: "t1", Name: "Gum", ItemKey: "gum", Category: domain.CategoryOther, PriceCents: 150}))
+
+	finished, err := repo.Finish(ctx, "t1", time.Now())
+	require.NoError(t, err)
+	require.True(t, finished)
+
+	trip, err := repo.GetByID(ctx, "t1")
+	require.NoError(t, err)
+	require.False(t, trip.IsOpen())
+	require.Equal(t, int64(279), trip.TotalCents)
+	require.Equal(t, 2, trip.ItemCount)
+	require.Len(t, trip.Items, 2)
+
+	finished, err = repo.Finish(ctx, "t1", time.Now())
+	require.NoError(t, err)
+	require.False(t, finished, "a finished trip cannot be finished again")
+
+	trips, err := repo.ListWithItems(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, trips, 1)
+	require.Len(t, trips[0].Items, 2)
+}
+
+func TestShoppingTripRepository_ListPrices(t *testing.T) {
+	repo, db := newTestShoppingTripRepository(t)
+	ctx := context.Background()
+	corner := "corner"
+	require.NoError(t, db.Exec(`INSERT INTO stores (id, user_id, store_chain_id, name) VALUES ('corner', 'user-1', 'rewe', 'Corner')`).Error)
+	earlier, later := time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour)
+
+	for _, trip := range []domain.ShoppingTrip{
+		{ID: "old", UserID: "user-1", Currency: "EUR", StoreID: &corner, StoreName: "Corner", StartedAt: earlier},
+		{ID: "new", UserID: "user-1", Currency: "EUR", StoreName: "Market", StartedAt: later},
+		{ID: "open", UserID: "user-1", Currency: "EUR", StartedAt: later},
+		{ID: "other", UserID: "user-2", Currency: "EUR", StartedAt: later},
+	} {
+		require.NoError(t, repo.Create(ctx, &trip))
+		require.NoError(t, repo.SaveItem(ctx, &domain.ShoppingTripItem{ID: trip.ID + "-milk", TripID: trip.ID, Name: "Milk", ItemKey: "milk", Category: domain.CategoryDairy, PriceCents: 100}))
+	}
+	for id, at := range map[string]time.Time{"old": earlier, "new": later, "other": later} {
+		_, err := repo.Finish(ctx, id, at)
+		require.NoError(t, err)
+	}
+
+	prices, err := repo.ListPrices(ctx, "user-1", "", []string{"milk", "bread"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	require.Equal(t, "Market", prices[0].StoreName)
+	require.Equal(t, "Corner", prices[1].StoreName)
+	require.Equal(t, "milk", prices[0].ItemKey)
+	require.Equal(t, "EUR", prices[0].Currency)
+
+	// At a chain, only trips to its stores count.
+	prices, err = repo.ListPrices(ctx, "user-1", "rewe", []string{"milk"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, "Corner", prices[0].StoreName)
+
+	finished, err := repo.ListFinished(ctx, "user-1", later.Add(-time.Hour), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, finished, 1)
+	require.Equal(t, "new", finished[0].ID)
+	require.Len(t, finished[0].Items, 1)
+}
@@ -10,6 +10,10 @@ type StoreChainRepository interface {
 	GetChain(ctx context.Context, chainID string) (*domain.StoreChain, error)
 	GetChainByName(ctx context.Context, name string, country string) (*domain.StoreChain, error)
 	ListChains(ctx context.Context, country string) ([]domain.StoreChain, error)
+	CreateChain(ctx context.Context, chain *domain.StoreChain) error
+	UpdateChain(ctx context.Context, chain *domain.StoreChain) error
+	// DeleteChain reports false when there is no such chain.
+	DeleteChain(ctx context.Context, chainID string) (bool, error)
 }
 
 type StoreChainRepositoryImpl struct {
@@ -57,3 +61,19 @@ func (r *StoreChainRepositoryImpl) ListChains(ctx context.Context, country strin
 	}
 	return chains, nil
 }
+
+func (r *StoreChainRepositoryImpl) CreateChain(ctx context.Context, chain *domain.StoreChain) error {
+	return r.DB.WithContext(ctx).Create(chain).Error
+}
+
+func (r *StoreChainRepositoryImpl) UpdateChain(ctx context.Context, chain *domain.StoreChain) error {
+	return r.DB.WithContext(ctx).Save(chain).Error
+}
+
+func (r *StoreChainRepositoryImpl) DeleteChain(ctx context.Context, chainID string) (bool, error) {
+	result := r.DB.WithContext(ctx).Delete(&domain.StoreChain{}, "id = ?", chainID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StoreRepository interface {
+	Create(ctx context.Context, store *domain.Store) error
+	GetByID(ctx context.Context, id string) (*domain.Store, error)
+	ListByUserID(ctx context.Context, userID string) ([]domain.Store, error)
+	Update(ctx context.Context, store *domain.Store) error
+	Delete(ctx context.Context, id string) error
+	ListPositions(ctx context.Context, storeID string) ([]domain.StorePosition, error)
+	// UpdatePosition applies update to the learned position of key, starting
+	// from a zero position when there is none yet. The row is locked while
+	// update runs so concurrent check-offs are not lost.
+	UpdatePosition(ctx context.Context, storeID, kind, key string, update func(*domain.StorePosition)) error
+}
+
+type StoreRepositoryImpl struct {
+	*BaseRepository
+}
+
+func NewStoreRepository(db *gorm.DB) StoreRepository {
+	return &StoreRepositoryImpl{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+func (r *StoreRepositoryImpl) Create(ctx context.Context, store *domain.Store) error {
+	return r.DB.WithContext(ctx).Create(store).Error
+}
+
+func (r *StoreRepositoryImpl) GetByID(ctx context.Context, id string) (*domain.Store, error) {
+	var store domain.Store
+	if err := r.DB.WithContext(ctx).First(&store, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+func (r *StoreRepositoryImpl) ListByUserID(ctx context.Context, userID string) ([]domain.Store, error) {
+	var stores []domain.Store
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("name").
+		Find(&stores).Error
+	return stores, err
+}
+
+func (r *StoreRepositoryImpl) Update(ctx context.Context, store *domain.Store) error {
+	return r.DB.WithContext(ctx).Save(store).Error
+}
+
+func (r *StoreRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Delete(&domain.Store{}, "id = ?", id).Error
+}
+
+func (r *StoreRepositoryImpl) ListPositions(ctx context.Context, storeID string) ([]domain.StorePosition, error) {
+	var positions []domain.StorePosition
+	err := r.DB.WithContext(ctx).Where("store_id = ?", storeID).Find(&positions).Error
+	return positions, err
+}
+
+func (r *StoreRepositoryImpl) UpdatePosition(ctx context.Context, storeID, kind, key string, update func(*domain.StorePosition)) error {
+	return r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		position := domain.StorePosition{StoreID: storeID, Kind: kind, Key: key}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("store_id = ? AND kind = ? AND key = ?", storeID, kind, key).
+			First(&position).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		update(&position)
+		return tx.Save(&position).Error
+	})
+}
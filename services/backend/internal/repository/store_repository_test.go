@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStoreRepository(t *testing.T) StoreRepository {
+	t.Helper()
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE stores (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, store_chain_id TEXT, name TEXT NOT NULL,
+		layout TEXT NOT NULL DEFAULT '[]', created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE store_positions (
+		store_id TEXT NOT NULL, kind TEXT NOT NULL, key TEXT NOT NULL,
+		position REAL NOT NULL DEFAULT 0, observations INTEGER NOT NULL DEFAULT 0, updated_at DATETIME,
+		PRIMARY KEY (store_id, kind, key))`).Error)
+	return NewStoreRepository(db)
+}
+
+func TestStoreRepository_LayoutRoundTrip(t *testing.T) {
+	repo := newTestStoreRepository(t)
+	ctx := context.Background()
+
+	layout := []domain.StoreSection{{Order: 0, Name: "Fresh", Categories: []domain.Category{domain.CategoryProduce}}}
+	require.NoError(t, repo.Create(ctx, &domain.Store{ID: "s2", UserID: "user-1", Name: "Market", Layout: layout}))
+	require.NoError(t, repo.Create(ctx, &domain.Store{ID: "s1", UserID: "user-1", Name: "Corner", Layout: []domain.StoreSection{}}))
+	require.NoError(t, repo.Create(ctx, &domain.Store{ID: "s3", UserID: "user-2", Name: "Other", Layout: []domain.StoreSection{}}))
+
+	store, err := repo.GetByID(ctx, "s2")
+	require.NoError(t, err)
+	require.Equal(t, layout, store.Layout)
+
+	stores, err := repo.ListByUserID(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, stores, 2)
+	require.Equal(t, "Corner", stores[0].Name)
+}
+
+func TestStoreRepository_UpdatePosition(t *testing.T) {
+	repo := newTestStoreRepository(t)
+	ctx := context.Background()
+	observe := func(p *domain.StorePosition) {
+		p.Observations++
+		p.Position += 0.25
+	}
+
+	// The first update creates the row, later ones change it.
+	require.NoError(t, repo.UpdatePosition(ctx, "s1", domain.StorePositionItem, "milk", observe))
+	require.NoError(t, repo.UpdatePosition(ctx, "s1", domain.StorePositionItem, "milk", observe))
+	require.NoError(t, repo.UpdatePosition(ctx, "s1", domain.StorePositionCategory, "milk", observe))
+	require.NoError(t, repo.UpdatePosition(ctx, "s2", domain.StorePositionItem, "milk", observe))
+
+	positions, err := repo.ListPositions(ctx, "s1")
+	require.NoError(t, err)
+	require.Len(t, positions, 2)
+	for _, p := range positions {
+		switch p.Kind {
+		case domain.StorePositionItem:
+			require.Equal(t, 2, p.Observations)
+			require.InDelta(t, 0.5, p.Position, 1e-9)
+		case domain.StorePositionCategory:
+			require.Equal(t, 1, p.Observations)
+			require.InDelta(t, 0.25, p.Position, 1e-9)
+		}
+	}
+}
@@ -36,8 +36,48 @@ type UserRepository interface {
 	GetVerificationTokenByToken(ctx context.Context, token string) (*domain.EmailVerificationToken, error)
 	MarkVerificationTokenUsed(ctx context.Context, tokenID string) error
 	GetLatestVerificationToken(ctx context.Context, userID string) (*domain.EmailVerificationToken, error)
+	CreateMagicLinkToken(ctx context.Context, token *domain.MagicLinkToken) error
+	// ConsumeMagicLinkToken marks the unexpired, unused token with tokenHash
+	// as used and returns it, or a not-found error.
+	ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLinkToken, error)
 	MarkEmailVerified(ctx context.Context, userID string) error
 	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+	CreateSession(ctx context.Context, session *domain.Session) error
+	GetSessionByID(ctx context.Context, id string) (*domain.Session, error)
+	// RotateSessionToken swaps the session's refresh token hash from oldHash to
+	// newHash and records the client and expiry. It reports false when the
+	// session is revoked or no longer holds oldHash, i.e. the token was already
+	// used.
+	RotateSessionToken(ctx context.Context, id, oldHash, newHash string, client domain.SessionClient, seenAt, expiresAt time.Time) (bool, error)
+	ListActiveSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error)
+	// RevokeSession reports false when userID has no such unrevoked session.
+	RevokeSession(ctx context.Context, userID, sessionID string) (bool, error)
+	RevokeUserSessions(ctx context.Context, userID string) error
+	// RevokeUserAPITokens revokes every personal access token of userID.
+	RevokeUserAPITokens(ctx context.Context, userID string, revokedAt time.Time) error
+	// IsSessionActive reports whether the session exists, is unrevoked and has
+	// not expired.
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	// SetTOTPSecret stores a pending (not yet enabled) encrypted TOTP secret.
+	SetTOTPSecret(ctx context.Context, userID string, encryptedSecret string) error
+	EnableTOTP(ctx context.Context, userID string, enabledAt time.Time, step int64) error
+	DisableTOTP(ctx context.Context, userID string) error
+	// AdvanceTOTPStep records step as the last accepted TOTP step. It reports
+	// false when step is not newer than the last one, i.e. the code was replayed.
+	AdvanceTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	// UseRecoveryCode marks a matching unused code as used and reports whether
+	// there was one.
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error)
+	GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error
+	CreateOIDCState(ctx context.Context, state *domain.OIDCLoginState) error
+	// ConsumeOIDCState marks the unexpired, unused sign-in state with
+	// stateHash as used and returns it, or a not-found error.
+	ConsumeOIDCState(ctx context.Context, stateHash string, now time.Time) (*domain.OIDCLoginState, error)
+	SetRole(ctx context.Context, userID, role string) error
+	// SetAdminLock sets or, with a nil lockedAt, clears the administrator lock.
+	SetAdminLock(ctx context.Context, userID string, lockedAt *time.Time) error
 	WithTypedTransaction(ctx context.Context, fn func(UserRepository) error) error
 }
 
@@ -166,6 +206,32 @@ func (r *UserRepositoryImpl) GetLatestVerificationToken(ctx context.Context, use
 	return &verificationToken, err
 }
 
+func (r *UserRepositoryImpl) CreateMagicLinkToken(ctx context.Context, token *domain.MagicLinkToken) error {
+	return r.DB.WithContext(ctx).Create(token).Error
+}
+
+// ConsumeMagicLinkToken claims the token in the UPDATE itself so a link
+// opened twice at once cannot sign in twice.
+func (r *UserRepositoryImpl) ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLinkToken, error) {
+	var token domain.MagicLinkToken
+	err := r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&domain.MagicLinkToken{}).
+			Where("token_hash = ? AND used = ? AND expires_at > ?", tokenHash, false, now).
+			Update("used", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.First(&token, "token_hash = ?", tokenHash).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
 func (r *UserRepositoryImpl) MarkEmailVerified(ctx context.Context, userID string) error {
 	return r.DB.WithContext(ctx).Model(&domain.User{}).
 		Where("id = ?", userID).
@@ -180,6 +246,183 @@ func (r *UserRepositoryImpl) IsEmailVerified(ctx context.Context, userID string)
 	return user.IsEmailVerified(), nil
 }
 
+func (r *UserRepositoryImpl) CreateSession(ctx context.Context, session *domain.Session) error {
+	return r.DB.WithContext(ctx).Create(session).Error
+}
+
+func (r *UserRepositoryImpl) GetSessionByID(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	if err := r.DB.WithContext(ctx).First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RotateSessionToken matches on the old hash in the UPDATE itself so two
+// concurrent refreshes with the same token cannot both succeed.
+func (r *UserRepositoryImpl) RotateSessionToken(ctx context.Context, id, oldHash, newHash string, client domain.SessionClient, seenAt, expiresAt time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":          newHash,
+			"previous_refresh_token_hash": oldHash,
+			"user_agent":                  client.UserAgent,
+			"ip_address":                  client.IPAddress,
+			"last_seen_at":                seenAt,
+			"expires_at":                  expiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserRepositoryImpl) ListActiveSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *UserRepositoryImpl) RevokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserRepositoryImpl) RevokeUserSessions(ctx context.Context, userID string) error {
+	return r.DB.WithContext(ctx).Model(&domain.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *UserRepositoryImpl) RevokeUserAPITokens(ctx context.Context, userID string, revokedAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&domain.APIToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *UserRepositoryImpl) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *UserRepositoryImpl) SetTOTPSecret(ctx context.Context, userID string, encryptedSecret string) error {
+	return r.DB.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"totp_secret":     encryptedSecret,
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error
+}
+
+func (r *UserRepositoryImpl) EnableTOTP(ctx context.Context, userID string, enabledAt time.Time, step int64) error {
+	return r.DB.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"totp_enabled_at": enabledAt,
+			"totp_last_step":  step,
+		}).Error
+}
+
+func (r *UserRepositoryImpl) DisableTOTP(ctx context.Context, userID string) error {
+	return r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"totp_secret":     "",
+				"totp_enabled_at": nil,
+				"totp_last_step":  0,
+			}).Error
+	})
+}
+
+// AdvanceTOTPStep compares and sets in one UPDATE so two concurrent logins
+// with the same code cannot both succeed.
+func (r *UserRepositoryImpl) AdvanceTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	return r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]domain.RecoveryCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = domain.RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *UserRepositoryImpl) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRepositoryImpl) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	if err := r.DB.WithContext(ctx).First(&identity, "provider = ? AND subject = ?", provider, subject).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *UserRepositoryImpl) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	return r.DB.WithContext(ctx).Create(identity).Error
+}
+
+func (r *UserRepositoryImpl) CreateOIDCState(ctx context.Context, state *domain.OIDCLoginState) error {
+	return r.DB.WithContext(ctx).Create(state).Error
+}
+
+// ConsumeOIDCState claims the state in the UPDATE itself so a callback
+// replayed concurrently cannot redeem it twice.
+func (r *UserRepositoryImpl) ConsumeOIDCState(ctx context.Context, stateHash string, now time.Time) (*domain.OIDCLoginState, error) {
+	var state domain.OIDCLoginState
+	err := r.RunInTransaction(ctx, func(tx *gorm.DB) error {
+		result := tx.Model(&domain.OIDCLoginState{}).
+			Where("state_hash = ? AND used_at IS NULL AND expires_at > ?", stateHash, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.First(&state, "state_hash = ?", stateHash).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
 func (r *UserRepositoryImpl) Delete(ctx context.Context, userID string) error {
 	return r.DB.WithContext(ctx).Delete(&domain.User{ID: userID}).Error
 }
@@ -192,6 +435,18 @@ func (r *UserRepositoryImpl) ListAll(ctx context.Context) ([]domain.User, error)
 	return users, nil
 }
 
+func (r *UserRepositoryImpl) SetRole(ctx context.Context, userID, role string) error {
+	return r.DB.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", userID).
+		Update("role", role).Error
+}
+
+func (r *UserRepositoryImpl) SetAdminLock(ctx context.Context, userID string, lockedAt *time.Time) error {
+	return r.DB.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ?", userID).
+		Update("admin_locked_at", lockedAt).Error
+}
+
 // ResetLoginLockout clears the failed-login counter and any lockout, called after a successful
 // login so past failures don't carry forward.
 func (r *UserRepositoryImpl) ResetLoginLockout(ctx context.Context, userID string) error {
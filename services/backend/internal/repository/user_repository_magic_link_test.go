@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserRepository_ConsumeMagicLinkToken_SingleUse proves a sign-in link
+// can be redeemed once and not after it expires.
+func TestUserRepository_ConsumeMagicLinkToken_SingleUse(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE magic_link_tokens (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, token_hash TEXT NOT NULL UNIQUE,
+		expires_at DATETIME NOT NULL, used BOOLEAN NOT NULL DEFAULT FALSE, created_at DATETIME)`).Error)
+
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, repo.CreateMagicLinkToken(ctx, &domain.MagicLinkToken{
+		ID: "t1", UserID: "u1", TokenHash: "hash-1", ExpiresAt: now.Add(time.Minute),
+	}))
+	require.NoError(t, repo.CreateMagicLinkToken(ctx, &domain.MagicLinkToken{
+		ID: "t2", UserID: "u1", TokenHash: "hash-2", ExpiresAt: now.Add(-time.Minute),
+	}))
+
+	token, err := repo.ConsumeMagicLinkToken(ctx, "hash-1", now)
+	require.NoError(t, err)
+	require.Equal(t, "u1", token.UserID)
+	require.True(t, token.Used)
+
+	_, err = repo.ConsumeMagicLinkToken(ctx, "hash-1", now)
+	require.True(t, apperrors.IsNotFound(err), "a link must not be redeemed twice")
+
+	_, err = repo.ConsumeMagicLinkToken(ctx, "hash-2", now)
+	require.True(t, apperrors.IsNotFound(err), "an expired link must not be redeemed")
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserRepository_ConsumeOIDCState_SingleUse proves a sign-in state can be
+// redeemed once and not after it expires.
+func TestUserRepository_ConsumeOIDCState_SingleUse(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE oidc_login_states (
+		id TEXT PRIMARY KEY, state_hash TEXT NOT NULL UNIQUE, binding_hash TEXT NOT NULL DEFAULT '', provider TEXT NOT NULL,
+		nonce TEXT NOT NULL, code_verifier TEXT NOT NULL, expires_at DATETIME NOT NULL,
+		used_at DATETIME, created_at DATETIME)`).Error)
+
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, repo.CreateOIDCState(ctx, &domain.OIDCLoginState{
+		ID: "st1", StateHash: "hash-1", Provider: "google", Nonce: "n", CodeVerifier: "v", ExpiresAt: now.Add(time.Minute),
+	}))
+	require.NoError(t, repo.CreateOIDCState(ctx, &domain.OIDCLoginState{
+		ID: "st2", StateHash: "hash-2", Provider: "google", Nonce: "n", CodeVerifier: "v", ExpiresAt: now.Add(-time.Minute),
+	}))
+
+	state, err := repo.ConsumeOIDCState(ctx, "hash-1", now)
+	require.NoError(t, err)
+	require.Equal(t, "google", state.Provider)
+	require.Equal(t, "v", state.CodeVerifier)
+
+	_, err = repo.ConsumeOIDCState(ctx, "hash-1", now)
+	require.True(t, apperrors.IsNotFound(err), "a state must not be redeemed twice")
+
+	_, err = repo.ConsumeOIDCState(ctx, "hash-2", now)
+	require.True(t, apperrors.IsNotFound(err), "an expired state must not be redeemed")
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserRepository_RotateSessionToken_SingleUse proves rotation is
+// conditional on the presented hash: once a token has been rotated out, a
+// second rotation with it reports false so the service can treat it as reuse.
+func TestUserRepository_RotateSessionToken_SingleUse(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE sessions (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, refresh_token_hash TEXT NOT NULL,
+		previous_refresh_token_hash TEXT NOT NULL DEFAULT '',
+		user_agent TEXT, ip_address TEXT, last_seen_at DATETIME, expires_at DATETIME,
+		revoked_at DATETIME, created_at DATETIME)`).Error)
+
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, repo.CreateSession(ctx, &domain.Session{
+		ID: "s1", UserID: "u1", RefreshTokenHash: "hash-1", LastSeenAt: now, ExpiresAt: now.Add(time.Hour),
+	}))
+	client := domain.SessionClient{UserAgent: "phone", IPAddress: "203.0.113.7"}
+
+	rotated, err := repo.RotateSessionToken(ctx, "s1", "hash-1", "hash-2", client, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, rotated)
+
+	rotated, err = repo.RotateSessionToken(ctx, "s1", "hash-1", "hash-3", client, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.False(t, rotated, "a rotated-out hash must not rotate again")
+
+	session, err := repo.GetSessionByID(ctx, "s1")
+	require.NoError(t, err)
+	require.Equal(t, "hash-2", session.RefreshTokenHash)
+	require.Equal(t, "hash-1", session.PreviousRefreshTokenHash)
+	require.Equal(t, "phone", session.UserAgent)
+
+	active, err := repo.IsSessionActive(ctx, "s1")
+	require.NoError(t, err)
+	require.True(t, active)
+
+	revoked, err := repo.RevokeSession(ctx, "u2", "s1")
+	require.NoError(t, err)
+	require.False(t, revoked, "another user cannot revoke the session")
+
+	revoked, err = repo.RevokeSession(ctx, "u1", "s1")
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	active, err = repo.IsSessionActive(ctx, "s1")
+	require.NoError(t, err)
+	require.False(t, active)
+
+	rotated, err = repo.RotateSessionToken(ctx, "s1", "hash-2", "hash-4", client, now, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.False(t, rotated, "a revoked session cannot be refreshed")
+}
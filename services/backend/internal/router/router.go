@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/H3nSte1n/recipe/internal/domain"
 	"github.com/H3nSte1n/recipe/internal/handler"
 	"github.com/H3nSte1n/recipe/internal/middleware"
 	"github.com/H3nSte1n/recipe/pkg/config"
@@ -17,7 +18,7 @@ import (
 // Rate limits for the public auth endpoints, keyed per client IP (see middleware.RateLimit).
 // These endpoints have no auth token to key on, so IP is the only signal available; limits are
 // generous enough for normal use (typos, retries) but bound how fast an attacker can guess
-// passwords, spam registrations, or trigger password-reset emails against one IP.
+// passwords, spam registrations, or trigger password-reset and sign-in-link emails against one IP.
 var (
 	loginRateLimit          = rate.Every(12 * time.Second) // 5 requests/min
 	loginRateBurst          = 5
@@ -25,6 +26,12 @@ var (
 	registerRateBurst       = 3
 	forgotPasswordRateLimit = rate.Every(20 * time.Second) // 3 requests/min
 	forgotPasswordRateBurst = 3
+	magicLinkRateLimit      = rate.Every(20 * time.Second) // 3 requests/min
+	magicLinkRateBurst      = 3
+	refreshRateLimit        = rate.Every(2 * time.Second) // 30 requests/min
+	refreshRateBurst        = 10
+	sharedListRateLimit     = rate.Every(time.Second) // 60 requests/min
+	sharedListRateBurst     = 20
 )
 
 // defaultTrustedProxies is used when TRUSTED_PROXIES is unset. The production deployment plan
@@ -61,7 +68,7 @@ type Router struct {
 	logger   *zap.Logger
 }
 
-func NewRouter(handlers *handler.Handlers, config config.Config, logger *zap.Logger, revocations middleware.TokenRevocationChecker) *Router {
+func NewRouter(handlers *handler.Handlers, config config.Config, logger *zap.Logger, revocations middleware.TokenRevocationChecker, apiTokens middleware.APITokenVerifier) *Router {
 	engine := gin.Default()
 
 	// Gin trusts every proxy by default, which makes X-Forwarded-For/X-Real-IP
@@ -77,11 +84,14 @@ func NewRouter(handlers *handler.Handlers, config config.Config, logger *zap.Log
 
 	engine.Use(middleware.BodySizeLimit(middleware.MaxJSONBodyBytes))
 	engine.Use(middleware.CORS(config.CORS.AllowedOrigins))
+	engine.Use(middleware.AuditContext())
+	engine.Use(middleware.EmailLanguage())
+	engine.Use(middleware.CategoryLanguage())
 
 	return &Router{
 		engine:   engine,
 		handlers: handlers,
-		auth:     middleware.NewAuthMiddleware(config.JWT.Secret, config.JWT.Issuer, config.JWT.Audience, revocations),
+		auth:     middleware.NewAuthMiddleware(config.JWT.Secret, config.JWT.Issuer, config.JWT.Audience, revocations, apiTokens),
 		config:   config,
 		logger:   logger,
 	}
@@ -100,6 +110,12 @@ func (r *Router) SetupRoutes() *gin.Engine {
 		r.engine.GET("/uploads/:filename", uploads.Serve)
 	}
 
+	// Data export archives are served the same way, from their own directory;
+	// the signed link is emailed to the account owner.
+	exportSigner := signedurl.NewSigner(r.config.JWT.Secret, r.config.Export.LinkTTL)
+	exports := handler.NewUploadsHandler(r.config.Export.Dir, exportSigner, r.logger)
+	r.engine.GET("/exports/:filename", exports.Serve)
+
 	// Public routes (no authentication required)
 	r.setupPublicRoutes(v1)
 
@@ -115,17 +131,38 @@ func (r *Router) SetupRoutes() *gin.Engine {
 func (r *Router) setupPublicRoutes(rg *gin.RouterGroup) {
 	auth := rg.Group("/auth")
 	{
-		// Login/register/forgot-password are rate-limited per client IP: they're the
+		// Login/register/forgot-password/magic-link are rate-limited per client IP: they're the
 		// endpoints an attacker would hit to brute-force credentials, mass-register, or spam
-		// password-reset emails, and (unlike protected routes) have no user identity to key
+		// password-reset and sign-in emails, and (unlike protected routes) have no user identity to key
 		// on until after they succeed.
 		auth.POST("/register", middleware.RateLimit(registerRateLimit, registerRateBurst), r.handlers.UserHandler.Register)
 		auth.POST("/login", middleware.RateLimit(loginRateLimit, loginRateBurst), r.handlers.UserHandler.Login)
+		auth.POST("/login/2fa", middleware.RateLimit(loginRateLimit, loginRateBurst), r.handlers.UserHandler.CompleteTwoFactorLogin)
+		// Refresh is limited more loosely, since every signed-in device behind one IP
+		// renews its tokens through it, but still bounds guessing at session secrets.
+		auth.POST("/refresh", middleware.RateLimit(refreshRateLimit, refreshRateBurst), r.handlers.UserHandler.Refresh)
+
+		auth.GET("/oidc/providers", r.handlers.UserHandler.ListOIDCProviders)
+		auth.POST("/oidc/:provider/start", middleware.RateLimit(loginRateLimit, loginRateBurst), r.handlers.UserHandler.StartOIDCLogin)
+		auth.POST("/oidc/:provider/callback", middleware.RateLimit(loginRateLimit, loginRateBurst), r.handlers.UserHandler.CompleteOIDCLogin)
+
 		auth.POST("/forgot-password", middleware.RateLimit(forgotPasswordRateLimit, forgotPasswordRateBurst), r.handlers.UserHandler.ForgotPassword)
+		auth.POST("/magic-link", middleware.RateLimit(magicLinkRateLimit, magicLinkRateBurst), r.handlers.UserHandler.RequestMagicLink)
+		auth.POST("/magic-link/login", middleware.RateLimit(loginRateLimit, loginRateBurst), r.handlers.UserHandler.MagicLinkLogin)
 		auth.POST("/reset-password", r.handlers.UserHandler.ResetPassword)
 		auth.POST("/verify-email", r.handlers.UserHandler.VerifyEmail)
 		auth.POST("/resend-verification", r.handlers.UserHandler.ResendVerification)
 	}
+
+	// Shared shopping lists are reached by their link's signed token alone.
+	// They are rate-limited per client IP like the auth endpoints, but loosely
+	// enough for someone checking items off in the store.
+	shared := rg.Group("/shared/shopping-lists/:token", middleware.RateLimit(sharedListRateLimit, sharedListRateBurst))
+	{
+		shared.GET("", r.handlers.ShareHandler.GetShared)
+		shared.PATCH("/items/:itemId/toggle", r.handlers.ShareHandler.ToggleSharedItem)
+		shared.GET("/export", r.handlers.ShareHandler.ExportShared)
+	}
 }
 
 // setupProtectedRoutes handles all routes that require authentication
@@ -136,15 +173,53 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 	// — see registration/email-verification remediation notes.
 	requireVerified := middleware.RequireVerified(r.handlers.UserHandler.IsEmailVerified)
 
+	// Personal access tokens only reach routes whose group requires a scope
+	// they hold; account security routes are sessionOnly and refuse them.
+	sessionOnly := middleware.SessionOnly()
+
+	// Roles are looked up per request, so a demoted admin loses access at once.
+	requireAdmin := middleware.RequireRole(r.handlers.UserHandler.GetRole, domain.RoleAdmin)
+
 	users := rg.Group("/users")
 	{
-		users.GET("", r.handlers.ProfileHandler.Get)
-		users.PUT("", requireVerified, r.handlers.ProfileHandler.Update)
-		users.DELETE("/me", requireVerified, r.handlers.UserHandler.DeleteAccount)
-		users.GET("/list", r.handlers.UserHandler.ListAll)
+		profileScope := middleware.RequireScope("profile")
+		users.GET("", profileScope, r.handlers.ProfileHandler.Get)
+		users.PUT("", profileScope, requireVerified, r.handlers.ProfileHandler.Update)
+		users.DELETE("/me", sessionOnly, requireVerified, r.handlers.UserHandler.DeleteAccount)
+		users.GET("/list", profileScope, requireAdmin, r.handlers.UserHandler.ListAll)
+
+		// Like signing out devices, securing the account is not gated on
+		// email verification.
+		users.POST("/me/2fa/enroll", sessionOnly, r.handlers.UserHandler.EnrollTOTP)
+		users.POST("/me/2fa/confirm", sessionOnly, r.handlers.UserHandler.ConfirmTOTP)
+		users.POST("/me/2fa/disable", sessionOnly, middleware.RateLimit(loginRateLimit, loginRateBurst), r.handlers.UserHandler.DisableTOTP)
+
+		users.GET("/me/activity", sessionOnly, r.handlers.AuditHandler.ListActivity)
+
+		// The archive link is emailed, so the address must be verified.
+		users.POST("/me/export", sessionOnly, requireVerified, r.handlers.DataExportHandler.RequestExport)
+		users.GET("/me/export", sessionOnly, r.handlers.DataExportHandler.GetLatest)
 	}
 
-	aiConfigs := rg.Group("/ai-configs")
+	tokens := rg.Group("/users/me/tokens", sessionOnly)
+	{
+		tokens.GET("", r.handlers.APITokenHandler.List)
+		tokens.POST("", requireVerified, r.handlers.APITokenHandler.Create)
+		tokens.GET("/:id", r.handlers.APITokenHandler.Get)
+		tokens.PATCH("/:id", r.handlers.APITokenHandler.Update)
+		// Revoking is not gated on verification, like signing out a device.
+		tokens.DELETE("/:id", r.handlers.APITokenHandler.Revoke)
+	}
+
+	// Signing a device out is deliberately not gated on email verification:
+	// it is how a user contains a lost or stolen device.
+	sessions := rg.Group("/auth/sessions", sessionOnly)
+	{
+		sessions.GET("", r.handlers.UserHandler.ListSessions)
+		sessions.DELETE("/:id", r.handlers.UserHandler.RevokeSession)
+	}
+
+	aiConfigs := rg.Group("/ai-configs", middleware.RequireScope("ai_configs"))
 	{
 		aiConfigs.GET("", r.handlers.AIConfigHandler.List)
 		aiConfigs.POST("", requireVerified, r.handlers.AIConfigHandler.Create)
@@ -154,16 +229,31 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 
 		aiConfigs.GET("/default", r.handlers.AIConfigHandler.GetDefault)
 		aiConfigs.POST("/:id/set-default", requireVerified, r.handlers.AIConfigHandler.SetDefault)
+		aiConfigs.POST("/:id/test", requireVerified, r.handlers.AIConfigHandler.Test)
 
 		aiConfigs.GET("/models", r.handlers.AIConfigHandler.ListModels)
 	}
 
-	recipes := rg.Group("/recipes")
+	recipes := rg.Group("/recipes", middleware.RequireScope("recipes"))
 	{
 		recipes.POST("", requireVerified, r.handlers.RecipeHandler.Create)
+		recipes.POST("/generate", requireVerified, r.handlers.RecipeHandler.Generate)
 		recipes.GET("/:id", r.handlers.RecipeHandler.Get)
 		recipes.PUT("/:id", requireVerified, r.handlers.RecipeHandler.Update)
 		recipes.DELETE("/:id", requireVerified, r.handlers.RecipeHandler.Delete)
+		recipes.POST("/:id/restore", requireVerified, r.handlers.TrashHandler.RestoreRecipe)
+
+		recipes.POST("/:id/translate", requireVerified, r.handlers.RecipeHandler.Translate)
+		recipes.POST("/:id/ai-edit", requireVerified, r.handlers.RecipeHandler.ProposeEdit)
+		recipes.POST("/:id/ai-edit/:proposalId/accept", requireVerified, r.handlers.RecipeHandler.AcceptEdit)
+		recipes.POST("/:id/ai-edit/:proposalId/reject", requireVerified, r.handlers.RecipeHandler.RejectEdit)
+
+		// Costs come from prices and shopping trips, so they need that scope
+		// too.
+		recipes.GET("/:id/cost", middleware.RequireScope("shopping_lists"), r.handlers.PriceHandler.RecipeCost)
+
+		recipes.GET("/:id/ingredients/:ingredientId/substitutes", r.handlers.RecipeHandler.SuggestSubstitutes)
+		recipes.POST("/:id/ingredients/:ingredientId/substitutes/apply", requireVerified, r.handlers.RecipeHandler.ApplySubstitute)
 
 		recipes.GET("", r.handlers.RecipeHandler.ListMine)
 		recipes.GET("/public", r.handlers.RecipeHandler.ListPublic)
@@ -180,28 +270,119 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 		}
 	}
 
-	shoppingLists := rg.Group("/shopping-lists")
+	shoppingLists := rg.Group("/shopping-lists", middleware.RequireScope("shopping_lists"))
 	{
 		shoppingLists.POST("", requireVerified, r.handlers.ShoppingListHandler.Create)
 		shoppingLists.GET("", r.handlers.ShoppingListHandler.List)
+		shoppingLists.GET("/suggest", r.handlers.SuggestionHandler.Suggest)
 		shoppingLists.GET("/:id", r.handlers.ShoppingListHandler.Get)
 		shoppingLists.PUT("/:id", requireVerified, r.handlers.ShoppingListHandler.Update)
 		shoppingLists.DELETE("/:id", requireVerified, r.handlers.ShoppingListHandler.Delete)
+		shoppingLists.POST("/:id/restore", requireVerified, r.handlers.TrashHandler.RestoreShoppingList)
 
 		shoppingLists.POST("/:id/items", requireVerified, r.handlers.ShoppingListHandler.AddItem)
+		shoppingLists.POST("/:id/items/batch", requireVerified, r.handlers.ShoppingListHandler.BatchItems)
 		shoppingLists.PUT("/:id/items/:itemId", requireVerified, r.handlers.ShoppingListHandler.UpdateItem)
 		shoppingLists.DELETE("/:id/items/:itemId", requireVerified, r.handlers.ShoppingListHandler.DeleteItem)
+		shoppingLists.POST("/:id/items/:itemId/restore", requireVerified, r.handlers.TrashHandler.RestoreShoppingListItem)
 		shoppingLists.PATCH("/:id/items/:itemId/toggle", requireVerified, r.handlers.ShoppingListHandler.ToggleItem)
+		shoppingLists.POST("/:id/items/barcode", requireVerified, r.handlers.ProductHandler.AddToList)
 
 		shoppingLists.POST("/:id/add-recipe", requireVerified, r.handlers.ShoppingListHandler.AddRecipe)
 		shoppingLists.GET("/:id/sorted", r.handlers.ShoppingListHandler.SortByStore)
+		shoppingLists.GET("/:id/cost", r.handlers.PriceHandler.ListCost)
+		shoppingLists.GET("/:id/suggestions", r.handlers.SuggestionHandler.BoughtTogether)
+		shoppingLists.POST("/:id/clear-checked", requireVerified, r.handlers.ShoppingListHandler.ClearChecked)
+		shoppingLists.GET("/:id/export", r.handlers.ShoppingListHandler.Export)
+
+		shoppingLists.POST("/:id/share", requireVerified, r.handlers.ShareHandler.Create)
+		shoppingLists.GET("/:id/shares", r.handlers.ShareHandler.List)
+		shoppingLists.DELETE("/:id/shares/:shareId", requireVerified, r.handlers.ShareHandler.Revoke)
 	}
 
-	storeChains := rg.Group("/store-chains")
+	// Products are what barcodes are scanned onto shopping lists as.
+	products := rg.Group("/products", middleware.RequireScope("shopping_lists"))
+	{
+		products.GET("/saved", r.handlers.ProductHandler.ListSaved)
+		products.PUT("/saved", requireVerified, r.handlers.ProductHandler.Save)
+		products.DELETE("/saved/:barcode", requireVerified, r.handlers.ProductHandler.DeleteSaved)
+		products.GET("/:barcode", r.handlers.ProductHandler.Lookup)
+	}
+
+	// Prices are what the user pays for items at store chains; lists and
+	// recipes are costed from them.
+	prices := rg.Group("/prices", middleware.RequireScope("shopping_lists"))
+	{
+		prices.GET("", r.handlers.PriceHandler.List)
+		prices.PUT("", requireVerified, r.handlers.PriceHandler.Save)
+		prices.DELETE("/:id", requireVerified, r.handlers.PriceHandler.Delete)
+	}
+
+	templates := rg.Group("/shopping-list-templates", middleware.RequireScope("shopping_lists"))
+	{
+		templates.GET("", r.handlers.TemplateHandler.List)
+		templates.POST("", requireVerified, r.handlers.TemplateHandler.Create)
+		templates.GET("/:id", r.handlers.TemplateHandler.Get)
+		templates.PUT("/:id", requireVerified, r.handlers.TemplateHandler.Update)
+		templates.DELETE("/:id", requireVerified, r.handlers.TemplateHandler.Delete)
+		templates.POST("/:id/apply", requireVerified, r.handlers.TemplateHandler.Apply)
+	}
+
+	trips := rg.Group("/trips", middleware.RequireScope("shopping_lists"))
+	{
+		trips.GET("", r.handlers.ShoppingTripHandler.List)
+		trips.POST("", requireVerified, r.handlers.ShoppingTripHandler.Start)
+		trips.GET("/spending", r.handlers.ShoppingTripHandler.Spending)
+		trips.GET("/prices", r.handlers.ShoppingTripHandler.PriceHistory)
+		trips.GET("/:id", r.handlers.ShoppingTripHandler.Get)
+		trips.DELETE("/:id", requireVerified, r.handlers.ShoppingTripHandler.Delete)
+		trips.POST("/:id/items", requireVerified, r.handlers.ShoppingTripHandler.RecordItem)
+		trips.DELETE("/:id/items/:itemId", requireVerified, r.handlers.ShoppingTripHandler.RemoveItem)
+		trips.POST("/:id/finish", requireVerified, r.handlers.ShoppingTripHandler.Finish)
+	}
+
+	// The trash holds both recipes and shopping lists, so a token needs
+	// access to both to list it. Restoring lives under each resource.
+	rg.GET("/trash", middleware.RequireScope("recipes"), middleware.RequireScope("shopping_lists"), r.handlers.TrashHandler.List)
+
+	storeChains := rg.Group("/store-chains", middleware.RequireScope("store_chains"))
 	{
 		storeChains.GET("", r.handlers.StoreChainHandler.List)
 		storeChains.GET("/:id", r.handlers.StoreChainHandler.Get)
 	}
+
+	rg.GET("/categories", middleware.RequireScope("categories"), r.handlers.CategoryHandler.List)
+
+	stores := rg.Group("/stores", middleware.RequireScope("stores"))
+	{
+		stores.GET("", r.handlers.StoreHandler.List)
+		stores.POST("", requireVerified, r.handlers.StoreHandler.Create)
+		stores.GET("/:id", r.handlers.StoreHandler.Get)
+		stores.PUT("/:id", requireVerified, r.handlers.StoreHandler.Update)
+		stores.DELETE("/:id", requireVerified, r.handlers.StoreHandler.Delete)
+	}
+
+	// Administration is never reachable with an API token, and every write is
+	// recorded in the audit trail by AdminService.
+	admin := rg.Group("/admin", sessionOnly, requireAdmin)
+	{
+		admin.GET("/users", r.handlers.AdminHandler.ListUsers)
+		admin.GET("/users/:id", r.handlers.AdminHandler.GetUser)
+		admin.POST("/users/:id/lock", requireVerified, r.handlers.AdminHandler.LockUser)
+		admin.POST("/users/:id/unlock", requireVerified, r.handlers.AdminHandler.UnlockUser)
+		admin.POST("/users/:id/verify", requireVerified, r.handlers.AdminHandler.VerifyUser)
+		admin.PUT("/users/:id/role", requireVerified, r.handlers.AdminHandler.SetUserRole)
+		admin.DELETE("/users/:id", requireVerified, r.handlers.AdminHandler.DeleteUser)
+
+		admin.GET("/ai-models", r.handlers.AdminHandler.ListAIModels)
+		admin.PATCH("/ai-models/:id", requireVerified, r.handlers.AdminHandler.UpdateAIModel)
+
+		admin.POST("/store-chains", requireVerified, r.handlers.AdminHandler.CreateStoreChain)
+		admin.PUT("/store-chains/:id", requireVerified, r.handlers.AdminHandler.UpdateStoreChain)
+		admin.DELETE("/store-chains/:id", requireVerified, r.handlers.AdminHandler.DeleteStoreChain)
+
+		admin.GET("/audit-events", r.handlers.AdminHandler.ListAuditEvents)
+	}
 }
 
 func (r *Router) Run(addr string) error {
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type adminUserRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.User, error)
+	ListAll(ctx context.Context) ([]domain.User, error)
+	MarkEmailVerified(ctx context.Context, userID string) error
+	ResetLoginLockout(ctx context.Context, userID string) error
+	SetRole(ctx context.Context, userID, role string) error
+	SetAdminLock(ctx context.Context, userID string, lockedAt *time.Time) error
+	WithTypedTransaction(ctx context.Context, fn func(repository.UserRepository) error) error
+}
+
+type adminAIModelRepository interface {
+	ListAllAIModels(ctx context.Context) ([]domain.AIModel, error)
+	GetAIModelIncludingInactive(ctx context.Context, id string) (*domain.AIModel, error)
+	SetAIModelActive(ctx context.Context, id string, active bool) error
+}
+
+type adminAuditRepository interface {
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	List(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error)
+}
+
+type adminStoreChainRepository interface {
+	GetChain(ctx context.Context, chainID string) (*domain.StoreChain, error)
+	CreateChain(ctx context.Context, chain *domain.StoreChain) error
+	UpdateChain(ctx context.Context, chain *domain.StoreChain) error
+	DeleteChain(ctx context.Context, chainID string) (bool, error)
+}
+
+// AdminService backs the /admin API. Every change it makes is recorded in the
+// audit trail with the acting administrator.
+type AdminService interface {
+	ListUsers(ctx context.Context) ([]domain.AdminUser, error)
+	GetUser(ctx context.Context, userID string) (*domain.AdminUser, error)
+	LockUser(ctx context.Context, actor domain.AuditActor, userID string) (*domain.AdminUser, error)
+	UnlockUser(ctx context.Context, actor domain.AuditActor, userID string) (*domain.AdminUser, error)
+	VerifyUser(ctx context.Context, actor domain.AuditActor, userID string) (*domain.AdminUser, error)
+	SetUserRole(ctx context.Context, actor domain.AuditActor, userID string, req *domain.UpdateUserRoleRequest) (*domain.AdminUser, error)
+	DeleteUser(ctx context.Context, actor domain.AuditActor, userID string) error
+	ListAIModels(ctx context.Context) ([]domain.AIModel, error)
+	UpdateAIModel(ctx context.Context, actor domain.AuditActor, modelID string, req *domain.UpdateAIModelRequest) (*domain.AIModel, error)
+	CreateStoreChain(ctx context.Context, actor domain.AuditActor, req *domain.CreateStoreChainRequest) (*domain.StoreChain, error)
+	UpdateStoreChain(ctx context.Context, actor domain.AuditActor, chainID string, req *domain.UpdateStoreChainRequest) (*domain.StoreChain, error)
+	DeleteStoreChain(ctx context.Context, actor domain.AuditActor, chainID string) error
+	ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error)
+}
+
+// errAdminSelfAction stops an administrator from locking, deleting or
+// demoting their own account, which could leave nobody able to undo it.
+var errAdminSelfAction = apperrors.New("administrators cannot do this to their own account", "INVALID_INPUT")
+
+type adminService struct {
+	userRepo       adminUserRepository
+	aiModelRepo    adminAIModelRepository
+	storeChainRepo adminStoreChainRepository
+	auditRepo      adminAuditRepository
+	auditLog       *AuditLog
+	logger         *zap.Logger
+}
+
+func NewAdminService(userRepo adminUserRepository, aiModelRepo adminAIModelRepository, storeChainRepo adminStoreChainRepository, auditRepo adminAuditRepository, logger *zap.Logger) AdminService {
+	return &adminService{
+		userRepo:       userRepo,
+		aiModelRepo:    aiModelRepo,
+		storeChainRepo: storeChainRepo,
+		auditRepo:      auditRepo,
+		auditLog:       NewAuditLog(auditRepo, logger),
+		logger:         logger,
+	}
+}
+
+// audit records an administrative action by actor.
+func (s *adminService) audit(ctx context.Context, actor domain.AuditActor, action, targetType, targetID string, changes domain.AuditChanges) {
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(actor.UserID),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IPAddress:  actor.Client.IPAddress,
+		UserAgent:  actor.Client.UserAgent,
+		Changes:    changes,
+	})
+}
+
+func (s *adminService) getUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.ErrNotFound.Wrap("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *adminService) ListUsers(ctx context.Context) ([]domain.AdminUser, error) {
+	users, err := s.userRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.AdminUser, len(users))
+	for i := range users {
+		result[i] = domain.NewAdminUser(&users[i])
+	}
+	return result, nil
+}
+
+func (s *adminService) GetUser(ctx context.Context, userID string) (*domain.AdminUser, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	view := domain.NewAdminUser(user)
+	return &view, nil
+}
+
+// LockUser locks the account until an administrator unlocks it and signs it
+// out everywhere: outstanding JWTs are revoked and its sessions ended.
+// Personal access tokens stop working while the lock is in place.
+func (s *adminService) LockUser(ctx context.Context, actor domain.AuditActor, userID string) (*domain.AdminUser, error) {
+	if userID == actor.UserID {
+		return nil, errAdminSelfAction
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdminLocked() {
+		view := domain.NewAdminUser(user)
+		return &view, nil
+	}
+
+	now := time.Now()
+	err = s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+		if err := txRepo.SetAdminLock(ctx, user.ID, &now); err != nil {
+			return err
+		}
+		if err := txRepo.SetTokenRevocation(ctx, user.ID, now); err != nil {
+			return err
+		}
+		return txRepo.RevokeUserSessions(ctx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, actor, domain.AuditAdminUserLocked, domain.AuditTargetUser, user.ID, domain.AuditChanges{
+		"admin_locked_at": {From: nil, To: now},
+	})
+
+	user.AdminLockedAt = &now
+	view := domain.NewAdminUser(user)
+	return &view, nil
+}
+
+// UnlockUser clears both the administrator lock and any failed-login lockout.
+func (s *adminService) UnlockUser(ctx context.Context, actor domain.AuditActor, userID string) (*domain.AdminUser, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdminLocked() && user.LockedUntil == nil && user.FailedLoginAttempts == 0 {
+		view := domain.NewAdminUser(user)
+		return &view, nil
+	}
+
+	err = s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+		if err := txRepo.SetAdminLock(ctx, user.ID, nil); err != nil {
+			return err
+		}
+		return txRepo.ResetLoginLockout(ctx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, actor, domain.AuditAdminUserUnlocked, domain.AuditTargetUser, user.ID, domain.AuditChanges{
+		"admin_locked_at": {From: user.AdminLockedAt, To: nil},
+		"locked_until":    {From: user.LockedUntil, To: nil},
+	})
+
+	user.AdminLockedAt = nil
+	user.LockedUntil = nil
+	user.FailedLoginAttempts = 0
+	view := domain.NewAdminUser(user)
+	return &view, nil
+}
+
+func (s *adminService) VerifyUser(ctx context.Context, actor domain.AuditActor, userID string) (*domain.AdminUser, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsEmailVerified() {
+		view := domain.NewAdminUser(user)
+		return &view, nil
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	s.audit(ctx, actor, domain.AuditAdminUserVerified, domain.AuditTargetUser, user.ID, domain.AuditChanges{
+		"email_verified_at": {From: nil, To: now},
+	})
+
+	user.EmailVerifiedAt = &now
+	view := domain.NewAdminUser(user)
+	return &view, nil
+}
+
+func (s *adminService) SetUserRole(ctx context.Context, actor domain.AuditActor, userID string, req *domain.UpdateUserRoleRequest) (*domain.AdminUser, error) {
+	if userID == actor.UserID {
+		return nil, errAdminSelfAction
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == req.Role {
+		view := domain.NewAdminUser(user)
+		return &view, nil
+	}
+
+	if err := s.userRepo.SetRole(ctx, user.ID, req.Role); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, actor, domain.AuditAdminUserRoleChanged, domain.AuditTargetUser, user.ID, domain.AuditChanges{
+		"role": {From: user.Role, To: req.Role},
+	})
+
+	user.Role = req.Role
+	view := domain.NewAdminUser(user)
+	return &view, nil
+}
+
+// DeleteUser deletes the account the same way the owner deleting it would.
+func (s *adminService) DeleteUser(ctx context.Context, actor domain.AuditActor, userID string) error {
+	if userID == actor.UserID {
+		return errAdminSelfAction
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+		if err := txRepo.SetTokenRevocation(ctx, user.ID, time.Now()); err != nil {
+			return err
+		}
+		return txRepo.Delete(ctx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The audit log outlives the account, so it keeps only the user's ID.
+	s.audit(ctx, actor, domain.AuditAdminUserDeleted, domain.AuditTargetUser, user.ID, nil)
+	return nil
+}
+
+func (s *adminService) ListAIModels(ctx context.Context) ([]domain.AIModel, error) {
+	return s.aiModelRepo.ListAllAIModels(ctx)
+}
+
+// UpdateAIModel activates or deactivates a model. Deactivated models are no
+// longer offered for new configs.
+func (s *adminService) UpdateAIModel(ctx context.Context, actor domain.AuditActor, modelID string, req *domain.UpdateAIModelRequest) (*domain.AIModel, error) {
+	model, err := s.aiModelRepo.GetAIModelIncludingInactive(ctx, modelID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.ErrNotFound.Wrap("AI model not found")
+		}
+		return nil, err
+	}
+	if model.IsActive == *req.IsActive {
+		return model, nil
+	}
+
+	if err := s.aiModelRepo.SetAIModelActive(ctx, model.ID, *req.IsActive); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, actor, domain.AuditAdminAIModelUpdated, domain.AuditTargetAIModel, model.ID, domain.AuditChanges{
+		"is_active": {From: model.IsActive, To: *req.IsActive},
+	})
+
+	model.IsActive = *req.IsActive
+	return model, nil
+}
+
+func (s *adminService) CreateStoreChain(ctx context.Context, actor domain.AuditActor, req *domain.CreateStoreChainRequest) (*domain.StoreChain, error) {
+	chain := &domain.StoreChain{
+		ID:      uuid.New().String(),
+		Name:    strings.TrimSpace(req.Name),
+		Country: strings.ToUpper(req.Country),
+		Layout:  req.Layout,
+	}
+	if err := s.storeChainRepo.CreateChain(ctx, chain); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, actor, domain.AuditAdminStoreChainCreated, domain.AuditTargetStoreChain, chain.ID, domain.AuditChanges{
+		"name":    {From: nil, To: chain.Name},
+		"country": {From: nil, To: chain.Country},
+	})
+	return chain, nil
+}
+
+func (s *adminService) UpdateStoreChain(ctx context.Context, actor domain.AuditActor, chainID string, req *domain.UpdateStoreChainRequest) (*domain.StoreChain, error) {
+	chain, err := s.storeChainRepo.GetChain(ctx, chainID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.ErrNotFound.Wrap("store chain not found")
+		}
+		return nil, err
+	}
+
+	changes := domain.AuditChanges{}
+	if req.Name != nil {
+		if name := strings.TrimSpace(*req.Name); name != chain.Name {
+			changes["name"] = domain.AuditChange{From: chain.Name, To: name}
+			chain.Name = name
+		}
+	}
+	if req.Country != nil {
+		if country := strings.ToUpper(*req.Country); country != chain.Country {
+			changes["country"] = domain.AuditChange{From: chain.Country, To: country}
+			chain.Country = country
+		}
+	}
+	if req.Layout != nil {
+		// The layout is too large to diff usefully; record that it changed.
+		changes["layout"] = domain.AuditChange{From: len(chain.Layout), To: len(req.Layout)}
+		chain.Layout = req.Layout
+	}
+	if len(changes) == 0 {
+		return chain, nil
+	}
+
+	if err := s.storeChainRepo.UpdateChain(ctx, chain); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, actor, domain.AuditAdminStoreChainUpdated, domain.AuditTargetStoreChain, chain.ID, changes)
+	return chain, nil
+}
+
+// DeleteStoreChain removes a chain. Shopping lists sorted by it keep their
+// items and lose only the chain reference.
+func (s *adminService) DeleteStoreChain(ctx context.Context, actor domain.AuditActor, chainID string) error {
+	chain, err := s.storeChainRepo.GetChain(ctx, chainID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return apperrors.ErrNotFound.Wrap("store chain not found")
+		}
+		return err
+	}
+
+	deleted, err := s.storeChainRepo.DeleteChain(ctx, chain.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return apperrors.ErrNotFound.Wrap("store chain not found")
+	}
+
+	s.audit(ctx, actor, domain.AuditAdminStoreChainDeleted, domain.AuditTargetStoreChain, chain.ID, domain.AuditChanges{
+		"name":    {From: chain.Name, To: nil},
+		"country": {From: chain.Country, To: nil},
+	})
+	return nil
+}
+
+func (s *adminService) ListAuditEvents(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error) {
+	return s.auditRepo.List(ctx, filter)
+}
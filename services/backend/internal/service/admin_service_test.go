@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var testAdminActor = domain.AuditActor{
+	UserID: "admin-1",
+	Client: domain.SessionClient{IPAddress: "203.0.113.7", UserAgent: "test-agent"},
+}
+
+// auditEventMatching matches an event recorded by testAdminActor.
+func auditEventMatching(action, targetID string) interface{} {
+	return mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == action && e.TargetID == targetID &&
+			e.ActorID != nil && *e.ActorID == testAdminActor.UserID &&
+			e.IPAddress == testAdminActor.Client.IPAddress
+	})
+}
+
+func TestAdminService_LockUser(t *testing.T) {
+	t.Run("locks, signs the user out everywhere and audits", func(t *testing.T) {
+		users := new(mockUserRepository)
+		audit := new(mockAuditRepo)
+		users.On("GetByID", mock.Anything, "user-1").Return(&domain.User{ID: "user-1"}, nil).Once()
+		users.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+		users.On("SetAdminLock", mock.Anything, "user-1", mock.AnythingOfType("*time.Time")).Return(nil).Once()
+		users.On("SetTokenRevocation", mock.Anything, "user-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		users.On("RevokeUserSessions", mock.Anything, "user-1").Return(nil).Once()
+		audit.On("Create", mock.Anything, auditEventMatching(domain.AuditAdminUserLocked, "user-1")).Return(nil).Once()
+
+		srv := NewAdminService(users, nil, nil, audit, zap.NewNop())
+		view, err := srv.LockUser(context.Background(), testAdminActor, "user-1")
+
+		require.NoError(t, err)
+		require.NotNil(t, view.AdminLockedAt)
+		users.AssertExpectations(t)
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("refuses to lock the acting administrator", func(t *testing.T) {
+		users := new(mockUserRepository)
+		audit := new(mockAuditRepo)
+
+		srv := NewAdminService(users, nil, nil, audit, zap.NewNop())
+		_, err := srv.LockUser(context.Background(), testAdminActor, testAdminActor.UserID)
+
+		require.True(t, apperrors.IsInvalidInput(err))
+		users.AssertExpectations(t)
+		audit.AssertExpectations(t)
+	})
+
+	t.Run("still succeeds when the audit write fails", func(t *testing.T) {
+		users := new(mockUserRepository)
+		audit := new(mockAuditRepo)
+		users.On("GetByID", mock.Anything, "user-1").Return(&domain.User{ID: "user-1"}, nil).Once()
+		users.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+		users.On("SetAdminLock", mock.Anything, "user-1", mock.Anything).Return(nil).Once()
+		users.On("SetTokenRevocation", mock.Anything, "user-1", mock.Anything).Return(nil).Once()
+		users.On("RevokeUserSessions", mock.Anything, "user-1").Return(nil).Once()
+		audit.On("Create", mock.Anything, mock.Anything).Return(gorm.ErrInvalidDB).Once()
+
+		srv := NewAdminService(users, nil, nil, audit, zap.NewNop())
+		_, err := srv.LockUser(context.Background(), testAdminActor, "user-1")
+
+		require.NoError(t, err)
+		users.AssertExpectations(t)
+	})
+}
+
+func TestAdminService_UnlockUser_ClearsBothLocks(t *testing.T) {
+	lockedAt := time.Now().Add(-time.Hour)
+	lockedUntil := time.Now().Add(time.Minute)
+	users := new(mockUserRepository)
+	audit := new(mockAuditRepo)
+	users.On("GetByID", mock.Anything, "user-1").Return(&domain.User{
+		ID: "user-1", AdminLockedAt: &lockedAt, LockedUntil: &lockedUntil, FailedLoginAttempts: 5,
+	}, nil).Once()
+	users.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	users.On("SetAdminLock", mock.Anything, "user-1", (*time.Time)(nil)).Return(nil).Once()
+	users.On("ResetLoginLockout", mock.Anything, "user-1").Return(nil).Once()
+	audit.On("Create", mock.Anything, auditEventMatching(domain.AuditAdminUserUnlocked, "user-1")).Return(nil).Once()
+
+	srv := NewAdminService(users, nil, nil, audit, zap.NewNop())
+	view, err := srv.UnlockUser(context.Background(), testAdminActor, "user-1")
+
+	require.NoError(t, err)
+	require.Nil(t, view.AdminLockedAt)
+	require.Nil(t, view.LockedUntil)
+	users.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAdminService_SetUserRole(t *testing.T) {
+	users := new(mockUserRepository)
+	audit := new(mockAuditRepo)
+	users.On("GetByID", mock.Anything, "user-1").Return(&domain.User{ID: "user-1", Role: domain.RoleMember}, nil).Once()
+	users.On("SetRole", mock.Anything, "user-1", domain.RoleAdmin).Return(nil).Once()
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		change := e.Changes["role"]
+		return e.Action == domain.AuditAdminUserRoleChanged && change.From == domain.RoleMember && change.To == domain.RoleAdmin
+	})).Return(nil).Once()
+
+	srv := NewAdminService(users, nil, nil, audit, zap.NewNop())
+	view, err := srv.SetUserRole(context.Background(), testAdminActor, "user-1", &domain.UpdateUserRoleRequest{Role: domain.RoleAdmin})
+
+	require.NoError(t, err)
+	require.Equal(t, domain.RoleAdmin, view.Role)
+	users.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAdminService_DeleteUser_AuditsOnlyTheUserID(t *testing.T) {
+	users := new(mockUserRepository)
+	audit := new(mockAuditRepo)
+	users.On("GetByID", mock.Anything, "user-1").Return(&domain.User{ID: "user-1", Email: "foo@bar.com"}, nil).Once()
+	users.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	users.On("SetTokenRevocation", mock.Anything, "user-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	users.On("Delete", mock.Anything, "user-1").Return(nil).Once()
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditAdminUserDeleted && e.TargetID == "user-1" && len(e.Changes) == 0
+	})).Return(nil).Once()
+
+	srv := NewAdminService(users, nil, nil, audit, zap.NewNop())
+	err := srv.DeleteUser(context.Background(), testAdminActor, "user-1")
+
+	require.NoError(t, err)
+	users.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAdminService_DeleteUser_NotFound(t *testing.T) {
+	users := new(mockUserRepository)
+	audit := new(mockAuditRepo)
+	users.On("GetByID", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound).Once()
+
+	srv := NewAdminService(users, nil, nil, audit, zap.NewNop())
+	err := srv.DeleteUser(context.Background(), testAdminActor, "missing")
+
+	require.True(t, apperrors.IsNotFound(err))
+	users.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAdminService_UpdateStoreChain_AuditsOnlyChangedFields(t *testing.T) {
+	chains := new(mockStoreChainRepo)
+	audit := new(mockAuditRepo)
+	chains.On("GetChain", mock.Anything, "chain-1").Return(&domain.StoreChain{ID: "chain-1", Name: "Rewe", Country: "DE"}, nil).Once()
+	chains.On("UpdateChain", mock.Anything, mock.MatchedBy(func(c *domain.StoreChain) bool {
+		return c.Name == "REWE" && c.Country == "DE"
+	})).Return(nil).Once()
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		_, countryChanged := e.Changes["country"]
+		return e.Action == domain.AuditAdminStoreChainUpdated && len(e.Changes) == 1 && !countryChanged &&
+			e.Changes["name"] == domain.AuditChange{From: "Rewe", To: "REWE"}
+	})).Return(nil).Once()
+
+	name, country := " REWE ", "de"
+	srv := NewAdminService(nil, nil, chains, audit, zap.NewNop())
+	chain, err := srv.UpdateStoreChain(context.Background(), testAdminActor, "chain-1", &domain.UpdateStoreChainRequest{Name: &name, Country: &country})
+
+	require.NoError(t, err)
+	require.Equal(t, "REWE", chain.Name)
+	chains.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAdminService_UpdateAIModel(t *testing.T) {
+	models := newFakeAIConfigRepo()
+	audit := new(mockAuditRepo)
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditAdminAIModelUpdated && e.TargetType == domain.AuditTargetAIModel &&
+			e.Changes["is_active"] == domain.AuditChange{From: false, To: true}
+	})).Return(nil).Once()
+
+	active := true
+	srv := NewAdminService(nil, models, nil, audit, zap.NewNop())
+	model, err := srv.UpdateAIModel(context.Background(), testAdminActor, "model-1", &domain.UpdateAIModelRequest{IsActive: &active})
+
+	require.NoError(t, err)
+	require.True(t, model.IsActive)
+	audit.AssertExpectations(t)
+}
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"github.com/H3nSte1n/recipe/internal/domain"
 	apperrors "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/repository"
 	"go.uber.org/zap"
+	"time"
 )
 
 type aiConfigRepository interface {
@@ -15,6 +18,8 @@ type aiConfigRepository interface {
 	ListByUserID(ctx context.Context, userID string) ([]domain.UserAIConfig, error)
 	Delete(ctx context.Context, id string) error
 	GetAIModels(ctx context.Context) ([]domain.AIModel, error)
+	GetAIModelByID(ctx context.Context, id string) (*domain.AIModel, error)
+	RecordVerification(ctx context.Context, id string, verifiedAt *time.Time, lastError string) error
 	GetDefaultConfig(ctx context.Context, userID string) (*domain.UserAIConfig, error)
 	SetDefault(ctx context.Context, userID, configID string) error
 	ClearDefaultByUserID(ctx context.Context, userID string, excludeIDs ...string) error
@@ -30,25 +35,69 @@ type AIConfigService interface {
 	ListAIModels(ctx context.Context) ([]domain.AIModel, error)
 	SetDefault(ctx context.Context, userID string, configID string) error
 	GetDefaultConfig(ctx context.Context, userID string) (*domain.UserAIConfig, error)
+	Test(ctx context.Context, userID string, configID string) (*domain.UserAIConfig, error)
 }
 
 type aiConfigService struct {
 	aiConfigRepo aiConfigRepository
 	cipher       APIKeyCipher
+	modelFactory aiModelFactory
+	auditLog     *AuditLog
 	logger       *zap.Logger
 }
 
-func NewAIConfigService(aiConfigRepo aiConfigRepository, cipher APIKeyCipher, logger *zap.Logger) AIConfigService {
+func NewAIConfigService(aiConfigRepo aiConfigRepository, cipher APIKeyCipher, modelFactory aiModelFactory, auditLog *AuditLog, logger *zap.Logger) AIConfigService {
 	return &aiConfigService{
 		aiConfigRepo: aiConfigRepo,
 		cipher:       cipher,
+		modelFactory: modelFactory,
+		auditLog:     auditLog,
 		logger:       logger,
 	}
 }
 
+// verify makes a minimal call to the provider of modelID with apiKey. Provider
+// failures come back as ErrAI* app errors.
+func (s *aiConfigService) verify(ctx context.Context, modelID string, apiKey string) error {
+	model, err := s.aiConfigRepo.GetAIModelByID(ctx, modelID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return apperrors.ErrNotFound.Wrap("AI model not found")
+		}
+		return err
+	}
+
+	modelType, err := mapAIModelToModelType(model)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.modelFactory.CreateModel(modelType, apiKey)
+	if err != nil {
+		return fmt.Errorf("create AI model: %w", err)
+	}
+
+	if err := client.Verify(ctx); err != nil {
+		s.logger.Info("AI config verification failed",
+			zap.String("modelID", modelID),
+			zap.Error(err))
+		if mapped := aiProviderError(err); mapped != err {
+			return mapped
+		}
+		return fmt.Errorf("verify AI config: %w", err)
+	}
+	return nil
+}
+
 func (s *aiConfigService) Create(ctx context.Context, userID string, req *domain.CreateUserAIConfigRequest) (*domain.UserAIConfig, error) {
 	var configID string
 
+	// Reject a key the provider refuses before anything is stored.
+	if err := s.verify(ctx, req.AIModelID, req.APIKey); err != nil {
+		return nil, err
+	}
+	verifiedAt := time.Now()
+
 	encryptedKey, err := s.cipher.Encrypt(req.APIKey)
 	if err != nil {
 		return nil, err
@@ -62,11 +111,12 @@ func (s *aiConfigService) Create(ctx context.Context, userID string, req *domain
 		}
 
 		config := &domain.UserAIConfig{
-			UserID:    userID,
-			AIModelID: req.AIModelID,
-			APIKey:    encryptedKey,
-			IsDefault: req.IsDefault,
-			Settings:  req.Settings,
+			UserID:         userID,
+			AIModelID:      req.AIModelID,
+			APIKey:         encryptedKey,
+			IsDefault:      req.IsDefault,
+			Settings:       req.Settings,
+			LastVerifiedAt: &verifiedAt,
 		}
 
 		if err := txRepo.Create(ctx, config); err != nil {
@@ -79,6 +129,16 @@ func (s *aiConfigService) Create(ctx context.Context, userID string, req *domain
 	if err != nil {
 		return nil, err
 	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditAIConfigCreated,
+		TargetType: domain.AuditTargetAIConfig,
+		TargetID:   configID,
+		Changes: domain.AuditChanges{
+			"ai_model_id": {To: req.AIModelID},
+			"is_default":  {To: req.IsDefault},
+		},
+	})
 
 	// Return via the service read path so the API key is decrypted for the caller.
 	return s.GetByID(ctx, userID, configID)
@@ -90,6 +150,17 @@ func (s *aiConfigService) Update(ctx context.Context, userID string, configID st
 		return nil, err
 	}
 
+	changes := aiConfigChanges(config, req)
+
+	if req.APIKey != nil {
+		if err := s.verify(ctx, config.AIModelID, *req.APIKey); err != nil {
+			return nil, err
+		}
+		verifiedAt := time.Now()
+		config.LastVerifiedAt = &verifiedAt
+		config.LastError = ""
+	}
+
 	err = s.aiConfigRepo.WithTypedTransaction(ctx, func(txRepo repository.AIConfigRepository) error {
 		if req.IsDefault != nil && *req.IsDefault {
 			if err := txRepo.ClearDefaultByUserID(ctx, userID, configID); err != nil {
@@ -121,6 +192,15 @@ func (s *aiConfigService) Update(ctx context.Context, userID string, configID st
 	if err != nil {
 		return nil, err
 	}
+	if len(changes) > 0 {
+		s.auditLog.Record(ctx, domain.AuditEvent{
+			ActorID:    auditActor(userID),
+			Action:     domain.AuditAIConfigUpdated,
+			TargetType: domain.AuditTargetAIConfig,
+			TargetID:   configID,
+			Changes:    changes,
+		})
+	}
 
 	// Return via the service read path so the API key is decrypted for the caller.
 	return s.GetByID(ctx, userID, configID)
@@ -162,7 +242,33 @@ func (s *aiConfigService) Delete(ctx context.Context, userID string, configID st
 		return err
 	}
 
-	return s.aiConfigRepo.Delete(ctx, config.ID)
+	if err := s.aiConfigRepo.Delete(ctx, config.ID); err != nil {
+		return err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditAIConfigDeleted,
+		TargetType: domain.AuditTargetAIConfig,
+		TargetID:   config.ID,
+		Changes:    domain.AuditChanges{"ai_model_id": {From: config.AIModelID}},
+	})
+	return nil
+}
+
+// aiConfigChanges diffs req against config for the audit trail. Keys are
+// never written to it: a replaced key is recorded as redacted on both sides.
+func aiConfigChanges(config *domain.UserAIConfig, req *domain.UpdateUserAIConfigRequest) domain.AuditChanges {
+	changes := domain.AuditChanges{}
+	if req.APIKey != nil && *req.APIKey != config.APIKey {
+		changes["api_key"] = domain.AuditChange{From: auditRedacted, To: auditRedacted}
+	}
+	if req.IsDefault != nil && *req.IsDefault != config.IsDefault {
+		changes["is_default"] = domain.AuditChange{From: config.IsDefault, To: *req.IsDefault}
+	}
+	if req.Settings != nil && !bytes.Equal(req.Settings, config.Settings) {
+		changes["settings"] = domain.AuditChange{From: config.Settings, To: req.Settings}
+	}
+	return changes
 }
 
 func (s *aiConfigService) ListAIModels(ctx context.Context) ([]domain.AIModel, error) {
@@ -194,3 +300,34 @@ func (s *aiConfigService) GetDefaultConfig(ctx context.Context, userID string) (
 	config.APIKey = decryptAPIKey(s.cipher, s.logger, config.APIKey)
 	return config, nil
 }
+
+// Test re-checks a stored config against its provider and records the outcome
+// on the config. A provider failure is both recorded and returned.
+func (s *aiConfigService) Test(ctx context.Context, userID string, configID string) (*domain.UserAIConfig, error) {
+	config, err := s.GetByID(ctx, userID, configID)
+	if err != nil {
+		return nil, err
+	}
+
+	verifyErr := s.verify(ctx, config.AIModelID, config.APIKey)
+	if verifyErr != nil && !apperrors.IsAIProvider(verifyErr) {
+		return nil, verifyErr
+	}
+
+	var verifiedAt *time.Time
+	lastError := ""
+	if verifyErr != nil {
+		lastError = verifyErr.Error()
+	} else {
+		now := time.Now()
+		verifiedAt = &now
+	}
+	if err := s.aiConfigRepo.RecordVerification(ctx, config.ID, verifiedAt, lastError); err != nil {
+		return nil, err
+	}
+	if verifyErr != nil {
+		return nil, verifyErr
+	}
+
+	return s.GetByID(ctx, userID, configID)
+}
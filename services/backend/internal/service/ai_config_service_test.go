@@ -2,12 +2,18 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/repository"
+	"github.com/H3nSte1n/recipe/pkg/ai"
 	"github.com/H3nSte1n/recipe/pkg/crypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -60,6 +66,30 @@ func (r *fakeAIConfigRepo) GetAIModels(_ context.Context) ([]domain.AIModel, err
 	return nil, nil
 }
 
+func (r *fakeAIConfigRepo) GetAIModelByID(_ context.Context, id string) (*domain.AIModel, error) {
+	return &domain.AIModel{ID: id, Provider: "anthropic", ModelVersion: "claude-haiku-4-5"}, nil
+}
+
+func (r *fakeAIConfigRepo) ListAllAIModels(_ context.Context) ([]domain.AIModel, error) {
+	return nil, nil
+}
+
+func (r *fakeAIConfigRepo) GetAIModelIncludingInactive(ctx context.Context, id string) (*domain.AIModel, error) {
+	return r.GetAIModelByID(ctx, id)
+}
+
+func (r *fakeAIConfigRepo) SetAIModelActive(_ context.Context, _ string, _ bool) error { return nil }
+
+func (r *fakeAIConfigRepo) RecordVerification(_ context.Context, id string, verifiedAt *time.Time, lastError string) error {
+	c := r.store[id]
+	if verifiedAt != nil {
+		c.LastVerifiedAt = verifiedAt
+	}
+	c.LastError = lastError
+	r.store[id] = c
+	return nil
+}
+
 func (r *fakeAIConfigRepo) GetDefaultConfig(_ context.Context, userID string) (*domain.UserAIConfig, error) {
 	for _, c := range r.store {
 		if c.UserID == userID && c.IsDefault {
@@ -90,11 +120,22 @@ func (r *fakeAIConfigRepo) rawStoredKey(id string) string {
 }
 
 func newTestAIConfigService(t *testing.T) (*fakeAIConfigRepo, AIConfigService, *crypto.Cipher) {
+	t.Helper()
+	model := new(mockAIModel)
+	model.On("Verify", mock.Anything).Return(nil).Maybe()
+	return newTestAIConfigServiceWithModel(t, model)
+}
+
+// newTestAIConfigServiceWithModel wires a factory that hands out model for
+// every key, so tests control what verification reports.
+func newTestAIConfigServiceWithModel(t *testing.T, model *mockAIModel) (*fakeAIConfigRepo, AIConfigService, *crypto.Cipher) {
 	t.Helper()
 	repo := newFakeAIConfigRepo()
 	cipher, err := crypto.NewCipher("test-encryption-key")
 	require.NoError(t, err)
-	svc := NewAIConfigService(repo, cipher, zap.NewNop())
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", ai.ModelClaudeHaiku45, mock.AnythingOfType("string")).Return(model, nil).Maybe()
+	svc := NewAIConfigService(repo, cipher, factory, nil, zap.NewNop())
 	return repo, svc, cipher
 }
 
@@ -167,3 +208,97 @@ func TestAIConfigService_LegacyPlaintextReadFallback(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "legacy-plaintext-key", got.APIKey, "legacy plaintext must be returned as-is")
 }
+
+func TestAIConfigService_CreateRejectsKeyTheProviderRefuses(t *testing.T) {
+	model := new(mockAIModel)
+	model.On("Verify", mock.Anything).Return(fmt.Errorf("Claude API error: %w", ai.ErrProviderAuth)).Once()
+	repo, svc, _ := newTestAIConfigServiceWithModel(t, model)
+
+	_, err := svc.Create(context.Background(), "user-1", &domain.CreateUserAIConfigRequest{
+		AIModelID: "model-1",
+		APIKey:    "sk-ant-WRONG",
+	})
+
+	require.ErrorIs(t, err, apperrors.ErrAIAuthFailed)
+	assert.Equal(t, http.StatusUnprocessableEntity, apperrors.StatusCode(err))
+	assert.Empty(t, repo.store, "a rejected key must not be stored")
+}
+
+func TestAIConfigService_CreateRecordsVerification(t *testing.T) {
+	_, svc, _ := newTestAIConfigService(t)
+
+	created, err := svc.Create(context.Background(), "user-1", &domain.CreateUserAIConfigRequest{
+		AIModelID: "model-1",
+		APIKey:    "sk-ant-EXAMPLE",
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, created.LastVerifiedAt)
+	assert.Empty(t, created.LastError)
+}
+
+func TestAIConfigService_UpdateVerifiesOnlyNewKeys(t *testing.T) {
+	model := new(mockAIModel)
+	model.On("Verify", mock.Anything).Return(nil).Once()
+	model.On("Verify", mock.Anything).Return(fmt.Errorf("GPT API error: %w", ai.ErrProviderQuota)).Once()
+	_, svc, _ := newTestAIConfigServiceWithModel(t, model)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "user-1", &domain.CreateUserAIConfigRequest{
+		AIModelID: "model-1",
+		APIKey:    "sk-ant-EXAMPLE",
+	})
+	require.NoError(t, err)
+
+	isDefault := true
+	_, err = svc.Update(ctx, "user-1", created.ID, &domain.UpdateUserAIConfigRequest{IsDefault: &isDefault})
+	require.NoError(t, err, "an update without a new key must not call the provider")
+
+	newKey := "sk-ant-OTHER"
+	_, err = svc.Update(ctx, "user-1", created.ID, &domain.UpdateUserAIConfigRequest{APIKey: &newKey})
+	require.ErrorIs(t, err, apperrors.ErrAIQuotaExceeded)
+	assert.Equal(t, http.StatusTooManyRequests, apperrors.StatusCode(err))
+	model.AssertExpectations(t)
+}
+
+func TestAIConfigService_TestRecordsOutcome(t *testing.T) {
+	model := new(mockAIModel)
+	model.On("Verify", mock.Anything).Return(nil).Once()
+	model.On("Verify", mock.Anything).Return(fmt.Errorf("Claude API error: %w", ai.ErrProviderModelNotFound)).Once()
+	model.On("Verify", mock.Anything).Return(nil).Once()
+	repo, svc, _ := newTestAIConfigServiceWithModel(t, model)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "user-1", &domain.CreateUserAIConfigRequest{
+		AIModelID: "model-1",
+		APIKey:    "sk-ant-EXAMPLE",
+	})
+	require.NoError(t, err)
+	firstVerified := *created.LastVerifiedAt
+
+	_, err = svc.Test(ctx, "user-1", created.ID)
+	require.ErrorIs(t, err, apperrors.ErrAIModelNotFound)
+	stored := repo.store[created.ID]
+	assert.Equal(t, apperrors.ErrAIModelNotFound.Message, stored.LastError)
+	assert.Equal(t, firstVerified, *stored.LastVerifiedAt, "a failed check keeps the last success time")
+
+	got, err := svc.Test(ctx, "user-1", created.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.LastError)
+	assert.False(t, got.LastVerifiedAt.Before(firstVerified))
+	model.AssertExpectations(t)
+}
+
+func TestAIConfigService_TestHidesOtherUsersConfig(t *testing.T) {
+	_, svc, _ := newTestAIConfigService(t)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "user-1", &domain.CreateUserAIConfigRequest{
+		AIModelID: "model-1",
+		APIKey:    "sk-ant-EXAMPLE",
+	})
+	require.NoError(t, err)
+
+	_, err = svc.Test(ctx, "user-2", created.ID)
+	assert.True(t, apperrors.IsNotFound(err))
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/ai"
+)
+
+// aiModelFactory is the part of ai.ModelFactory the services use.
+type aiModelFactory interface {
+	CreateModel(modelType ai.ModelType, apiKey string) (ai.AIModel, error)
+}
+
+// mapAIModelToModelType resolves a stored AI model row to the client the
+// factory builds for it.
+func mapAIModelToModelType(model *domain.AIModel) (ai.ModelType, error) {
+	key := fmt.Sprintf("%s-%s", model.Provider, model.ModelVersion)
+
+	switch key {
+	case "openai-gpt-4":
+		return ai.ModelGPT4, nil
+	case "openai-gpt-4-turbo-preview":
+		return ai.ModelGPT4Turbo, nil
+	case "openai-gpt-3.5-turbo":
+		return ai.ModelGPT35, nil
+	case "anthropic-claude-sonnet-5":
+		return ai.ModelClaudeSonnet5, nil
+	case "anthropic-claude-opus-4-8":
+		return ai.ModelClaudeOpus48, nil
+	case "anthropic-claude-haiku-4-5":
+		return ai.ModelClaudeHaiku45, nil
+	default:
+		return "", apperrors.New(fmt.Sprintf("unsupported model: %s", key), "INVALID_INPUT")
+	}
+}
+
+// aiProviderError converts a classified provider failure into the matching
+// ErrAI* app error. Anything else is returned unchanged.
+func aiProviderError(err error) error {
+	switch {
+	case errors.Is(err, ai.ErrProviderAuth):
+		return apperrors.ErrAIAuthFailed
+	case errors.Is(err, ai.ErrProviderQuota):
+		return apperrors.ErrAIQuotaExceeded
+	case errors.Is(err, ai.ErrProviderModelNotFound):
+		return apperrors.ErrAIModelNotFound
+	case errors.Is(err, ai.ErrProviderUnavailable):
+		return apperrors.ErrAIUnavailable
+	default:
+		return err
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAPITokenLifetime = 90 * 24 * time.Hour
+	// maxAPITokensPerUser bounds how many live tokens one account can hold.
+	maxAPITokensPerUser = 50
+	// apiTokenLastUsedResolution is how stale last_used_at may get before a
+	// request refreshes it.
+	apiTokenLastUsedResolution = time.Minute
+	// apiTokenPrefixLen is how much of the token is kept for display.
+	apiTokenPrefixLen = len(domain.APITokenPrefix) + 8
+)
+
+type apiTokenRepository interface {
+	Create(ctx context.Context, token *domain.APIToken) error
+	ListActive(ctx context.Context, userID string) ([]domain.APIToken, error)
+	CountActive(ctx context.Context, userID string, now time.Time) (int64, error)
+	GetByID(ctx context.Context, userID, id string) (*domain.APIToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error)
+	UpdateName(ctx context.Context, userID, id, name string) (bool, error)
+	Revoke(ctx context.Context, userID, id string, revokedAt time.Time) (bool, error)
+	TouchLastUsed(ctx context.Context, id string, usedAt, notBefore time.Time) error
+}
+
+type APITokenService interface {
+	Create(ctx context.Context, userID string, req *domain.CreateAPITokenRequest) (*domain.CreateAPITokenResponse, error)
+	List(ctx context.Context, userID string) ([]domain.APIToken, error)
+	Get(ctx context.Context, userID, id string) (*domain.APIToken, error)
+	Update(ctx context.Context, userID, id string, req *domain.UpdateAPITokenRequest) (*domain.APIToken, error)
+	Revoke(ctx context.Context, userID, id string) error
+	// VerifyAPIToken authenticates a presented token for the auth middleware.
+	VerifyAPIToken(ctx context.Context, token string) (userID string, scopes []string, err error)
+}
+
+// errInvalidAPIToken covers unknown, expired and revoked tokens alike.
+var errInvalidAPIToken = apperrors.New("invalid API token")
+
+type apiTokenService struct {
+	repo     apiTokenRepository
+	auditLog *AuditLog
+	logger   *zap.Logger
+}
+
+func NewAPITokenService(repo apiTokenRepository, auditLog *AuditLog, logger *zap.Logger) APITokenService {
+	return &apiTokenService{
+		repo:     repo,
+		auditLog: auditLog,
+		logger:   logger,
+	}
+}
+
+func (s *apiTokenService) Create(ctx context.Context, userID string, req *domain.CreateAPITokenRequest) (*domain.CreateAPITokenResponse, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	count, err := s.repo.CountActive(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAPITokensPerUser {
+		return nil, apperrors.New("too many API tokens; revoke an unused one first", "INVALID_INPUT")
+	}
+
+	lifetime := defaultAPITokenLifetime
+	if req.ExpiresInDays > 0 {
+		lifetime = time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	plaintext := domain.APITokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	token := domain.APIToken{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		TokenPrefix: plaintext[:apiTokenPrefixLen],
+		TokenHash:   hashRefreshSecret(plaintext),
+		Scopes:      scopes,
+		ExpiresAt:   now.Add(lifetime),
+	}
+	if err := s.repo.Create(ctx, &token); err != nil {
+		return nil, err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditAPITokenCreated,
+		TargetType: domain.AuditTargetAPIToken,
+		TargetID:   token.ID,
+		Changes: domain.AuditChanges{
+			"name":       {To: token.Name},
+			"scopes":     {To: token.Scopes},
+			"expires_at": {To: token.ExpiresAt.UTC()},
+		},
+	})
+
+	return &domain.CreateAPITokenResponse{APIToken: token, Token: plaintext}, nil
+}
+
+// normalizeScopes validates scopes and drops duplicates.
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !domain.ValidAPITokenScope(scope) {
+			return nil, apperrors.New("invalid scope: "+scope, "INVALID_INPUT")
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
+
+func (s *apiTokenService) List(ctx context.Context, userID string) ([]domain.APIToken, error) {
+	return s.repo.ListActive(ctx, userID)
+}
+
+func (s *apiTokenService) Get(ctx context.Context, userID, id string) (*domain.APIToken, error) {
+	token, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.ErrNotFound.Wrap("API token not found")
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *apiTokenService) Update(ctx context.Context, userID, id string, req *domain.UpdateAPITokenRequest) (*domain.APIToken, error) {
+	updated, err := s.repo.UpdateName(ctx, userID, id, strings.TrimSpace(req.Name))
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, apperrors.ErrNotFound.Wrap("API token not found")
+	}
+	return s.Get(ctx, userID, id)
+}
+
+func (s *apiTokenService) Revoke(ctx context.Context, userID, id string) error {
+	revoked, err := s.repo.Revoke(ctx, userID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return apperrors.ErrNotFound.Wrap("API token not found")
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditAPITokenRevoked,
+		TargetType: domain.AuditTargetAPIToken,
+		TargetID:   id,
+	})
+	return nil
+}
+
+func (s *apiTokenService) VerifyAPIToken(ctx context.Context, plaintext string) (string, []string, error) {
+	if !strings.HasPrefix(plaintext, domain.APITokenPrefix) {
+		return "", nil, errInvalidAPIToken
+	}
+
+	token, err := s.repo.GetByHash(ctx, hashRefreshSecret(plaintext))
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return "", nil, errInvalidAPIToken
+		}
+		return "", nil, err
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil || !now.Before(token.ExpiresAt) {
+		return "", nil, errInvalidAPIToken
+	}
+
+	// Best-effort: a failed bookkeeping write must not fail the request.
+	if err := s.repo.TouchLastUsed(ctx, token.ID, now, now.Add(-apiTokenLastUsedResolution)); err != nil {
+		s.logger.Warn("failed to record API token use", zap.String("token_id", token.ID), zap.Error(err))
+	}
+
+	return token.UserID, token.Scopes, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockAPITokenRepo struct {
+	mock.Mock
+}
+
+func (m *mockAPITokenRepo) Create(ctx context.Context, token *domain.APIToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *mockAPITokenRepo) ListActive(ctx context.Context, userID string) ([]domain.APIToken, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.APIToken)
+	return v, args.Error(1)
+}
+
+func (m *mockAPITokenRepo) CountActive(ctx context.Context, userID string, now time.Time) (int64, error) {
+	args := m.Called(ctx, userID, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockAPITokenRepo) GetByID(ctx context.Context, userID, id string) (*domain.APIToken, error) {
+	args := m.Called(ctx, userID, id)
+	v, _ := args.Get(0).(*domain.APIToken)
+	return v, args.Error(1)
+}
+
+func (m *mockAPITokenRepo) GetByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	args := m.Called(ctx, tokenHash)
+	v, _ := args.Get(0).(*domain.APIToken)
+	return v, args.Error(1)
+}
+
+func (m *mockAPITokenRepo) UpdateName(ctx context.Context, userID, id, name string) (bool, error) {
+	args := m.Called(ctx, userID, id, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockAPITokenRepo) Revoke(ctx context.Context, userID, id string, revokedAt time.Time) (bool, error) {
+	args := m.Called(ctx, userID, id, revokedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockAPITokenRepo) TouchLastUsed(ctx context.Context, id string, usedAt, notBefore time.Time) error {
+	args := m.Called(ctx, id, usedAt, notBefore)
+	return args.Error(0)
+}
+
+func TestAPITokenService_Create(t *testing.T) {
+	t.Run("returns the plaintext once and stores only its hash", func(t *testing.T) {
+		m := new(mockAPITokenRepo)
+		m.On("CountActive", mock.Anything, "user-1", mock.Anything).Return(int64(0), nil).Once()
+		m.On("Create", mock.Anything, mock.AnythingOfType("*domain.APIToken")).Return(nil).Once()
+
+		srv := NewAPITokenService(m, nil, zap.NewNop())
+		resp, err := srv.Create(context.Background(), "user-1", &domain.CreateAPITokenRequest{
+			Name:          " CI export ",
+			Scopes:        []string{"recipes:read", "Recipes:Read", "shopping_lists:write"},
+			ExpiresInDays: 7,
+		})
+
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(resp.Token, domain.APITokenPrefix))
+		require.Equal(t, "CI export", resp.Name)
+		require.Equal(t, []string{"recipes:read", "shopping_lists:write"}, resp.Scopes)
+		require.Equal(t, resp.Token[:apiTokenPrefixLen], resp.TokenPrefix)
+		require.Equal(t, hashRefreshSecret(resp.Token), resp.TokenHash)
+		require.WithinDuration(t, time.Now().Add(7*24*time.Hour), resp.ExpiresAt, time.Minute)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown scopes", func(t *testing.T) {
+		m := new(mockAPITokenRepo)
+
+		srv := NewAPITokenService(m, nil, zap.NewNop())
+		_, err := srv.Create(context.Background(), "user-1", &domain.CreateAPITokenRequest{
+			Name:   "bad",
+			Scopes: []string{"recipes:admin"},
+		})
+
+		require.True(t, apperrors.IsInvalidInput(err))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects when the user has too many tokens", func(t *testing.T) {
+		m := new(mockAPITokenRepo)
+		m.On("CountActive", mock.Anything, "user-1", mock.Anything).Return(int64(maxAPITokensPerUser), nil).Once()
+
+		srv := NewAPITokenService(m, nil, zap.NewNop())
+		_, err := srv.Create(context.Background(), "user-1", &domain.CreateAPITokenRequest{
+			Name:   "one too many",
+			Scopes: []string{"recipes:read"},
+		})
+
+		require.True(t, apperrors.IsInvalidInput(err))
+		m.AssertExpectations(t)
+	})
+}
+
+func TestAPITokenService_VerifyAPIToken(t *testing.T) {
+	const plaintext = domain.APITokenPrefix + "secret"
+	active := func() *domain.APIToken {
+		return &domain.APIToken{
+			ID:        "token-1",
+			UserID:    "user-1",
+			Scopes:    []string{"recipes:read"},
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("accepts an active token and records its use", func(t *testing.T) {
+		m := new(mockAPITokenRepo)
+		m.On("GetByHash", mock.Anything, hashRefreshSecret(plaintext)).Return(active(), nil).Once()
+		m.On("TouchLastUsed", mock.Anything, "token-1", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewAPITokenService(m, nil, zap.NewNop())
+		userID, scopes, err := srv.VerifyAPIToken(context.Background(), plaintext)
+
+		require.NoError(t, err)
+		require.Equal(t, "user-1", userID)
+		require.Equal(t, []string{"recipes:read"}, scopes)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		token := active()
+		token.ExpiresAt = time.Now().Add(-time.Minute)
+		m := new(mockAPITokenRepo)
+		m.On("GetByHash", mock.Anything, hashRefreshSecret(plaintext)).Return(token, nil).Once()
+
+		srv := NewAPITokenService(m, nil, zap.NewNop())
+		_, _, err := srv.VerifyAPIToken(context.Background(), plaintext)
+
+		require.ErrorIs(t, err, errInvalidAPIToken)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects revoked tokens", func(t *testing.T) {
+		token := active()
+		revokedAt := time.Now().Add(-time.Minute)
+		token.RevokedAt = &revokedAt
+		m := new(mockAPITokenRepo)
+		m.On("GetByHash", mock.Anything, hashRefreshSecret(plaintext)).Return(token, nil).Once()
+
+		srv := NewAPITokenService(m, nil, zap.NewNop())
+		_, _, err := srv.VerifyAPIToken(context.Background(), plaintext)
+
+		require.ErrorIs(t, err, errInvalidAPIToken)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown tokens", func(t *testing.T) {
+		m := new(mockAPITokenRepo)
+		m.On("GetByHash", mock.Anything, hashRefreshSecret(plaintext)).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewAPITokenService(m, nil, zap.NewNop())
+		_, _, err := srv.VerifyAPIToken(context.Background(), plaintext)
+
+		require.ErrorIs(t, err, errInvalidAPIToken)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestAPITokenService_Revoke_NotFound(t *testing.T) {
+	m := new(mockAPITokenRepo)
+	m.On("Revoke", mock.Anything, "user-1", "token-1", mock.Anything).Return(false, nil).Once()
+
+	srv := NewAPITokenService(m, nil, zap.NewNop())
+	err := srv.Revoke(context.Background(), "user-1", "token-1")
+
+	require.True(t, apperrors.IsNotFound(err))
+	m.AssertExpectations(t)
+}
@@ -3,7 +3,8 @@ package service
 import "go.uber.org/zap"
 
 // APIKeyCipher encrypts and decrypts user AI API keys at the repository boundary.
-// Implemented by pkg/crypto.Cipher.
+// The user service also uses it for TOTP secrets. Implemented by
+// pkg/crypto.Cipher.
 type APIKeyCipher interface {
 	Encrypt(plaintext string) (string, error)
 	Decrypt(ciphertext string) (string, error)
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"go.uber.org/zap"
+)
+
+type auditEventWriter interface {
+	Create(ctx context.Context, event *domain.AuditEvent) error
+}
+
+type auditRepository interface {
+	ListForUser(ctx context.Context, userID string, filter domain.ActivityFilter) ([]domain.AuditEvent, error)
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// AuditLog appends events to the audit trail on behalf of the other services.
+// The audited action has already happened by the time it is recorded, so a
+// failed write is logged rather than returned. A nil *AuditLog records
+// nothing, which keeps it optional for services under test.
+type AuditLog struct {
+	repo   auditEventWriter
+	logger *zap.Logger
+}
+
+func NewAuditLog(repo auditEventWriter, logger *zap.Logger) *AuditLog {
+	return &AuditLog{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Record writes event. IP address and user agent default to the client
+// attached to ctx by middleware.AuditContext.
+func (a *AuditLog) Record(ctx context.Context, event domain.AuditEvent) {
+	if a == nil {
+		return
+	}
+
+	client := domain.AuditClientFromContext(ctx)
+	if event.IPAddress == "" {
+		event.IPAddress = client.IPAddress
+	}
+	if event.UserAgent == "" {
+		event.UserAgent = client.UserAgent
+	}
+	event.UserAgent = truncateUserAgent(event.UserAgent)
+	if event.Changes == nil {
+		event.Changes = domain.AuditChanges{}
+	}
+
+	if err := a.repo.Create(ctx, &event); err != nil {
+		a.logger.Error("failed to write audit event",
+			zap.String("action", event.Action),
+			zap.String("target_type", event.TargetType),
+			zap.String("target_id", event.TargetID),
+			zap.Error(err))
+	}
+}
+
+// auditRedacted stands in for secret values, such as API keys, in an event's
+// changes.
+const auditRedacted = "[redacted]"
+
+// auditActor returns userID as an event's ActorID, or nil for no actor.
+func auditActor(userID string) *string {
+	if userID == "" {
+		return nil
+	}
+	return &userID
+}
+
+// defaultAuditRetention applies when audit.retention_days is not set.
+const defaultAuditRetention = 365 * 24 * time.Hour
+
+// auditRetentionInterval is how often RunRetention purges expired events.
+const auditRetentionInterval = 24 * time.Hour
+
+type AuditService interface {
+	// ListActivity returns what happened on the user's account: their own
+	// actions, sign-in attempts against it and administrator actions on it.
+	ListActivity(ctx context.Context, userID string, filter domain.ActivityFilter) ([]domain.AuditEvent, error)
+	// PurgeExpired deletes events older than the retention period.
+	PurgeExpired(ctx context.Context) (int64, error)
+	// RunRetention purges expired events once at start and then daily until
+	// ctx is done.
+	RunRetention(ctx context.Context)
+}
+
+type auditService struct {
+	repo      auditRepository
+	retention time.Duration
+	logger    *zap.Logger
+}
+
+func NewAuditService(repo auditRepository, retentionDays int, logger *zap.Logger) AuditService {
+	retention := defaultAuditRetention
+	if retentionDays > 0 {
+		retention = time.Duration(retentionDays) * 24 * time.Hour
+	}
+	return &auditService{
+		repo:      repo,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+func (s *auditService) ListActivity(ctx context.Context, userID string, filter domain.ActivityFilter) ([]domain.AuditEvent, error) {
+	events, err := s.repo.ListForUser(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Where someone else acted on the account (an administrator), keep who
+	// and what but not their network details. Anonymous events such as
+	// failed sign-ins keep theirs: that is what the owner needs to see.
+	for i := range events {
+		if actor := events[i].ActorID; actor != nil && *actor != userID {
+			events[i].IPAddress = ""
+			events[i].UserAgent = ""
+		}
+	}
+	return events, nil
+}
+
+func (s *auditService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteBefore(ctx, time.Now().Add(-s.retention))
+}
+
+func (s *auditService) RunRetention(ctx context.Context) {
+	ticker := time.NewTicker(auditRetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.PurgeExpired(ctx)
+		if err != nil {
+			s.logger.Error("failed to purge expired audit events", zap.Error(err))
+		} else if deleted > 0 {
+			s.logger.Info("purged expired audit events", zap.Int64("deleted", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/pkg/config"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type mockAuditRepo struct {
+	mock.Mock
+}
+
+func (m *mockAuditRepo) Create(ctx context.Context, event *domain.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *mockAuditRepo) List(ctx context.Context, filter domain.AuditEventFilter) ([]domain.AuditEvent, error) {
+	args := m.Called(ctx, filter)
+	v, _ := args.Get(0).([]domain.AuditEvent)
+	return v, args.Error(1)
+}
+
+func (m *mockAuditRepo) ListForUser(ctx context.Context, userID string, filter domain.ActivityFilter) ([]domain.AuditEvent, error) {
+	args := m.Called(ctx, userID, filter)
+	v, _ := args.Get(0).([]domain.AuditEvent)
+	return v, args.Error(1)
+}
+
+func (m *mockAuditRepo) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestAuditLog_Record(t *testing.T) {
+	t.Run("a nil log records nothing", func(t *testing.T) {
+		var log *AuditLog
+		log.Record(context.Background(), domain.AuditEvent{Action: domain.AuditLoginFailed})
+	})
+
+	t.Run("fills the client from the request context", func(t *testing.T) {
+		repo := new(mockAuditRepo)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+			return e.IPAddress == "198.51.100.4" && e.UserAgent == "test-agent" && e.Changes != nil
+		})).Return(nil).Once()
+
+		ctx := domain.ContextWithAuditClient(context.Background(), domain.SessionClient{IPAddress: "198.51.100.4", UserAgent: "test-agent"})
+		NewAuditLog(repo, zap.NewNop()).Record(ctx, domain.AuditEvent{Action: domain.AuditRecipeDeleted})
+
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestAuditService_ListActivity_RedactsOtherActorsClient(t *testing.T) {
+	user, admin := "user-1", "admin-1"
+	repo := new(mockAuditRepo)
+	repo.On("ListForUser", mock.Anything, user, domain.ActivityFilter{}).Return([]domain.AuditEvent{
+		{ID: "by-admin", ActorID: &admin, IPAddress: "203.0.113.7", UserAgent: "admin-agent"},
+		{ID: "own", ActorID: &user, IPAddress: "198.51.100.4", UserAgent: "user-agent"},
+		{ID: "failed", IPAddress: "192.0.2.1", UserAgent: "attacker-agent"},
+	}, nil).Once()
+
+	events, err := NewAuditService(repo, 0, zap.NewNop()).ListActivity(context.Background(), user, domain.ActivityFilter{})
+
+	require.NoError(t, err)
+	require.Empty(t, events[0].IPAddress)
+	require.Empty(t, events[0].UserAgent)
+	require.Equal(t, "198.51.100.4", events[1].IPAddress)
+	require.Equal(t, "192.0.2.1", events[2].IPAddress)
+}
+
+func TestAuditService_PurgeExpired(t *testing.T) {
+	repo := new(mockAuditRepo)
+	repo.On("DeleteBefore", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff).Round(time.Hour) == 30*24*time.Hour
+	})).Return(int64(3), nil).Once()
+
+	deleted, err := NewAuditService(repo, 30, zap.NewNop()).PurgeExpired(context.Background())
+
+	require.NoError(t, err)
+	require.EqualValues(t, 3, deleted)
+	repo.AssertExpectations(t)
+}
+
+func TestUserService_Login_AuditsFailureAndLockout(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("barfoo"), bcrypt.MinCost)
+	user := &domain.User{ID: "1_foo", Email: "foo@bar.com", PasswordHash: string(hash), FailedLoginAttempts: maxFailedLoginAttempts - 1}
+	lockedUntil := time.Now().Add(accountLockDuration)
+	client := domain.SessionClient{IPAddress: "192.0.2.1", UserAgent: "test-agent"}
+
+	m := new(mockUserRepository)
+	m.On("GetByEmail", mock.Anything, user.Email).Return(user, nil).Once()
+	m.On("RecordFailedLogin", mock.Anything, user.ID, maxFailedLoginAttempts, mock.AnythingOfType("time.Time")).
+		Return(maxFailedLoginAttempts, &lockedUntil, nil).Once()
+	audit := new(mockAuditRepo)
+	for _, action := range []string{domain.AuditLoginFailed, domain.AuditAccountLocked} {
+		audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+			return e.Action == action && e.ActorID == nil && e.TargetID == user.ID && e.IPAddress == client.IPAddress
+		})).Return(nil).Once()
+	}
+
+	srv := NewUserService(m, "foobar", config.Config{}, nil, nil, NewAuditLog(audit, zap.NewNop()), zap.NewNop())
+	_, err := srv.Login(context.Background(), &domain.LoginRequest{Email: user.Email, Password: "foobar"}, client)
+
+	require.Error(t, err)
+	m.AssertExpectations(t)
+	audit.AssertExpectations(t)
+}
+
+func TestAIConfigService_Update_AuditsKeyChangeWithoutTheKey(t *testing.T) {
+	repo, _, cipher := newTestAIConfigService(t)
+	model := new(mockAIModel)
+	model.On("Verify", mock.Anything).Return(nil).Maybe()
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", mock.Anything, mock.Anything).Return(model, nil).Maybe()
+	audit := new(mockAuditRepo)
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditAIConfigCreated
+	})).Return(nil).Once()
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		change, ok := e.Changes["api_key"]
+		return e.Action == domain.AuditAIConfigUpdated && ok &&
+			change.From == auditRedacted && change.To == auditRedacted
+	})).Return(nil).Once()
+
+	svc := NewAIConfigService(repo, cipher, factory, NewAuditLog(audit, zap.NewNop()), zap.NewNop())
+	ctx := context.Background()
+	created, err := svc.Create(ctx, "user-1", &domain.CreateUserAIConfigRequest{AIModelID: "model-1", APIKey: "sk-ant-OLD"})
+	require.NoError(t, err)
+	newKey := "sk-ant-NEW"
+	_, err = svc.Update(ctx, "user-1", created.ID, &domain.UpdateUserAIConfigRequest{APIKey: &newKey})
+
+	require.NoError(t, err)
+	audit.AssertExpectations(t)
+}
+
+func TestAPITokenService_AuditsCreateAndRevoke(t *testing.T) {
+	m := new(mockAPITokenRepo)
+	m.On("CountActive", mock.Anything, "user-1", mock.Anything).Return(int64(0), nil).Once()
+	m.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	m.On("Revoke", mock.Anything, "user-1", mock.Anything, mock.Anything).Return(true, nil).Once()
+
+	var created *domain.AuditEvent
+	audit := new(mockAuditRepo)
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditAPITokenCreated
+	})).Run(func(args mock.Arguments) { created = args.Get(1).(*domain.AuditEvent) }).Return(nil).Once()
+	audit.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.Action == domain.AuditAPITokenRevoked && *e.ActorID == "user-1" && e.TargetType == domain.AuditTargetAPIToken
+	})).Return(nil).Once()
+
+	srv := NewAPITokenService(m, NewAuditLog(audit, zap.NewNop()), zap.NewNop())
+	resp, err := srv.Create(context.Background(), "user-1", &domain.CreateAPITokenRequest{Name: "CI", Scopes: []string{"recipes:read"}})
+	require.NoError(t, err)
+	require.NoError(t, srv.Revoke(context.Background(), "user-1", resp.ID))
+
+	audit.AssertExpectations(t)
+	require.Equal(t, resp.ID, created.TargetID)
+	require.Equal(t, "CI", created.Changes["name"].To)
+	require.NotContains(t, created.Changes, "token_hash")
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/pkg/ai"
+	"go.uber.org/zap"
+)
+
+type categoryRepository interface {
+	List(ctx context.Context) ([]domain.GroceryCategory, error)
+	FindTerms(ctx context.Context, terms []string) ([]domain.CategoryTerm, error)
+}
+
+type CategoryService interface {
+	// List returns the category tree, each category named in the first of
+	// languages it has a name in.
+	List(ctx context.Context, languages []string) ([]domain.GroceryCategory, error)
+	Taxonomy(ctx context.Context) (*domain.CategoryTaxonomy, error)
+	// Categorize assigns each of names a category: from the term dictionary
+	// where it knows the name, otherwise from the AI model, otherwise OTHER.
+	// Categorizing is best-effort and never fails the caller.
+	Categorize(ctx context.Context, names []string) map[string]domain.Category
+	// Recategorize is Categorize asking the AI model before the term
+	// dictionary, for items the dictionary placed wrongly. Names the model
+	// cannot place are categorized as Categorize does.
+	Recategorize(ctx context.Context, names []string) map[string]domain.Category
+}
+
+type categoryService struct {
+	categoryRepo categoryRepository
+	aiModel      ai.AIModel
+	logger       *zap.Logger
+}
+
+func NewCategoryService(categoryRepo categoryRepository, aiModel ai.AIModel, logger *zap.Logger) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		aiModel:      aiModel,
+		logger:       logger,
+	}
+}
+
+func (s *categoryService) List(ctx context.Context, languages []string) ([]domain.GroceryCategory, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range categories {
+		categories[i].Name = categoryName(&categories[i], languages)
+	}
+	return categoryTree(categories), nil
+}
+
+func (s *categoryService) Taxonomy(ctx context.Context) (*domain.CategoryTaxonomy, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewCategoryTaxonomy(categories), nil
+}
+
+func (s *categoryService) Categorize(ctx context.Context, names []string) map[string]domain.Category {
+	result := make(map[string]domain.Category, len(names))
+	unknown := s.categorizeWithDictionary(ctx, names, result)
+	if len(unknown) > 0 && s.aiModel != nil {
+		s.categorizeWithAI(ctx, unknown, result)
+	}
+	return result
+}
+
+func (s *categoryService) Recategorize(ctx context.Context, names []string) map[string]domain.Category {
+	result := make(map[string]domain.Category, len(names))
+	if s.aiModel != nil && len(names) > 0 {
+		s.categorizeWithAI(ctx, slices.Compact(slices.Sorted(slices.Values(names))), result)
+	}
+
+	var rest []string
+	for _, name := range names {
+		if _, ok := result[name]; !ok {
+			rest = append(rest, name)
+		}
+	}
+	if len(rest) > 0 {
+		s.categorizeWithDictionary(ctx, rest, result)
+	}
+	return result
+}
+
+// categorizeWithDictionary records in result the categories the term
+// dictionary knows for names, and OTHER for the rest. It returns the names it
+// did not know that have terms worth offering the AI model.
+func (s *categoryService) categorizeWithDictionary(ctx context.Context, names []string, result map[string]domain.Category) []string {
+	candidates := make(map[string][]string, len(names))
+	var terms []string
+	for _, name := range names {
+		if _, ok := candidates[name]; ok {
+			continue
+		}
+		candidates[name] = categoryTermCandidates(name)
+		terms = append(terms, candidates[name]...)
+	}
+
+	// A term spelled the same in several locales takes the category of the
+	// locale the caller prefers most, then of the default locale.
+	locales := slices.Concat(domain.CategoryLanguagesFromContext(ctx), []string{domain.DefaultCategoryLocale})
+	known := make(map[string]domain.Category)
+	rank := make(map[string]int)
+	if found, err := s.categoryRepo.FindTerms(ctx, terms); err != nil {
+		s.logger.Warn("failed to look up category terms", zap.Error(err))
+	} else {
+		for _, t := range found {
+			r := slices.Index(locales, t.Locale)
+			if r < 0 {
+				r = len(locales)
+			}
+			if best, ok := rank[t.Term]; ok && best <= r {
+				continue
+			}
+			known[t.Term] = t.CategoryCode
+			rank[t.Term] = r
+		}
+	}
+
+	var unknown []string
+	for _, name := range names {
+		if _, done := result[name]; done {
+			continue
+		}
+		result[name] = domain.CategoryOther
+		if category, ok := firstKnownTerm(known, candidates[name]); ok {
+			result[name] = category
+		} else if len(candidates[name]) > 0 {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// categorizeWithAI asks the AI model for the categories of names it is
+// offered the taxonomy for, and records the valid answers in result.
+func (s *categoryService) categorizeWithAI(ctx context.Context, names []string, result map[string]domain.Category) {
+	taxonomy, err := s.Taxonomy(ctx)
+	if err != nil {
+		s.logger.Warn("failed to load categories for classification", zap.Error(err))
+		return
+	}
+
+	categories, err := s.aiModel.CategorizeItems(ctx, names, taxonomy.Categories)
+	if err != nil {
+		s.logger.Warn("failed to classify items", zap.Error(err))
+		return
+	}
+	for _, name := range names {
+		if category := domain.Category(categories[name]); taxonomy.Valid(category) {
+			result[name] = category
+		}
+	}
+}
+
+func firstKnownTerm(known map[string]domain.Category, terms []string) (domain.Category, bool) {
+	for _, term := range terms {
+		if category, ok := known[term]; ok {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// categoryTermCandidates returns the dictionary terms name may be listed
+// under, most specific first: the whole name, then its last word ("red
+// onions" is an onion), each also without an English plural ending.
+func categoryTermCandidates(name string) []string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	if normalized == "" {
+		return nil
+	}
+
+	candidates := []string{normalized}
+	add := func(term string) {
+		for _, c := range candidates {
+			if c == term {
+				return
+			}
+		}
+		candidates = append(candidates, term)
+	}
+	add(singularTerm(normalized))
+	if i := strings.LastIndexByte(normalized, ' '); i >= 0 {
+		last := normalized[i+1:]
+		add(last)
+		add(singularTerm(last))
+	}
+	return candidates
+}
+
+// singularTerm strips the common English plural endings. It only has to be
+// good enough to find dictionary terms, not to be correct English.
+func singularTerm(term string) string {
+	switch {
+	case strings.HasSuffix(term, "ies") && len(term) > 4:
+		return strings.TrimSuffix(term, "ies") + "y"
+	case strings.HasSuffix(term, "oes"), strings.HasSuffix(term, "ches"), strings.HasSuffix(term, "shes"):
+		return strings.TrimSuffix(term, "es")
+	case strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") && !strings.HasSuffix(term, "us") && len(term) > 3:
+		return strings.TrimSuffix(term, "s")
+	}
+	return term
+}
+
+// categoryName picks the name of c in the first of languages it has, trying
+// each language's base tag ("de" for "de-AT") as well.
+func categoryName(c *domain.GroceryCategory, languages []string) string {
+	for _, language := range languages {
+		language = strings.ToLower(language)
+		if name := c.Names[language]; name != "" {
+			return name
+		}
+		if base, _, ok := strings.Cut(language, "-"); ok {
+			if name := c.Names[base]; name != "" {
+				return name
+			}
+		}
+	}
+	return c.LocalizedName(domain.DefaultCategoryLocale)
+}
+
+// categoryTree nests categories, which list parents before their children,
+// under their parents and returns the top-level ones.
+func categoryTree(categories []domain.GroceryCategory) []domain.GroceryCategory {
+	children := make(map[domain.Category][]domain.GroceryCategory)
+	for _, c := range categories {
+		if c.ParentCode != nil {
+			children[*c.ParentCode] = append(children[*c.ParentCode], c)
+		}
+	}
+
+	var nest func(c domain.GroceryCategory) domain.GroceryCategory
+	nest = func(c domain.GroceryCategory) domain.GroceryCategory {
+		for _, child := range children[c.Code] {
+			c.Children = append(c.Children, nest(child))
+		}
+		return c
+	}
+	roots := make([]domain.GroceryCategory, 0)
+	for _, c := range categories {
+		if c.ParentCode == nil {
+			roots = append(roots, nest(c))
+		}
+	}
+	return roots
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/pkg/ai"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeCategoryRepo serves a small taxonomy: every top-level category plus a
+// few subcategories, and a handful of dictionary terms. localized holds terms
+// spelled the same in several locales.
+type fakeCategoryRepo struct {
+	terms     map[string]domain.Category
+	localized []domain.CategoryTerm
+	findErr   error
+}
+
+func testCategoryTaxonomy() []domain.GroceryCategory {
+	produce, dairy, pantry := domain.CategoryProduce, domain.CategoryDairy, domain.CategoryPantry
+	categories := []domain.GroceryCategory{}
+	for i, code := range []domain.Category{
+		domain.CategoryProduce, domain.CategoryMeat, domain.CategoryDairy, domain.CategoryBakery, domain.CategoryPantry,
+		domain.CategoryFrozen, domain.CategoryBeverages, domain.CategoryHousehold, domain.CategoryOther,
+	} {
+		categories = append(categories, domain.GroceryCategory{Code: code, Position: i + 1, Names: map[string]string{"en": string(code)}})
+	}
+	return append(categories,
+		domain.GroceryCategory{Code: "PRODUCE_VEGETABLES", ParentCode: &produce, Position: 1, Names: map[string]string{"en": "Vegetables", "de": "Gemüse"}},
+		domain.GroceryCategory{Code: "DAIRY_EGGS", ParentCode: &dairy, Position: 1, Names: map[string]string{"en": "Eggs", "de": "Eier"}},
+		domain.GroceryCategory{Code: "PANTRY_SPICES", ParentCode: &pantry, Position: 1, Names: map[string]string{"en": "Spices"}},
+		domain.GroceryCategory{Code: "PANTRY_CANNED", ParentCode: &pantry, Position: 2, Names: map[string]string{"en": "Canned goods"}},
+	)
+}
+
+func (r *fakeCategoryRepo) List(ctx context.Context) ([]domain.GroceryCategory, error) {
+	return testCategoryTaxonomy(), nil
+}
+
+func (r *fakeCategoryRepo) FindTerms(ctx context.Context, terms []string) ([]domain.CategoryTerm, error) {
+	if r.findErr != nil {
+		return nil, r.findErr
+	}
+	var found []domain.CategoryTerm
+	for _, term := range terms {
+		if category, ok := r.terms[term]; ok {
+			found = append(found, domain.CategoryTerm{Term: term, Locale: domain.DefaultCategoryLocale, CategoryCode: category})
+		}
+		for _, t := range r.localized {
+			if t.Term == term {
+				found = append(found, t)
+			}
+		}
+	}
+	return found, nil
+}
+
+func newTestCategoryService(aiModel ai.AIModel) CategoryService {
+	repo := &fakeCategoryRepo{terms: map[string]domain.Category{
+		"onion":    "PRODUCE_VEGETABLES",
+		"egg":      "DAIRY_EGGS",
+		"tomato":   "PRODUCE_VEGETABLES",
+		"cumin":    "PANTRY_SPICES",
+		"chickpea": "PANTRY_CANNED",
+	}}
+	return NewCategoryService(repo, aiModel, zap.NewNop())
+}
+
+func TestCategoryService_Categorize_UsesDictionaryBeforeAI(t *testing.T) {
+	m := new(mockAIModel)
+	m.On("CategorizeItems", mock.Anything, []string{"Quinoa", "Mystery"}, mock.Anything).
+		Return(map[string]string{"Quinoa": string(domain.CategoryPantry), "Mystery": "NOT_A_CATEGORY"}, nil).Once()
+
+	srv := newTestCategoryService(m)
+	got := srv.Categorize(context.Background(), []string{"Red  Onions", "Eggs", "Tomatoes", "Quinoa", "Eggs", "Mystery", ""})
+
+	require.Equal(t, map[string]domain.Category{
+		"Red  Onions": "PRODUCE_VEGETABLES",
+		"Eggs":        "DAIRY_EGGS",
+		"Tomatoes":    "PRODUCE_VEGETABLES",
+		"Quinoa":      domain.CategoryPantry,
+		"Mystery":     domain.CategoryOther,
+		"":            domain.CategoryOther,
+	}, got)
+	m.AssertExpectations(t)
+}
+
+func TestCategoryService_Categorize_OffersTaxonomyToAI(t *testing.T) {
+	m := new(mockAIModel)
+	m.On("CategorizeItems", mock.Anything, []string{"Paprika"}, mock.MatchedBy(func(categories []domain.GroceryCategory) bool {
+		return len(categories) == len(testCategoryTaxonomy())
+	})).Return(map[string]string{"Paprika": "PANTRY_SPICES"}, nil).Once()
+
+	srv := newTestCategoryService(m)
+	got := srv.Categorize(context.Background(), []string{"Paprika"})
+
+	require.Equal(t, domain.Category("PANTRY_SPICES"), got["Paprika"])
+	m.AssertExpectations(t)
+}
+
+func TestCategoryService_Categorize_SkipsAIWhenDictionaryKnowsAll(t *testing.T) {
+	m := new(mockAIModel)
+
+	srv := newTestCategoryService(m)
+	got := srv.Categorize(context.Background(), []string{"cumin", "Chickpeas"})
+
+	require.Equal(t, domain.Category("PANTRY_SPICES"), got["cumin"])
+	require.Equal(t, domain.Category("PANTRY_CANNED"), got["Chickpeas"])
+	m.AssertNotCalled(t, "CategorizeItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCategoryService_Categorize_PrefersCallerLocale(t *testing.T) {
+	repo := &fakeCategoryRepo{localized: []domain.CategoryTerm{
+		{Term: "mais", Locale: "fr", CategoryCode: "PANTRY_CANNED"},
+		{Term: "mais", Locale: "de", CategoryCode: "PRODUCE_VEGETABLES"},
+		{Term: "gift", Locale: "de", CategoryCode: domain.CategoryHousehold},
+		{Term: "gift", Locale: "en", CategoryCode: domain.CategoryOther},
+	}}
+	srv := NewCategoryService(repo, new(mockAIModel), zap.NewNop())
+
+	ctx := domain.ContextWithCategoryLanguages(context.Background(), []string{"de", "fr"})
+	got := srv.Categorize(ctx, []string{"Mais", "Gift"})
+	require.Equal(t, domain.Category("PRODUCE_VEGETABLES"), got["Mais"])
+	require.Equal(t, domain.CategoryHousehold, got["Gift"])
+
+	ctx = domain.ContextWithCategoryLanguages(context.Background(), []string{"fr"})
+	got = srv.Categorize(ctx, []string{"Mais", "Gift"})
+	require.Equal(t, domain.Category("PANTRY_CANNED"), got["Mais"])
+	// Without a preference for German, the default locale's entry wins.
+	require.Equal(t, domain.CategoryOther, got["Gift"])
+}
+
+func TestCategoryService_Categorize_FallsBackToOther(t *testing.T) {
+	m := new(mockAIModel)
+	m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("ai error")).Once()
+	repo := &fakeCategoryRepo{findErr: errors.New("db error")}
+
+	srv := NewCategoryService(repo, m, zap.NewNop())
+	got := srv.Categorize(context.Background(), []string{"onion"})
+
+	require.Equal(t, domain.CategoryOther, got["onion"])
+	m.AssertExpectations(t)
+}
+
+func TestCategoryService_Recategorize_AsksAIBeforeDictionary(t *testing.T) {
+	m := new(mockAIModel)
+	m.On("CategorizeItems", mock.Anything, []string{"Eggs", "Tofu"}, mock.Anything).
+		Return(map[string]string{"Eggs": string(domain.CategoryBakery), "Tofu": "NOT_A_CATEGORY"}, nil).Once()
+
+	srv := newTestCategoryService(m)
+	got := srv.Recategorize(context.Background(), []string{"Tofu", "Eggs", "Tofu"})
+
+	require.Equal(t, domain.CategoryBakery, got["Eggs"])
+	require.Equal(t, domain.CategoryOther, got["Tofu"])
+}
+
+func TestCategoryService_List(t *testing.T) {
+	srv := newTestCategoryService(nil)
+
+	tree, err := srv.List(context.Background(), []string{"de-AT", "en"})
+	require.NoError(t, err)
+	require.Len(t, tree, 9)
+
+	produce := tree[0]
+	require.Equal(t, domain.CategoryProduce, produce.Code)
+	require.Equal(t, "PRODUCE", produce.Name)
+	require.Len(t, produce.Children, 1)
+	require.Equal(t, "Gemüse", produce.Children[0].Name)
+
+	pantry := tree[4]
+	require.Equal(t, []domain.Category{"PANTRY_SPICES", "PANTRY_CANNED"}, []domain.Category{pantry.Children[0].Code, pantry.Children[1].Code})
+	require.Equal(t, "Spices", pantry.Children[0].Name)
+}
+
+func TestCategoryTermCandidates(t *testing.T) {
+	require.Equal(t, []string{"red onions", "red onion", "onions", "onion"}, categoryTermCandidates(" Red  Onions "))
+	require.Equal(t, []string{"potatoes", "potato"}, categoryTermCandidates("Potatoes"))
+	require.Equal(t, []string{"berries", "berry"}, categoryTermCandidates("berries"))
+	require.Equal(t, []string{"hummus"}, categoryTermCandidates("hummus"))
+	require.Equal(t, []string{"glass"}, categoryTermCandidates("glass"))
+	require.Nil(t, categoryTermCandidates("  "))
+}
@@ -0,0 +1,546 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/config"
+	"github.com/H3nSte1n/recipe/pkg/email"
+	"github.com/H3nSte1n/recipe/pkg/signedurl"
+	"github.com/H3nSte1n/recipe/pkg/storage"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type dataExportRepository interface {
+	Create(ctx context.Context, export *domain.DataExport) error
+	GetLatest(ctx context.Context, userID string) (*domain.DataExport, error)
+	MarkReady(ctx context.Context, id, fileName string, sizeBytes int64, completedAt, expiresAt time.Time) error
+	MarkFailed(ctx context.Context, id string, completedAt time.Time) error
+	FailPendingBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	ListExpired(ctx context.Context, now time.Time) ([]domain.DataExport, error)
+	MarkExpired(ctx context.Context, id string) error
+}
+
+type exportUserRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.User, error)
+	ListActiveSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error)
+}
+
+type exportProfileRepository interface {
+	GetByUserID(ctx context.Context, userID string) (*domain.Profile, error)
+}
+
+type exportRecipeRepository interface {
+	ListByUserID(ctx context.Context, userID string, includePrivate bool) ([]domain.Recipe, error)
+	ListTrashed(ctx context.Context, userID string) ([]domain.Recipe, error)
+}
+
+type exportShoppingListRepository interface {
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingList, error)
+	ListTrashed(ctx context.Context, userID string) ([]domain.ShoppingList, error)
+	ListTrashedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error)
+	ListArchivedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error)
+}
+
+type exportStoreRepository interface {
+	ListByUserID(ctx context.Context, userID string) ([]domain.Store, error)
+}
+
+type exportShoppingTripRepository interface {
+	ListWithItems(ctx context.Context, userID string) ([]domain.ShoppingTrip, error)
+}
+
+type exportTemplateRepository interface {
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListTemplate, error)
+}
+
+type exportShareRepository interface {
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListShare, error)
+}
+
+type exportItemPriceRepository interface {
+	List(ctx context.Context, userID string, storeChainID string) ([]domain.ItemPrice, error)
+}
+
+type exportProductRepository interface {
+	ListSaved(ctx context.Context, userID string) ([]domain.UserProduct, error)
+}
+
+type exportAPITokenRepository interface {
+	ListActive(ctx context.Context, userID string) ([]domain.APIToken, error)
+}
+
+type exportAIConfigRepository interface {
+	ListByUserID(ctx context.Context, userID string) ([]domain.UserAIConfig, error)
+}
+
+type exportActivityLister interface {
+	ListActivity(ctx context.Context, userID string, filter domain.ActivityFilter) ([]domain.AuditEvent, error)
+}
+
+const (
+	// exportBuildTimeout bounds one build. An export still pending after it
+	// was abandoned, e.g. by a restart, and is marked failed.
+	exportBuildTimeout = time.Hour
+	// exportCleanupInterval is how often RunCleanup looks for expired archives.
+	exportCleanupInterval = time.Hour
+	// exportActivityPageSize is the page size used to read the full activity
+	// history into an archive.
+	exportActivityPageSize = 200
+)
+
+type DataExportService interface {
+	// RequestExport starts building an archive of everything tied to the
+	// user. It returns at once; the download link is emailed when the archive
+	// is ready.
+	RequestExport(ctx context.Context, userID string) (*domain.DataExport, error)
+	// GetLatest reports on the user's most recent export.
+	GetLatest(ctx context.Context, userID string) (*domain.DataExport, error)
+	// RunCleanup deletes archives whose link has expired, once at start and
+	// then hourly until ctx is done.
+	RunCleanup(ctx context.Context)
+}
+
+type dataExportService struct {
+	exportRepo       dataExportRepository
+	userRepo         exportUserRepository
+	profileRepo      exportProfileRepository
+	recipeRepo       exportRecipeRepository
+	shoppingListRepo exportShoppingListRepository
+	storeRepo        exportStoreRepository
+	tripRepo         exportShoppingTripRepository
+	templateRepo     exportTemplateRepository
+	shareRepo        exportShareRepository
+	priceRepo        exportItemPriceRepository
+	productRepo      exportProductRepository
+	apiTokenRepo     exportAPITokenRepository
+	aiConfigRepo     exportAIConfigRepository
+	activity         exportActivityLister
+	fileStorage      storage.FileStore
+	emailService     email.EmailService
+	signer           *signedurl.Signer
+	config           config.ExportConfig
+	auditLog         *AuditLog
+	logger           *zap.Logger
+	// async runs a build in the background; tests swap it for a direct call.
+	async func(func())
+}
+
+func NewDataExportService(
+	exportRepo dataExportRepository,
+	userRepo exportUserRepository,
+	profileRepo exportProfileRepository,
+	recipeRepo exportRecipeRepository,
+	shoppingListRepo exportShoppingListRepository,
+	storeRepo exportStoreRepository,
+	tripRepo exportShoppingTripRepository,
+	templateRepo exportTemplateRepository,
+	shareRepo exportShareRepository,
+	priceRepo exportItemPriceRepository,
+	productRepo exportProductRepository,
+	apiTokenRepo exportAPITokenRepository,
+	aiConfigRepo exportAIConfigRepository,
+	activity exportActivityLister,
+	fileStorage storage.FileStore,
+	emailService email.EmailService,
+	config config.ExportConfig,
+	signingSecret string,
+	auditLog *AuditLog,
+	logger *zap.Logger,
+) DataExportService {
+	return &dataExportService{
+		exportRepo:       exportRepo,
+		userRepo:         userRepo,
+		profileRepo:      profileRepo,
+		recipeRepo:       recipeRepo,
+		shoppingListRepo: shoppingListRepo,
+		storeRepo:        storeRepo,
+		tripRepo:         tripRepo,
+		templateRepo:     templateRepo,
+		shareRepo:        shareRepo,
+		priceRepo:        priceRepo,
+		productRepo:      productRepo,
+		apiTokenRepo:     apiTokenRepo,
+		aiConfigRepo:     aiConfigRepo,
+		activity:         activity,
+		fileStorage:      fileStorage,
+		emailService:     emailService,
+		signer:           signedurl.NewSigner(signingSecret, config.LinkTTL),
+		config:           config,
+		auditLog:         auditLog,
+		logger:           logger,
+		async:            func(build func()) { go build() },
+	}
+}
+
+func (s *dataExportService) RequestExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	latest, err := s.exportRepo.GetLatest(ctx, userID)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return nil, err
+	}
+	if latest != nil && latest.Status == domain.DataExportPending {
+		return nil, apperrors.ErrConflict.Wrap("an export is already being prepared")
+	}
+
+	export := &domain.DataExport{
+		UserID: userID,
+		Status: domain.DataExportPending,
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditDataExportRequested,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID,
+	})
+
+	// The build outlives the request, so it must not be cancelled with it.
+	buildCtx := context.WithoutCancel(ctx)
+	s.async(func() { s.build(buildCtx, export) })
+	return export, nil
+}
+
+func (s *dataExportService) GetLatest(ctx context.Context, userID string) (*domain.DataExport, error) {
+	export, err := s.exportRepo.GetLatest(ctx, userID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, apperrors.ErrNotFound.Wrap("no data export requested")
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// build writes the archive, marks the export ready and emails the link.
+// Failures are logged and leave the export failed with no archive behind.
+func (s *dataExportService) build(ctx context.Context, export *domain.DataExport) {
+	ctx, cancel := context.WithTimeout(ctx, exportBuildTimeout)
+	defer cancel()
+
+	if err := s.buildAndDeliver(ctx, export); err != nil {
+		s.logger.Error("failed to build data export",
+			zap.String("export_id", export.ID),
+			zap.String("user_id", export.UserID),
+			zap.Error(err))
+		if err := s.exportRepo.MarkFailed(ctx, export.ID, time.Now()); err != nil {
+			s.logger.Error("failed to mark data export failed", zap.String("export_id", export.ID), zap.Error(err))
+		}
+	}
+}
+
+func (s *dataExportService) buildAndDeliver(ctx context.Context, export *domain.DataExport) error {
+	user, err := s.userRepo.GetByID(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	fileName := uuid.New().String() + ".zip"
+	archivePath := filepath.Join(s.config.Dir, fileName)
+	size, err := s.writeArchive(ctx, archivePath, user)
+	if err != nil {
+		s.removeArchive(fileName)
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.config.LinkTTL)
+	link, err := s.signer.SignFile(s.config.BaseURL, fileName)
+	if err != nil {
+		s.removeArchive(fileName)
+		return err
+	}
+	if err := s.exportRepo.MarkReady(ctx, export.ID, fileName, size, now, expiresAt); err != nil {
+		s.removeArchive(fileName)
+		return err
+	}
+
+	// The emailed link is the only way to the archive, so an export that
+	// cannot be delivered is failed rather than left ready.
+	if err := s.emailService.SendDataExportEmail(ctx, user.Email, link, expiresAt); err != nil {
+		s.removeArchive(fileName)
+		return fmt.Errorf("send data export email: %w", err)
+	}
+	return nil
+}
+
+// writeArchive writes user's data as a zip archive to archivePath and returns
+// its size.
+func (s *dataExportService) writeArchive(ctx context.Context, archivePath string, user *domain.User) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0o700); err != nil {
+		return 0, fmt.Errorf("create export directory: %w", err)
+	}
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("create export archive: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	if err := s.writeArchiveEntries(ctx, zw, user); err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), f.Close()
+}
+
+func (s *dataExportService) writeArchiveEntries(ctx context.Context, zw *zip.Writer, user *domain.User) error {
+	profile, err := s.profileRepo.GetByUserID(ctx, user.ID)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return err
+	}
+	recipes, err := s.recipeRepo.ListByUserID(ctx, user.ID, true)
+	if err != nil {
+		return err
+	}
+	lists, err := s.shoppingListRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	cleared, err := s.shoppingListRepo.ListArchivedItems(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	trash, err := s.listTrash(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	stores, err := s.storeRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	trips, err := s.tripRepo.ListWithItems(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	templates, err := s.templateRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	shares, err := s.shareRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	prices, err := s.priceRepo.List(ctx, user.ID, "")
+	if err != nil {
+		return err
+	}
+	products, err := s.productRepo.ListSaved(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	sessions, err := s.userRepo.ListActiveSessions(ctx, user.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	apiTokens, err := s.apiTokenRepo.ListActive(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	configs, err := s.aiConfigRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	aiConfigs := make([]domain.AIConfigExport, len(configs))
+	for i, c := range configs {
+		aiConfigs[i] = domain.NewAIConfigExport(c)
+	}
+	activity, err := s.listAllActivity(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	documents := []struct {
+		name  string
+		value any
+	}{
+		{"user.json", user},
+		{"profile.json", profile},
+		{"recipes.json", recipes},
+		{"shopping_lists.json", lists},
+		{"cleared_shopping_list_items.json", cleared},
+		{"trash.json", trash},
+		{"stores.json", stores},
+		{"shopping_trips.json", trips},
+		{"shopping_list_templates.json", templates},
+		{"shopping_list_shares.json", shares},
+		{"item_prices.json", prices},
+		{"saved_products.json", products},
+		{"sessions.json", sessions},
+		{"api_tokens.json", apiTokens},
+		{"ai_configs.json", aiConfigs},
+		{"activity.json", activity},
+	}
+	for _, doc := range documents {
+		w, err := zw.Create(doc.name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc.value); err != nil {
+			return fmt.Errorf("write %s: %w", doc.name, err)
+		}
+	}
+
+	// Uploaded images go under images/, a recipe's in a folder named by its
+	// ID so images with the same file name don't collide. Images linked from
+	// elsewhere are left as the URLs in the documents.
+	if profile != nil && profile.AvatarURL != "" {
+		if err := s.writeImage(ctx, zw, "images/profile", profile.AvatarURL); err != nil {
+			return err
+		}
+	}
+	for _, recipe := range append(recipes, trash.Recipes...) {
+		if recipe.ImageURL == "" {
+			continue
+		}
+		if err := s.writeImage(ctx, zw, "images/recipes/"+recipe.ID, recipe.ImageURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportTrash is what the user has in the trash.
+type exportTrash struct {
+	Recipes           []domain.Recipe           `json:"recipes"`
+	ShoppingLists     []domain.ShoppingList     `json:"shopping_lists"`
+	ShoppingListItems []domain.ShoppingListItem `json:"shopping_list_items"`
+}
+
+func (s *dataExportService) listTrash(ctx context.Context, userID string) (*exportTrash, error) {
+	recipes, err := s.recipeRepo.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := s.shoppingListRepo.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.shoppingListRepo.ListTrashedItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &exportTrash{Recipes: recipes, ShoppingLists: lists, ShoppingListItems: items}, nil
+}
+
+func (s *dataExportService) writeImage(ctx context.Context, zw *zip.Writer, dir string, imageURL string) error {
+	src, err := s.fileStorage.OpenFile(ctx, imageURL)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotStored) {
+			return nil
+		}
+		return fmt.Errorf("open image: %w", err)
+	}
+	defer src.Close()
+
+	w, err := zw.Create(dir + "/" + path.Base(imageURL))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
+
+// listAllActivity pages through the user's whole activity history, as the
+// user would see it at GET /users/me/activity.
+func (s *dataExportService) listAllActivity(ctx context.Context, userID string) ([]domain.AuditEvent, error) {
+	events := []domain.AuditEvent{}
+	filter := domain.ActivityFilter{Limit: exportActivityPageSize}
+	for {
+		page, err := s.activity.ListActivity(ctx, userID, filter)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+		if len(page) < exportActivityPageSize {
+			return events, nil
+		}
+		before := page[len(page)-1].CreatedAt
+		filter.Before = &before
+	}
+}
+
+func (s *dataExportService) removeArchive(fileName string) {
+	err := os.Remove(filepath.Join(s.config.Dir, fileName))
+	if err != nil && !os.IsNotExist(err) {
+		s.logger.Error("failed to remove data export archive", zap.String("file", fileName), zap.Error(err))
+	}
+}
+
+func (s *dataExportService) RunCleanup(ctx context.Context) {
+	ticker := time.NewTicker(exportCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup fails abandoned builds and deletes archives whose link expired.
+// Archives are also swept by age, which catches those of deleted accounts:
+// their rows are gone with the user.
+func (s *dataExportService) cleanup(ctx context.Context) {
+	now := time.Now()
+	if _, err := s.exportRepo.FailPendingBefore(ctx, now.Add(-exportBuildTimeout)); err != nil {
+		s.logger.Error("failed to fail abandoned data exports", zap.Error(err))
+	}
+
+	expired, err := s.exportRepo.ListExpired(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to list expired data exports", zap.Error(err))
+	}
+	for _, export := range expired {
+		s.removeArchive(export.FileName)
+		if err := s.exportRepo.MarkExpired(ctx, export.ID); err != nil {
+			s.logger.Error("failed to mark data export expired", zap.String("export_id", export.ID), zap.Error(err))
+		}
+	}
+
+	entries, err := os.ReadDir(s.config.Dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Error("failed to read export directory", zap.Error(err))
+		}
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".zip") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if now.Sub(info.ModTime()) > s.config.LinkTTL+exportBuildTimeout {
+			s.removeArchive(entry.Name())
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/config"
+	"github.com/H3nSte1n/recipe/pkg/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockDataExportRepo struct {
+	mock.Mock
+}
+
+func (m *mockDataExportRepo) Create(ctx context.Context, export *domain.DataExport) error {
+	args := m.Called(ctx, export)
+	return args.Error(0)
+}
+
+func (m *mockDataExportRepo) GetLatest(ctx context.Context, userID string) (*domain.DataExport, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).(*domain.DataExport)
+	return v, args.Error(1)
+}
+
+func (m *mockDataExportRepo) MarkReady(ctx context.Context, id, fileName string, sizeBytes int64, completedAt, expiresAt time.Time) error {
+	args := m.Called(ctx, id, fileName, sizeBytes, completedAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *mockDataExportRepo) MarkFailed(ctx context.Context, id string, completedAt time.Time) error {
+	args := m.Called(ctx, id, completedAt)
+	return args.Error(0)
+}
+
+func (m *mockDataExportRepo) FailPendingBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockDataExportRepo) ListExpired(ctx context.Context, now time.Time) ([]domain.DataExport, error) {
+	args := m.Called(ctx, now)
+	v, _ := args.Get(0).([]domain.DataExport)
+	return v, args.Error(1)
+}
+
+func (m *mockDataExportRepo) MarkExpired(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type dataExportTestDeps struct {
+	exports  *mockDataExportRepo
+	users    *mockUserRepository
+	profiles *mockProfileRepository
+	recipes  *mockRecipeRepo
+	lists    *mockShoppingListRepository
+	stores   *mockStoreRepo
+	trips    *mockShoppingTripRepo
+	tmpls    *mockTemplateRepo
+	shares   *mockShareRepo
+	prices   *mockItemPriceRepo
+	products *mockProductRepo
+	tokens   *mockAPITokenRepo
+	configs  *fakeAIConfigRepo
+	activity *mockAuditRepo
+	files    *mockFileStore
+	email    *mockEmailService
+	dir      string
+}
+
+// newTestDataExportService builds exports synchronously into a temp directory.
+func newTestDataExportService(t *testing.T) (*dataExportService, *dataExportTestDeps) {
+	t.Helper()
+	deps := &dataExportTestDeps{
+		exports:  new(mockDataExportRepo),
+		users:    new(mockUserRepository),
+		profiles: new(mockProfileRepository),
+		recipes:  new(mockRecipeRepo),
+		lists:    new(mockShoppingListRepository),
+		stores:   new(mockStoreRepo),
+		trips:    new(mockShoppingTripRepo),
+		tmpls:    new(mockTemplateRepo),
+		shares:   new(mockShareRepo),
+		prices:   new(mockItemPriceRepo),
+		products: new(mockProductRepo),
+		tokens:   new(mockAPITokenRepo),
+		configs:  newFakeAIConfigRepo(),
+		activity: new(mockAuditRepo),
+		files:    new(mockFileStore),
+		email:    new(mockEmailService),
+		dir:      t.TempDir(),
+	}
+	cfg := config.ExportConfig{Dir: deps.dir, BaseURL: "http://localhost:8080/exports", LinkTTL: time.Hour}
+	srv := NewDataExportService(deps.exports, deps.users, deps.profiles, deps.recipes, deps.lists, deps.stores, deps.trips,
+		deps.tmpls, deps.shares, deps.prices, deps.products, deps.tokens, deps.configs, NewAuditService(deps.activity, 0, zap.NewNop()), deps.files, deps.email, cfg, "test-secret", nil, zap.NewNop()).(*dataExportService)
+	srv.async = func(build func()) { build() }
+	return srv, deps
+}
+
+// expectNothingElse has the sources a test does not care about return
+// nothing for userID.
+func (deps *dataExportTestDeps) expectNothingElse(userID string) {
+	deps.recipes.On("ListTrashed", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.lists.On("ListTrashed", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.lists.On("ListTrashedItems", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.lists.On("ListArchivedItems", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.stores.On("ListByUserID", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.trips.On("ListWithItems", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.tmpls.On("ListByUserID", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.shares.On("ListByUserID", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.prices.On("List", mock.Anything, userID, "").Return(nil, nil).Maybe()
+	deps.products.On("ListSaved", mock.Anything, userID).Return(nil, nil).Maybe()
+	deps.users.On("ListActiveSessions", mock.Anything, userID, mock.AnythingOfType("time.Time")).Return(nil, nil).Maybe()
+	deps.tokens.On("ListActive", mock.Anything, userID).Return(nil, nil).Maybe()
+}
+
+func TestDataExportService_RequestExport_ConflictsWithPendingExport(t *testing.T) {
+	srv, deps := newTestDataExportService(t)
+	deps.exports.On("GetLatest", mock.Anything, "user-1").Return(&domain.DataExport{Status: domain.DataExportPending}, nil).Once()
+
+	_, err := srv.RequestExport(context.Background(), "user-1")
+
+	require.True(t, apperrors.IsConflict(err))
+	deps.exports.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestDataExportService_RequestExport_BuildsArchiveAndEmailsLink(t *testing.T) {
+	srv, deps := newTestDataExportService(t)
+	ctx := context.Background()
+	user := &domain.User{ID: "user-1", Email: "foo@bar.com"}
+	deps.configs.store["cfg-1"] = domain.UserAIConfig{ID: "cfg-1", UserID: "user-1", AIModelID: "model-1", APIKey: "sk-ant-SECRET"}
+
+	deps.exports.On("GetLatest", mock.Anything, "user-1").Return(nil, gorm.ErrRecordNotFound).Once()
+	deps.exports.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.DataExport).ID = "export-1"
+	}).Return(nil).Once()
+	deps.users.On("GetByID", mock.Anything, "user-1").Return(user, nil).Once()
+	deps.profiles.On("GetByUserID", mock.Anything, "user-1").Return(nil, gorm.ErrRecordNotFound).Once()
+	deps.recipes.On("ListByUserID", mock.Anything, "user-1", true).Return([]domain.Recipe{
+		{ID: "r1", Title: "Pancakes", ImageURL: "http://localhost:8080/uploads/pancakes.png"},
+		{ID: "r2", Title: "Imported", ImageURL: "https://example.com/soup.jpg"},
+	}, nil).Once()
+	deps.recipes.On("ListTrashed", mock.Anything, "user-1").Return([]domain.Recipe{
+		{ID: "r3", Title: "Old pancakes", ImageURL: "http://localhost:8080/uploads/pancakes.png"},
+	}, nil).Once()
+	deps.lists.On("ListByUserID", mock.Anything, "user-1").Return([]domain.ShoppingList{{ID: "l1", Name: "Weekly"}}, nil).Once()
+	deps.lists.On("ListTrashed", mock.Anything, "user-1").Return([]domain.ShoppingList{{ID: "l2", Name: "Party"}}, nil).Once()
+	deps.trips.On("ListWithItems", mock.Anything, "user-1").Return([]domain.ShoppingTrip{
+		{ID: "t1", StoreName: "Rewe", Items: []domain.ShoppingTripItem{{Name: "Flour", PriceCents: 79}}},
+	}, nil).Once()
+	deps.tokens.On("ListActive", mock.Anything, "user-1").Return([]domain.APIToken{{ID: "tok-1", Name: "script", TokenHash: "TOKEN-HASH"}}, nil).Once()
+	deps.expectNothingElse("user-1")
+	deps.activity.On("ListForUser", mock.Anything, "user-1", domain.ActivityFilter{Limit: exportActivityPageSize}).
+		Return([]domain.AuditEvent{{ID: "e1", Action: domain.AuditLoginFailed}}, nil).Once()
+	deps.files.On("OpenFile", mock.Anything, "http://localhost:8080/uploads/pancakes.png").Return(io.NopCloser(strings.NewReader("png-bytes")), nil).Once()
+	deps.files.On("OpenFile", mock.Anything, "http://localhost:8080/uploads/pancakes.png").Return(io.NopCloser(strings.NewReader("old-png-bytes")), nil).Once()
+	deps.files.On("OpenFile", mock.Anything, "https://example.com/soup.jpg").Return(nil, storage.ErrNotStored).Once()
+
+	var fileName string
+	deps.exports.On("MarkReady", mock.Anything, "export-1", mock.AnythingOfType("string"), mock.AnythingOfType("int64"), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { fileName = args.String(2) }).Return(nil).Once()
+	var link string
+	deps.email.On("SendDataExportEmail", mock.Anything, user.Email, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { link = args.String(2) }).Return(nil).Once()
+
+	export, err := srv.RequestExport(ctx, "user-1")
+	require.NoError(t, err)
+	require.Equal(t, domain.DataExportPending, export.Status)
+	deps.exports.AssertExpectations(t)
+	deps.email.AssertExpectations(t)
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "/exports/"+fileName, u.Path)
+	require.NoError(t, srv.signer.Verify(fileName, u.Query().Get("exp"), u.Query().Get("sig")))
+
+	archive, err := zip.OpenReader(filepath.Join(deps.dir, fileName))
+	require.NoError(t, err)
+	defer archive.Close()
+	entries := map[string]string{}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		entries[f.Name] = string(content)
+	}
+	for _, name := range []string{"user.json", "profile.json", "recipes.json", "shopping_lists.json", "cleared_shopping_list_items.json", "trash.json", "stores.json",
+		"shopping_trips.json", "shopping_list_templates.json", "shopping_list_shares.json", "item_prices.json",
+		"saved_products.json", "sessions.json", "api_tokens.json", "ai_configs.json", "activity.json"} {
+		require.Contains(t, entries, name)
+	}
+	// Two recipes' images share a file name without overwriting each other.
+	require.Equal(t, "png-bytes", entries["images/recipes/r1/pancakes.png"])
+	require.Equal(t, "old-png-bytes", entries["images/recipes/r3/pancakes.png"])
+	require.NotContains(t, entries, "images/recipes/r2/soup.jpg")
+	require.Contains(t, entries["trash.json"], "Old pancakes")
+	require.Contains(t, entries["trash.json"], "Party")
+	require.Contains(t, entries["shopping_trips.json"], "Flour")
+	require.Contains(t, entries["api_tokens.json"], "script")
+	require.Contains(t, entries["ai_configs.json"], "model-1")
+	for name, content := range entries {
+		require.NotContains(t, content, "sk-ant-SECRET", "API key leaked into %s", name)
+		require.NotContains(t, content, "TOKEN-HASH", "API token hash leaked into %s", name)
+	}
+}
+
+func TestDataExportService_Build_FailsWhenTheLinkCannotBeSent(t *testing.T) {
+	srv, deps := newTestDataExportService(t)
+	deps.users.On("GetByID", mock.Anything, "user-1").Return(&domain.User{ID: "user-1", Email: "foo@bar.com"}, nil).Once()
+	deps.profiles.On("GetByUserID", mock.Anything, "user-1").Return(&domain.Profile{UserID: "user-1"}, nil).Once()
+	deps.recipes.On("ListByUserID", mock.Anything, "user-1", true).Return(nil, nil).Once()
+	deps.lists.On("ListByUserID", mock.Anything, "user-1").Return(nil, nil).Once()
+	deps.expectNothingElse("user-1")
+	deps.activity.On("ListForUser", mock.Anything, "user-1", mock.Anything).Return(nil, nil).Once()
+	deps.exports.On("MarkReady", mock.Anything, "export-1", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	deps.email.On("SendDataExportEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("smtp down")).Once()
+	deps.exports.On("MarkFailed", mock.Anything, "export-1", mock.Anything).Return(nil).Once()
+
+	srv.build(context.Background(), &domain.DataExport{ID: "export-1", UserID: "user-1"})
+
+	deps.exports.AssertExpectations(t)
+	entries, err := os.ReadDir(deps.dir)
+	require.NoError(t, err)
+	require.Empty(t, entries, "an undeliverable archive must not be left behind")
+}
+
+func TestDataExportService_Cleanup(t *testing.T) {
+	srv, deps := newTestDataExportService(t)
+	expired := filepath.Join(deps.dir, "expired.zip")
+	orphaned := filepath.Join(deps.dir, "orphaned.zip")
+	current := filepath.Join(deps.dir, "current.zip")
+	for _, p := range []string{expired, orphaned, current} {
+		require.NoError(t, os.WriteFile(p, []byte("zip"), 0o600))
+	}
+	old := time.Now().Add(-3 * time.Hour)
+	require.NoError(t, os.Chtimes(orphaned, old, old))
+
+	deps.exports.On("FailPendingBefore", mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+	deps.exports.On("ListExpired", mock.Anything, mock.Anything).Return([]domain.DataExport{{ID: "export-1", FileName: "expired.zip"}}, nil).Once()
+	deps.exports.On("MarkExpired", mock.Anything, "export-1").Return(nil).Once()
+
+	srv.cleanup(context.Background())
+
+	deps.exports.AssertExpectations(t)
+	require.NoFileExists(t, expired)
+	require.NoFileExists(t, orphaned)
+	require.FileExists(t, current)
+}
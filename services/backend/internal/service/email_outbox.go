@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/pkg/email"
+	"go.uber.org/zap"
+)
+
+type emailOutboxRepository interface {
+	Create(ctx context.Context, email *domain.OutboxEmail) error
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]domain.OutboxEmail, error)
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string) error
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+const (
+	// outboxPollInterval is how often the sender looks for due email when it
+	// is not woken by a new one.
+	outboxPollInterval = 10 * time.Second
+	// outboxBatchSize is how many emails one pass of the sender claims.
+	outboxBatchSize = 20
+	// outboxSendTimeout bounds delivering one email; the claim lease is a
+	// little longer so a slow send is not picked up twice.
+	outboxSendTimeout = time.Minute
+	outboxClaimLease  = 2 * outboxSendTimeout
+	// outboxMaxAttempts is how often delivery is tried before an email is
+	// marked failed. With outboxRetryBase doubling per attempt up to
+	// outboxRetryMax, the last try is about four hours after the first.
+	outboxMaxAttempts = 10
+	outboxRetryBase   = 30 * time.Second
+	outboxRetryMax    = 6 * time.Hour
+	// outboxRetention is how long sent and failed emails are kept.
+	outboxRetention = 7 * 24 * time.Hour
+	// outboxLastErrorMax caps the stored delivery error.
+	outboxLastErrorMax = 1000
+)
+
+// EmailOutbox is the queue behind email.EmailService: messages are stored in
+// the database and delivered by RunSender through the configured transport,
+// with retries.
+type EmailOutbox interface {
+	email.Queue
+	// SendDue delivers the emails that are due and returns how many were
+	// sent.
+	SendDue(ctx context.Context) (int, error)
+	// RunSender sends due email until ctx is done, waking early whenever a
+	// new email is queued.
+	RunSender(ctx context.Context)
+}
+
+type emailOutbox struct {
+	repo      emailOutboxRepository
+	transport email.Transport
+	wake      chan struct{}
+	logger    *zap.Logger
+}
+
+func NewEmailOutbox(repo emailOutboxRepository, transport email.Transport, logger *zap.Logger) EmailOutbox {
+	return &emailOutbox{
+		repo:      repo,
+		transport: transport,
+		wake:      make(chan struct{}, 1),
+		logger:    logger,
+	}
+}
+
+func (o *emailOutbox) Enqueue(ctx context.Context, msg email.Message) error {
+	if err := o.repo.Create(ctx, &domain.OutboxEmail{
+		Recipient:     msg.To,
+		Subject:       msg.Subject,
+		TextBody:      msg.Text,
+		HTMLBody:      msg.HTML,
+		Status:        domain.OutboxEmailPending,
+		NextAttemptAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (o *emailOutbox) SendDue(ctx context.Context) (int, error) {
+	emails, err := o.repo.ClaimDue(ctx, time.Now(), outboxBatchSize, outboxClaimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, e := range emails {
+		if o.deliver(ctx, e) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// deliver sends one claimed email and records the outcome.
+func (o *emailOutbox) deliver(ctx context.Context, e domain.OutboxEmail) bool {
+	sendCtx, cancel := context.WithTimeout(ctx, outboxSendTimeout)
+	err := o.transport.Send(sendCtx, email.Message{
+		To:      e.Recipient,
+		Subject: e.Subject,
+		Text:    e.TextBody,
+		HTML:    e.HTMLBody,
+	})
+	cancel()
+
+	if err == nil {
+		if err := o.repo.MarkSent(ctx, e.ID, time.Now()); err != nil {
+			o.logger.Error("failed to mark email sent", zap.String("email_id", e.ID), zap.Error(err))
+		}
+		return true
+	}
+
+	attempts := e.Attempts + 1
+	lastError := err.Error()
+	if len(lastError) > outboxLastErrorMax {
+		lastError = lastError[:outboxLastErrorMax]
+	}
+	if attempts >= outboxMaxAttempts {
+		o.logger.Error("giving up on email delivery",
+			zap.String("email_id", e.ID),
+			zap.Int("attempts", attempts),
+			zap.Error(err))
+		if err := o.repo.MarkFailed(ctx, e.ID, attempts, lastError); err != nil {
+			o.logger.Error("failed to mark email failed", zap.String("email_id", e.ID), zap.Error(err))
+		}
+		return false
+	}
+
+	o.logger.Warn("email delivery failed, will retry",
+		zap.String("email_id", e.ID),
+		zap.Int("attempts", attempts),
+		zap.Error(err))
+	if err := o.repo.MarkRetry(ctx, e.ID, attempts, time.Now().Add(outboxRetryDelay(attempts)), lastError); err != nil {
+		o.logger.Error("failed to schedule email retry", zap.String("email_id", e.ID), zap.Error(err))
+	}
+	return false
+}
+
+// outboxRetryDelay is the wait before the next attempt after attempts failed
+// ones: outboxRetryBase, doubling each time, capped at outboxRetryMax.
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 1; i < attempts && delay < outboxRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMax {
+		delay = outboxRetryMax
+	}
+	return delay
+}
+
+func (o *emailOutbox) RunSender(ctx context.Context) {
+	poll := time.NewTicker(outboxPollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		// Keep going while full batches come back, so a backlog drains
+		// without waiting for the next tick.
+		for {
+			sent, err := o.SendDue(ctx)
+			if err != nil {
+				o.logger.Error("failed to send queued email", zap.Error(err))
+				break
+			}
+			if sent < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		case <-poll.C:
+		case <-cleanup.C:
+			if _, err := o.repo.DeleteFinishedBefore(ctx, time.Now().Add(-outboxRetention)); err != nil {
+				o.logger.Error("failed to delete old outbox email", zap.Error(err))
+			}
+		}
+	}
+}
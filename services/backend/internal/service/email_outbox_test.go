@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/pkg/email"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockEmailOutboxRepo struct {
+	mock.Mock
+}
+
+func (m *mockEmailOutboxRepo) Create(ctx context.Context, e *domain.OutboxEmail) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *mockEmailOutboxRepo) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]domain.OutboxEmail, error) {
+	args := m.Called(ctx, now, limit, lease)
+	v, _ := args.Get(0).([]domain.OutboxEmail)
+	return v, args.Error(1)
+}
+
+func (m *mockEmailOutboxRepo) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	args := m.Called(ctx, id, sentAt)
+	return args.Error(0)
+}
+
+func (m *mockEmailOutboxRepo) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	args := m.Called(ctx, id, attempts, nextAttemptAt, lastError)
+	return args.Error(0)
+}
+
+func (m *mockEmailOutboxRepo) MarkFailed(ctx context.Context, id string, attempts int, lastError string) error {
+	args := m.Called(ctx, id, attempts, lastError)
+	return args.Error(0)
+}
+
+func (m *mockEmailOutboxRepo) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type failingTransport struct{}
+
+func (failingTransport) Send(context.Context, email.Message) error {
+	return errors.New("connection refused")
+}
+
+func TestEmailOutbox_Enqueue(t *testing.T) {
+	repo := new(mockEmailOutboxRepo)
+	outbox := NewEmailOutbox(repo, &email.CaptureTransport{}, zap.NewNop())
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.OutboxEmail) bool {
+		return e.Recipient == "foo@bar.com" && e.Subject == "Hi" && e.HTMLBody == "<p>hi</p>" &&
+			e.Status == domain.OutboxEmailPending
+	})).Return(nil)
+
+	require.NoError(t, outbox.Enqueue(context.Background(), email.Message{To: "foo@bar.com", Subject: "Hi", Text: "hi", HTML: "<p>hi</p>"}))
+	repo.AssertExpectations(t)
+}
+
+func TestEmailOutbox_SendDue_Delivers(t *testing.T) {
+	repo := new(mockEmailOutboxRepo)
+	transport := &email.CaptureTransport{}
+	outbox := NewEmailOutbox(repo, transport, zap.NewNop())
+	repo.On("ClaimDue", mock.Anything, mock.Anything, outboxBatchSize, outboxClaimLease).
+		Return([]domain.OutboxEmail{{ID: "e1", Recipient: "foo@bar.com", Subject: "Hi", TextBody: "hi"}}, nil)
+	repo.On("MarkSent", mock.Anything, "e1", mock.Anything).Return(nil)
+
+	sent, err := outbox.SendDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+	require.Equal(t, []email.Message{{To: "foo@bar.com", Subject: "Hi", Text: "hi"}}, transport.Messages())
+	repo.AssertExpectations(t)
+}
+
+func TestEmailOutbox_SendDue_SchedulesRetry(t *testing.T) {
+	repo := new(mockEmailOutboxRepo)
+	outbox := NewEmailOutbox(repo, failingTransport{}, zap.NewNop())
+	repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.OutboxEmail{{ID: "e1", Attempts: 2}}, nil)
+	before := time.Now()
+	repo.On("MarkRetry", mock.Anything, "e1", 3, mock.MatchedBy(func(next time.Time) bool {
+		return !next.Before(before.Add(2*time.Minute)) && next.Before(time.Now().Add(2*time.Minute+time.Second))
+	}), "connection refused").Return(nil)
+
+	sent, err := outbox.SendDue(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, sent)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailOutbox_SendDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo := new(mockEmailOutboxRepo)
+	outbox := NewEmailOutbox(repo, failingTransport{}, zap.NewNop())
+	repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.OutboxEmail{{ID: "e1", Attempts: outboxMaxAttempts - 1}}, nil)
+	repo.On("MarkFailed", mock.Anything, "e1", outboxMaxAttempts, "connection refused").Return(nil)
+
+	_, err := outbox.SendDue(context.Background())
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	require.Equal(t, 30*time.Second, outboxRetryDelay(1))
+	require.Equal(t, time.Minute, outboxRetryDelay(2))
+	require.Equal(t, 4*time.Minute, outboxRetryDelay(4))
+	require.Equal(t, 256*time.Minute, outboxRetryDelay(10))
+	require.Equal(t, outboxRetryMax, outboxRetryDelay(11))
+	require.Equal(t, outboxRetryMax, outboxRetryDelay(100))
+}
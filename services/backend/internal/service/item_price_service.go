@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/units"
+	"go.uber.org/zap"
+)
+
+// recentPriceCount is how many of an item's most recent trip prices its cost
+// is judged from when the user has not set a price for it.
+const recentPriceCount = 5
+
+type itemPriceRepository interface {
+	List(ctx context.Context, userID string, storeChainID string) ([]domain.ItemPrice, error)
+	ListForItems(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.ItemPrice, error)
+	Save(ctx context.Context, price *domain.ItemPrice) error
+	Delete(ctx context.Context, userID string, id string) (bool, error)
+}
+
+// costTripPrices is where the prices paid on the user's shopping trips come
+// from.
+type costTripPrices interface {
+	ListPrices(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.PricePoint, error)
+}
+
+// costShoppingLists is the part of ShoppingListService a list's cost is
+// taken from.
+type costShoppingLists interface {
+	GetByID(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error)
+}
+
+type ItemPriceService interface {
+	// List returns the user's prices at storeChainID, or at every chain when
+	// it is empty.
+	List(ctx context.Context, userID string, storeChainID string) ([]domain.ItemPrice, error)
+	// Save sets what an item costs at a store chain, replacing the price it
+	// had there.
+	Save(ctx context.Context, userID string, req *domain.SaveItemPriceRequest) (*domain.ItemPrice, error)
+	Delete(ctx context.Context, userID string, priceID string) error
+	// ListCost prices the list's items at storeChainID. Without one it uses
+	// the chain of the list or of its store, and failing that each item's
+	// latest price at any chain. Items without a price are costed from what
+	// was recently paid for them on trips.
+	ListCost(ctx context.Context, userID string, listID string, storeChainID string) (*domain.Cost, error)
+	// RecipeCost prices the recipe's ingredients scaled to servings (zero
+	// keeps the recipe's own) at storeChainID, or at any chain when it is
+	// empty.
+	RecipeCost(ctx context.Context, userID string, recipeID string, servings float64, storeChainID string) (*domain.Cost, error)
+}
+
+type itemPriceService struct {
+	priceRepo         itemPriceRepository
+	tripPrices        costTripPrices
+	shoppingLists     costShoppingLists
+	recipeRepo        shoppingListRecipeRepository
+	storeChainService StoreChainService
+	storeService      StoreService
+	logger            *zap.Logger
+}
+
+func NewItemPriceService(priceRepo itemPriceRepository, tripPrices costTripPrices, shoppingLists costShoppingLists, recipeRepo shoppingListRecipeRepository, storeChainService StoreChainService, storeService StoreService, logger *zap.Logger) ItemPriceService {
+	return &itemPriceService{
+		priceRepo:         priceRepo,
+		tripPrices:        tripPrices,
+		shoppingLists:     shoppingLists,
+		recipeRepo:        recipeRepo,
+		storeChainService: storeChainService,
+		storeService:      storeService,
+		logger:            logger,
+	}
+}
+
+func (s *itemPriceService) List(ctx context.Context, userID string, storeChainID string) ([]domain.ItemPrice, error) {
+	return s.priceRepo.List(ctx, userID, storeChainID)
+}
+
+func (s *itemPriceService) Save(ctx context.Context, userID string, req *domain.SaveItemPriceRequest) (*domain.ItemPrice, error) {
+	name := strings.TrimSpace(req.Name)
+	if itemKey(name) == "" {
+		return nil, errors.New("name must not be empty", "INVALID_INPUT")
+	}
+	if req.Quantity <= 0 {
+		return nil, errors.New("quantity must be positive", "INVALID_INPUT")
+	}
+	if _, err := s.storeChainService.GetChain(ctx, req.StoreChainID); err != nil {
+		return nil, err
+	}
+
+	base, perUnit, _ := units.Normalize(req.Quantity, req.Unit)
+	price := &domain.ItemPrice{
+		UserID:         userID,
+		StoreChainID:   req.StoreChainID,
+		Name:           name,
+		ItemKey:        itemKey(name),
+		Quantity:       req.Quantity,
+		Unit:           strings.TrimSpace(req.Unit),
+		PriceCents:     req.PriceCents,
+		Currency:       strings.ToUpper(req.Currency),
+		UnitPriceCents: float64(req.PriceCents) / base,
+		PerUnit:        perUnit,
+	}
+	if price.Currency == "" {
+		price.Currency = domain.DefaultTripCurrency
+	}
+
+	if err := s.priceRepo.Save(ctx, price); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+func (s *itemPriceService) Delete(ctx context.Context, userID string, priceID string) error {
+	deleted, err := s.priceRepo.Delete(ctx, userID, priceID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotFound.Wrap("price not found")
+	}
+	return nil
+}
+
+func (s *itemPriceService) ListCost(ctx context.Context, userID string, listID string, storeChainID string) (*domain.Cost, error) {
+	list, err := s.shoppingLists.GetByID(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if storeChainID != "" {
+		if _, err := s.storeChainService.GetChain(ctx, storeChainID); err != nil {
+			return nil, err
+		}
+	} else if storeChainID, err = s.listChain(ctx, userID, list); err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.ItemCost, len(list.Items))
+	for i, item := range list.Items {
+		items[i] = domain.ItemCost{ListItemID: item.ID, Name: item.Name, Amount: item.Amount, Unit: item.Unit}
+	}
+	return s.cost(ctx, userID, storeChainID, items)
+}
+
+// listChain returns the chain of the list or, failing that, of its store;
+// empty when neither has one.
+func (s *itemPriceService) listChain(ctx context.Context, userID string, list *domain.ShoppingList) (string, error) {
+	if list.StoreChainID != nil {
+		return *list.StoreChainID, nil
+	}
+	if list.StoreID == nil {
+		return "", nil
+	}
+	store, err := s.storeService.Get(ctx, userID, *list.StoreID)
+	if err != nil {
+		return "", err
+	}
+	if store.StoreChainID == nil {
+		return "", nil
+	}
+	return *store.StoreChainID, nil
+}
+
+func (s *itemPriceService) RecipeCost(ctx context.Context, userID string, recipeID string, servings float64, storeChainID string) (*domain.Cost, error) {
+	recipe, err := s.recipeRepo.GetByID(ctx, recipeID, domain.NutritionDetailBase)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.IsPrivate && recipe.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
+	if storeChainID != "" {
+		if _, err := s.storeChainService.GetChain(ctx, storeChainID); err != nil {
+			return nil, err
+		}
+	}
+
+	scale := 1.0
+	if servings > 0 && recipe.Servings > 0 {
+		scale = servings / float64(recipe.Servings)
+	} else {
+		servings = float64(recipe.Servings)
+	}
+	items := make([]domain.ItemCost, len(recipe.Ingredients))
+	for i, ingredient := range recipe.Ingredients {
+		items[i] = domain.ItemCost{IngredientID: ingredient.ID, Name: ingredient.Name, Amount: ingredient.Amount * scale, Unit: ingredient.Unit}
+	}
+
+	cost, err := s.cost(ctx, userID, storeChainID, items)
+	if err != nil {
+		return nil, err
+	}
+	if servings > 0 {
+		perServing := roundCents(float64(cost.TotalCents) / servings)
+		cost.Servings, cost.PerServingCents = servings, &perServing
+	}
+	return cost, nil
+}
+
+// cost prices items by the user's latest price for each at storeChainID, or
+// at any chain when it is empty. An item that price does not cost is judged
+// from what was recently paid for it on trips, at the chain's stores when
+// there is one. Costs are only added up in one currency, that of the latest
+// price, or of the latest trip without any.
+func (s *itemPriceService) cost(ctx context.Context, userID string, storeChainID string, items []domain.ItemCost) (*domain.Cost, error) {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, itemKey(item.Name))
+	}
+	prices, err := s.priceRepo.ListForItems(ctx, userID, storeChainID, keys)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.tripPrices.ListPrices(ctx, userID, storeChainID, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.Cost{StoreChainID: storeChainID, Currency: domain.DefaultTripCurrency, Items: items}
+	if len(prices) > 0 {
+		result.Currency = prices[0].Currency
+	} else if len(paid) > 0 {
+		result.Currency = paid[0].Currency
+	}
+	byKey := make(map[string][]domain.ItemPrice)
+	for _, p := range prices {
+		byKey[p.ItemKey] = append(byKey[p.ItemKey], p)
+	}
+	recent := make(map[string][]domain.PricePoint)
+	paidElsewhere := make(map[string]bool)
+	for _, p := range paid {
+		if p.Currency != result.Currency {
+			paidElsewhere[p.ItemKey] = true
+		} else if len(recent[p.ItemKey]) < recentPriceCount {
+			recent[p.ItemKey] = append(recent[p.ItemKey], p)
+		}
+	}
+
+	for i := range result.Items {
+		item := &result.Items[i]
+		key := itemKey(item.Name)
+		priced := *item
+		missing := priceItem(&priced, byKey[key], result.Currency)
+		switch {
+		case missing == "":
+			*item = priced
+		case len(recent[key]) > 0:
+			estimateItem(item, recent[key])
+		default:
+			*item = priced
+			if missing == domain.CostMissingPrice && paidElsewhere[key] {
+				missing = domain.CostMissingCurrency
+			}
+			item.Missing = missing
+			result.MissingCount++
+			continue
+		}
+		result.TotalCents += *item.CostCents
+	}
+	return result, nil
+}
+
+// priceItem sets what item costs by the latest of prices in currency and
+// returns why it has no cost, if it has none. Its amount is converted to the
+// price's unit; an item without an amount, like salt to taste, costs a pack
+// as priced.
+func priceItem(item *domain.ItemCost, prices []domain.ItemPrice, currency string) string {
+	if len(prices) == 0 {
+		return domain.CostMissingPrice
+	}
+	var price *domain.ItemPrice
+	for i := range prices {
+		if prices[i].Currency == currency {
+			price = &prices[i]
+			break
+		}
+	}
+	if price == nil {
+		return domain.CostMissingCurrency
+	}
+	item.PriceID, item.UnitPriceCents, item.PerUnit = price.ID, price.UnitPriceCents, price.PerUnit
+
+	cents := price.PriceCents
+	if item.Amount > 0 {
+		amount, unit, _ := units.Normalize(item.Amount, item.Unit)
+		if unit != price.PerUnit {
+			return domain.CostMissingUnit
+		}
+		cents = roundCents(amount * price.UnitPriceCents)
+	}
+	item.CostCents, item.Basis = &cents, domain.CostBasisPrice
+	return ""
+}
+
+// estimateItem sets what item costs judged from its recent trip prices: the
+// median price per unit scaled to the amount where its amount converts to
+// the unit they were bought in, otherwise the median price of a purchase.
+func estimateItem(item *domain.ItemCost, prices []domain.PricePoint) {
+	amount, unit, _ := units.Normalize(item.Amount, item.Unit)
+	var unitPrices, purchases []float64
+	for _, p := range prices {
+		purchases = append(purchases, float64(p.PriceCents))
+		if base, perUnit, _ := units.Normalize(p.Quantity, p.Unit); base > 0 && perUnit == unit {
+			unitPrices = append(unitPrices, float64(p.PriceCents)/base)
+		}
+	}
+
+	cents, basis := roundCents(median(purchases)), domain.CostBasisPurchase
+	if item.Amount > 0 && len(unitPrices) > 0 {
+		cents, basis = roundCents(median(unitPrices)*amount), domain.CostBasisUnitPrice
+		item.UnitPriceCents, item.PerUnit = median(unitPrices), unit
+	}
+	item.CostCents, item.Basis = &cents, basis
+}
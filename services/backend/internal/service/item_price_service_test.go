@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockItemPriceRepo struct {
+	mock.Mock
+}
+
+func (m *mockItemPriceRepo) List(ctx context.Context, userID string, storeChainID string) ([]domain.ItemPrice, error) {
+	args := m.Called(ctx, userID, storeChainID)
+	v, _ := args.Get(0).([]domain.ItemPrice)
+	return v, args.Error(1)
+}
+
+func (m *mockItemPriceRepo) ListForItems(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.ItemPrice, error) {
+	args := m.Called(ctx, userID, storeChainID, itemKeys)
+	v, _ := args.Get(0).([]domain.ItemPrice)
+	return v, args.Error(1)
+}
+
+func (m *mockItemPriceRepo) Save(ctx context.Context, price *domain.ItemPrice) error {
+	args := m.Called(ctx, price)
+	return args.Error(0)
+}
+
+func (m *mockItemPriceRepo) Delete(ctx context.Context, userID string, id string) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestItemPriceService_Save(t *testing.T) {
+	t.Run("normalizes the price per kilogram", func(t *testing.T) {
+		repo, chains := new(mockItemPriceRepo), new(mockStoreChainService)
+		chains.On("GetChain", mock.Anything, "rewe").Return(&domain.StoreChain{ID: "rewe"}, nil).Once()
+		repo.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewItemPriceService(repo, nil, nil, nil, chains, nil, zap.NewNop())
+		price, err := srv.Save(context.Background(), "user-1", &domain.SaveItemPriceRequest{StoreChainID: "rewe", Name: " Flour ", Quantity: 500, Unit: "g", PriceCents: 79})
+
+		require.NoError(t, err)
+		require.Equal(t, "flour", price.ItemKey)
+		require.Equal(t, 158.0, price.UnitPriceCents)
+		require.Equal(t, "kg", price.PerUnit)
+		require.Equal(t, domain.DefaultTripCurrency, price.Currency)
+	})
+
+	t.Run("rejects unknown chains", func(t *testing.T) {
+		repo, chains := new(mockItemPriceRepo), new(mockStoreChainService)
+		chains.On("GetChain", mock.Anything, "nope").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewItemPriceService(repo, nil, nil, nil, chains, nil, zap.NewNop())
+		_, err := srv.Save(context.Background(), "user-1", &domain.SaveItemPriceRequest{StoreChainID: "nope", Name: "Flour", Quantity: 1, PriceCents: 79})
+
+		require.True(t, apperrors.IsNotFound(err))
+		repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestItemPriceService_ListCost(t *testing.T) {
+	chainID := "rewe"
+	list := &domain.ShoppingList{ID: "list-1", UserID: "user-1", StoreChainID: &chainID, Items: []domain.ShoppingListItem{
+		{ID: "i1", Name: "Flour", Amount: 750, Unit: "g"},
+		{ID: "i2", Name: "Milk", Amount: 2, Unit: "l"},
+		{ID: "i3", Name: "Parsley", Amount: 1, Unit: "bunch"},
+		{ID: "i4", Name: "Saffron"},
+		{ID: "i5", Name: "Butter", Amount: 250, Unit: "g"},
+	}}
+
+	repo, trips, lists := new(mockItemPriceRepo), new(mockShoppingTripRepo), new(mockTripShoppingLists)
+	lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(list, nil).Once()
+	trips.On("ListPrices", mock.Anything, "user-1", chainID, []string{"flour", "milk", "parsley", "saffron", "butter"}).Return(nil, nil).Once()
+	repo.On("ListForItems", mock.Anything, "user-1", chainID, []string{"flour", "milk", "parsley", "saffron", "butter"}).Return([]domain.ItemPrice{
+		{ID: "p1", ItemKey: "flour", PriceCents: 99, Currency: "EUR", UnitPriceCents: 99, PerUnit: "kg"},
+		{ID: "p2", ItemKey: "milk", PriceCents: 109, Currency: "EUR", UnitPriceCents: 109, PerUnit: "l"},
+		{ID: "p3", ItemKey: "parsley", PriceCents: 129, Currency: "EUR", UnitPriceCents: 129, PerUnit: "bunch"},
+		{ID: "p5", ItemKey: "butter", PriceCents: 50, Currency: "CHF", UnitPriceCents: 200, PerUnit: "kg"},
+	}, nil).Once()
+
+	srv := NewItemPriceService(repo, trips, lists, nil, nil, nil, zap.NewNop())
+	cost, err := srv.ListCost(context.Background(), "user-1", "list-1", "")
+
+	require.NoError(t, err)
+	require.Equal(t, chainID, cost.StoreChainID)
+	require.Equal(t, int64(74+218+129), cost.TotalCents)
+	require.Equal(t, 2, cost.MissingCount)
+	require.Equal(t, int64(74), *cost.Items[0].CostCents)
+	require.Equal(t, domain.CostMissingPrice, cost.Items[3].Missing)
+	require.Nil(t, cost.Items[3].CostCents)
+	require.Equal(t, domain.CostMissingCurrency, cost.Items[4].Missing)
+}
+
+func TestItemPriceService_RecipeCost(t *testing.T) {
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: "user-1", Servings: 4, Ingredients: []domain.RecipeIngredient{
+		{ID: "ing-1", Name: "Flour", Amount: 500, Unit: "g"},
+		{ID: "ing-2", Name: "Milk", Amount: 2, Unit: "cups"},
+		{ID: "ing-3", Name: "Eggs", Amount: 2},
+	}}
+
+	repo, trips, recipes := new(mockItemPriceRepo), new(mockShoppingTripRepo), new(mockShoppingListRecipeRepository)
+	recipes.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	trips.On("ListPrices", mock.Anything, "user-1", "", []string{"flour", "milk", "eggs"}).Return(nil, nil).Once()
+	repo.On("ListForItems", mock.Anything, "user-1", "", []string{"flour", "milk", "eggs"}).Return([]domain.ItemPrice{
+		{ID: "p1", ItemKey: "flour", PriceCents: 99, Currency: "EUR", UnitPriceCents: 100, PerUnit: "kg"},
+		{ID: "p2", ItemKey: "milk", PriceCents: 109, Currency: "EUR", UnitPriceCents: 100, PerUnit: "kg"},
+		{ID: "p3", ItemKey: "eggs", PriceCents: 300, Currency: "EUR", UnitPriceCents: 30, PerUnit: "piece"},
+	}, nil).Once()
+
+	srv := NewItemPriceService(repo, trips, nil, recipes, nil, nil, zap.NewNop())
+	cost, err := srv.RecipeCost(context.Background(), "user-1", "recipe-1", 8, "")
+
+	require.NoError(t, err)
+	require.Equal(t, 8.0, cost.Servings)
+	require.Equal(t, int64(100+120), cost.TotalCents)
+	require.Equal(t, int64(28), *cost.PerServingCents)
+	require.Equal(t, "ing-2", cost.Items[1].IngredientID)
+	require.Equal(t, domain.CostMissingUnit, cost.Items[1].Missing)
+	require.Equal(t, "p2", cost.Items[1].PriceID)
+	require.Equal(t, 1, cost.MissingCount)
+}
+
+func TestItemPriceService_RecipeCost_FromTrips(t *testing.T) {
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: "user-1", Servings: 2, Ingredients: []domain.RecipeIngredient{
+		{Name: "Flour", Amount: 500, Unit: "g"},
+		{Name: "Eggs", Amount: 2},
+		{Name: "Saffron", Amount: 1, Unit: "pinch"},
+		{Name: "Milk", Amount: 1, Unit: "l"},
+	}}
+
+	repo, trips, recipes := new(mockItemPriceRepo), new(mockShoppingTripRepo), new(mockShoppingListRecipeRepository)
+	recipes.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	repo.On("ListForItems", mock.Anything, "user-1", "", []string{"flour", "eggs", "saffron", "milk"}).Return([]domain.ItemPrice{
+		{ID: "p1", ItemKey: "milk", PriceCents: 109, Currency: "EUR", UnitPriceCents: 109, PerUnit: "l"},
+	}, nil).Once()
+	trips.On("ListPrices", mock.Anything, "user-1", "", []string{"flour", "eggs", "saffron", "milk"}).Return([]domain.PricePoint{
+		{ItemKey: "flour", Quantity: 1, Unit: "kg", PriceCents: 120, Currency: "EUR"},
+		{ItemKey: "eggs", Quantity: 10, Unit: "pack", PriceCents: 300, Currency: "EUR"},
+		{ItemKey: "flour", Quantity: 1000, Unit: "g", PriceCents: 100, Currency: "EUR"},
+		{ItemKey: "flour", Quantity: 500, Unit: "G", PriceCents: 40, Currency: "EUR"},
+		{ItemKey: "saffron", Quantity: 1, Unit: "pinch", PriceCents: 900, Currency: "USD"},
+		{ItemKey: "eggs", Quantity: 6, Unit: "pack", PriceCents: 200, Currency: "EUR"},
+		{ItemKey: "milk", Quantity: 1, Unit: "l", PriceCents: 500, Currency: "EUR"},
+	}, nil).Once()
+
+	srv := NewItemPriceService(repo, trips, nil, recipes, nil, nil, zap.NewNop())
+	cost, err := srv.RecipeCost(context.Background(), "user-1", "recipe-1", 4, "")
+	require.NoError(t, err)
+
+	require.Equal(t, "EUR", cost.Currency)
+	// 1 kg of flour at a median 1.00 a kilogram whatever unit it was bought
+	// in, and the eggs bought by the pack at a median of 2.50 a purchase.
+	require.Equal(t, int64(100), *cost.Items[0].CostCents)
+	require.Equal(t, domain.CostBasisUnitPrice, cost.Items[0].Basis)
+	require.Equal(t, "kg", cost.Items[0].PerUnit)
+	require.Equal(t, int64(250), *cost.Items[1].CostCents)
+	require.Equal(t, domain.CostBasisPurchase, cost.Items[1].Basis)
+	// Saffron was only paid for in USD.
+	require.Nil(t, cost.Items[2].CostCents)
+	require.Equal(t, domain.CostMissingCurrency, cost.Items[2].Missing)
+	// A price the user set wins over what a trip paid.
+	require.Equal(t, int64(218), *cost.Items[3].CostCents)
+	require.Equal(t, domain.CostBasisPrice, cost.Items[3].Basis)
+	require.Equal(t, "p1", cost.Items[3].PriceID)
+	require.Equal(t, int64(100+250+218), cost.TotalCents)
+	require.Equal(t, 1, cost.MissingCount)
+}
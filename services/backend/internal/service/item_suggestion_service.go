@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// itemHistoryWindow is how far back suggestions look into past lists.
+	itemHistoryWindow      = 365 * 24 * time.Hour
+	defaultSuggestionLimit = 10
+	// minTogether is how many past lists an item must share with a list's
+	// items before it is suggested for the list.
+	minTogether = 2
+)
+
+type itemHistoryRepository interface {
+	GetByID(ctx context.Context, listID string) (*domain.ShoppingList, error)
+	ListItemHistory(ctx context.Context, userID string, since time.Time, keys []string) ([]domain.ShoppingListItem, error)
+	RankItemHistory(ctx context.Context, userID string, since time.Time, q string, limit int) ([]domain.ItemUsage, error)
+	ListBoughtTogether(ctx context.Context, userID string, listID string, keys []string, since time.Time, minLists int, limit int) ([]domain.ItemUsage, error)
+}
+
+type ItemSuggestionService interface {
+	// Suggest returns items from the user's history whose name matches q,
+	// best match first: names starting with q, then names with a word
+	// starting with q, then names containing it. Within each, items used
+	// often and then recently come first.
+	Suggest(ctx context.Context, userID string, q string, limit int) ([]domain.ItemSuggestion, error)
+	// BoughtTogether suggests items that were often on past lists with the
+	// items on the list, leaving out those already on it.
+	BoughtTogether(ctx context.Context, userID string, listID string, limit int) ([]domain.ItemSuggestion, error)
+}
+
+type itemSuggestionService struct {
+	historyRepo itemHistoryRepository
+	logger      *zap.Logger
+}
+
+func NewItemSuggestionService(historyRepo itemHistoryRepository, logger *zap.Logger) ItemSuggestionService {
+	return &itemSuggestionService{
+		historyRepo: historyRepo,
+		logger:      logger,
+	}
+}
+
+// itemHistoryEntry is everything known about one item, by itemKey.
+type itemHistoryEntry struct {
+	suggestion domain.ItemSuggestion
+	amounts    []float64
+	lists      map[string]bool
+}
+
+func (s *itemSuggestionService) Suggest(ctx context.Context, userID string, q string, limit int) ([]domain.ItemSuggestion, error) {
+	q = itemKey(q)
+	if q == "" {
+		return nil, errors.New("q must not be empty", "INVALID_INPUT")
+	}
+
+	since := time.Now().Add(-itemHistoryWindow)
+	usage, err := s.historyRepo.RankItemHistory(ctx, userID, since, q, suggestionLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	return s.describeItems(ctx, userID, since, usage)
+}
+
+func (s *itemSuggestionService) BoughtTogether(ctx context.Context, userID string, listID string, limit int) ([]domain.ItemSuggestion, error) {
+	list, err := s.historyRepo.GetByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
+
+	var onList []string
+	seen := make(map[string]bool, len(list.Items))
+	for _, item := range list.Items {
+		if key := itemKey(item.Name); key != "" && !seen[key] {
+			seen[key] = true
+			onList = append(onList, key)
+		}
+	}
+	if len(onList) == 0 {
+		return make([]domain.ItemSuggestion, 0), nil
+	}
+
+	since := time.Now().Add(-itemHistoryWindow)
+	usage, err := s.historyRepo.ListBoughtTogether(ctx, userID, listID, onList, since, minTogether, suggestionLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	suggestions, err := s.describeItems(ctx, userID, since, usage)
+	if err != nil {
+		return nil, err
+	}
+	for i := range suggestions {
+		suggestions[i].Together = usage[i].Lists
+	}
+	return suggestions, nil
+}
+
+// describeItems turns usage into suggestions, in its order, from the history
+// of just those items.
+func (s *itemSuggestionService) describeItems(ctx context.Context, userID string, since time.Time, usage []domain.ItemUsage) ([]domain.ItemSuggestion, error) {
+	suggestions := make([]domain.ItemSuggestion, 0, len(usage))
+	if len(usage) == 0 {
+		return suggestions, nil
+	}
+	keys := make([]string, len(usage))
+	for i, u := range usage {
+		keys[i] = u.Key
+	}
+	items, err := s.historyRepo.ListItemHistory(ctx, userID, since, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	history := buildItemHistory(items)
+	for _, u := range usage {
+		entry, ok := history[itemKey(u.Key)]
+		if !ok {
+			entry = &itemHistoryEntry{suggestion: domain.ItemSuggestion{Name: u.Key, TimesUsed: u.Lists}}
+		}
+		suggestions = append(suggestions, entry.suggestion)
+	}
+	return suggestions, nil
+}
+
+// buildItemHistory folds items, newest first, into one entry per item. An
+// item counts once per list; its newest use gives its name, category and
+// unit, and its typical amount is the median of the amounts it was put on
+// lists with in that unit.
+func buildItemHistory(items []domain.ShoppingListItem) map[string]*itemHistoryEntry {
+	history := make(map[string]*itemHistoryEntry)
+	for _, item := range items {
+		key := itemKey(item.Name)
+		if key == "" {
+			continue
+		}
+		entry, ok := history[key]
+		if !ok {
+			entry = &itemHistoryEntry{
+				suggestion: domain.ItemSuggestion{
+					Name:       strings.TrimSpace(item.Name),
+					Category:   item.Category,
+					Unit:       item.Unit,
+					LastUsedAt: item.CreatedAt,
+				},
+				lists: make(map[string]bool),
+			}
+			history[key] = entry
+		}
+		if entry.lists[item.ListID] {
+			continue
+		}
+		entry.lists[item.ListID] = true
+
+		entry.suggestion.TimesUsed++
+		if item.Amount > 0 && strings.EqualFold(item.Unit, entry.suggestion.Unit) {
+			entry.amounts = append(entry.amounts, item.Amount)
+		}
+	}
+
+	for _, entry := range history {
+		if len(entry.amounts) > 0 {
+			entry.suggestion.Amount = median(entry.amounts)
+		}
+	}
+	return history
+}
+
+func suggestionLimit(limit int) int {
+	if limit <= 0 {
+		return defaultSuggestionLimit
+	}
+	return limit
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func historyItem(listID string, name string, category domain.Category, amount float64, unit string, age time.Duration) domain.ShoppingListItem {
+	return domain.ShoppingListItem{ListID: listID, Name: name, Category: category, Amount: amount, Unit: unit, CreatedAt: time.Now().Add(-age)}
+}
+
+func TestItemSuggestionService_Suggest(t *testing.T) {
+	day := 24 * time.Hour
+	repo := new(mockShoppingListRepository)
+	repo.On("RankItemHistory", mock.Anything, "user-1", mock.Anything, "mil", defaultSuggestionLimit).Return([]domain.ItemUsage{
+		{Key: "milk", Lists: 3}, {Key: "buttermilk", Lists: 1},
+	}, nil)
+	// Newest first, as the repository returns them.
+	repo.On("ListItemHistory", mock.Anything, "user-1", mock.Anything, []string{"milk", "buttermilk"}).Return([]domain.ShoppingListItem{
+		historyItem("l4", "Milk", domain.CategoryDairy, 2, "l", 1*day),
+		historyItem("l4", "milk", domain.CategoryDairy, 5, "l", 1*day),
+		historyItem("l3", "Buttermilk", domain.CategoryDairy, 0, "", 2*day),
+		historyItem("l2", "Milk", domain.Category("DAIRY_MILK"), 1, "l", 10*day),
+		historyItem("l1", "Milk", domain.CategoryDairy, 500, "ml", 20*day),
+	}, nil)
+
+	srv := NewItemSuggestionService(repo, zap.NewNop())
+	suggestions, err := srv.Suggest(context.Background(), "user-1", " MIL", 0)
+
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	require.Equal(t, "Milk", suggestions[0].Name)
+	require.Equal(t, domain.CategoryDairy, suggestions[0].Category)
+	require.Equal(t, "l", suggestions[0].Unit)
+	require.WithinDuration(t, time.Now().Add(-day), suggestions[0].LastUsedAt, time.Minute)
+	// Once per list, and the typical amount only counts litres.
+	require.Equal(t, 3, suggestions[0].TimesUsed)
+	require.Equal(t, 1.5, suggestions[0].Amount)
+	require.Equal(t, "Buttermilk", suggestions[1].Name)
+
+	repo.On("RankItemHistory", mock.Anything, "user-1", mock.Anything, "xyz", 5).Return(nil, nil).Once()
+	none, err := srv.Suggest(context.Background(), "user-1", "xyz", 5)
+	require.NoError(t, err)
+	require.Empty(t, none)
+
+	_, err = srv.Suggest(context.Background(), "user-1", "  ", 0)
+	require.True(t, apperrors.IsInvalidInput(err))
+}
+
+func TestItemSuggestionService_BoughtTogether(t *testing.T) {
+	repo := new(mockShoppingListRepository)
+	repo.On("GetByID", mock.Anything, "current").Return(&domain.ShoppingList{ID: "current", UserID: "user-1", Items: []domain.ShoppingListItem{
+		{Name: "Pasta"}, {Name: " pasta"}, {Name: "Basil"},
+	}}, nil)
+	repo.On("ListBoughtTogether", mock.Anything, "user-1", "current", []string{"pasta", "basil"}, mock.Anything, minTogether, defaultSuggestionLimit).
+		Return([]domain.ItemUsage{{Key: "tomatoes", Lists: 2}}, nil)
+	repo.On("ListItemHistory", mock.Anything, "user-1", mock.Anything, []string{"tomatoes"}).Return([]domain.ShoppingListItem{
+		historyItem("l3", "Tomatoes", domain.CategoryProduce, 0, "", time.Hour),
+		historyItem("l2", "Tomatoes", domain.CategoryProduce, 0, "", 2*time.Hour),
+		historyItem("l1", "Tomatoes", domain.CategoryProduce, 0, "", 3*time.Hour),
+	}, nil)
+
+	srv := NewItemSuggestionService(repo, zap.NewNop())
+	suggestions, err := srv.BoughtTogether(context.Background(), "user-1", "current", 0)
+
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+	require.Equal(t, "Tomatoes", suggestions[0].Name)
+	require.Equal(t, 2, suggestions[0].Together)
+	require.Equal(t, 3, suggestions[0].TimesUsed)
+
+	_, err = srv.BoughtTogether(context.Background(), "user-2", "current", 0)
+	require.ErrorIs(t, err, apperrors.ErrUnauthorized)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/pkg/gtin"
+	"github.com/H3nSte1n/recipe/pkg/openfoodfacts"
+	"go.uber.org/zap"
+)
+
+// productImportBatch is how many products an import writes at a time.
+const productImportBatch = 1000
+
+type productImportRepository interface {
+	Upsert(ctx context.Context, products []domain.Product) error
+}
+
+type ProductImportService interface {
+	// Import loads the products of an Open Food Facts JSONL dump, gzipped or
+	// not, replacing products already known by barcode.
+	Import(ctx context.Context, dump io.Reader) (*domain.ProductImportStats, error)
+}
+
+type productImportService struct {
+	productRepo     productImportRepository
+	categoryService CategoryService
+	logger          *zap.Logger
+}
+
+// NewProductImportService returns an importer that categorizes products with
+// categoryService. Give it one without an AI model: a dump has millions of
+// products, so their categories come from the term dictionary alone.
+func NewProductImportService(productRepo productImportRepository, categoryService CategoryService, logger *zap.Logger) ProductImportService {
+	return &productImportService{
+		productRepo:     productRepo,
+		categoryService: categoryService,
+		logger:          logger,
+	}
+}
+
+func (s *productImportService) Import(ctx context.Context, dump io.Reader) (*domain.ProductImportStats, error) {
+	reader, err := openfoodfacts.NewReader(dump)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.ProductImportStats{}
+	batch := make([]*openfoodfacts.Product, 0, productImportBatch)
+	seen := make(map[string]bool, productImportBatch)
+	batches := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.productRepo.Upsert(ctx, s.toProducts(ctx, batch)); err != nil {
+			return err
+		}
+		stats.Imported += len(batch)
+		if batches++; batches%100 == 0 {
+			s.logger.Info("importing products", zap.Int("imported", stats.Imported))
+		}
+		batch = batch[:0]
+		clear(seen)
+		return nil
+	}
+
+	for {
+		product, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, err
+		}
+
+		code, err := gtin.Normalize(product.Code)
+		if err != nil || product.Name == "" {
+			stats.Skipped++
+			continue
+		}
+		product.Code = code
+		// One statement cannot upsert the same barcode twice.
+		if seen[code] {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+		seen[code] = true
+		batch = append(batch, product)
+
+		if len(batch) == productImportBatch {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return stats, err
+	}
+
+	stats.Skipped += reader.Skipped()
+	return stats, nil
+}
+
+// toProducts maps a batch of dump products to products, categorizing each
+// by its most specific English category the term dictionary knows, or else
+// by its name.
+func (s *productImportService) toProducts(ctx context.Context, batch []*openfoodfacts.Product) []domain.Product {
+	candidates := make([][]string, len(batch))
+	var terms []string
+	for i, p := range batch {
+		for j := len(p.CategoryTags) - 1; j >= 0; j-- {
+			if term := openfoodfacts.CategoryTerm(p.CategoryTags[j]); term != "" {
+				candidates[i] = append(candidates[i], term)
+			}
+		}
+		candidates[i] = append(candidates[i], p.Name)
+		terms = append(terms, candidates[i]...)
+	}
+	categories := s.categoryService.Categorize(ctx, terms)
+
+	products := make([]domain.Product, len(batch))
+	for i, p := range batch {
+		category := domain.CategoryOther
+		for _, term := range candidates[i] {
+			if c := categories[term]; c != "" && c != domain.CategoryOther {
+				category = c
+				break
+			}
+		}
+		products[i] = domain.Product{
+			Barcode:  p.Code,
+			Name:     p.Name,
+			Brand:    p.Brand,
+			Quantity: p.Quantity,
+			Category: category,
+			Nutrition: domain.ProductNutrition{
+				EnergyKcal:    p.Nutrition.EnergyKcal,
+				Fat:           p.Nutrition.Fat,
+				SaturatedFat:  p.Nutrition.SaturatedFat,
+				Carbohydrates: p.Nutrition.Carbohydrates,
+				Sugars:        p.Nutrition.Sugars,
+				Fiber:         p.Nutrition.Fiber,
+				Protein:       p.Nutrition.Protein,
+				Salt:          p.Nutrition.Salt,
+			},
+		}
+	}
+	return products
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// productDump is a small Open Food Facts dump: two good products, a line that
+// is not JSON, a barcode with a wrong check digit, a product without a name
+// and the first product again under its EAN-13 code.
+const productDump = `{"code":"036000291452","product_name":"Free range eggs","brands":"Happy Hens, Farm Co","quantity":"6 pcs","categories_tags":["en:farming-products","en:eggs"],"nutriments":{"energy-kcal_100g":143,"proteins_100g":"12.6"}}
+{"code":4006381333931,"product_name":"Chickpeas","categories_tags":["fr:conserves"]}
+{broken
+{"code":"4006381333932","product_name":"Bad check digit"}
+{"code":"5901234123457","product_name":"  "}
+
+{"code":"0036000291452","product_name":"Free range eggs XL","categories_tags":["en:eggs"]}
+`
+
+func importProducts(t *testing.T, dump []byte, upsertErr error) ([][]domain.Product, *domain.ProductImportStats, error) {
+	t.Helper()
+	var batches [][]domain.Product
+	repo := new(mockProductRepo)
+	repo.On("Upsert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(1).([]domain.Product))
+	}).Return(upsertErr)
+
+	srv := NewProductImportService(repo, newTestCategoryService(nil), zap.NewNop())
+	stats, err := srv.Import(context.Background(), bytes.NewReader(dump))
+	return batches, stats, err
+}
+
+func TestProductImportService_Import(t *testing.T) {
+	dump := `{"code":"3017620422003","product_name":"Nutella","categories_tags":["en:spreads","en:sweet-spreads"]}
+{"code":"0036000291452","product_name":"Eggs","categories_tags":["en:farming-products","fr:oeufs"],"nutriments":{"proteins_100g":12.6}}
+{"code":"036000291452","product_name":"Eggs, large","categories_tags":["en:eggs"]}
+{"code":"12345","product_name":"Bad code"}
+{"code":"96385074","product_name":""}
+{"code":"96385074"
+`
+	products := new(mockProductRepo)
+	var batches [][]domain.Product
+	products.On("Upsert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		batches = append(batches, append([]domain.Product(nil), args.Get(1).([]domain.Product)...))
+	}).Return(nil)
+
+	srv := NewProductImportService(products, newTestCategoryService(nil), zap.NewNop())
+	stats, err := srv.Import(context.Background(), bytes.NewBufferString(dump))
+
+	require.NoError(t, err)
+	require.Equal(t, &domain.ProductImportStats{Imported: 3, Skipped: 3}, stats)
+	// The second scan of the same eggs goes in its own batch.
+	require.Len(t, batches, 2)
+	require.Equal(t, domain.CategoryOther, batches[0][0].Category)
+	require.Equal(t, domain.Category("DAIRY_EGGS"), batches[0][1].Category)
+	require.Equal(t, 12.6, *batches[0][1].Nutrition.Protein)
+	require.Equal(t, "0036000291452", batches[1][0].Barcode)
+	require.Equal(t, domain.Category("DAIRY_EGGS"), batches[1][0].Category)
+}
+
+func TestProductImportService_Import_NormalizesAndSkips(t *testing.T) {
+	batches, stats, err := importProducts(t, []byte(productDump), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, &domain.ProductImportStats{Imported: 3, Skipped: 3}, stats)
+	// The repeated barcode starts a new upsert rather than going into the
+	// same statement twice; the later row wins.
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], 2)
+
+	eggs := batches[0][0]
+	require.Equal(t, "0036000291452", eggs.Barcode)
+	require.Equal(t, "Happy Hens", eggs.Brand)
+	require.Equal(t, "6 pcs", eggs.Quantity)
+	require.Equal(t, domain.Category("DAIRY_EGGS"), eggs.Category)
+	require.Equal(t, 143.0, *eggs.Nutrition.EnergyKcal)
+	require.Equal(t, 12.6, *eggs.Nutrition.Protein)
+	require.Nil(t, eggs.Nutrition.Fat)
+
+	// A numeric code is read as a barcode; without an English category the
+	// name decides.
+	chickpeas := batches[0][1]
+	require.Equal(t, "4006381333931", chickpeas.Barcode)
+	require.Equal(t, domain.Category("PANTRY_CANNED"), chickpeas.Category)
+
+	require.Equal(t, []domain.Product{{
+		Barcode: "0036000291452", Name: "Free range eggs XL", Category: "DAIRY_EGGS",
+	}}, batches[1])
+}
+
+func TestProductImportService_Import_Gzipped(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, err := zw.Write([]byte(productDump))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	batches, stats, err := importProducts(t, gz.Bytes(), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, &domain.ProductImportStats{Imported: 3, Skipped: 3}, stats)
+	require.Len(t, batches, 2)
+}
+
+func TestProductImportService_Import_StopsAtFailedUpsert(t *testing.T) {
+	_, stats, err := importProducts(t, []byte(productDump), errors.New("db error"))
+
+	require.EqualError(t, err, "db error")
+	require.Zero(t, stats.Imported)
+}
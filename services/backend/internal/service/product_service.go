@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/gtin"
+	"go.uber.org/zap"
+)
+
+type productRepository interface {
+	GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error)
+	GetSaved(ctx context.Context, userID string, barcode string) (*domain.UserProduct, error)
+	ListSaved(ctx context.Context, userID string) ([]domain.UserProduct, error)
+	Save(ctx context.Context, product *domain.UserProduct) error
+	DeleteSaved(ctx context.Context, userID string, barcode string) (bool, error)
+}
+
+type productShoppingListRepository interface {
+	GetByID(ctx context.Context, listID string) (*domain.ShoppingList, error)
+	AddItems(ctx context.Context, items []domain.ShoppingListItem) error
+}
+
+type ProductService interface {
+	// Lookup returns the product barcode stands for: the user's own product
+	// for it, otherwise the product table's.
+	Lookup(ctx context.Context, userID string, barcode string) (*domain.ScannedProduct, error)
+	// AddToList adds the scanned product to the list and returns the new
+	// item. The request's amount wins over the one saved with the product.
+	AddToList(ctx context.Context, userID string, listID string, req *domain.ScanBarcodeRequest) (*domain.ShoppingListItem, error)
+	ListSaved(ctx context.Context, userID string) ([]domain.UserProduct, error)
+	// Save saves a product for the barcode, replacing the one the user saved
+	// before.
+	Save(ctx context.Context, userID string, req *domain.SaveProductRequest) (*domain.UserProduct, error)
+	DeleteSaved(ctx context.Context, userID string, barcode string) error
+}
+
+type productService struct {
+	productRepo      productRepository
+	shoppingListRepo productShoppingListRepository
+	categoryService  CategoryService
+	logger           *zap.Logger
+}
+
+func NewProductService(productRepo productRepository, shoppingListRepo productShoppingListRepository, categoryService CategoryService, logger *zap.Logger) ProductService {
+	return &productService{
+		productRepo:      productRepo,
+		shoppingListRepo: shoppingListRepo,
+		categoryService:  categoryService,
+		logger:           logger,
+	}
+}
+
+func (s *productService) Lookup(ctx context.Context, userID string, barcode string) (*domain.ScannedProduct, error) {
+	code, err := normalizeBarcode(barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.productRepo.GetSaved(ctx, userID, code)
+	if err == nil {
+		return &domain.ScannedProduct{
+			Barcode:  saved.Barcode,
+			Name:     saved.Name,
+			Brand:    saved.Brand,
+			Quantity: saved.Quantity,
+			Category: saved.Category,
+			Amount:   saved.Amount,
+			Unit:     saved.Unit,
+			Saved:    true,
+		}, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByBarcode(ctx, code)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.ErrNotFound.Wrap("unknown barcode; save it as a product to scan it next time")
+		}
+		return nil, err
+	}
+	return &domain.ScannedProduct{
+		Barcode:   product.Barcode,
+		Name:      product.Name,
+		Brand:     product.Brand,
+		Quantity:  product.Quantity,
+		Category:  product.Category,
+		Nutrition: &product.Nutrition,
+	}, nil
+}
+
+func (s *productService) AddToList(ctx context.Context, userID string, listID string, req *domain.ScanBarcodeRequest) (*domain.ShoppingListItem, error) {
+	list, err := s.shoppingListRepo.GetByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
+
+	product, err := s.Lookup(ctx, userID, req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	item := domain.ShoppingListItem{
+		ListID:   list.ID,
+		Name:     product.Name,
+		Amount:   req.Amount,
+		Unit:     req.Unit,
+		Category: product.Category,
+		Notes:    req.Notes,
+	}
+	if item.Amount == 0 {
+		item.Amount, item.Unit = product.Amount, product.Unit
+	}
+	if item.Amount == 0 {
+		item.Amount = 1
+	}
+	if item.Notes == "" {
+		item.Notes = strings.Join(nonEmpty(product.Brand, product.Quantity), ", ")
+	}
+	// The dump's categories miss many products; their names may do better.
+	if item.Category == "" || item.Category == domain.CategoryOther {
+		item.Category = s.categoryService.Categorize(ctx, []string{item.Name})[item.Name]
+	}
+
+	items := []domain.ShoppingListItem{item}
+	if err := s.shoppingListRepo.AddItems(ctx, items); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+func (s *productService) ListSaved(ctx context.Context, userID string) ([]domain.UserProduct, error) {
+	return s.productRepo.ListSaved(ctx, userID)
+}
+
+func (s *productService) Save(ctx context.Context, userID string, req *domain.SaveProductRequest) (*domain.UserProduct, error) {
+	code, err := normalizeBarcode(req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	product := &domain.UserProduct{
+		UserID:   userID,
+		Barcode:  code,
+		Name:     strings.TrimSpace(req.Name),
+		Brand:    strings.TrimSpace(req.Brand),
+		Quantity: strings.TrimSpace(req.Quantity),
+		Category: req.Category,
+		Amount:   req.Amount,
+		Unit:     req.Unit,
+	}
+	if product.Name == "" {
+		return nil, errors.New("name must not be empty", "INVALID_INPUT")
+	}
+	if product.Category == "" {
+		product.Category = s.categoryService.Categorize(ctx, []string{product.Name})[product.Name]
+	}
+
+	if err := s.productRepo.Save(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *productService) DeleteSaved(ctx context.Context, userID string, barcode string) error {
+	code, err := normalizeBarcode(barcode)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.productRepo.DeleteSaved(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotFound.Wrap("product not found")
+	}
+	return nil
+}
+
+func normalizeBarcode(barcode string) (string, error) {
+	code, err := gtin.Normalize(barcode)
+	if err != nil {
+		return "", errors.New(err.Error(), "INVALID_INPUT")
+	}
+	return code, nil
+}
+
+func nonEmpty(values ...string) []string {
+	var kept []string
+	for _, v := range values {
+		if v != "" {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockProductRepo struct {
+	mock.Mock
+}
+
+func (m *mockProductRepo) GetByBarcode(ctx context.Context, barcode string) (*domain.Product, error) {
+	args := m.Called(ctx, barcode)
+	v, _ := args.Get(0).(*domain.Product)
+	return v, args.Error(1)
+}
+
+func (m *mockProductRepo) Upsert(ctx context.Context, products []domain.Product) error {
+	args := m.Called(ctx, products)
+	return args.Error(0)
+}
+
+func (m *mockProductRepo) GetSaved(ctx context.Context, userID string, barcode string) (*domain.UserProduct, error) {
+	args := m.Called(ctx, userID, barcode)
+	v, _ := args.Get(0).(*domain.UserProduct)
+	return v, args.Error(1)
+}
+
+func (m *mockProductRepo) ListSaved(ctx context.Context, userID string) ([]domain.UserProduct, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.UserProduct)
+	return v, args.Error(1)
+}
+
+func (m *mockProductRepo) Save(ctx context.Context, product *domain.UserProduct) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *mockProductRepo) DeleteSaved(ctx context.Context, userID string, barcode string) (bool, error) {
+	args := m.Called(ctx, userID, barcode)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestProductService_AddToList(t *testing.T) {
+	t.Run("adds the product with its brand and size as notes", func(t *testing.T) {
+		products, lists := new(mockProductRepo), new(mockShoppingListRepository)
+		lists.On("GetByID", mock.Anything, "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+		// A UPC-A scan finds the product stored under its EAN-13 code.
+		products.On("GetSaved", mock.Anything, "user-1", "0036000291452").Return(nil, gorm.ErrRecordNotFound).Once()
+		products.On("GetByBarcode", mock.Anything, "0036000291452").Return(&domain.Product{
+			Barcode: "0036000291452", Name: "Free range eggs", Brand: "Happy Hens", Quantity: "6 pcs", Category: domain.CategoryOther,
+		}, nil).Once()
+		lists.On("AddItems", mock.Anything, []domain.ShoppingListItem{{
+			ListID: "list-1", Name: "Free range eggs", Amount: 1, Category: "DAIRY_EGGS", Notes: "Happy Hens, 6 pcs",
+		}}).Return(nil).Once()
+
+		srv := NewProductService(products, lists, newTestCategoryService(nil), zap.NewNop())
+		item, err := srv.AddToList(context.Background(), "user-1", "list-1", &domain.ScanBarcodeRequest{Barcode: "036000291452"})
+
+		require.NoError(t, err)
+		require.Equal(t, "Free range eggs", item.Name)
+		lists.AssertExpectations(t)
+	})
+
+	t.Run("uses the user's saved product and amount", func(t *testing.T) {
+		products, lists := new(mockProductRepo), new(mockShoppingListRepository)
+		lists.On("GetByID", mock.Anything, "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+		products.On("GetSaved", mock.Anything, "user-1", "3017620422003").Return(&domain.UserProduct{
+			Barcode: "3017620422003", Name: "Hazelnut spread", Category: domain.CategoryPantry, Amount: 2, Unit: "jar",
+		}, nil).Once()
+		lists.On("AddItems", mock.Anything, mock.MatchedBy(func(items []domain.ShoppingListItem) bool {
+			return items[0].Name == "Hazelnut spread" && items[0].Amount == 2 && items[0].Unit == "jar" && items[0].Category == domain.CategoryPantry
+		})).Return(nil).Once()
+
+		srv := NewProductService(products, lists, newTestCategoryService(nil), zap.NewNop())
+		_, err := srv.AddToList(context.Background(), "user-1", "list-1", &domain.ScanBarcodeRequest{Barcode: "3017620422003"})
+
+		require.NoError(t, err)
+		products.AssertNotCalled(t, "GetByBarcode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports unknown barcodes", func(t *testing.T) {
+		products, lists := new(mockProductRepo), new(mockShoppingListRepository)
+		lists.On("GetByID", mock.Anything, "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+		products.On("GetSaved", mock.Anything, "user-1", "96385074").Return(nil, gorm.ErrRecordNotFound).Once()
+		products.On("GetByBarcode", mock.Anything, "96385074").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewProductService(products, lists, newTestCategoryService(nil), zap.NewNop())
+		_, err := srv.AddToList(context.Background(), "user-1", "list-1", &domain.ScanBarcodeRequest{Barcode: "96385074"})
+
+		require.ErrorIs(t, err, apperrors.ErrNotFound)
+		lists.AssertNotCalled(t, "AddItems", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects bad check digits", func(t *testing.T) {
+		lists := new(mockShoppingListRepository)
+		lists.On("GetByID", mock.Anything, "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+
+		srv := NewProductService(new(mockProductRepo), lists, newTestCategoryService(nil), zap.NewNop())
+		_, err := srv.AddToList(context.Background(), "user-1", "list-1", &domain.ScanBarcodeRequest{Barcode: "3017620422004"})
+
+		require.True(t, apperrors.IsInvalidInput(err))
+	})
+
+	t.Run("rejects another user's list", func(t *testing.T) {
+		lists := new(mockShoppingListRepository)
+		lists.On("GetByID", mock.Anything, "list-2").Return(&domain.ShoppingList{ID: "list-2", UserID: "someone-else"}, nil).Once()
+
+		srv := NewProductService(new(mockProductRepo), lists, newTestCategoryService(nil), zap.NewNop())
+		_, err := srv.AddToList(context.Background(), "user-1", "list-2", &domain.ScanBarcodeRequest{Barcode: "3017620422003"})
+
+		require.ErrorIs(t, err, apperrors.ErrUnauthorized)
+	})
+}
+
+func TestProductService_Save_CategorizesByName(t *testing.T) {
+	products := new(mockProductRepo)
+	products.On("Save", mock.Anything, mock.MatchedBy(func(p *domain.UserProduct) bool {
+		return p.UserID == "user-1" && p.Barcode == "0036000291452" && p.Name == "Red onions" && p.Category == "PRODUCE_VEGETABLES"
+	})).Return(nil).Once()
+
+	srv := NewProductService(products, new(mockShoppingListRepository), newTestCategoryService(nil), zap.NewNop())
+	_, err := srv.Save(context.Background(), "user-1", &domain.SaveProductRequest{Barcode: "036000291452", Name: " Red onions "})
+
+	require.NoError(t, err)
+	products.AssertExpectations(t)
+}
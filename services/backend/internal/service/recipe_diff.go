@@ -0,0 +1,94 @@
+package service
+
+import (
+	"reflect"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+)
+
+// comparableIngredient and comparableInstruction drop row identity so lists
+// are compared by content only.
+type comparableIngredient struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	Unit        string  `json:"unit"`
+	Notes       string  `json:"notes"`
+}
+
+type comparableInstruction struct {
+	StepNumber  int    `json:"step_number"`
+	Instruction string `json:"instruction"`
+}
+
+func comparableIngredients(ingredients []domain.RecipeIngredient) []comparableIngredient {
+	out := make([]comparableIngredient, len(ingredients))
+	for i, ing := range ingredients {
+		out[i] = comparableIngredient{
+			Name:        ing.Name,
+			Description: ing.Description,
+			Amount:      ing.Amount,
+			Unit:        ing.Unit,
+			Notes:       ing.Notes,
+		}
+	}
+	return out
+}
+
+func comparableInstructions(instructions []domain.RecipeInstruction) []comparableInstruction {
+	out := make([]comparableInstruction, len(instructions))
+	for i, inst := range instructions {
+		out[i] = comparableInstruction{
+			StepNumber:  inst.StepNumber,
+			Instruction: inst.Instruction,
+		}
+	}
+	return out
+}
+
+// diffRecipe lists the fields a revision would change, in a fixed order.
+func diffRecipe(current *domain.Recipe, revision *domain.RecipeRevision) []domain.RecipeFieldChange {
+	changes := []domain.RecipeFieldChange{}
+	add := func(field string, before, after any) {
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, domain.RecipeFieldChange{Field: field, Before: before, After: after})
+		}
+	}
+
+	add("title", current.Title, revision.Title)
+	add("description", current.Description, revision.Description)
+	add("notes", current.Notes, revision.Notes)
+	add("servings", current.Servings, revision.Servings)
+	add("prep_time", current.PrepTime, revision.PrepTime)
+	add("cook_time", current.CookTime, revision.CookTime)
+	add("ingredients", comparableIngredients(current.Ingredients), comparableIngredients(revision.Ingredients))
+	add("instructions", comparableInstructions(current.Instructions), comparableInstructions(revision.Instructions))
+
+	return changes
+}
+
+// recipeAuditChanges diffs an update request against the stored recipe for
+// the audit trail: the revision fields plus visibility and status.
+func recipeAuditChanges(current *domain.Recipe, req *domain.CreateRecipeRequest) domain.AuditChanges {
+	changes := domain.AuditChanges{}
+	fields := diffRecipe(current, &domain.RecipeRevision{
+		Title:        req.Title,
+		Description:  req.Description,
+		Notes:        req.Notes,
+		Servings:     req.Servings,
+		PrepTime:     req.PrepTime,
+		CookTime:     req.CookTime,
+		Ingredients:  req.Ingredients,
+		Instructions: req.Instructions,
+	})
+	for _, field := range fields {
+		changes[field.Field] = domain.AuditChange{From: field.Before, To: field.After}
+	}
+	if current.IsPrivate != req.IsPrivate {
+		changes["is_private"] = domain.AuditChange{From: current.IsPrivate, To: req.IsPrivate}
+	}
+	if current.Status != req.Status {
+		changes["status"] = domain.AuditChange{From: current.Status, To: req.Status}
+	}
+	return changes
+}
@@ -2,7 +2,8 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"strings"
+
 	"github.com/H3nSte1n/recipe/internal/domain"
 	"github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/repository"
@@ -11,6 +12,7 @@ import (
 	"github.com/H3nSte1n/recipe/pkg/storage"
 	"github.com/H3nSte1n/recipe/pkg/urlparser"
 	"go.uber.org/zap"
+	"golang.org/x/text/language"
 )
 
 type recipeRepository interface {
@@ -27,6 +29,18 @@ type recipeUserRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 }
 
+type recipeTranslationRepository interface {
+	Upsert(ctx context.Context, translation *domain.RecipeTranslation) error
+	ListByRecipeID(ctx context.Context, recipeID string, languages []string) ([]domain.RecipeTranslation, error)
+}
+
+type recipeEditProposalRepository interface {
+	Create(ctx context.Context, proposal *domain.RecipeEditProposal) error
+	GetByID(ctx context.Context, id string) (*domain.RecipeEditProposal, error)
+	Resolve(ctx context.Context, id string, status string) (bool, error)
+	Reopen(ctx context.Context, id string) error
+}
+
 type recipeAIConfigRepository interface {
 	GetDefaultConfig(ctx context.Context, userID string) (*domain.UserAIConfig, error)
 }
@@ -41,44 +55,61 @@ type RecipeService interface {
 	ImportFromURL(ctx context.Context, userID string, req *domain.ImportURLRequest) (*domain.Recipe, error)
 	ImportFromPDF(ctx context.Context, userID string, req *domain.ImportPDFRequest, file []byte) (*domain.Recipe, error)
 	ParsePlainTextInstructions(ctx context.Context, userID string, req *domain.ParsePlainTextInstructionsRequest) (*[]domain.RecipeInstruction, error)
+	Generate(ctx context.Context, userID string, req *domain.GenerateRecipeRequest) (*domain.Recipe, error)
+	SuggestSubstitutes(ctx context.Context, userID string, recipeID string, ingredientID string) ([]domain.IngredientSubstitute, error)
+	ApplySubstitute(ctx context.Context, userID string, recipeID string, ingredientID string, req *domain.ApplySubstituteRequest) (*domain.Recipe, error)
+	Translate(ctx context.Context, userID string, recipeID string, language string) (*domain.RecipeTranslation, error)
+	GetTranslated(ctx context.Context, userID string, recipeID string, nutritionLevel domain.NutritionDetailLevel, languages []string) (*domain.Recipe, string, error)
+	ProposeEdit(ctx context.Context, userID string, recipeID string, req *domain.AIEditRecipeRequest) (*domain.RecipeEditProposal, error)
+	AcceptEdit(ctx context.Context, userID string, recipeID string, proposalID string) (*domain.Recipe, error)
+	RejectEdit(ctx context.Context, userID string, recipeID string, proposalID string) error
 }
 
 type recipeService struct {
-	recipeRepo   recipeRepository
-	userRepo     recipeUserRepository
-	aiConfigRepo recipeAIConfigRepository
-	fileStorage  storage.FileStore
-	logger       *zap.Logger
-	modelFactory *ai.ModelFactory
-	urlParser    urlparser.Service
-	pdfParser    pdfparser.Service
-	cipher       APIKeyCipher
-	imageSigner  ImageURLSigner
+	recipeRepo      recipeRepository
+	translationRepo recipeTranslationRepository
+	proposalRepo    recipeEditProposalRepository
+	userRepo        recipeUserRepository
+	aiConfigRepo    recipeAIConfigRepository
+	fileStorage     storage.FileStore
+	logger          *zap.Logger
+	modelFactory    aiModelFactory
+	urlParser       urlparser.Service
+	pdfParser       pdfparser.Service
+	cipher          APIKeyCipher
+	imageSigner     ImageURLSigner
+	auditLog        *AuditLog
 }
 
 func NewRecipeService(
 	recipeRepo recipeRepository,
+	translationRepo recipeTranslationRepository,
+	proposalRepo recipeEditProposalRepository,
 	userRepo recipeUserRepository,
 	aiConfigRepo recipeAIConfigRepository,
 	fileStorage storage.FileStore,
 	logger *zap.Logger,
-	modelFactory *ai.ModelFactory,
+	modelFactory aiModelFactory,
 	urlParser urlparser.Service,
 	pdfParser pdfparser.Service,
 	cipher APIKeyCipher,
 	imageSigner ImageURLSigner,
+	auditLog *AuditLog,
 ) RecipeService {
 	return &recipeService{
-		recipeRepo:   recipeRepo,
-		userRepo:     userRepo,
-		aiConfigRepo: aiConfigRepo,
-		fileStorage:  fileStorage,
-		logger:       logger,
-		modelFactory: modelFactory,
-		urlParser:    urlParser,
-		pdfParser:    pdfParser,
-		cipher:       cipher,
-		imageSigner:  imageSigner,
+		recipeRepo:      recipeRepo,
+		translationRepo: translationRepo,
+		proposalRepo:    proposalRepo,
+		userRepo:        userRepo,
+		aiConfigRepo:    aiConfigRepo,
+		fileStorage:     fileStorage,
+		logger:          logger,
+		modelFactory:    modelFactory,
+		urlParser:       urlParser,
+		pdfParser:       pdfParser,
+		cipher:          cipher,
+		imageSigner:     imageSigner,
+		auditLog:        auditLog,
 	}
 }
 
@@ -176,6 +207,14 @@ func (s *recipeService) Create(ctx context.Context, userID string, req *domain.C
 		return nil, err
 	}
 
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditRecipeCreated,
+		TargetType: domain.AuditTargetRecipe,
+		TargetID:   recipe.ID,
+		Changes:    domain.AuditChanges{"title": {To: recipe.Title}},
+	})
+
 	created, err := s.recipeRepo.GetByID(ctx, recipe.ID, domain.NutritionDetailBase)
 	if err != nil {
 		return nil, err
@@ -238,6 +277,13 @@ func (s *recipeService) Update(ctx context.Context, userID string, recipeID stri
 		imageURL = newImageURL
 	}
 
+	// Editing a generated recipe keeps it labelled as AI-made; clients can't
+	// set that source type themselves.
+	sourceType := req.SourceType
+	if existingRecipe.SourceType == domain.SourceTypeAI {
+		sourceType = domain.SourceTypeAI
+	}
+
 	err = s.recipeRepo.WithTypedTransaction(ctx, func(txRepo repository.RecipeRepository) error {
 		recipe := &domain.Recipe{
 			ID:           recipeID,
@@ -248,7 +294,7 @@ func (s *recipeService) Update(ctx context.Context, userID string, recipeID stri
 			Rating:       req.Rating,
 			ImageURL:     imageURL,
 			Status:       req.Status,
-			SourceType:   req.SourceType,
+			SourceType:   sourceType,
 			Source:       req.SourceURL,
 			IsPrivate:    req.IsPrivate,
 			Servings:     req.Servings,
@@ -295,6 +341,13 @@ func (s *recipeService) Update(ctx context.Context, userID string, recipeID stri
 			zap.Error(err))
 		return nil, err
 	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditRecipeUpdated,
+		TargetType: domain.AuditTargetRecipe,
+		TargetID:   recipeID,
+		Changes:    recipeAuditChanges(existingRecipe, req),
+	})
 
 	updated, err := s.recipeRepo.GetByID(ctx, recipeID, domain.NutritionDetailBase)
 	if err != nil {
@@ -316,24 +369,35 @@ func (s *recipeService) Delete(ctx context.Context, userID string, recipeID stri
 		return errors.ErrUnauthorized
 	}
 
-	return s.recipeRepo.WithTypedTransaction(ctx, func(txRepo repository.RecipeRepository) error {
-		if err := txRepo.Delete(ctx, recipeID); err != nil {
-			return err
-		}
-
-		if recipe.ImageURL != "" {
-			if err := s.fileStorage.DeleteFile(ctx, recipe.ImageURL); err != nil {
-				s.logger.Warn("failed to delete recipe image",
-					zap.Error(err),
-					zap.String("imageURL", recipe.ImageURL))
-			}
-		}
+	// The recipe goes to the trash; its image is only deleted once the trash
+	// is purged, so a restored recipe keeps it.
+	if err := s.recipeRepo.Delete(ctx, recipeID); err != nil {
+		return err
+	}
 
-		return nil
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditRecipeDeleted,
+		TargetType: domain.AuditTargetRecipe,
+		TargetID:   recipeID,
+		Changes:    domain.AuditChanges{"title": {From: recipe.Title}},
 	})
+	return nil
 }
 
 func (s *recipeService) GetByID(ctx context.Context, userID string, recipeID string, nutritionLevel domain.NutritionDetailLevel) (*domain.Recipe, error) {
+	recipe, err := s.getReadableRecipe(ctx, userID, recipeID, nutritionLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	s.signRecipeImages(recipe)
+	return recipe, nil
+}
+
+// getReadableRecipe loads a recipe the user is allowed to read: their own, or
+// anyone's public recipe.
+func (s *recipeService) getReadableRecipe(ctx context.Context, userID string, recipeID string, nutritionLevel domain.NutritionDetailLevel) (*domain.Recipe, error) {
 	recipe, err := s.recipeRepo.GetByID(ctx, recipeID, nutritionLevel)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -350,7 +414,20 @@ func (s *recipeService) GetByID(ctx context.Context, userID string, recipeID str
 		return nil, errors.ErrNotFound
 	}
 
-	s.signRecipeImages(recipe)
+	return recipe, nil
+}
+
+// getOwnedRecipe loads a recipe the user may modify. Someone else's private
+// recipe reports ErrNotFound, as in getReadableRecipe; someone else's public
+// recipe reports ErrUnauthorized.
+func (s *recipeService) getOwnedRecipe(ctx context.Context, userID string, recipeID string, nutritionLevel domain.NutritionDetailLevel) (*domain.Recipe, error) {
+	recipe, err := s.getReadableRecipe(ctx, userID, recipeID, nutritionLevel)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
 	return recipe, nil
 }
 
@@ -435,6 +512,585 @@ func (s *recipeService) ParsePlainTextInstructions(ctx context.Context, userID s
 	return aiModel.ParseInstructions(ctx, req.PlainText)
 }
 
+// Generate asks the user's default model for a new recipe and saves it as a
+// draft with source type AI. The model output goes through the same clamping
+// as any other parsed recipe before it is stored.
+func (s *recipeService) Generate(ctx context.Context, userID string, req *domain.GenerateRecipeRequest) (*domain.Recipe, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	userPrefs, err := s.getUserAIPreferences(ctx, userID)
+	if err != nil {
+		userPrefs = &ai.UserAIPreferences{ModelType: ai.ModelDefault}
+	}
+
+	aiModel, err := s.modelFactory.CreateModel(userPrefs.ModelType, userPrefs.APIKey)
+	if err != nil {
+		s.logger.Error("failed to create AI model", zap.Error(err))
+		return nil, err
+	}
+
+	generated, err := aiModel.GenerateRecipe(ctx, ai.RecipeGenerationRequest{
+		Ingredients:        req.Ingredients,
+		DietaryConstraints: req.DietaryConstraints,
+		MaxTotalTime:       req.MaxTime,
+		Servings:           req.Servings,
+		Cuisine:            req.Cuisine,
+	})
+	if err != nil {
+		s.logger.Error("failed to generate recipe",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return nil, errors.ErrInternal.Wrap("failed to generate recipe")
+	}
+	if generated.Title == "" || len(generated.Ingredients) == 0 || len(generated.Instructions) == 0 {
+		return nil, errors.ErrInternal.Wrap("generated recipe is incomplete")
+	}
+
+	recipe := &domain.Recipe{
+		UserID:       userID,
+		Title:        generated.Title,
+		Description:  generated.Description,
+		Notes:        generated.Notes,
+		Status:       "draft",
+		SourceType:   domain.SourceTypeAI,
+		Source:       string(userPrefs.ModelType),
+		IsPrivate:    req.IsPrivate,
+		Servings:     generated.Servings,
+		PrepTime:     generated.PrepTime,
+		CookTime:     generated.CookTime,
+		Ingredients:  generated.Ingredients,
+		Instructions: generated.Instructions,
+		Nutrition:    generated.Nutrition,
+	}
+	if recipe.Servings <= 0 {
+		recipe.Servings = req.Servings
+	}
+
+	if err := s.recipeRepo.WithTypedTransaction(ctx, func(txRepo repository.RecipeRepository) error {
+		return txRepo.Create(ctx, recipe)
+	}); err != nil {
+		s.logger.Error("failed to save generated recipe",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditRecipeCreated,
+		TargetType: domain.AuditTargetRecipe,
+		TargetID:   recipe.ID,
+		Changes:    domain.AuditChanges{"title": {To: recipe.Title}},
+	})
+
+	created, err := s.recipeRepo.GetByID(ctx, recipe.ID, domain.NutritionDetailBase)
+	if err != nil {
+		return nil, err
+	}
+	s.signRecipeImages(created)
+	return created, nil
+}
+
+// SuggestSubstitutes returns ranked replacements for one ingredient of a recipe
+// the user can read. The curated table answers common cases without an AI call.
+func (s *recipeService) SuggestSubstitutes(ctx context.Context, userID string, recipeID string, ingredientID string) ([]domain.IngredientSubstitute, error) {
+	recipe, err := s.getReadableRecipe(ctx, userID, recipeID, domain.NutritionDetailBase)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredient := findIngredient(recipe, ingredientID)
+	if ingredient == nil {
+		return nil, errors.ErrNotFound.Wrap("ingredient not found")
+	}
+
+	if curated := curatedSubstitutesFor(*ingredient); len(curated) > 0 {
+		return curated, nil
+	}
+
+	userPrefs, err := s.getUserAIPreferences(ctx, userID)
+	if err != nil {
+		userPrefs = &ai.UserAIPreferences{ModelType: ai.ModelDefault}
+	}
+
+	aiModel, err := s.modelFactory.CreateModel(userPrefs.ModelType, userPrefs.APIKey)
+	if err != nil {
+		s.logger.Error("failed to create AI model", zap.Error(err))
+		return nil, err
+	}
+
+	others := make([]string, 0, len(recipe.Ingredients))
+	for _, ing := range recipe.Ingredients {
+		if ing.ID != ingredientID {
+			others = append(others, ing.Name)
+		}
+	}
+
+	substitutes, err := aiModel.SuggestSubstitutions(ctx, ai.SubstitutionRequest{
+		RecipeTitle:      recipe.Title,
+		Ingredient:       ingredient.Name,
+		Amount:           ingredient.Amount,
+		Unit:             ingredient.Unit,
+		OtherIngredients: others,
+	})
+	if err != nil {
+		s.logger.Error("failed to suggest substitutes",
+			zap.String("recipeID", recipeID),
+			zap.String("ingredientID", ingredientID),
+			zap.Error(err))
+		return nil, errors.ErrInternal.Wrap("failed to suggest substitutes")
+	}
+
+	return substitutes, nil
+}
+
+// ApplySubstitute saves a private copy of the recipe for the user with one
+// ingredient replaced. The original recipe is never modified, so this works on
+// other users' public recipes too. The copy does not share the original's
+// image (deleting either would remove the file) or nutrition (no longer
+// accurate once an ingredient changes).
+func (s *recipeService) ApplySubstitute(ctx context.Context, userID string, recipeID string, ingredientID string, req *domain.ApplySubstituteRequest) (*domain.Recipe, error) {
+	original, err := s.getReadableRecipe(ctx, userID, recipeID, domain.NutritionDetailBase)
+	if err != nil {
+		return nil, err
+	}
+
+	if findIngredient(original, ingredientID) == nil {
+		return nil, errors.ErrNotFound.Wrap("ingredient not found")
+	}
+
+	variant := &domain.Recipe{
+		UserID:      userID,
+		Title:       original.Title,
+		Description: original.Description,
+		Notes:       original.Notes,
+		Status:      original.Status,
+		SourceType:  original.SourceType,
+		Source:      original.Source,
+		IsPrivate:   true,
+		Servings:    original.Servings,
+		PrepTime:    original.PrepTime,
+		CookTime:    original.CookTime,
+		ShelfLife:   original.ShelfLife,
+		VariantOfID: &original.ID,
+	}
+
+	variant.Ingredients = make([]domain.RecipeIngredient, len(original.Ingredients))
+	for i, ing := range original.Ingredients {
+		if ing.ID == ingredientID {
+			ing = domain.RecipeIngredient{
+				Name:        req.Name,
+				Description: req.Name,
+				Amount:      req.Amount,
+				Unit:        req.Unit,
+				Notes:       req.Notes,
+			}
+		}
+		ing.ID = ""
+		ing.RecipeID = ""
+		variant.Ingredients[i] = ing
+	}
+
+	variant.Instructions = make([]domain.RecipeInstruction, len(original.Instructions))
+	for i, inst := range original.Instructions {
+		variant.Instructions[i] = domain.RecipeInstruction{
+			StepNumber:  inst.StepNumber,
+			Instruction: inst.Instruction,
+		}
+	}
+
+	if err := s.recipeRepo.WithTypedTransaction(ctx, func(txRepo repository.RecipeRepository) error {
+		if err := txRepo.Create(ctx, variant); err != nil {
+			return err
+		}
+
+		for _, sr := range original.SubRecipes {
+			// Same rule as Create: never link a sub-recipe the user cannot read.
+			if sr.Child != nil && sr.Child.IsPrivate && sr.Child.UserID != userID {
+				continue
+			}
+			variant.SubRecipes = append(variant.SubRecipes, domain.SubRecipe{
+				ParentID:      variant.ID,
+				ChildID:       sr.ChildID,
+				ServingFactor: sr.ServingFactor,
+			})
+		}
+		if len(variant.SubRecipes) > 0 {
+			if err := txRepo.Update(ctx, variant); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		s.logger.Error("failed to create recipe variant",
+			zap.String("user_id", userID),
+			zap.String("recipe_id", recipeID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	created, err := s.recipeRepo.GetByID(ctx, variant.ID, domain.NutritionDetailBase)
+	if err != nil {
+		return nil, err
+	}
+	s.signRecipeImages(created)
+	return created, nil
+}
+
+// Translate has the user's AI model translate a recipe they own and stores the
+// result alongside it, replacing any earlier translation into that language.
+func (s *recipeService) Translate(ctx context.Context, userID string, recipeID string, language string) (*domain.RecipeTranslation, error) {
+	lang, ok := normalizeLanguage(language)
+	if !ok {
+		return nil, errors.New("unsupported language", "INVALID_INPUT")
+	}
+
+	recipe, err := s.getOwnedRecipe(ctx, userID, recipeID, domain.NutritionDetailBase)
+	if err != nil {
+		return nil, err
+	}
+
+	userPrefs, err := s.getUserAIPreferences(ctx, userID)
+	if err != nil {
+		userPrefs = &ai.UserAIPreferences{ModelType: ai.ModelDefault}
+	}
+
+	aiModel, err := s.modelFactory.CreateModel(userPrefs.ModelType, userPrefs.APIKey)
+	if err != nil {
+		s.logger.Error("failed to create AI model", zap.Error(err))
+		return nil, err
+	}
+
+	content := ai.RecipeTranslationContent{
+		Title:        recipe.Title,
+		Description:  recipe.Description,
+		Notes:        recipe.Notes,
+		Ingredients:  make([]ai.RecipeTranslationIngredient, len(recipe.Ingredients)),
+		Instructions: make([]ai.RecipeTranslationInstruction, len(recipe.Instructions)),
+	}
+	for i, ing := range recipe.Ingredients {
+		content.Ingredients[i] = ai.RecipeTranslationIngredient{
+			ID:          ing.ID,
+			Name:        ing.Name,
+			Description: ing.Description,
+			Unit:        ing.Unit,
+			Notes:       ing.Notes,
+		}
+	}
+	for i, inst := range recipe.Instructions {
+		content.Instructions[i] = ai.RecipeTranslationInstruction{
+			ID:          inst.ID,
+			Instruction: inst.Instruction,
+		}
+	}
+
+	translated, err := aiModel.TranslateRecipe(ctx, content, lang)
+	if err != nil {
+		s.logger.Error("failed to translate recipe",
+			zap.String("recipeID", recipeID),
+			zap.String("language", lang),
+			zap.Error(err))
+		return nil, errors.ErrInternal.Wrap("failed to translate recipe")
+	}
+
+	translation := &domain.RecipeTranslation{
+		RecipeID:     recipeID,
+		Language:     lang,
+		Title:        translated.Title,
+		Description:  translated.Description,
+		Notes:        translated.Notes,
+		Ingredients:  make([]domain.TranslatedIngredient, len(translated.Ingredients)),
+		Instructions: make([]domain.TranslatedInstruction, len(translated.Instructions)),
+	}
+	for i, ing := range translated.Ingredients {
+		translation.Ingredients[i] = domain.TranslatedIngredient{
+			IngredientID: ing.ID,
+			Name:         ing.Name,
+			Description:  ing.Description,
+			Unit:         ing.Unit,
+			Notes:        ing.Notes,
+		}
+	}
+	for i, inst := range translated.Instructions {
+		translation.Instructions[i] = domain.TranslatedInstruction{
+			InstructionID: inst.ID,
+			Instruction:   inst.Instruction,
+		}
+	}
+
+	if err := s.translationRepo.Upsert(ctx, translation); err != nil {
+		s.logger.Error("failed to save recipe translation",
+			zap.String("recipeID", recipeID),
+			zap.String("language", lang),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+// GetTranslated is GetByID with the recipe's text replaced by the first stored
+// translation matching languages, which are in order of preference. It returns
+// the language applied, or "" when the recipe is returned as stored.
+func (s *recipeService) GetTranslated(ctx context.Context, userID string, recipeID string, nutritionLevel domain.NutritionDetailLevel, languages []string) (*domain.Recipe, string, error) {
+	recipe, err := s.getReadableRecipe(ctx, userID, recipeID, nutritionLevel)
+	if err != nil {
+		return nil, "", err
+	}
+
+	wanted := make([]string, 0, len(languages))
+	for _, l := range languages {
+		if lang, ok := normalizeLanguage(l); ok {
+			wanted = append(wanted, lang)
+		}
+	}
+
+	var applied string
+	if len(wanted) > 0 {
+		translations, err := s.translationRepo.ListByRecipeID(ctx, recipeID, wanted)
+		if err != nil {
+			return nil, "", err
+		}
+		byLanguage := make(map[string]*domain.RecipeTranslation, len(translations))
+		for i := range translations {
+			byLanguage[translations[i].Language] = &translations[i]
+		}
+		for _, lang := range wanted {
+			if t, ok := byLanguage[lang]; ok {
+				applyTranslation(recipe, t)
+				applied = lang
+				break
+			}
+		}
+	}
+
+	s.signRecipeImages(recipe)
+	return recipe, applied, nil
+}
+
+// applyTranslation overlays a translation's text onto the recipe. Rows the
+// translation does not cover keep their original text; amounts are untouched.
+func applyTranslation(recipe *domain.Recipe, t *domain.RecipeTranslation) {
+	recipe.Title = t.Title
+	recipe.Description = t.Description
+	recipe.Notes = t.Notes
+
+	ingredients := make(map[string]domain.TranslatedIngredient, len(t.Ingredients))
+	for _, ing := range t.Ingredients {
+		ingredients[ing.IngredientID] = ing
+	}
+	for i := range recipe.Ingredients {
+		if ing, ok := ingredients[recipe.Ingredients[i].ID]; ok {
+			recipe.Ingredients[i].Name = ing.Name
+			recipe.Ingredients[i].Description = ing.Description
+			recipe.Ingredients[i].Unit = ing.Unit
+			recipe.Ingredients[i].Notes = ing.Notes
+		}
+	}
+
+	instructions := make(map[string]string, len(t.Instructions))
+	for _, inst := range t.Instructions {
+		instructions[inst.InstructionID] = inst.Instruction
+	}
+	for i := range recipe.Instructions {
+		if text, ok := instructions[recipe.Instructions[i].ID]; ok {
+			recipe.Instructions[i].Instruction = text
+		}
+	}
+}
+
+// normalizeLanguage reduces a BCP 47 tag such as "de-AT" to its ISO 639 base
+// language ("de"), which is how translations are stored.
+func normalizeLanguage(tag string) (string, bool) {
+	parsed, err := language.Parse(strings.TrimSpace(tag))
+	if err != nil || parsed == language.Und {
+		return "", false
+	}
+	base, confidence := parsed.Base()
+	if confidence == language.No {
+		return "", false
+	}
+	return base.String(), true
+}
+
+// ProposeEdit asks the user's AI model to revise a recipe they own according
+// to a natural-language instruction. Nothing is changed yet: the revision and
+// its field-level diff are stored as a pending proposal for the owner to
+// accept or reject.
+func (s *recipeService) ProposeEdit(ctx context.Context, userID string, recipeID string, req *domain.AIEditRecipeRequest) (*domain.RecipeEditProposal, error) {
+	recipe, err := s.getOwnedRecipe(ctx, userID, recipeID, domain.NutritionDetailMacro)
+	if err != nil {
+		return nil, err
+	}
+
+	userPrefs, err := s.getUserAIPreferences(ctx, userID)
+	if err != nil {
+		userPrefs = &ai.UserAIPreferences{ModelType: ai.ModelDefault}
+	}
+
+	aiModel, err := s.modelFactory.CreateModel(userPrefs.ModelType, userPrefs.APIKey)
+	if err != nil {
+		s.logger.Error("failed to create AI model", zap.Error(err))
+		return nil, err
+	}
+
+	edited, err := aiModel.EditRecipe(ctx, recipe, req.Instruction)
+	if err != nil {
+		s.logger.Error("failed to edit recipe",
+			zap.String("recipeID", recipeID),
+			zap.Error(err))
+		return nil, errors.ErrInternal.Wrap("failed to edit recipe")
+	}
+
+	revision := domain.RecipeRevision{
+		Title:        edited.Title,
+		Description:  edited.Description,
+		Notes:        edited.Notes,
+		Servings:     edited.Servings,
+		PrepTime:     edited.PrepTime,
+		CookTime:     edited.CookTime,
+		Ingredients:  edited.Ingredients,
+		Instructions: edited.Instructions,
+		Nutrition:    edited.Nutrition,
+	}
+	if revision.Servings <= 0 {
+		revision.Servings = recipe.Servings
+	}
+
+	proposal := &domain.RecipeEditProposal{
+		RecipeID:      recipeID,
+		UserID:        userID,
+		Instruction:   req.Instruction,
+		Revision:      revision,
+		Changes:       diffRecipe(recipe, &revision),
+		Status:        domain.EditProposalPending,
+		BaseUpdatedAt: recipe.UpdatedAt,
+	}
+	if err := s.proposalRepo.Create(ctx, proposal); err != nil {
+		s.logger.Error("failed to save edit proposal",
+			zap.String("recipeID", recipeID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return proposal, nil
+}
+
+// AcceptEdit applies a pending proposal through Update. It fails with
+// ErrConflict if the proposal was already resolved or the recipe changed after
+// the proposal was made.
+func (s *recipeService) AcceptEdit(ctx context.Context, userID string, recipeID string, proposalID string) (*domain.Recipe, error) {
+	proposal, err := s.getPendingProposal(ctx, userID, recipeID, proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Micro level so Update writes back the full nutrition row when the
+	// revision does not carry its own.
+	recipe, err := s.getOwnedRecipe(ctx, userID, recipeID, domain.NutritionDetailMicro)
+	if err != nil {
+		return nil, err
+	}
+	if !recipe.UpdatedAt.Equal(proposal.BaseUpdatedAt) {
+		return nil, errors.ErrConflict.Wrap("recipe changed since the proposal was made")
+	}
+
+	if ok, err := s.proposalRepo.Resolve(ctx, proposalID, domain.EditProposalAccepted); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.ErrConflict.Wrap("proposal already resolved")
+	}
+
+	revision := proposal.Revision
+	nutrition := revision.Nutrition
+	if nutrition == nil && recipe.Nutrition != nil {
+		nutrition = recipe.Nutrition
+		nutrition.ID = ""
+	}
+	subRecipes := make([]domain.SubRecipeRequest, len(recipe.SubRecipes))
+	for i, sr := range recipe.SubRecipes {
+		subRecipes[i] = domain.SubRecipeRequest{RecipeID: sr.ChildID, ServingFactor: sr.ServingFactor}
+	}
+
+	updated, err := s.Update(ctx, userID, recipeID, &domain.CreateRecipeRequest{
+		Title:        revision.Title,
+		Description:  revision.Description,
+		SourceType:   recipe.SourceType,
+		SourceURL:    recipe.Source,
+		IsPrivate:    recipe.IsPrivate,
+		Servings:     revision.Servings,
+		PrepTime:     revision.PrepTime,
+		CookTime:     revision.CookTime,
+		ShelfLife:    recipe.ShelfLife,
+		Ingredients:  revision.Ingredients,
+		Instructions: revision.Instructions,
+		Notes:        revision.Notes,
+		Rating:       recipe.Rating,
+		Status:       recipe.Status,
+		Nutrition:    nutrition,
+		SubRecipes:   subRecipes,
+	})
+	if err != nil {
+		if reopenErr := s.proposalRepo.Reopen(ctx, proposalID); reopenErr != nil {
+			s.logger.Error("failed to reopen edit proposal",
+				zap.String("proposalID", proposalID),
+				zap.Error(reopenErr))
+		}
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *recipeService) RejectEdit(ctx context.Context, userID string, recipeID string, proposalID string) error {
+	if _, err := s.getPendingProposal(ctx, userID, recipeID, proposalID); err != nil {
+		return err
+	}
+
+	ok, err := s.proposalRepo.Resolve(ctx, proposalID, domain.EditProposalRejected)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.ErrConflict.Wrap("proposal already resolved")
+	}
+	return nil
+}
+
+// getPendingProposal loads a proposal made by userID for recipeID. Anyone
+// else's proposal reports ErrNotFound.
+func (s *recipeService) getPendingProposal(ctx context.Context, userID string, recipeID string, proposalID string) (*domain.RecipeEditProposal, error) {
+	proposal, err := s.proposalRepo.GetByID(ctx, proposalID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	if proposal.UserID != userID || proposal.RecipeID != recipeID {
+		return nil, errors.ErrNotFound
+	}
+	if proposal.Status != domain.EditProposalPending {
+		return nil, errors.ErrConflict.Wrap("proposal already resolved")
+	}
+	return proposal, nil
+}
+
+func findIngredient(recipe *domain.Recipe, ingredientID string) *domain.RecipeIngredient {
+	for i := range recipe.Ingredients {
+		if recipe.Ingredients[i].ID == ingredientID {
+			return &recipe.Ingredients[i]
+		}
+	}
+	return nil
+}
+
 func (s *recipeService) getUserAIPreferences(ctx context.Context, userID string) (*ai.UserAIPreferences, error) {
 	userAIConfig, err := s.aiConfigRepo.GetDefaultConfig(ctx, userID)
 	if err != nil {
@@ -456,7 +1112,7 @@ func (s *recipeService) getUserAIPreferences(ctx context.Context, userID string)
 		return nil, errors.New("AI model not found for config", "NOT_FOUND")
 	}
 
-	modelType, err := s.mapAIModelToModelType(aiModel)
+	modelType, err := mapAIModelToModelType(aiModel)
 	if err != nil {
 		s.logger.Error("unsupported AI model",
 			zap.String("provider", aiModel.Provider),
@@ -469,24 +1125,3 @@ func (s *recipeService) getUserAIPreferences(ctx context.Context, userID string)
 		APIKey:    decryptAPIKey(s.cipher, s.logger, userAIConfig.APIKey),
 	}, nil
 }
-
-func (s *recipeService) mapAIModelToModelType(model *domain.AIModel) (ai.ModelType, error) {
-	key := fmt.Sprintf("%s-%s", model.Provider, model.ModelVersion)
-
-	switch key {
-	case "openai-gpt-4":
-		return ai.ModelGPT4, nil
-	case "openai-gpt-4-turbo-preview":
-		return ai.ModelGPT4Turbo, nil
-	case "openai-gpt-3.5-turbo":
-		return ai.ModelGPT35, nil
-	case "anthropic-claude-sonnet-5":
-		return ai.ModelClaudeSonnet5, nil
-	case "anthropic-claude-opus-4-8":
-		return ai.ModelClaudeOpus48, nil
-	case "anthropic-claude-haiku-4-5":
-		return ai.ModelClaudeHaiku45, nil
-	default:
-		return "", errors.New(fmt.Sprintf("unsupported model: %s", key), "INVALID_INPUT")
-	}
-}
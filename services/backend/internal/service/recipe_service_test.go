@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"io"
 	"mime/multipart"
 	"testing"
+	"time"
 
 	"github.com/H3nSte1n/recipe/internal/domain"
 	apperrors "github.com/H3nSte1n/recipe/internal/errors"
@@ -12,6 +14,7 @@ import (
 	"github.com/H3nSte1n/recipe/pkg/ai"
 	"github.com/H3nSte1n/recipe/pkg/config"
 	"github.com/H3nSte1n/recipe/pkg/crypto"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -59,6 +62,34 @@ func (m *mockRecipeRepo) Exists(ctx context.Context, id string) (bool, error) {
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *mockRecipeRepo) ListTrashed(ctx context.Context, userID string) ([]domain.Recipe, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.Recipe)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeRepo) GetTrashedByID(ctx context.Context, id string) (*domain.Recipe, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.Recipe)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeRepo) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockRecipeRepo) ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]domain.Recipe, error) {
+	args := m.Called(ctx, cutoff)
+	v, _ := args.Get(0).([]domain.Recipe)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeRepo) Purge(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *mockRecipeRepo) WithTypedTransaction(ctx context.Context, fn func(repository.RecipeRepository) error) error {
 	args := m.Called(ctx, fn)
 	if args.Get(0) == nil {
@@ -67,6 +98,46 @@ func (m *mockRecipeRepo) WithTypedTransaction(ctx context.Context, fn func(repos
 	return args.Error(0)
 }
 
+type mockRecipeTranslationRepo struct {
+	mock.Mock
+}
+
+func (m *mockRecipeTranslationRepo) Upsert(ctx context.Context, translation *domain.RecipeTranslation) error {
+	args := m.Called(ctx, translation)
+	return args.Error(0)
+}
+
+func (m *mockRecipeTranslationRepo) ListByRecipeID(ctx context.Context, recipeID string, languages []string) ([]domain.RecipeTranslation, error) {
+	args := m.Called(ctx, recipeID, languages)
+	v, _ := args.Get(0).([]domain.RecipeTranslation)
+	return v, args.Error(1)
+}
+
+type mockRecipeEditProposalRepo struct {
+	mock.Mock
+}
+
+func (m *mockRecipeEditProposalRepo) Create(ctx context.Context, proposal *domain.RecipeEditProposal) error {
+	args := m.Called(ctx, proposal)
+	return args.Error(0)
+}
+
+func (m *mockRecipeEditProposalRepo) GetByID(ctx context.Context, id string) (*domain.RecipeEditProposal, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.RecipeEditProposal)
+	return v, args.Error(1)
+}
+
+func (m *mockRecipeEditProposalRepo) Resolve(ctx context.Context, id string, status string) (bool, error) {
+	args := m.Called(ctx, id, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockRecipeEditProposalRepo) Reopen(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 type mockRecipeUserRepo struct {
 	mock.Mock
 }
@@ -101,6 +172,12 @@ func (m *mockFileStore) DeleteFile(ctx context.Context, fileURL string) error {
 	return args.Error(0)
 }
 
+func (m *mockFileStore) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	args := m.Called(ctx, fileURL)
+	v, _ := args.Get(0).(io.ReadCloser)
+	return v, args.Error(1)
+}
+
 type mockURLParser struct {
 	mock.Mock
 }
@@ -121,6 +198,16 @@ func (m *mockPDFParser) Parse(ctx context.Context, pdfData []byte, aiModel ai.AI
 	return v, args.Error(1)
 }
 
+type mockModelFactory struct {
+	mock.Mock
+}
+
+func (m *mockModelFactory) CreateModel(modelType ai.ModelType, apiKey string) (ai.AIModel, error) {
+	args := m.Called(modelType, apiKey)
+	v, _ := args.Get(0).(ai.AIModel)
+	return v, args.Error(1)
+}
+
 func newTestRecipeService(
 	recipeRepo *mockRecipeRepo,
 	userRepo *mockRecipeUserRepo,
@@ -131,7 +218,7 @@ func newTestRecipeService(
 ) RecipeService {
 	modelFactory := ai.NewModelFactory(&config.Config{}, zap.NewNop())
 	cipher, _ := crypto.NewCipher("test-encryption-key")
-	return NewRecipeService(recipeRepo, userRepo, aiConfigRepo, fileStore, zap.NewNop(), modelFactory, urlParser, pdfParser, cipher, nil)
+	return NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), new(mockRecipeEditProposalRepo), userRepo, aiConfigRepo, fileStore, zap.NewNop(), modelFactory, urlParser, pdfParser, cipher, nil, nil)
 }
 
 func TestRecipeService_GetByID_Success(t *testing.T) {
@@ -259,6 +346,27 @@ func TestRecipeService_Update_Success(t *testing.T) {
 	recipeRepo.AssertExpectations(t)
 }
 
+func TestRecipeService_Update_KeepsAISourceType(t *testing.T) {
+	userID := "user-1"
+	recipeID := "recipe-1"
+	req := &domain.CreateRecipeRequest{Title: "Updated", SourceType: "MANUAL", Servings: 2}
+	existingRecipe := &domain.Recipe{ID: recipeID, UserID: userID, SourceType: domain.SourceTypeAI}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, recipeID, domain.NutritionDetailBase).Return(existingRecipe, nil).Once()
+	recipeRepo.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	recipeRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *domain.Recipe) bool {
+		return r.SourceType == domain.SourceTypeAI
+	})).Return(nil).Once()
+	recipeRepo.On("GetByID", mock.Anything, recipeID, domain.NutritionDetailBase).Return(existingRecipe, nil).Once()
+
+	srv := newTestRecipeService(recipeRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), new(mockURLParser), new(mockPDFParser))
+	_, err := srv.Update(context.Background(), userID, recipeID, req)
+
+	require.NoError(t, err)
+	recipeRepo.AssertExpectations(t)
+}
+
 func TestRecipeService_Update_NotFound(t *testing.T) {
 	recipeRepo := new(mockRecipeRepo)
 	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(nil, apperrors.ErrNotFound).Once()
@@ -291,7 +399,6 @@ func TestRecipeService_Delete_Success(t *testing.T) {
 
 	recipeRepo := new(mockRecipeRepo)
 	recipeRepo.On("GetByID", mock.Anything, recipeID, domain.NutritionDetailBase).Return(recipe, nil).Once()
-	recipeRepo.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
 	recipeRepo.On("Delete", mock.Anything, recipeID).Return(nil).Once()
 
 	srv := newTestRecipeService(recipeRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), new(mockURLParser), new(mockPDFParser))
@@ -589,24 +696,24 @@ func TestRecipeService_Update_SubRecipeUnauthorized(t *testing.T) {
 	recipeRepo.AssertExpectations(t)
 }
 
-func TestRecipeService_Delete_ImageDeletedOnSuccess(t *testing.T) {
+// A deleted recipe goes to the trash and can be restored, so its image must
+// stay until the trash is purged.
+func TestRecipeService_Delete_KeepsImageForRestore(t *testing.T) {
 	userID := "user-1"
 	recipeID := "recipe-1"
 	recipe := &domain.Recipe{ID: recipeID, UserID: userID, ImageURL: "https://storage/img.jpg"}
 
 	recipeRepo := new(mockRecipeRepo)
 	recipeRepo.On("GetByID", mock.Anything, recipeID, domain.NutritionDetailBase).Return(recipe, nil).Once()
-	recipeRepo.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
 	recipeRepo.On("Delete", mock.Anything, recipeID).Return(nil).Once()
 
 	fileStore := new(mockFileStore)
-	fileStore.On("DeleteFile", mock.Anything, "https://storage/img.jpg").Return(nil).Once()
 
 	srv := newTestRecipeService(recipeRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), fileStore, new(mockURLParser), new(mockPDFParser))
 	err := srv.Delete(context.Background(), userID, recipeID)
 
 	require.NoError(t, err)
-	fileStore.AssertExpectations(t)
+	fileStore.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
 	recipeRepo.AssertExpectations(t)
 }
 
@@ -726,3 +833,433 @@ func TestRecipeService_ImportFromPDF_ParseError(t *testing.T) {
 	require.Error(t, err)
 	pdfParser.AssertExpectations(t)
 }
+
+func TestRecipeService_Generate_Success(t *testing.T) {
+	userID := "user-1"
+	req := &domain.GenerateRecipeRequest{Ingredients: []string{"rice", "eggs"}, MaxTime: 20, Servings: 3, IsPrivate: true}
+	generated := &domain.Recipe{
+		Title:        "Egg fried rice",
+		Ingredients:  []domain.RecipeIngredient{{Name: "rice", Amount: 300, Unit: "g"}},
+		Instructions: []domain.RecipeInstruction{{StepNumber: 1, Instruction: "Fry it"}},
+	}
+	saved := &domain.Recipe{ID: "recipe-1", UserID: userID, Title: generated.Title, SourceType: domain.SourceTypeAI}
+
+	userRepo := new(mockRecipeUserRepo)
+	userRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID}, nil).Once()
+	aiConfigRepo := new(mockRecipeAIConfigRepo)
+	aiConfigRepo.On("GetDefaultConfig", mock.Anything, userID).Return(nil, nil).Once()
+
+	model := new(mockAIModel)
+	model.On("GenerateRecipe", mock.Anything, ai.RecipeGenerationRequest{
+		Ingredients:  req.Ingredients,
+		MaxTotalTime: 20,
+		Servings:     3,
+	}).Return(generated, nil).Once()
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", ai.ModelDefault, "").Return(model, nil).Once()
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	recipeRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.Recipe) bool {
+		// The model left servings at 0, so the requested count is used.
+		return r.UserID == userID && r.SourceType == domain.SourceTypeAI && r.Status == "draft" &&
+			r.IsPrivate && r.Servings == 3 && r.Title == "Egg fried rice"
+	})).Return(nil).Once()
+	recipeRepo.On("GetByID", mock.Anything, mock.AnythingOfType("string"), domain.NutritionDetailBase).Return(saved, nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), new(mockRecipeEditProposalRepo), userRepo, aiConfigRepo, new(mockFileStore), zap.NewNop(), factory, new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.Generate(context.Background(), userID, req)
+
+	require.NoError(t, err)
+	require.Equal(t, saved, result)
+	recipeRepo.AssertExpectations(t)
+	model.AssertExpectations(t)
+	factory.AssertExpectations(t)
+}
+
+func TestRecipeService_Generate_ModelError(t *testing.T) {
+	userID := "user-1"
+
+	userRepo := new(mockRecipeUserRepo)
+	userRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID}, nil).Once()
+	aiConfigRepo := new(mockRecipeAIConfigRepo)
+	aiConfigRepo.On("GetDefaultConfig", mock.Anything, userID).Return(nil, errors.New("no config")).Once()
+
+	model := new(mockAIModel)
+	model.On("GenerateRecipe", mock.Anything, mock.Anything).Return(nil, errors.New("rate limited")).Once()
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", ai.ModelDefault, "").Return(model, nil).Once()
+
+	recipeRepo := new(mockRecipeRepo)
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), new(mockRecipeEditProposalRepo), userRepo, aiConfigRepo, new(mockFileStore), zap.NewNop(), factory, new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.Generate(context.Background(), userID, &domain.GenerateRecipeRequest{Ingredients: []string{"rice"}, Servings: 1})
+
+	require.Nil(t, result)
+	require.ErrorIs(t, err, apperrors.ErrInternal)
+	recipeRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRecipeService_Generate_IncompleteRecipeNotSaved(t *testing.T) {
+	userID := "user-1"
+
+	userRepo := new(mockRecipeUserRepo)
+	userRepo.On("GetByID", mock.Anything, userID).Return(&domain.User{ID: userID}, nil).Once()
+	aiConfigRepo := new(mockRecipeAIConfigRepo)
+	aiConfigRepo.On("GetDefaultConfig", mock.Anything, userID).Return(nil, nil).Once()
+
+	model := new(mockAIModel)
+	model.On("GenerateRecipe", mock.Anything, mock.Anything).Return(&domain.Recipe{Title: "Nothing"}, nil).Once()
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", ai.ModelDefault, "").Return(model, nil).Once()
+
+	recipeRepo := new(mockRecipeRepo)
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), new(mockRecipeEditProposalRepo), userRepo, aiConfigRepo, new(mockFileStore), zap.NewNop(), factory, new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.Generate(context.Background(), userID, &domain.GenerateRecipeRequest{Ingredients: []string{"rice"}, Servings: 1})
+
+	require.Nil(t, result)
+	require.Error(t, err)
+	recipeRepo.AssertNotCalled(t, "WithTypedTransaction", mock.Anything, mock.Anything)
+}
+
+func TestRecipeService_SuggestSubstitutes_CuratedSkipsAI(t *testing.T) {
+	userID := "user-1"
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: userID, Ingredients: []domain.RecipeIngredient{
+		{ID: "ing-1", Name: "Buttermilk", Amount: 2, Unit: "cups"},
+	}}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	factory := new(mockModelFactory)
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), new(mockRecipeEditProposalRepo), new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), zap.NewNop(), factory, new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.SuggestSubstitutes(context.Background(), userID, "recipe-1", "ing-1")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+	require.Equal(t, "milk", result[0].Name)
+	require.Equal(t, 2.0, result[0].Amount)
+	require.Equal(t, "cups", result[0].Unit)
+	require.Equal(t, domain.SubstituteSourceCurated, result[0].Source)
+	factory.AssertNotCalled(t, "CreateModel", mock.Anything, mock.Anything)
+}
+
+func TestRecipeService_SuggestSubstitutes_FallsBackToAI(t *testing.T) {
+	userID := "user-1"
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: userID, Title: "Pancakes", Ingredients: []domain.RecipeIngredient{
+		{ID: "ing-1", Name: "tahini", Amount: 3, Unit: "tbsp"},
+		{ID: "ing-2", Name: "flour"},
+	}}
+	aiSubstitutes := []domain.IngredientSubstitute{{Name: "peanut butter", Amount: 3, Unit: "tbsp", Source: domain.SubstituteSourceAI}}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	aiConfigRepo := new(mockRecipeAIConfigRepo)
+	aiConfigRepo.On("GetDefaultConfig", mock.Anything, userID).Return(nil, nil).Once()
+
+	model := new(mockAIModel)
+	model.On("SuggestSubstitutions", mock.Anything, ai.SubstitutionRequest{
+		RecipeTitle:      "Pancakes",
+		Ingredient:       "tahini",
+		Amount:           3,
+		Unit:             "tbsp",
+		OtherIngredients: []string{"flour"},
+	}).Return(aiSubstitutes, nil).Once()
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", ai.ModelDefault, "").Return(model, nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), new(mockRecipeEditProposalRepo), new(mockRecipeUserRepo), aiConfigRepo, new(mockFileStore), zap.NewNop(), factory, new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.SuggestSubstitutes(context.Background(), userID, "recipe-1", "ing-1")
+
+	require.NoError(t, err)
+	require.Equal(t, aiSubstitutes, result)
+	model.AssertExpectations(t)
+}
+
+func TestRecipeService_SuggestSubstitutes_IngredientNotFound(t *testing.T) {
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: "user-1"}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+
+	srv := newTestRecipeService(recipeRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), new(mockURLParser), new(mockPDFParser))
+	result, err := srv.SuggestSubstitutes(context.Background(), "user-1", "recipe-1", "missing")
+
+	require.Nil(t, result)
+	require.True(t, apperrors.IsNotFound(err))
+}
+
+func TestRecipeService_SuggestSubstitutes_OtherUsersPrivateRecipe(t *testing.T) {
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: "owner", IsPrivate: true, Ingredients: []domain.RecipeIngredient{{ID: "ing-1", Name: "butter"}}}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+
+	srv := newTestRecipeService(recipeRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), new(mockURLParser), new(mockPDFParser))
+	result, err := srv.SuggestSubstitutes(context.Background(), "user-1", "recipe-1", "ing-1")
+
+	require.Nil(t, result)
+	require.ErrorIs(t, err, apperrors.ErrNotFound)
+}
+
+func TestRecipeService_ApplySubstitute_CreatesPrivateVariant(t *testing.T) {
+	userID := "user-1"
+	original := &domain.Recipe{
+		ID: "recipe-1", UserID: "owner", Title: "Pancakes", ImageURL: "uploads/pancakes.jpg", Servings: 4,
+		Ingredients: []domain.RecipeIngredient{
+			{ID: "ing-1", RecipeID: "recipe-1", Name: "buttermilk", Amount: 2, Unit: "cups"},
+			{ID: "ing-2", RecipeID: "recipe-1", Name: "flour", Amount: 300, Unit: "g"},
+		},
+		Instructions: []domain.RecipeInstruction{{ID: "step-1", RecipeID: "recipe-1", StepNumber: 1, Instruction: "Mix"}},
+		Nutrition:    &domain.RecipeNutrition{BaseNutrition: domain.BaseNutrition{Calories: 500}},
+	}
+	saved := &domain.Recipe{ID: "recipe-2", UserID: userID}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(original, nil).Once()
+	recipeRepo.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	recipeRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.Recipe) bool {
+		return r.UserID == userID && r.IsPrivate && r.VariantOfID != nil && *r.VariantOfID == "recipe-1" &&
+			r.ImageURL == "" && r.Nutrition == nil && r.Servings == 4 &&
+			len(r.Ingredients) == 2 && r.Ingredients[0].Name == "milk" && r.Ingredients[0].ID == "" &&
+			r.Ingredients[1].Name == "flour" && r.Ingredients[1].ID == "" && r.Ingredients[1].RecipeID == "" &&
+			len(r.Instructions) == 1 && r.Instructions[0].ID == ""
+	})).Return(nil).Once()
+	recipeRepo.On("GetByID", mock.Anything, mock.AnythingOfType("string"), domain.NutritionDetailBase).Return(saved, nil).Once()
+
+	srv := newTestRecipeService(recipeRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), new(mockURLParser), new(mockPDFParser))
+	result, err := srv.ApplySubstitute(context.Background(), userID, "recipe-1", "ing-1", &domain.ApplySubstituteRequest{Name: "milk", Amount: 2, Unit: "cups"})
+
+	require.NoError(t, err)
+	require.Equal(t, saved, result)
+	require.Equal(t, "buttermilk", original.Ingredients[0].Name, "original recipe must not be modified")
+	recipeRepo.AssertExpectations(t)
+}
+
+func TestRecipeService_Translate_StoresTranslationWithoutAmounts(t *testing.T) {
+	userID := "user-1"
+	recipe := &domain.Recipe{
+		ID: "recipe-1", UserID: userID, Title: "Pancakes",
+		Ingredients:  []domain.RecipeIngredient{{ID: "ing-1", Name: "flour", Amount: 200, Unit: "g"}},
+		Instructions: []domain.RecipeInstruction{{ID: "step-1", StepNumber: 1, Instruction: "Mix"}},
+	}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	aiConfigRepo := new(mockRecipeAIConfigRepo)
+	aiConfigRepo.On("GetDefaultConfig", mock.Anything, userID).Return(nil, nil).Once()
+
+	model := new(mockAIModel)
+	model.On("TranslateRecipe", mock.Anything, mock.MatchedBy(func(c ai.RecipeTranslationContent) bool {
+		return c.Title == "Pancakes" && len(c.Ingredients) == 1 && c.Ingredients[0].ID == "ing-1"
+	}), "de").Return(&ai.RecipeTranslationContent{
+		Title:        "Pfannkuchen",
+		Ingredients:  []ai.RecipeTranslationIngredient{{ID: "ing-1", Name: "Mehl", Unit: "g"}},
+		Instructions: []ai.RecipeTranslationInstruction{{ID: "step-1", Instruction: "Verrühren"}},
+	}, nil).Once()
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", ai.ModelDefault, "").Return(model, nil).Once()
+
+	translationRepo := new(mockRecipeTranslationRepo)
+	translationRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(tr *domain.RecipeTranslation) bool {
+		return tr.RecipeID == "recipe-1" && tr.Language == "de" && tr.Title == "Pfannkuchen" &&
+			len(tr.Ingredients) == 1 && tr.Ingredients[0].IngredientID == "ing-1" && tr.Ingredients[0].Name == "Mehl" &&
+			len(tr.Instructions) == 1 && tr.Instructions[0].InstructionID == "step-1"
+	})).Return(nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, translationRepo, new(mockRecipeEditProposalRepo), new(mockRecipeUserRepo), aiConfigRepo, new(mockFileStore), zap.NewNop(), factory, new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.Translate(context.Background(), userID, "recipe-1", "de-DE")
+
+	require.NoError(t, err)
+	require.Equal(t, "de", result.Language)
+	require.Equal(t, "Pancakes", recipe.Title, "the recipe itself must not be overwritten")
+	recipeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	translationRepo.AssertExpectations(t)
+	model.AssertExpectations(t)
+}
+
+func TestRecipeService_Translate_NotOwner(t *testing.T) {
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(&domain.Recipe{ID: "recipe-1", UserID: "owner"}, nil).Once()
+
+	srv := newTestRecipeService(recipeRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), new(mockURLParser), new(mockPDFParser))
+	result, err := srv.Translate(context.Background(), "user-1", "recipe-1", "de")
+
+	require.Nil(t, result)
+	require.ErrorIs(t, err, apperrors.ErrUnauthorized)
+}
+
+func TestRecipeService_GetTranslated_AppliesPreferredLanguage(t *testing.T) {
+	userID := "user-1"
+	recipe := &domain.Recipe{
+		ID: "recipe-1", UserID: userID, Title: "Pancakes",
+		Ingredients: []domain.RecipeIngredient{
+			{ID: "ing-1", Name: "flour", Amount: 200, Unit: "g"},
+			{ID: "ing-2", Name: "milk", Amount: 1, Unit: "cup"},
+		},
+		Instructions: []domain.RecipeInstruction{{ID: "step-1", StepNumber: 1, Instruction: "Mix"}},
+	}
+	translations := []domain.RecipeTranslation{
+		{RecipeID: "recipe-1", Language: "fr", Title: "Crêpes"},
+		{
+			RecipeID: "recipe-1", Language: "de", Title: "Pfannkuchen",
+			Ingredients:  []domain.TranslatedIngredient{{IngredientID: "ing-1", Name: "Mehl", Unit: "g"}},
+			Instructions: []domain.TranslatedInstruction{{InstructionID: "step-1", Instruction: "Verrühren"}},
+		},
+	}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	translationRepo := new(mockRecipeTranslationRepo)
+	translationRepo.On("ListByRecipeID", mock.Anything, "recipe-1", []string{"de", "fr"}).Return(translations, nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, translationRepo, new(mockRecipeEditProposalRepo), new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), zap.NewNop(), new(mockModelFactory), new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, lang, err := srv.GetTranslated(context.Background(), userID, "recipe-1", domain.NutritionDetailBase, []string{"de-AT", "fr", "*"})
+
+	require.NoError(t, err)
+	require.Equal(t, "de", lang)
+	require.Equal(t, "Pfannkuchen", result.Title)
+	require.Equal(t, "Mehl", result.Ingredients[0].Name)
+	require.Equal(t, 200.0, result.Ingredients[0].Amount)
+	require.Equal(t, "milk", result.Ingredients[1].Name, "untranslated rows keep their text")
+	require.Equal(t, "Verrühren", result.Instructions[0].Instruction)
+}
+
+func TestRecipeService_GetTranslated_NoMatch(t *testing.T) {
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: "user-1", Title: "Pancakes"}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	translationRepo := new(mockRecipeTranslationRepo)
+	translationRepo.On("ListByRecipeID", mock.Anything, "recipe-1", []string{"it"}).Return([]domain.RecipeTranslation{}, nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, translationRepo, new(mockRecipeEditProposalRepo), new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), zap.NewNop(), new(mockModelFactory), new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, lang, err := srv.GetTranslated(context.Background(), "user-1", "recipe-1", domain.NutritionDetailBase, []string{"it"})
+
+	require.NoError(t, err)
+	require.Equal(t, "", lang)
+	require.Equal(t, "Pancakes", result.Title)
+}
+
+func TestRecipeService_ProposeEdit_StoresDiffWithoutUpdating(t *testing.T) {
+	userID := "user-1"
+	updatedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	recipe := &domain.Recipe{
+		ID: "recipe-1", UserID: userID, Title: "Chili", Servings: 4, UpdatedAt: updatedAt,
+		Ingredients:  []domain.RecipeIngredient{{ID: "ing-1", Name: "beef", Amount: 500, Unit: "g"}},
+		Instructions: []domain.RecipeInstruction{{ID: "step-1", StepNumber: 1, Instruction: "Brown the beef"}},
+	}
+	edited := &domain.Recipe{
+		Title:        "Veggie chili",
+		Ingredients:  []domain.RecipeIngredient{{Name: "black beans", Amount: 500, Unit: "g"}},
+		Instructions: []domain.RecipeInstruction{{StepNumber: 1, Instruction: "Brown the beef"}},
+	}
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailMacro).Return(recipe, nil).Once()
+	aiConfigRepo := new(mockRecipeAIConfigRepo)
+	aiConfigRepo.On("GetDefaultConfig", mock.Anything, userID).Return(nil, nil).Once()
+	model := new(mockAIModel)
+	model.On("EditRecipe", mock.Anything, recipe, "make it vegetarian").Return(edited, nil).Once()
+	factory := new(mockModelFactory)
+	factory.On("CreateModel", ai.ModelDefault, "").Return(model, nil).Once()
+
+	proposalRepo := new(mockRecipeEditProposalRepo)
+	proposalRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.RecipeEditProposal) bool {
+		fields := make([]string, len(p.Changes))
+		for i, c := range p.Changes {
+			fields[i] = c.Field
+		}
+		return p.RecipeID == "recipe-1" && p.UserID == userID && p.Status == domain.EditProposalPending &&
+			p.BaseUpdatedAt.Equal(updatedAt) && p.Revision.Servings == 4 &&
+			assert.ObjectsAreEqual([]string{"title", "ingredients"}, fields)
+	})).Return(nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), proposalRepo, new(mockRecipeUserRepo), aiConfigRepo, new(mockFileStore), zap.NewNop(), factory, new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.ProposeEdit(context.Background(), userID, "recipe-1", &domain.AIEditRecipeRequest{Instruction: "make it vegetarian"})
+
+	require.NoError(t, err)
+	require.Equal(t, "Veggie chili", result.Revision.Title)
+	recipeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	proposalRepo.AssertExpectations(t)
+}
+
+func TestRecipeService_AcceptEdit_AppliesRevisionThroughUpdate(t *testing.T) {
+	userID := "user-1"
+	updatedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: userID, Title: "Chili", SourceType: "MANUAL", Servings: 4, UpdatedAt: updatedAt}
+	proposal := &domain.RecipeEditProposal{
+		ID: "p-1", RecipeID: "recipe-1", UserID: userID, Status: domain.EditProposalPending, BaseUpdatedAt: updatedAt,
+		Revision: domain.RecipeRevision{Title: "Veggie chili", Servings: 4, Ingredients: []domain.RecipeIngredient{{Name: "black beans"}}},
+	}
+	updated := &domain.Recipe{ID: "recipe-1", UserID: userID, Title: "Veggie chili"}
+
+	proposalRepo := new(mockRecipeEditProposalRepo)
+	proposalRepo.On("GetByID", mock.Anything, "p-1").Return(proposal, nil).Once()
+	proposalRepo.On("Resolve", mock.Anything, "p-1", domain.EditProposalAccepted).Return(true, nil).Once()
+
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailMicro).Return(recipe, nil).Once()
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(recipe, nil).Once()
+	recipeRepo.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+	recipeRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *domain.Recipe) bool {
+		return r.ID == "recipe-1" && r.Title == "Veggie chili" && r.SourceType == "MANUAL" && len(r.Ingredients) == 1
+	})).Return(nil).Once()
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailBase).Return(updated, nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), proposalRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), zap.NewNop(), new(mockModelFactory), new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.AcceptEdit(context.Background(), userID, "recipe-1", "p-1")
+
+	require.NoError(t, err)
+	require.Equal(t, updated, result)
+	recipeRepo.AssertExpectations(t)
+	proposalRepo.AssertExpectations(t)
+}
+
+func TestRecipeService_AcceptEdit_StaleProposal(t *testing.T) {
+	userID := "user-1"
+	proposal := &domain.RecipeEditProposal{
+		ID: "p-1", RecipeID: "recipe-1", UserID: userID, Status: domain.EditProposalPending,
+		BaseUpdatedAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	recipe := &domain.Recipe{ID: "recipe-1", UserID: userID, UpdatedAt: time.Date(2026, 5, 2, 9, 0, 0, 0, time.UTC)}
+
+	proposalRepo := new(mockRecipeEditProposalRepo)
+	proposalRepo.On("GetByID", mock.Anything, "p-1").Return(proposal, nil).Once()
+	recipeRepo := new(mockRecipeRepo)
+	recipeRepo.On("GetByID", mock.Anything, "recipe-1", domain.NutritionDetailMicro).Return(recipe, nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(recipeRepo, new(mockRecipeTranslationRepo), proposalRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), zap.NewNop(), new(mockModelFactory), new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	result, err := srv.AcceptEdit(context.Background(), userID, "recipe-1", "p-1")
+
+	require.Nil(t, result)
+	require.True(t, apperrors.IsConflict(err))
+	proposalRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything)
+	recipeRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestRecipeService_RejectEdit_OtherUsersProposal(t *testing.T) {
+	proposalRepo := new(mockRecipeEditProposalRepo)
+	proposalRepo.On("GetByID", mock.Anything, "p-1").Return(&domain.RecipeEditProposal{
+		ID: "p-1", RecipeID: "recipe-1", UserID: "someone-else", Status: domain.EditProposalPending,
+	}, nil).Once()
+
+	cipher, _ := crypto.NewCipher("test-encryption-key")
+	srv := NewRecipeService(new(mockRecipeRepo), new(mockRecipeTranslationRepo), proposalRepo, new(mockRecipeUserRepo), new(mockRecipeAIConfigRepo), new(mockFileStore), zap.NewNop(), new(mockModelFactory), new(mockURLParser), new(mockPDFParser), cipher, nil, nil)
+	err := srv.RejectEdit(context.Background(), "user-1", "recipe-1", "p-1")
+
+	require.ErrorIs(t, err, apperrors.ErrNotFound)
+	proposalRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -14,21 +14,35 @@ import (
 
 type Services struct {
 	UserService         UserService
+	APITokenService     APITokenService
 	ProfileService      ProfileService
 	AIConfigService     AIConfigService
 	RecipeService       RecipeService
 	ShoppingListService ShoppingListService
+	ShoppingTripService ShoppingTripService
+	TemplateService     ShoppingListTemplateService
+	ShareService        ShoppingListShareService
+	SuggestionService   ItemSuggestionService
+	ProductService      ProductService
+	PriceService        ItemPriceService
 	StoreChainService   StoreChainService
+	StoreService        StoreService
+	CategoryService     CategoryService
+	AdminService        AdminService
+	AuditService        AuditService
+	DataExportService   DataExportService
+	TrashService        TrashService
+	EmailOutbox         EmailOutbox
 }
 
-func NewServices(repos *repository.Repositories, config config.Config, fileStorage storage.FileStore, logger *zap.Logger, factory ai.ModelFactory, cipher APIKeyCipher) *Services {
+func NewServices(repos *repository.Repositories, config config.Config, fileStorage storage.FileStore, mailTransport email.Transport, logger *zap.Logger, factory ai.ModelFactory, cipher APIKeyCipher) *Services {
 	pdfParserService := pdfparser.NewService(logger)
 	urlParserService := urlparser.NewService(logger)
 
-	// Create AI model for shopping list service
+	// Create AI model for categorizing shopping list items
 	aiModel, err := factory.CreateModel(ai.ModelDefault, "")
 	if err != nil {
-		logger.Warn("failed to create AI model for shopping list service", zap.Error(err))
+		logger.Warn("failed to create AI model for item categorization", zap.Error(err))
 	}
 
 	// Sign local upload URLs so the file handler can serve them; for non-local
@@ -38,16 +52,36 @@ func NewServices(repos *repository.Repositories, config config.Config, fileStora
 		imageSigner = signedurl.NewSigner(config.JWT.Secret, signedurl.DefaultTTL)
 	}
 
-	// Initialize store chain service first since shopping list service depends on it
-	storeChainService := NewStoreChainService(repos.StoreChainRepository, logger)
-	emailSvc := email.NewEmailService(config.SMTP.From, config.SMTP.Password, config.SMTP.Host, config.SMTP.Port, config.Frontend.Url)
+	// Initialize category and store chain services first since shopping list service depends on them
+	categoryService := NewCategoryService(repos.CategoryRepository, aiModel, logger)
+	storeChainService := NewStoreChainService(repos.StoreChainRepository, categoryService, logger)
+	emailOutbox := NewEmailOutbox(repos.EmailOutboxRepository, mailTransport, logger)
+	emailSvc := email.NewEmailService(emailOutbox, config.Frontend.Url)
+	auditLog := NewAuditLog(repos.AuditRepository, logger)
+	auditService := NewAuditService(repos.AuditRepository, config.Audit.RetentionDays, logger)
+	storeService := NewStoreService(repos.StoreRepository, repos.StoreChainRepository, categoryService, auditLog, logger)
+	shoppingListService := NewShoppingListService(repos.ShoppingListRepository, repos.RecipeRepository, storeChainService, storeService, categoryService, auditLog, logger)
 
 	return &Services{
-		UserService:         NewUserService(repos.UserRepository, config.JWT.Secret, config, emailSvc, logger),
+		UserService:         NewUserService(repos.UserRepository, config.JWT.Secret, config, emailSvc, cipher, auditLog, logger),
+		APITokenService:     NewAPITokenService(repos.APITokenRepository, auditLog, logger),
 		ProfileService:      NewProfileService(repos.ProfileRepository),
-		AIConfigService:     NewAIConfigService(repos.AIConfigRepository, cipher, logger),
-		RecipeService:       NewRecipeService(repos.RecipeRepository, repos.UserRepository, repos.AIConfigRepository, fileStorage, logger, &factory, urlParserService, pdfParserService, cipher, imageSigner),
-		ShoppingListService: NewShoppingListService(repos.ShoppingListRepository, repos.RecipeRepository, storeChainService, aiModel, logger),
+		AIConfigService:     NewAIConfigService(repos.AIConfigRepository, cipher, &factory, auditLog, logger),
+		RecipeService:       NewRecipeService(repos.RecipeRepository, repos.RecipeTranslationRepository, repos.RecipeEditProposalRepository, repos.UserRepository, repos.AIConfigRepository, fileStorage, logger, &factory, urlParserService, pdfParserService, cipher, imageSigner, auditLog),
+		ShoppingListService: shoppingListService,
+		ShoppingTripService: NewShoppingTripService(repos.ShoppingTripRepository, shoppingListService, storeService, categoryService, logger),
+		TemplateService:     NewShoppingListTemplateService(repos.ShoppingListTemplateRepository, repos.ShoppingListRepository, categoryService, logger),
+		ShareService:        NewShoppingListShareService(repos.ShoppingListShareRepository, shoppingListService, config.JWT.Secret, config.Frontend.Url, auditLog, logger),
+		SuggestionService:   NewItemSuggestionService(repos.ShoppingListRepository, logger),
+		ProductService:      NewProductService(repos.ProductRepository, repos.ShoppingListRepository, categoryService, logger),
+		PriceService:        NewItemPriceService(repos.ItemPriceRepository, repos.ShoppingTripRepository, shoppingListService, repos.RecipeRepository, storeChainService, storeService, logger),
 		StoreChainService:   storeChainService,
+		StoreService:        storeService,
+		CategoryService:     categoryService,
+		AdminService:        NewAdminService(repos.UserRepository, repos.AIConfigRepository, repos.StoreChainRepository, repos.AuditRepository, logger),
+		AuditService:        auditService,
+		DataExportService:   NewDataExportService(repos.DataExportRepository, repos.UserRepository, repos.ProfileRepository, repos.RecipeRepository, repos.ShoppingListRepository, repos.StoreRepository, repos.ShoppingTripRepository, repos.ShoppingListTemplateRepository, repos.ShoppingListShareRepository, repos.ItemPriceRepository, repos.ProductRepository, repos.APITokenRepository, repos.AIConfigRepository, auditService, fileStorage, emailSvc, config.Export, config.JWT.Secret, auditLog, logger),
+		TrashService:        NewTrashService(repos.RecipeRepository, repos.ShoppingListRepository, fileStorage, config.Trash.RetentionDays, auditLog, logger),
+		EmailOutbox:         emailOutbox,
 	}
 }
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/repository"
+)
+
+func (s *shoppingListService) BatchItems(ctx context.Context, userID string, listID string, req *domain.BatchItemsRequest) (*domain.BatchItemsResult, error) {
+	list, err := s.verifyListOwnership(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BatchItemsResult{Results: make([]domain.BatchItemResult, len(req.Operations))}
+	for i, op := range req.Operations {
+		result.Results[i] = domain.BatchItemResult{Op: op.Op, Status: domain.BatchStatusSkipped}
+	}
+
+	batch := &itemBatch{
+		userID:     userID,
+		list:       list,
+		items:      make(map[string]*domain.ShoppingListItem, len(list.Items)),
+		categories: s.batchCategories(ctx, list, req.Operations),
+	}
+	if batch.taxonomy, err = s.categoryService.Taxonomy(ctx); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		batch.items[list.Items[i].ID] = &list.Items[i]
+	}
+
+	failed := -1
+	err = s.shoppingListRepo.WithTypedTransaction(ctx, func(txRepo repository.ShoppingListRepository) error {
+		batch.repo = txRepo
+		for i, op := range req.Operations {
+			ids, err := batch.apply(ctx, op)
+			if err != nil {
+				failed = i
+				return err
+			}
+			result.Results[i].Status, result.Results[i].ItemIDs = domain.BatchStatusApplied, ids
+		}
+		return nil
+	})
+	if err != nil {
+		if failed < 0 {
+			return nil, err
+		}
+		for i := range failed {
+			result.Results[i].Status = domain.BatchStatusRolledBack
+		}
+		result.Results[failed].Status, result.Results[failed].Error = domain.BatchStatusFailed, err.Error()
+		return result, err
+	}
+
+	result.Applied = true
+	if result.List, err = s.shoppingListRepo.GetByID(ctx, listID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// batchCategories categorizes the items the batch's categorize operations
+// name before its transaction opens, so the AI model is not waited on while
+// rows are locked. Names updates give items are included, since a batch may
+// rename an item and then categorize it.
+func (s *shoppingListService) batchCategories(ctx context.Context, list *domain.ShoppingList, ops []domain.BatchItemOperation) map[string]domain.Category {
+	var names, renamed []string
+	categorize := false
+	for _, op := range ops {
+		switch op.Op {
+		case domain.BatchOpUpdate:
+			if op.Item != nil {
+				renamed = append(renamed, op.Item.Name)
+			}
+		case domain.BatchOpCategorize:
+			categorize = true
+			for _, item := range list.Items {
+				if len(op.ItemIDs) == 0 || slices.Contains(op.ItemIDs, item.ID) {
+					names = append(names, item.Name)
+				}
+			}
+		}
+	}
+	if !categorize {
+		return nil
+	}
+	return s.categoryService.Recategorize(ctx, append(names, renamed...))
+}
+
+// itemBatch applies a batch's operations to the items of list in a
+// transaction. items are the items still on the list as operations move and
+// delete them. Each operation writes only the columns it changes, so edits
+// made to an item's other columns since the list was read are kept.
+type itemBatch struct {
+	repo       repository.ShoppingListRepository
+	userID     string
+	list       *domain.ShoppingList
+	items      map[string]*domain.ShoppingListItem
+	categories map[string]domain.Category
+	taxonomy   *domain.CategoryTaxonomy
+}
+
+// apply applies op and returns the IDs of the items it applied to.
+func (b *itemBatch) apply(ctx context.Context, op domain.BatchItemOperation) ([]string, error) {
+	items, err := b.targets(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *domain.ShoppingList
+	switch op.Op {
+	case domain.BatchOpUpdate:
+		if len(items) != 1 || op.Item == nil {
+			return nil, errors.New("update takes one item and its new fields in item", "INVALID_INPUT")
+		}
+		if err := b.validCategory(op.Item.Category); err != nil {
+			return nil, err
+		}
+	case domain.BatchOpMove:
+		if target, err = b.targetList(ctx, op.TargetListID); err != nil {
+			return nil, err
+		}
+	case domain.BatchOpSetCategory:
+		if op.Category == "" {
+			return nil, errors.New("set_category needs a category", "INVALID_INPUT")
+		}
+		if err := b.validCategory(op.Category); err != nil {
+			return nil, err
+		}
+	case domain.BatchOpReorder:
+		return b.reorder(ctx, items)
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+		if op.Op == domain.BatchOpDelete {
+			if err := b.repo.DeleteItem(ctx, item.ID); err != nil {
+				return nil, err
+			}
+			delete(b.items, item.ID)
+			continue
+		}
+
+		var fields map[string]interface{}
+		switch op.Op {
+		case domain.BatchOpUpdate:
+			item.Name, item.Amount, item.Unit = op.Item.Name, op.Item.Amount, op.Item.Unit
+			item.Category, item.Notes = op.Item.Category, op.Item.Notes
+			fields = map[string]interface{}{
+				"name": item.Name, "amount": item.Amount, "unit": item.Unit, "category": item.Category, "notes": item.Notes,
+			}
+		// Checking items in bulk teaches the store nothing about its order,
+		// unlike checking them off one by one.
+		case domain.BatchOpCheck, domain.BatchOpUncheck:
+			item.IsChecked = op.Op == domain.BatchOpCheck
+			fields = map[string]interface{}{"is_checked": item.IsChecked}
+		// A moved item has no place on its new list until it is reordered.
+		case domain.BatchOpMove:
+			item.ListID, item.Position = target.ID, nil
+			delete(b.items, item.ID)
+			fields = map[string]interface{}{"list_id": item.ListID, "position": nil}
+		case domain.BatchOpSetCategory:
+			item.Category = op.Category
+			fields = map[string]interface{}{"category": item.Category}
+		case domain.BatchOpCategorize:
+			category, ok := b.categories[item.Name]
+			if !ok {
+				continue
+			}
+			item.Category = category
+			fields = map[string]interface{}{"category": item.Category}
+		}
+		if err := b.repo.UpdateItemFields(ctx, item.ID, fields); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// reorder places items first on the list in the order given and the list's
+// other items after them in their current order. Only the positions that
+// change are written.
+func (b *itemBatch) reorder(ctx context.Context, items []*domain.ShoppingListItem) ([]string, error) {
+	ids := make([]string, len(items))
+	placed := make(map[string]bool, len(items))
+	for i, item := range items {
+		ids[i], placed[item.ID] = item.ID, true
+	}
+	var rest []*domain.ShoppingListItem
+	for _, item := range b.items {
+		if !placed[item.ID] {
+			rest = append(rest, item)
+		}
+	}
+	current := &itemSorter{keys: []sortKey{{field: domain.SortKeyPosition}}}
+	slices.SortFunc(rest, current.compare)
+
+	for i, item := range slices.Concat(items, rest) {
+		if item.Position != nil && *item.Position == i {
+			continue
+		}
+		position := i
+		item.Position = &position
+		if err := b.repo.UpdateItemFields(ctx, item.ID, map[string]interface{}{"position": position}); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// validCategory rejects categories that are not in the taxonomy.
+func (b *itemBatch) validCategory(category domain.Category) error {
+	if !b.taxonomy.Valid(category) {
+		return errors.New(fmt.Sprintf("unknown category %s", category), "INVALID_INPUT")
+	}
+	return nil
+}
+
+// targets returns the items op applies to. Without item IDs, checking,
+// unchecking and categorizing apply to every item on the list.
+func (b *itemBatch) targets(op domain.BatchItemOperation) ([]*domain.ShoppingListItem, error) {
+	if len(op.ItemIDs) == 0 {
+		switch op.Op {
+		case domain.BatchOpCheck, domain.BatchOpUncheck, domain.BatchOpCategorize:
+			var items []*domain.ShoppingListItem
+			for i := range b.list.Items {
+				if item, ok := b.items[b.list.Items[i].ID]; ok {
+					items = append(items, item)
+				}
+			}
+			return items, nil
+		}
+		return nil, errors.New(fmt.Sprintf("%s needs item_ids", op.Op), "INVALID_INPUT")
+	}
+
+	items := make([]*domain.ShoppingListItem, 0, len(op.ItemIDs))
+	seen := make(map[string]bool, len(op.ItemIDs))
+	for _, id := range op.ItemIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		item, ok := b.items[id]
+		if !ok {
+			return nil, errors.ErrNotFound.Wrap(fmt.Sprintf("item %s is not on the list", id))
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// targetList returns the list items are moved to, another of the user's.
+func (b *itemBatch) targetList(ctx context.Context, listID string) (*domain.ShoppingList, error) {
+	if listID == "" {
+		return nil, errors.New("move needs a target_list_id", "INVALID_INPUT")
+	}
+	if listID == b.list.ID {
+		return nil, errors.New("items are already on the target list", "INVALID_INPUT")
+	}
+	target, err := b.repo.GetByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if target.UserID != b.userID {
+		return nil, errors.ErrUnauthorized
+	}
+	return target, nil
+}
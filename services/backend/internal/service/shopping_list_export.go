@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+)
+
+func (s *shoppingListService) GetSections(ctx context.Context, userID string, listID string, languages []string) (*domain.ShoppingList, []domain.ListSection, error) {
+	list, err := s.verifyListOwnership(ctx, userID, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var layout []domain.StoreSection
+	switch {
+	case list.StoreID != nil:
+		store, err := s.storeService.Get(ctx, userID, *list.StoreID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := s.storeService.OrganizeShoppingList(ctx, userID, list, store.ID); err != nil {
+			return nil, nil, err
+		}
+		layout = store.Layout
+	case list.StoreChainID != nil:
+		chain, err := s.storeChainService.GetChain(ctx, *list.StoreChainID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := s.storeChainService.OrganizeShoppingList(ctx, list, chain.ID); err != nil {
+			return nil, nil, err
+		}
+		layout = chain.Layout
+	default:
+		sortItems(list.Items, "category", "asc")
+	}
+
+	taxonomy, err := s.categoryService.Taxonomy(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, groupSections(list.Items, layout, taxonomy, languages), nil
+}
+
+func (s *shoppingListService) Export(ctx context.Context, userID string, listID string, format string, languages []string) (*domain.ShoppingListExport, error) {
+	list, sections, err := s.GetSections(ctx, userID, listID, languages)
+	if err != nil {
+		return nil, err
+	}
+	return renderShoppingList(list.Name, sections, format)
+}
+
+// groupSections splits items, already sorted for the store, into the
+// sections of layout they are found in, in the order the sections are first
+// reached. Items in a category the layout does not place are grouped under
+// their top-level category, named in the first of languages it has.
+func groupSections(items []domain.ShoppingListItem, layout []domain.StoreSection, taxonomy *domain.CategoryTaxonomy, languages []string) []domain.ListSection {
+	sectionOf := sectionLookup(layoutIndex(layout), taxonomy)
+
+	categoryNames := make(map[domain.Category]string)
+	if taxonomy != nil {
+		for i := range taxonomy.Categories {
+			categoryNames[taxonomy.Categories[i].Code] = categoryName(&taxonomy.Categories[i], languages)
+		}
+	}
+
+	sections := make([]domain.ListSection, 0)
+	indexOf := make(map[string]int)
+	for _, item := range items {
+		var name string
+		if i, ok := sectionOf(item.Category); ok {
+			name = layout[i].Name
+		} else {
+			top := topLevelCategory(item.Category, taxonomy)
+			if name = categoryNames[top]; name == "" {
+				name = string(top)
+			}
+		}
+
+		i, ok := indexOf[name]
+		if !ok {
+			i = len(sections)
+			indexOf[name] = i
+			sections = append(sections, domain.ListSection{Name: name})
+		}
+		sections[i].Items = append(sections[i].Items, item)
+	}
+	return sections
+}
+
+var exportFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// renderShoppingList renders the sections of the list called name in format.
+func renderShoppingList(name string, sections []domain.ListSection, format string) (*domain.ShoppingListExport, error) {
+	base := strings.Trim(exportFilenameUnsafe.ReplaceAllString(name, "-"), "-")
+	if base == "" {
+		base = "shopping-list"
+	}
+
+	switch format {
+	case domain.ExportFormatText, "":
+		return &domain.ShoppingListExport{
+			Filename:    base + ".txt",
+			ContentType: "text/plain; charset=utf-8",
+			Body:        []byte(renderShoppingListText(name, sections)),
+		}, nil
+	case domain.ExportFormatMarkdown:
+		return &domain.ShoppingListExport{
+			Filename:    base + ".md",
+			ContentType: "text/markdown; charset=utf-8",
+			Body:        []byte(renderShoppingListMarkdown(name, sections)),
+		}, nil
+	case domain.ExportFormatHTML:
+		var buf bytes.Buffer
+		if err := shoppingListHTML.Execute(&buf, struct {
+			Name     string
+			Sections []domain.ListSection
+		}{name, sections}); err != nil {
+			return nil, err
+		}
+		return &domain.ShoppingListExport{
+			Filename:    base + ".html",
+			ContentType: "text/html; charset=utf-8",
+			Body:        buf.Bytes(),
+		}, nil
+	}
+	return nil, errors.New("format must be text, markdown or html", "INVALID_INPUT")
+}
+
+func renderShoppingListText(name string, sections []domain.ListSection) string {
+	var b strings.Builder
+	b.WriteString(name + "\n")
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n%s\n", section.Name)
+		for _, item := range section.Items {
+			box := "[ ]"
+			if item.IsChecked {
+				box = "[x]"
+			}
+			fmt.Fprintf(&b, "%s %s\n", box, exportItemLine(item))
+		}
+	}
+	return b.String()
+}
+
+func renderShoppingListMarkdown(name string, sections []domain.ListSection) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", markdownEscaper.Replace(name))
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownEscaper.Replace(section.Name))
+		for _, item := range section.Items {
+			box := "[ ]"
+			if item.IsChecked {
+				box = "[x]"
+			}
+			fmt.Fprintf(&b, "- %s %s\n", box, markdownEscaper.Replace(exportItemLine(item)))
+		}
+	}
+	return b.String()
+}
+
+// markdownEscaper keeps item names from turning into markup.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "#", `\#`, "<", "&lt;", ">", "&gt;",
+)
+
+// exportItemLine describes an item as "2 kg Apples (organic)".
+func exportItemLine(item domain.ShoppingListItem) string {
+	line := item.Name
+	if item.Amount > 0 {
+		amount := strconv.FormatFloat(item.Amount, 'f', -1, 64)
+		line = strings.TrimSpace(amount+" "+item.Unit) + " " + line
+	}
+	if item.Notes != "" {
+		line += " (" + item.Notes + ")"
+	}
+	return line
+}
+
+var shoppingListHTML = template.Must(template.New("list").Funcs(template.FuncMap{
+	"line": exportItemLine,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+h2 { font-size: 1.1em; border-bottom: 1px solid #ccc; margin-top: 1.5em; }
+ul { list-style: none; padding: 0; }
+li { padding: 0.2em 0; }
+li.checked { color: #888; text-decoration: line-through; }
+li::before { content: "\2610\00a0"; }
+li.checked::before { content: "\2611\00a0"; }
+@media print { body { margin: 0; } h2 { break-after: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{range .Sections}}<h2>{{.Name}}</h2>
+<ul>
+{{range .Items}}<li{{if .IsChecked}} class="checked"{{end}}>{{line .}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
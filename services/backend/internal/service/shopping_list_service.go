@@ -5,9 +5,8 @@ import (
 	"github.com/H3nSte1n/recipe/internal/domain"
 	"github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/repository"
-	"github.com/H3nSte1n/recipe/pkg/ai"
 	"go.uber.org/zap"
-	"sort"
+	"slices"
 )
 
 type shoppingListRepository interface {
@@ -18,8 +17,9 @@ type shoppingListRepository interface {
 	Delete(ctx context.Context, id string) error
 	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingList, error)
 	AddItems(ctx context.Context, items []domain.ShoppingListItem) error
-	UpdateItem(ctx context.Context, item *domain.ShoppingListItem) error
+	UpdateItemFields(ctx context.Context, id string, fields map[string]interface{}) error
 	DeleteItem(ctx context.Context, id string) error
+	ArchiveCheckedItems(ctx context.Context, listID string) (int64, error)
 	WithTypedTransaction(ctx context.Context, fn func(repository.ShoppingListRepository) error) error
 }
 
@@ -32,50 +32,87 @@ type ShoppingListService interface {
 	Update(ctx context.Context, userID string, listID string, req *domain.UpdateShoppingListRequest) (*domain.ShoppingList, error)
 	Delete(ctx context.Context, userID string, listID string) error
 	GetByID(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error)
-	GetSorted(ctx context.Context, userID string, listID string, sortBy string, sortDirection string) (*domain.ShoppingList, error)
+	// GetSorted sorts the list by sortBy, a sort order such as
+	// "checked,section,-name", or else by the list's saved sort order; names
+	// compare as the first of languages does. Unknown keys are ignored.
+	GetSorted(ctx context.Context, userID string, listID string, sortBy string, sortDirection string, languages []string) (*domain.ShoppingList, error)
 	GetSortedByStoreName(ctx context.Context, userID string, listID string, storeName string, country string, sortDirection string) (*domain.ShoppingList, error)
 	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingList, error)
 	AddItem(ctx context.Context, userID string, listID string, req *domain.ShoppingListItemRequest) error
 	UpdateItem(ctx context.Context, userID string, itemID string, req *domain.UpdateShoppingListItemRequest) error
 	DeleteItem(ctx context.Context, userID string, itemID string) error
 	ToggleItem(ctx context.Context, userID string, itemID string, checked bool) error
+	// ClearChecked archives the list's checked items so the list can be
+	// shopped again, and returns how many were cleared. Cleared items do not
+	// go to the trash; they stay in the user's shopping history.
+	ClearChecked(ctx context.Context, userID string, listID string) (int64, error)
+	// BatchItems applies the request's operations to the list's items in one
+	// transaction. When one fails none are kept; the result says which failed
+	// and the error why.
+	BatchItems(ctx context.Context, userID string, listID string, req *domain.BatchItemsRequest) (*domain.BatchItemsResult, error)
 	AddRecipeToList(ctx context.Context, userID string, listID string, req *domain.AddRecipeToListRequest) error
-	GetSortedForStore(ctx context.Context, userID string, listID string, chainID string) (*domain.ShoppingList, error)
+	// GetSortedForStore orders the list for the user's store with storeID or,
+	// failing that, the chain with chainID. With neither it uses the list's
+	// own store or chain.
+	GetSortedForStore(ctx context.Context, userID string, listID string, storeID string, chainID string) (*domain.ShoppingList, error)
+	// GetSections returns the list sorted for its own store or chain and its
+	// items grouped by the sections of the store's layout. Section names not
+	// taken from a layout are given in the first of languages available.
+	GetSections(ctx context.Context, userID string, listID string, languages []string) (*domain.ShoppingList, []domain.ListSection, error)
+	// Export renders the list's sections in format, one of the
+	// domain.ExportFormat constants.
+	Export(ctx context.Context, userID string, listID string, format string, languages []string) (*domain.ShoppingListExport, error)
 }
 
 type shoppingListService struct {
 	shoppingListRepo  shoppingListRepository
 	recipeRepo        shoppingListRecipeRepository
 	storeChainService StoreChainService
-	aiModel           ai.AIModel
+	storeService      StoreService
+	categoryService   CategoryService
+	auditLog          *AuditLog
 	logger            *zap.Logger
 }
 
-func NewShoppingListService(shoppingListRepo shoppingListRepository, recipeRepo shoppingListRecipeRepository, storeChainService StoreChainService, aiModel ai.AIModel, logger *zap.Logger) ShoppingListService {
+func NewShoppingListService(shoppingListRepo shoppingListRepository, recipeRepo shoppingListRecipeRepository, storeChainService StoreChainService, storeService StoreService, categoryService CategoryService, auditLog *AuditLog, logger *zap.Logger) ShoppingListService {
 	return &shoppingListService{
 		shoppingListRepo:  shoppingListRepo,
 		recipeRepo:        recipeRepo,
 		storeChainService: storeChainService,
-		aiModel:           aiModel,
+		storeService:      storeService,
+		categoryService:   categoryService,
+		auditLog:          auditLog,
 		logger:            logger,
 	}
 }
 
 func (s *shoppingListService) Create(ctx context.Context, userID string, req *domain.CreateShoppingListRequest) (*domain.ShoppingList, error) {
+	sortOrder, err := normalizeSortOrder(req.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
 	list := &domain.ShoppingList{
 		UserID:      userID,
 		Name:        req.Name,
 		Description: req.Description,
 		SortType:    req.SortType,
+		SortOrder:   sortOrder,
 	}
 
 	if req.StoreChainID != "" {
 		list.StoreChainID = &req.StoreChainID
 	}
+	if req.StoreID != "" {
+		if _, err := s.storeService.Get(ctx, userID, req.StoreID); err != nil {
+			return nil, err
+		}
+		list.StoreID = &req.StoreID
+	}
 
 	// Write the list and its initial items atomically so a failure adding items
 	// cannot leave an orphaned empty list behind.
-	err := s.shoppingListRepo.WithTypedTransaction(ctx, func(txRepo repository.ShoppingListRepository) error {
+	err = s.shoppingListRepo.WithTypedTransaction(ctx, func(txRepo repository.ShoppingListRepository) error {
 		if err := txRepo.Create(ctx, list); err != nil {
 			return err
 		}
@@ -107,6 +144,13 @@ func (s *shoppingListService) Create(ctx context.Context, userID string, req *do
 	if err != nil {
 		return nil, err
 	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditShoppingListCreated,
+		TargetType: domain.AuditTargetShoppingList,
+		TargetID:   list.ID,
+		Changes:    domain.AuditChanges{"name": {To: list.Name}},
+	})
 
 	return s.shoppingListRepo.GetByID(ctx, list.ID)
 }
@@ -128,42 +172,108 @@ func (s *shoppingListService) Update(ctx context.Context, userID string, listID
 		return nil, err
 	}
 
+	changes := domain.AuditChanges{}
+	if list.Name != req.Name {
+		changes["name"] = domain.AuditChange{From: list.Name, To: req.Name}
+	}
+	if list.Description != req.Description {
+		changes["description"] = domain.AuditChange{From: list.Description, To: req.Description}
+	}
+	if list.SortType != req.SortType {
+		changes["sort_type"] = domain.AuditChange{From: list.SortType, To: req.SortType}
+	}
+	sortOrder, err := normalizeSortOrder(req.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	if list.SortOrder != sortOrder {
+		changes["sort_order"] = domain.AuditChange{From: list.SortOrder, To: sortOrder}
+	}
+	if current := derefString(list.StoreID); current != req.StoreID {
+		if req.StoreID != "" {
+			if _, err := s.storeService.Get(ctx, userID, req.StoreID); err != nil {
+				return nil, err
+			}
+		}
+		changes["store_id"] = domain.AuditChange{From: current, To: req.StoreID}
+		list.StoreID = nil
+		list.Store = nil
+		if req.StoreID != "" {
+			list.StoreID = &req.StoreID
+		}
+	}
+
 	list.Name = req.Name
 	list.Description = req.Description
 	list.SortType = req.SortType
+	list.SortOrder = sortOrder
 
 	if err := s.shoppingListRepo.Update(ctx, list); err != nil {
 		return nil, err
 	}
+	if len(changes) > 0 {
+		s.auditLog.Record(ctx, domain.AuditEvent{
+			ActorID:    auditActor(userID),
+			Action:     domain.AuditShoppingListUpdated,
+			TargetType: domain.AuditTargetShoppingList,
+			TargetID:   listID,
+			Changes:    changes,
+		})
+	}
 
 	// Reload from DB to reflect any fields set on save (e.g. UpdatedAt)
 	return s.shoppingListRepo.GetByID(ctx, listID)
 }
 
 func (s *shoppingListService) Delete(ctx context.Context, userID string, listID string) error {
-	if _, err := s.verifyListOwnership(ctx, userID, listID); err != nil {
+	list, err := s.verifyListOwnership(ctx, userID, listID)
+	if err != nil {
+		return err
+	}
+	if err := s.shoppingListRepo.Delete(ctx, listID); err != nil {
 		return err
 	}
-	return s.shoppingListRepo.Delete(ctx, listID)
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditShoppingListDeleted,
+		TargetType: domain.AuditTargetShoppingList,
+		TargetID:   listID,
+		Changes:    domain.AuditChanges{"name": {From: list.Name}},
+	})
+	return nil
 }
 
 func (s *shoppingListService) GetByID(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error) {
 	return s.verifyListOwnership(ctx, userID, listID)
 }
 
-func (s *shoppingListService) GetSorted(ctx context.Context, userID string, listID string, sortBy string, sortDirection string) (*domain.ShoppingList, error) {
+func (s *shoppingListService) GetSorted(ctx context.Context, userID string, listID string, sortBy string, sortDirection string, languages []string) (*domain.ShoppingList, error) {
 	list, err := s.verifyListOwnership(ctx, userID, listID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Warn on unknown sortBy so callers can detect misconfiguration
-	validSortFields := map[string]bool{"name": true, "category": true, "amount": true, "checked": true, "created_at": true}
-	if !validSortFields[sortBy] {
-		s.logger.Warn("unknown sortBy field, defaulting to name", zap.String("sortBy", sortBy))
+	if sortBy == "" {
+		sortBy = list.SortOrder
+	}
+	if sortBy == "" {
+		return list, nil
+	}
+
+	// Warn on unknown sort keys so callers can detect misconfiguration
+	keys, unknown := parseSortOrder(sortBy)
+	if len(unknown) > 0 {
+		s.logger.Warn("unknown sort keys ignored", zap.Strings("keys", unknown))
 	}
+	keys = directedKeys(keys, sortDirection)
 
-	sortItems(list.Items, sortBy, sortDirection)
+	sorter := newItemSorter(keys, languages)
+	if slices.ContainsFunc(keys, func(k sortKey) bool { return k.field == domain.SortKeySection }) {
+		if sorter.sectionOf, err = s.storeSections(ctx, userID, list); err != nil {
+			return nil, err
+		}
+	}
+	sorter.sort(list.Items)
 
 	return list, nil
 }
@@ -200,16 +310,7 @@ func (s *shoppingListService) AddItem(ctx context.Context, userID string, listID
 		return err
 	}
 
-	// Classify the item
-	category := domain.CategoryOther
-	if s.aiModel != nil {
-		categories, err := s.aiModel.CategorizeItems(ctx, []string{req.Name})
-		if err != nil {
-			s.logger.Warn("failed to classify item", zap.Error(err))
-		} else if cat, ok := categories[req.Name]; ok {
-			category = domain.Category(cat)
-		}
-	}
+	category := s.categoryService.Categorize(ctx, []string{req.Name})[req.Name]
 
 	item := &domain.ShoppingListItem{
 		ListID:   listID,
@@ -223,51 +324,70 @@ func (s *shoppingListService) AddItem(ctx context.Context, userID string, listID
 	return s.shoppingListRepo.AddItems(ctx, []domain.ShoppingListItem{*item})
 }
 
-func (s *shoppingListService) verifyItemOwnership(ctx context.Context, userID string, itemID string) (*domain.ShoppingListItem, error) {
+func (s *shoppingListService) verifyItemOwnership(ctx context.Context, userID string, itemID string) (*domain.ShoppingListItem, *domain.ShoppingList, error) {
 	item, err := s.shoppingListRepo.GetItemByID(ctx, itemID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	list, err := s.shoppingListRepo.GetByID(ctx, item.ListID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if list.UserID != userID {
-		return nil, errors.ErrUnauthorized
+		return nil, nil, errors.ErrUnauthorized
 	}
-	return item, nil
+	return item, list, nil
 }
 
 func (s *shoppingListService) UpdateItem(ctx context.Context, userID string, itemID string, req *domain.UpdateShoppingListItemRequest) error {
-	item, err := s.verifyItemOwnership(ctx, userID, itemID)
+	item, _, err := s.verifyItemOwnership(ctx, userID, itemID)
 	if err != nil {
 		return err
 	}
 
-	item.Name = req.Name
-	item.Amount = req.Amount
-	item.Unit = req.Unit
-	item.Category = req.Category
-	item.Notes = req.Notes
-
-	return s.shoppingListRepo.UpdateItem(ctx, item)
+	return s.shoppingListRepo.UpdateItemFields(ctx, item.ID, map[string]interface{}{
+		"name":     req.Name,
+		"amount":   req.Amount,
+		"unit":     req.Unit,
+		"category": req.Category,
+		"notes":    req.Notes,
+	})
 }
 
 func (s *shoppingListService) DeleteItem(ctx context.Context, userID string, itemID string) error {
-	item, err := s.verifyItemOwnership(ctx, userID, itemID)
+	item, _, err := s.verifyItemOwnership(ctx, userID, itemID)
 	if err != nil {
 		return err
 	}
 	return s.shoppingListRepo.DeleteItem(ctx, item.ID)
 }
 
+func (s *shoppingListService) ClearChecked(ctx context.Context, userID string, listID string) (int64, error) {
+	if _, err := s.verifyListOwnership(ctx, userID, listID); err != nil {
+		return 0, err
+	}
+	return s.shoppingListRepo.ArchiveCheckedItems(ctx, listID)
+}
+
 func (s *shoppingListService) ToggleItem(ctx context.Context, userID string, itemID string, checked bool) error {
-	item, err := s.verifyItemOwnership(ctx, userID, itemID)
+	item, list, err := s.verifyItemOwnership(ctx, userID, itemID)
 	if err != nil {
 		return err
 	}
+	learn := checked && !item.IsChecked
 	item.IsChecked = checked
-	return s.shoppingListRepo.UpdateItem(ctx, item)
+	if err := s.shoppingListRepo.UpdateItemFields(ctx, item.ID, map[string]interface{}{"is_checked": checked}); err != nil {
+		return err
+	}
+
+	// Learning the store's order is best-effort; the check-off itself has
+	// already been saved.
+	if learn && list.StoreID != nil {
+		if err := s.storeService.RecordCheckOff(ctx, list, item); err != nil {
+			s.logger.Warn("failed to learn store order from check-off", zap.String("item_id", item.ID), zap.Error(err))
+		}
+	}
+	return nil
 }
 
 func (s *shoppingListService) AddRecipeToList(ctx context.Context, userID string, listID string, req *domain.AddRecipeToListRequest) error {
@@ -307,30 +427,18 @@ func (s *shoppingListService) AddRecipeToList(ctx context.Context, userID string
 	}
 
 	// Categorize all items at once
-	categories := make(map[string]string)
-	if s.aiModel != nil {
-		if cats, err := s.aiModel.CategorizeItems(ctx, itemNames); err != nil {
-			s.logger.Warn("failed to classify items", zap.Error(err))
-		} else {
-			categories = cats
-		}
-	}
+	categories := s.categoryService.Categorize(ctx, itemNames)
 
 	// Create shopping list items from recipe ingredients
 	items := make([]domain.ShoppingListItem, len(recipe.Ingredients))
 	for i, ingredient := range recipe.Ingredients {
-		category := domain.CategoryOther
-		if cat, ok := categories[ingredient.Name]; ok {
-			category = domain.Category(cat)
-		}
-
 		items[i] = domain.ShoppingListItem{
 			ListID:   listID,
 			RecipeID: &recipe.ID,
 			Name:     ingredient.Name,
 			Amount:   ingredient.Amount * scalingFactor,
 			Unit:     ingredient.Unit,
-			Category: category,
+			Category: categories[ingredient.Name],
 			// Recipe ingredients don't carry free-text notes — set manually by the user after adding
 			Notes: "",
 		}
@@ -339,55 +447,43 @@ func (s *shoppingListService) AddRecipeToList(ctx context.Context, userID string
 	return s.shoppingListRepo.AddItems(ctx, items)
 }
 
-func (s *shoppingListService) GetSortedForStore(ctx context.Context, userID string, listID string, chainID string) (*domain.ShoppingList, error) {
+func (s *shoppingListService) GetSortedForStore(ctx context.Context, userID string, listID string, storeID string, chainID string) (*domain.ShoppingList, error) {
 	list, err := s.verifyListOwnership(ctx, userID, listID)
 	if err != nil {
 		return nil, err
 	}
 
+	if storeID == "" && chainID == "" {
+		storeID, chainID = derefString(list.StoreID), derefString(list.StoreChainID)
+	}
+
 	// Organize items according to store layout (sorts in-memory, doesn't persist)
-	if err := s.storeChainService.OrganizeShoppingList(ctx, list, chainID); err != nil {
+	switch {
+	case storeID != "":
+		err = s.storeService.OrganizeShoppingList(ctx, userID, list, storeID)
+	case chainID != "":
+		err = s.storeChainService.OrganizeShoppingList(ctx, list, chainID)
+	default:
+		err = errors.New("store_id or chain_id is required for a list without a store", "INVALID_INPUT")
+	}
+	if err != nil {
 		return nil, err
 	}
 
 	return list, nil
 }
 
-// sortItems sorts shopping list items based on the specified field and direction.
-// Unknown sortBy values fall back to name sort — callers should validate before invoking.
-func sortItems(items []domain.ShoppingListItem, sortBy string, sortDirection string) {
-	if len(items) == 0 {
-		return
-	}
-
-	sort.SliceStable(items, func(i, j int) bool {
-		var less bool
-
-		switch sortBy {
-		case "name":
-			less = items[i].Name < items[j].Name
-		case "category":
-			less = items[i].Category < items[j].Category
-		case "amount":
-			less = items[i].Amount < items[j].Amount
-		case "checked":
-			less = !items[i].IsChecked && items[j].IsChecked
-		case "created_at":
-			less = items[i].CreatedAt.Before(items[j].CreatedAt)
-		default:
-			less = items[i].Name < items[j].Name
-		}
-
-		if sortDirection == "desc" {
-			return !less
-		}
-		return less
-	})
-}
-
 // reverseItems reverses the order of items (for descending store order)
 func reverseItems(items []domain.ShoppingListItem) {
 	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
 		items[i], items[j] = items[j], items[i]
 	}
 }
+
+// derefString returns the value of an optional ID, or "" when it is unset.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -6,10 +6,12 @@ import (
 	"github.com/H3nSte1n/recipe/internal/domain"
 	internalErr "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/repository"
+	"github.com/H3nSte1n/recipe/pkg/ai"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"testing"
+	"time"
 )
 
 func itemIDs(items []domain.ShoppingListItem) []string {
@@ -62,8 +64,8 @@ func (m *mockShoppingListRepository) AddItems(ctx context.Context, items []domai
 	return args.Error(0)
 }
 
-func (m *mockShoppingListRepository) UpdateItem(ctx context.Context, item *domain.ShoppingListItem) error {
-	args := m.Called(ctx, item)
+func (m *mockShoppingListRepository) UpdateItemFields(ctx context.Context, id string, fields map[string]interface{}) error {
+	args := m.Called(ctx, id, fields)
 	return args.Error(0)
 }
 
@@ -72,6 +74,74 @@ func (m *mockShoppingListRepository) DeleteItem(ctx context.Context, id string)
 	return args.Error(0)
 }
 
+func (m *mockShoppingListRepository) ArchiveCheckedItems(ctx context.Context, listID string) (int64, error) {
+	args := m.Called(ctx, listID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockShoppingListRepository) ListTrashed(ctx context.Context, userID string) ([]domain.ShoppingList, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.ShoppingList)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) ListArchivedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.ShoppingListItem)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) ListTrashedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.ShoppingListItem)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) ListItemHistory(ctx context.Context, userID string, since time.Time, keys []string) ([]domain.ShoppingListItem, error) {
+	args := m.Called(ctx, userID, since, keys)
+	v, _ := args.Get(0).([]domain.ShoppingListItem)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) RankItemHistory(ctx context.Context, userID string, since time.Time, q string, limit int) ([]domain.ItemUsage, error) {
+	args := m.Called(ctx, userID, since, q, limit)
+	v, _ := args.Get(0).([]domain.ItemUsage)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) ListBoughtTogether(ctx context.Context, userID string, listID string, keys []string, since time.Time, minLists int, limit int) ([]domain.ItemUsage, error) {
+	args := m.Called(ctx, userID, listID, keys, since, minLists, limit)
+	v, _ := args.Get(0).([]domain.ItemUsage)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) GetTrashedByID(ctx context.Context, id string) (*domain.ShoppingList, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.ShoppingList)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) GetTrashedItemByID(ctx context.Context, id string) (*domain.ShoppingListItem, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.ShoppingListItem)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingListRepository) Restore(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockShoppingListRepository) RestoreItem(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockShoppingListRepository) PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // WithTypedTransaction runs the closure against the mock itself so the inner
 // Create/AddItems expectations fire exactly as before the tx wrapping.
 func (m *mockShoppingListRepository) WithTypedTransaction(ctx context.Context, fn func(repository.ShoppingListRepository) error) error {
@@ -115,6 +185,49 @@ func (m *mockStoreChainService) OrganizeShoppingList(ctx context.Context, list *
 	return args.Error(0)
 }
 
+type mockStoreService struct {
+	mock.Mock
+}
+
+func (m *mockStoreService) List(ctx context.Context, userID string) ([]domain.Store, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.Store)
+	return v, args.Error(1)
+}
+
+func (m *mockStoreService) Get(ctx context.Context, userID string, storeID string) (*domain.Store, error) {
+	args := m.Called(ctx, userID, storeID)
+	v, _ := args.Get(0).(*domain.Store)
+	return v, args.Error(1)
+}
+
+func (m *mockStoreService) Create(ctx context.Context, userID string, req *domain.CreateStoreRequest) (*domain.Store, error) {
+	args := m.Called(ctx, userID, req)
+	v, _ := args.Get(0).(*domain.Store)
+	return v, args.Error(1)
+}
+
+func (m *mockStoreService) Update(ctx context.Context, userID string, storeID string, req *domain.UpdateStoreRequest) (*domain.Store, error) {
+	args := m.Called(ctx, userID, storeID, req)
+	v, _ := args.Get(0).(*domain.Store)
+	return v, args.Error(1)
+}
+
+func (m *mockStoreService) Delete(ctx context.Context, userID string, storeID string) error {
+	args := m.Called(ctx, userID, storeID)
+	return args.Error(0)
+}
+
+func (m *mockStoreService) OrganizeShoppingList(ctx context.Context, userID string, list *domain.ShoppingList, storeID string) error {
+	args := m.Called(ctx, userID, list, storeID)
+	return args.Error(0)
+}
+
+func (m *mockStoreService) RecordCheckOff(ctx context.Context, list *domain.ShoppingList, item *domain.ShoppingListItem) error {
+	args := m.Called(ctx, list, item)
+	return args.Error(0)
+}
+
 type mockAIModel struct {
 	mock.Mock
 }
@@ -131,12 +244,40 @@ func (m *mockAIModel) ParseInstructions(ctx context.Context, content string) (*[
 	return v, args.Error(1)
 }
 
-func (m *mockAIModel) CategorizeItems(ctx context.Context, items []string) (map[string]string, error) {
-	args := m.Called(ctx, items)
+func (m *mockAIModel) CategorizeItems(ctx context.Context, items []string, categories []domain.GroceryCategory) (map[string]string, error) {
+	args := m.Called(ctx, items, categories)
 	v, _ := args.Get(0).(map[string]string)
 	return v, args.Error(1)
 }
 
+func (m *mockAIModel) SuggestSubstitutions(ctx context.Context, req ai.SubstitutionRequest) ([]domain.IngredientSubstitute, error) {
+	args := m.Called(ctx, req)
+	v, _ := args.Get(0).([]domain.IngredientSubstitute)
+	return v, args.Error(1)
+}
+
+func (m *mockAIModel) TranslateRecipe(ctx context.Context, content ai.RecipeTranslationContent, language string) (*ai.RecipeTranslationContent, error) {
+	args := m.Called(ctx, content, language)
+	v, _ := args.Get(0).(*ai.RecipeTranslationContent)
+	return v, args.Error(1)
+}
+
+func (m *mockAIModel) EditRecipe(ctx context.Context, recipe *domain.Recipe, instruction string) (*domain.Recipe, error) {
+	args := m.Called(ctx, recipe, instruction)
+	v, _ := args.Get(0).(*domain.Recipe)
+	return v, args.Error(1)
+}
+
+func (m *mockAIModel) Verify(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *mockAIModel) GenerateRecipe(ctx context.Context, req ai.RecipeGenerationRequest) (*domain.Recipe, error) {
+	args := m.Called(ctx, req)
+	v, _ := args.Get(0).(*domain.Recipe)
+	return v, args.Error(1)
+}
+
 func TestShoppingListService_Update(t *testing.T) {
 	var (
 		errGetShoppingList = errors.New("get shopping list error")
@@ -219,7 +360,7 @@ func TestShoppingListService_Update(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockShoppingListRepo(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			v, err := srv.Update(context.Background(), tt.userID, shoppingList.ID, &req)
 
 			if tt.expectedErr != nil {
@@ -283,7 +424,7 @@ func TestShoppingListService_Delete(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockMethod(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			err := srv.Delete(context.Background(), shoppingList.UserID, shoppingList.ID)
 
 			if tt.expectedErr != nil {
@@ -355,8 +496,8 @@ func TestShoppingListService_GetSorted(t *testing.T) {
 				sortBy = "invalid_field"
 			}
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
-			v, err := srv.GetSorted(context.Background(), tt.userID, shoppingList.ID, sortBy, "asc")
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
+			v, err := srv.GetSorted(context.Background(), tt.userID, shoppingList.ID, sortBy, "asc", nil)
 
 			if tt.expectedErr != nil {
 				require.ErrorIs(t, err, tt.expectedErr)
@@ -371,6 +512,94 @@ func TestShoppingListService_GetSorted(t *testing.T) {
 	}
 }
 
+func TestShoppingListService_GetSorted_MultiKey(t *testing.T) {
+	storeID := "store-1"
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recipeA, recipeB := "recipe-a", "recipe-b"
+	first, second := 0, 1
+	items := func() []domain.ShoppingListItem {
+		return []domain.ShoppingListItem{
+			{ID: "1", Name: "Zucchini", Category: "PRODUCE_VEGETABLES", CreatedAt: base},
+			{ID: "2", Name: "Eggs 10", Category: "DAIRY_EGGS", RecipeID: &recipeB, Position: &second, CreatedAt: base.Add(time.Minute)},
+			{ID: "3", Name: "Äpfel", Category: domain.CategoryProduce, IsChecked: true, CreatedAt: base.Add(2 * time.Minute)},
+			{ID: "4", Name: "Eggs 6", Category: "DAIRY_EGGS", RecipeID: &recipeA, CreatedAt: base.Add(3 * time.Minute)},
+			{ID: "5", Name: "apfel", Category: domain.CategoryProduce, CreatedAt: base.Add(4 * time.Minute)},
+			{ID: "6", Name: "Soap", Category: domain.CategoryHousehold, RecipeID: &recipeA, Position: &first, CreatedAt: base.Add(5 * time.Minute)},
+		}
+	}
+	store := &domain.Store{ID: storeID, Layout: []domain.StoreSection{
+		{Order: 1, Name: "Dairy", Categories: []domain.Category{domain.CategoryDairy}},
+		{Order: 2, Name: "Produce", Categories: []domain.Category{domain.CategoryProduce}},
+	}}
+
+	tests := []struct {
+		name          string
+		sortBy        string
+		sortDirection string
+		savedOrder    string
+		languages     []string
+		expected      []string
+	}{
+		{
+			name:      "puts checked items last, then follows the store's sections with unplaced categories at the end",
+			sortBy:    "checked,section,name",
+			languages: []string{"de-DE"},
+			expected:  []string{"4", "2", "5", "1", "6", "3"},
+		},
+		{
+			name:       "uses the list's saved sort order when none is asked for",
+			savedOrder: "checked,section,name",
+			languages:  []string{"de"},
+			expected:   []string{"4", "2", "5", "1", "6", "3"},
+		},
+		{
+			name:     "compares names ignoring case and accents, and numbers by value",
+			sortBy:   "name",
+			expected: []string{"5", "3", "4", "2", "6", "1"},
+		},
+		{
+			name:          "reverses every key for a descending sort",
+			sortBy:        "checked,name",
+			sortDirection: "desc",
+			expected:      []string{"3", "1", "6", "2", "4", "5"},
+		},
+		{
+			name:     "groups items by recipe in the order recipes were added, hand-added items first",
+			sortBy:   "recipe",
+			expected: []string{"1", "3", "5", "2", "4", "6"},
+		},
+		{
+			name:     "keeps the order set by hand, with items never placed after it in the order added",
+			sortBy:   "position",
+			expected: []string{"6", "2", "1", "3", "4", "5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockShoppingListRepository)
+			stores := new(mockStoreService)
+			m.On("GetByID", mock.Anything, "1_foo").Return(&domain.ShoppingList{ID: "1_foo", UserID: "123", StoreID: &storeID, SortOrder: tt.savedOrder, Items: items()}, nil)
+			stores.On("Get", mock.Anything, "123", storeID).Return(store, nil)
+
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), stores, newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
+			v, err := srv.GetSorted(context.Background(), "123", "1_foo", tt.sortBy, tt.sortDirection, tt.languages)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, itemIDs(v.Items))
+		})
+	}
+}
+
+func TestNormalizeSortOrder(t *testing.T) {
+	order, err := normalizeSortOrder(" checked, section ,,-name ")
+	require.NoError(t, err)
+	require.Equal(t, "checked,section,-name", order)
+
+	_, err = normalizeSortOrder("checked,price")
+	require.True(t, internalErr.IsInvalidInput(err))
+}
+
 func TestShoppingListService_GetSortedByStoreName(t *testing.T) {
 	var (
 		errGetByID              = errors.New("getByID error")
@@ -482,7 +711,7 @@ func TestShoppingListService_GetSortedByStoreName(t *testing.T) {
 				tt.mockStoreChainServiceFunc(mockStoreChainSrv)
 			}
 
-			srv := NewShoppingListService(mockShoppingListRepo, new(mockShoppingListRecipeRepository), mockStoreChainSrv, new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(mockShoppingListRepo, new(mockShoppingListRecipeRepository), mockStoreChainSrv, new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			v, err := srv.GetSortedByStoreName(context.Background(), tt.userID, shoppingList.ID, storeChain.Name, "", tt.sortDirection)
 
 			if tt.expectedErr != nil {
@@ -543,7 +772,7 @@ func TestShoppingListService_AddItem(t *testing.T) {
 				})).Return(nil).Once()
 			},
 			mockAiModelFunc: func(m *mockAIModel) {
-				m.On("CategorizeItems", mock.Anything, mock.Anything).Return(nil, errors.New("categorization error")).Once()
+				m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("categorization error")).Once()
 			},
 		},
 		{
@@ -557,7 +786,7 @@ func TestShoppingListService_AddItem(t *testing.T) {
 				})).Return(nil).Once()
 			},
 			mockAiModelFunc: func(m *mockAIModel) {
-				m.On("CategorizeItems", mock.Anything, mock.Anything).Return(map[string]string{"different-item": string(domain.CategoryDairy)}, nil).Once()
+				m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(map[string]string{"different-item": string(domain.CategoryDairy)}, nil).Once()
 			},
 		},
 		{
@@ -570,7 +799,7 @@ func TestShoppingListService_AddItem(t *testing.T) {
 				m.On("AddItems", mock.Anything, mock.Anything).Return(errAddItems).Once()
 			},
 			mockAiModelFunc: func(m *mockAIModel) {
-				m.On("CategorizeItems", mock.Anything, mock.Anything).Return(map[string]string{"foo": string(domain.CategoryDairy)}, nil).Once()
+				m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(map[string]string{"foo": string(domain.CategoryDairy)}, nil).Once()
 			},
 		},
 		{
@@ -584,7 +813,7 @@ func TestShoppingListService_AddItem(t *testing.T) {
 				})).Return(nil).Once()
 			},
 			mockAiModelFunc: func(m *mockAIModel) {
-				m.On("CategorizeItems", mock.Anything, mock.Anything).Return(map[string]string{"foo": string(domain.CategoryDairy)}, nil).Once()
+				m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(map[string]string{"foo": string(domain.CategoryDairy)}, nil).Once()
 			},
 		},
 	}
@@ -602,7 +831,7 @@ func TestShoppingListService_AddItem(t *testing.T) {
 				tt.mockAiModelFunc(mAIModel)
 			}
 
-			srv := NewShoppingListService(mShoppingListRepo, new(mockShoppingListRecipeRepository), new(mockStoreChainService), mAIModel, zap.NewNop())
+			srv := NewShoppingListService(mShoppingListRepo, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(mAIModel), nil, zap.NewNop())
 			err := srv.AddItem(context.Background(), tt.userID, shoppingList.ID, &tt.req)
 
 			if tt.expectedErr != nil {
@@ -620,7 +849,7 @@ func TestShoppingListService_UpdateItem(t *testing.T) {
 	var (
 		errGetItemByID = errors.New("GetItemByID error")
 		errGetByID     = errors.New("GetByID error")
-		errUpdateItem  = errors.New("UpdateItem error")
+		errUpdateItem  = errors.New("UpdateItemFields error")
 	)
 	req := domain.UpdateShoppingListItemRequest{Name: "foo_edited", Amount: 312, Unit: "bar", Category: domain.CategoryProduce, Notes: "foobar edited"}
 	list := domain.ShoppingList{ID: "1", UserID: "123"}
@@ -658,24 +887,24 @@ func TestShoppingListService_UpdateItem(t *testing.T) {
 			},
 		},
 		{
-			name:        "returns error when UpdateItem fails",
+			name:        "returns error when UpdateItemFields fails",
 			userID:      list.UserID,
 			expectedErr: errUpdateItem,
 			mockFunc: func(m *mockShoppingListRepository) {
 				m.On("GetItemByID", mock.Anything, item.ID).Return(&domain.ShoppingListItem{ID: item.ID, ListID: item.ListID}, nil).Once()
 				m.On("GetByID", mock.Anything, list.ID).Return(&domain.ShoppingList{ID: list.ID, UserID: list.UserID}, nil).Once()
-				m.On("UpdateItem", mock.Anything, mock.AnythingOfType("*domain.ShoppingListItem")).Return(errUpdateItem).Once()
+				m.On("UpdateItemFields", mock.Anything, item.ID, mock.Anything).Return(errUpdateItem).Once()
 			},
 		},
 		{
-			name:   "calls UpdateItem with Name, Amount, Unit, Category, Notes updated and returns nil when request is successfully",
+			name:   "updates only Name, Amount, Unit, Category, Notes and returns nil when request is successfully",
 			userID: list.UserID,
 			mockFunc: func(m *mockShoppingListRepository) {
 				m.On("GetItemByID", mock.Anything, item.ID).Return(&domain.ShoppingListItem{ID: item.ID, ListID: item.ListID}, nil).Once()
 				m.On("GetByID", mock.Anything, list.ID).Return(&domain.ShoppingList{ID: list.ID, UserID: list.UserID}, nil).Once()
-				m.On("UpdateItem", mock.Anything, mock.MatchedBy(func(r *domain.ShoppingListItem) bool {
-					return req.Name == r.Name && req.Amount == r.Amount && req.Unit == r.Unit && req.Category == r.Category && req.Notes == r.Notes
-				})).Return(nil).Once()
+				m.On("UpdateItemFields", mock.Anything, item.ID, map[string]interface{}{
+					"name": req.Name, "amount": req.Amount, "unit": req.Unit, "category": req.Category, "notes": req.Notes,
+				}).Return(nil).Once()
 			},
 		},
 	}
@@ -685,7 +914,7 @@ func TestShoppingListService_UpdateItem(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockFunc(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			err := srv.UpdateItem(context.Background(), tt.userID, item.ID, &req)
 
 			if tt.expectedErr != nil {
@@ -798,7 +1027,7 @@ func TestShoppingListService_Create(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockFunc(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			v, err := srv.Create(context.Background(), userID, &tt.req)
 
 			if tt.expectedErr != nil {
@@ -860,7 +1089,7 @@ func TestShoppingListService_GetByID(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockFunc(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			v, err := srv.GetByID(context.Background(), tt.userID, shoppingList.ID)
 
 			if tt.expectedErr != nil {
@@ -908,7 +1137,7 @@ func TestShoppingListService_ListByUserID(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockFunc(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			v, err := srv.ListByUserID(context.Background(), userID)
 
 			if tt.expectedErr != nil {
@@ -990,7 +1219,7 @@ func TestShoppingListService_DeleteItem(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockFunc(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			err := srv.DeleteItem(context.Background(), tt.userID, item.ID)
 
 			if tt.expectedErr != nil {
@@ -1003,11 +1232,39 @@ func TestShoppingListService_DeleteItem(t *testing.T) {
 	}
 }
 
+func TestShoppingListService_ClearChecked(t *testing.T) {
+	list := &domain.ShoppingList{ID: "1", UserID: "123"}
+
+	t.Run("clears the owner's checked items", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		m.On("GetByID", mock.Anything, list.ID).Return(list, nil).Once()
+		m.On("ArchiveCheckedItems", mock.Anything, list.ID).Return(int64(3), nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		cleared, err := srv.ClearChecked(context.Background(), list.UserID, list.ID)
+
+		require.NoError(t, err)
+		require.Equal(t, int64(3), cleared)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects other users' lists", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		m.On("GetByID", mock.Anything, list.ID).Return(list, nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		_, err := srv.ClearChecked(context.Background(), "wrong-user", list.ID)
+
+		require.ErrorIs(t, err, internalErr.ErrUnauthorized)
+		m.AssertNotCalled(t, "ArchiveCheckedItems", mock.Anything, mock.Anything)
+	})
+}
+
 func TestShoppingListService_ToggleItem(t *testing.T) {
 	var (
 		errGetItemByID = errors.New("GetItemByID error")
 		errGetByID     = errors.New("GetByID error")
-		errUpdateItem  = errors.New("UpdateItem error")
+		errUpdateItem  = errors.New("UpdateItemFields error")
 	)
 	list := domain.ShoppingList{ID: "1", UserID: "123"}
 	item := domain.ShoppingListItem{ID: "1_foo", ListID: list.ID, IsChecked: false}
@@ -1049,38 +1306,34 @@ func TestShoppingListService_ToggleItem(t *testing.T) {
 			},
 		},
 		{
-			name:        "returns error when UpdateItem fails",
+			name:        "returns error when UpdateItemFields fails",
 			userID:      list.UserID,
 			checked:     true,
 			expectedErr: errUpdateItem,
 			mockFunc: func(m *mockShoppingListRepository) {
 				m.On("GetItemByID", mock.Anything, item.ID).Return(&domain.ShoppingListItem{ID: item.ID, ListID: item.ListID}, nil).Once()
 				m.On("GetByID", mock.Anything, list.ID).Return(&domain.ShoppingList{ID: list.ID, UserID: list.UserID}, nil).Once()
-				m.On("UpdateItem", mock.Anything, mock.AnythingOfType("*domain.ShoppingListItem")).Return(errUpdateItem).Once()
+				m.On("UpdateItemFields", mock.Anything, item.ID, mock.Anything).Return(errUpdateItem).Once()
 			},
 		},
 		{
-			name:    "calls UpdateItem with IsChecked set to true and returns nil when request is successfully",
+			name:    "sets only is_checked to true and returns nil when request is successfully",
 			userID:  list.UserID,
 			checked: true,
 			mockFunc: func(m *mockShoppingListRepository) {
 				m.On("GetItemByID", mock.Anything, item.ID).Return(&domain.ShoppingListItem{ID: item.ID, ListID: item.ListID, IsChecked: false}, nil).Once()
 				m.On("GetByID", mock.Anything, list.ID).Return(&domain.ShoppingList{ID: list.ID, UserID: list.UserID}, nil).Once()
-				m.On("UpdateItem", mock.Anything, mock.MatchedBy(func(i *domain.ShoppingListItem) bool {
-					return i.IsChecked == true
-				})).Return(nil).Once()
+				m.On("UpdateItemFields", mock.Anything, item.ID, map[string]interface{}{"is_checked": true}).Return(nil).Once()
 			},
 		},
 		{
-			name:    "calls UpdateItem with IsChecked set to false and returns nil when request is successfully",
+			name:    "sets only is_checked to false and returns nil when request is successfully",
 			userID:  list.UserID,
 			checked: false,
 			mockFunc: func(m *mockShoppingListRepository) {
 				m.On("GetItemByID", mock.Anything, item.ID).Return(&domain.ShoppingListItem{ID: item.ID, ListID: item.ListID, IsChecked: true}, nil).Once()
 				m.On("GetByID", mock.Anything, list.ID).Return(&domain.ShoppingList{ID: list.ID, UserID: list.UserID}, nil).Once()
-				m.On("UpdateItem", mock.Anything, mock.MatchedBy(func(i *domain.ShoppingListItem) bool {
-					return i.IsChecked == false
-				})).Return(nil).Once()
+				m.On("UpdateItemFields", mock.Anything, item.ID, map[string]interface{}{"is_checked": false}).Return(nil).Once()
 			},
 		},
 	}
@@ -1090,7 +1343,7 @@ func TestShoppingListService_ToggleItem(t *testing.T) {
 			m := new(mockShoppingListRepository)
 			tt.mockFunc(m)
 
-			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockAIModel), zap.NewNop())
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
 			err := srv.ToggleItem(context.Background(), tt.userID, item.ID, tt.checked)
 
 			if tt.expectedErr != nil {
@@ -1103,6 +1356,48 @@ func TestShoppingListService_ToggleItem(t *testing.T) {
 	}
 }
 
+func TestShoppingListService_ToggleItem_LearnsStoreOrder(t *testing.T) {
+	storeID := "store-1"
+	list := &domain.ShoppingList{ID: "1", UserID: "123", StoreID: &storeID}
+
+	tests := []struct {
+		name       string
+		wasChecked bool
+		checked    bool
+		learnErr   error
+		learns     bool
+	}{
+		{name: "learns when an item is checked off", checked: true, learns: true},
+		{name: "ignores a failure to learn", checked: true, learnErr: errors.New("UpdatePosition error"), learns: true},
+		{name: "does not learn when an item is unchecked", wasChecked: true, checked: false},
+		{name: "does not learn when an item is checked again", wasChecked: true, checked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mockShoppingListRepository)
+			stores := new(mockStoreService)
+			m.On("GetItemByID", mock.Anything, "1_foo").Return(&domain.ShoppingListItem{ID: "1_foo", ListID: list.ID, IsChecked: tt.wasChecked}, nil).Once()
+			m.On("GetByID", mock.Anything, list.ID).Return(list, nil).Once()
+			m.On("UpdateItemFields", mock.Anything, "1_foo", mock.Anything).Return(nil).Once()
+			if tt.learns {
+				stores.On("RecordCheckOff", mock.Anything, list, mock.MatchedBy(func(i *domain.ShoppingListItem) bool {
+					return i.ID == "1_foo" && i.IsChecked
+				})).Return(tt.learnErr).Once()
+			}
+
+			srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), stores, newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
+			require.NoError(t, srv.ToggleItem(context.Background(), list.UserID, "1_foo", tt.checked))
+
+			m.AssertExpectations(t)
+			stores.AssertExpectations(t)
+			if !tt.learns {
+				stores.AssertNotCalled(t, "RecordCheckOff", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestShoppingListService_AddRecipeToList(t *testing.T) {
 	var (
 		errGetByID   = errors.New("getByID error")
@@ -1192,7 +1487,7 @@ func TestShoppingListService_AddRecipeToList(t *testing.T) {
 				m.On("GetByID", mock.Anything, recipe.ID, domain.NutritionDetailBase).Return(&recipe, nil).Once()
 			},
 			mockAiModelFunc: func(m *mockAIModel) {
-				m.On("CategorizeItems", mock.Anything, mock.Anything).Return(map[string]string{"Milk": string(domain.CategoryDairy), "Flour": string(domain.CategoryBakery)}, nil).Once()
+				m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(map[string]string{"Milk": string(domain.CategoryDairy), "Flour": string(domain.CategoryBakery)}, nil).Once()
 			},
 		},
 		{
@@ -1222,7 +1517,7 @@ func TestShoppingListService_AddRecipeToList(t *testing.T) {
 				m.On("GetByID", mock.Anything, recipe.ID, domain.NutritionDetailBase).Return(&recipe, nil).Once()
 			},
 			mockAiModelFunc: func(m *mockAIModel) {
-				m.On("CategorizeItems", mock.Anything, mock.Anything).Return(map[string]string{"Milk": string(domain.CategoryDairy), "Flour": string(domain.CategoryBakery)}, nil).Once()
+				m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(map[string]string{"Milk": string(domain.CategoryDairy), "Flour": string(domain.CategoryBakery)}, nil).Once()
 			},
 		},
 		{
@@ -1238,7 +1533,7 @@ func TestShoppingListService_AddRecipeToList(t *testing.T) {
 				m.On("GetByID", mock.Anything, recipe.ID, domain.NutritionDetailBase).Return(&recipe, nil).Once()
 			},
 			mockAiModelFunc: func(m *mockAIModel) {
-				m.On("CategorizeItems", mock.Anything, mock.Anything).Return(nil, errors.New("ai error")).Once()
+				m.On("CategorizeItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("ai error")).Once()
 			},
 		},
 	}
@@ -1259,7 +1554,7 @@ func TestShoppingListService_AddRecipeToList(t *testing.T) {
 				tt.mockAiModelFunc(mAIModel)
 			}
 
-			srv := NewShoppingListService(mShoppingListRepo, mRecipeRepo, new(mockStoreChainService), mAIModel, zap.NewNop())
+			srv := NewShoppingListService(mShoppingListRepo, mRecipeRepo, new(mockStoreChainService), new(mockStoreService), newTestCategoryService(mAIModel), nil, zap.NewNop())
 			err := srv.AddRecipeToList(context.Background(), tt.userID, list.ID, &req)
 
 			if tt.expectedErr != nil {
@@ -1350,8 +1645,8 @@ func TestShoppingListService_GetSortedForStore(t *testing.T) {
 				tt.mockStoreChainServiceFunc(mockStoreChainSrv)
 			}
 
-			srv := NewShoppingListService(mockShoppingListRepo, new(mockShoppingListRecipeRepository), mockStoreChainSrv, new(mockAIModel), zap.NewNop())
-			v, err := srv.GetSortedForStore(context.Background(), tt.userID, shoppingList.ID, chainID)
+			srv := NewShoppingListService(mockShoppingListRepo, new(mockShoppingListRecipeRepository), mockStoreChainSrv, new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
+			v, err := srv.GetSortedForStore(context.Background(), tt.userID, shoppingList.ID, "", chainID)
 
 			if tt.expectedErr != nil {
 				require.ErrorIs(t, err, tt.expectedErr)
@@ -1366,3 +1661,171 @@ func TestShoppingListService_GetSortedForStore(t *testing.T) {
 		})
 	}
 }
+
+func TestShoppingListService_GetSortedForStore_DefaultsToListStore(t *testing.T) {
+	storeID := "store-1"
+
+	t.Run("organizes by the list's own store", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		stores := new(mockStoreService)
+		m.On("GetByID", mock.Anything, "1_foo").Return(&domain.ShoppingList{ID: "1_foo", UserID: "123", StoreID: &storeID}, nil).Once()
+		stores.On("OrganizeShoppingList", mock.Anything, "123", mock.AnythingOfType("*domain.ShoppingList"), storeID).Return(nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), stores, newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
+		_, err := srv.GetSortedForStore(context.Background(), "123", "1_foo", "", "")
+
+		require.NoError(t, err)
+		stores.AssertExpectations(t)
+	})
+
+	t.Run("rejects a list without a store", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(&domain.ShoppingList{ID: "1_foo", UserID: "123"}, nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(new(mockAIModel)), nil, zap.NewNop())
+		_, err := srv.GetSortedForStore(context.Background(), "123", "1_foo", "", "")
+
+		require.True(t, internalErr.IsInvalidInput(err))
+	})
+}
+
+func TestShoppingListService_BatchItems(t *testing.T) {
+	newList := func() *domain.ShoppingList {
+		return &domain.ShoppingList{ID: "list-1", UserID: "123", Items: []domain.ShoppingListItem{
+			{ID: "a", ListID: "list-1", Name: "Tofu", Category: domain.CategoryOther},
+			{ID: "b", ListID: "list-1", Name: "Milk", Category: domain.CategoryDairy},
+			{ID: "c", ListID: "list-1", Name: "Soap", Category: domain.CategoryHousehold},
+		}}
+	}
+
+	t.Run("applies every operation in order", func(t *testing.T) {
+		m, ai := new(mockShoppingListRepository), new(mockAIModel)
+		m.On("GetByID", mock.Anything, "list-1").Return(newList(), nil).Twice()
+		m.On("GetByID", mock.Anything, "list-2").Return(&domain.ShoppingList{ID: "list-2", UserID: "123"}, nil).Once()
+		ai.On("CategorizeItems", mock.Anything, []string{"Tofu"}, mock.Anything).Return(map[string]string{"Tofu": string(domain.CategoryProduce)}, nil).Once()
+		for _, id := range []string{"a", "b", "c"} {
+			m.On("UpdateItemFields", mock.Anything, id, map[string]interface{}{"is_checked": true}).Return(nil).Once()
+		}
+		m.On("UpdateItemFields", mock.Anything, "c", map[string]interface{}{"list_id": "list-2", "position": nil}).Return(nil).Once()
+		m.On("UpdateItemFields", mock.Anything, "a", map[string]interface{}{"category": domain.CategoryProduce}).Return(nil).Once()
+		m.On("DeleteItem", mock.Anything, "b").Return(nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(ai), nil, zap.NewNop())
+		result, err := srv.BatchItems(context.Background(), "123", "list-1", &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{
+			{Op: domain.BatchOpCheck},
+			{Op: domain.BatchOpMove, ItemIDs: []string{"c"}, TargetListID: "list-2"},
+			{Op: domain.BatchOpDelete, ItemIDs: []string{"b", "b"}},
+			{Op: domain.BatchOpCategorize, ItemIDs: []string{"a"}},
+		}})
+
+		require.NoError(t, err)
+		require.True(t, result.Applied)
+		require.NotNil(t, result.List)
+		require.Equal(t, []string{"a", "b", "c"}, result.Results[0].ItemIDs)
+		require.Equal(t, []string{"b"}, result.Results[2].ItemIDs)
+		for _, r := range result.Results {
+			require.Equal(t, domain.BatchStatusApplied, r.Status)
+		}
+		m.AssertExpectations(t)
+		ai.AssertExpectations(t)
+	})
+
+	t.Run("rolls back the batch when an operation fails", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		m.On("GetByID", mock.Anything, "list-1").Return(newList(), nil).Once()
+		m.On("DeleteItem", mock.Anything, "a").Return(nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		result, err := srv.BatchItems(context.Background(), "123", "list-1", &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{
+			{Op: domain.BatchOpDelete, ItemIDs: []string{"a"}},
+			{Op: domain.BatchOpSetCategory, ItemIDs: []string{"a"}, Category: domain.CategoryProduce},
+			{Op: domain.BatchOpUncheck},
+		}})
+
+		require.True(t, internalErr.IsNotFound(err))
+		require.False(t, result.Applied)
+		require.Nil(t, result.List)
+		require.Equal(t, domain.BatchStatusRolledBack, result.Results[0].Status)
+		require.Equal(t, domain.BatchStatusFailed, result.Results[1].Status)
+		require.Contains(t, result.Results[1].Error, "item a is not on the list")
+		require.Equal(t, domain.BatchStatusSkipped, result.Results[2].Status)
+	})
+
+	t.Run("rejects deleting without item IDs", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		m.On("GetByID", mock.Anything, "list-1").Return(newList(), nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		_, err := srv.BatchItems(context.Background(), "123", "list-1", &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{{Op: domain.BatchOpDelete}}})
+
+		require.True(t, internalErr.IsInvalidInput(err))
+		m.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not move items to someone else's list", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		m.On("GetByID", mock.Anything, "list-1").Return(newList(), nil).Once()
+		m.On("GetByID", mock.Anything, "list-9").Return(&domain.ShoppingList{ID: "list-9", UserID: "999"}, nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		_, err := srv.BatchItems(context.Background(), "123", "list-1", &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{
+			{Op: domain.BatchOpMove, ItemIDs: []string{"a"}, TargetListID: "list-9"},
+		}})
+
+		require.ErrorIs(t, err, internalErr.ErrUnauthorized)
+		m.AssertNotCalled(t, "UpdateItemFields", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reorders the items given first and keeps the others' order", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		list := newList()
+		placed := 0
+		list.Items[0].Position = &placed
+		m.On("GetByID", mock.Anything, "list-1").Return(list, nil).Twice()
+		// a was placed first, so it follows c; b was never placed and comes
+		// last.
+		m.On("UpdateItemFields", mock.Anything, "c", map[string]interface{}{"position": 0}).Return(nil).Once()
+		m.On("UpdateItemFields", mock.Anything, "a", map[string]interface{}{"position": 1}).Return(nil).Once()
+		m.On("UpdateItemFields", mock.Anything, "b", map[string]interface{}{"position": 2}).Return(nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		result, err := srv.BatchItems(context.Background(), "123", "list-1", &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{
+			{Op: domain.BatchOpReorder, ItemIDs: []string{"c"}},
+		}})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"c"}, result.Results[0].ItemIDs)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("skips items already in place", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		list := newList()
+		for i := range list.Items {
+			list.Items[i].Position = &i
+		}
+		m.On("GetByID", mock.Anything, "list-1").Return(list, nil).Twice()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		_, err := srv.BatchItems(context.Background(), "123", "list-1", &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{
+			{Op: domain.BatchOpReorder, ItemIDs: []string{"a", "b"}},
+		}})
+
+		require.NoError(t, err)
+		m.AssertNotCalled(t, "UpdateItemFields", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects unknown categories", func(t *testing.T) {
+		m := new(mockShoppingListRepository)
+		m.On("GetByID", mock.Anything, "list-1").Return(newList(), nil).Once()
+
+		srv := NewShoppingListService(m, new(mockShoppingListRecipeRepository), new(mockStoreChainService), new(mockStoreService), newTestCategoryService(nil), nil, zap.NewNop())
+		result, err := srv.BatchItems(context.Background(), "123", "list-1", &domain.BatchItemsRequest{Operations: []domain.BatchItemOperation{
+			{Op: domain.BatchOpSetCategory, ItemIDs: []string{"a"}, Category: "NOT_A_CATEGORY"},
+		}})
+
+		require.True(t, internalErr.IsInvalidInput(err))
+		require.Contains(t, result.Results[0].Error, "unknown category NOT_A_CATEGORY")
+		m.AssertNotCalled(t, "UpdateItemFields", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
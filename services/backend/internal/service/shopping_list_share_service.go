@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/signedurl"
+	"go.uber.org/zap"
+)
+
+type shoppingListShareRepository interface {
+	Create(ctx context.Context, share *domain.ShoppingListShare) error
+	GetByID(ctx context.Context, id string) (*domain.ShoppingListShare, error)
+	ListByListID(ctx context.Context, listID string) ([]domain.ShoppingListShare, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time) (bool, error)
+}
+
+// sharedShoppingLists is the part of ShoppingListService a share link
+// reaches, always acting as the list's owner.
+type sharedShoppingLists interface {
+	GetByID(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error)
+	GetSections(ctx context.Context, userID string, listID string, languages []string) (*domain.ShoppingList, []domain.ListSection, error)
+	ToggleItem(ctx context.Context, userID string, itemID string, checked bool) error
+	Export(ctx context.Context, userID string, listID string, format string, languages []string) (*domain.ShoppingListExport, error)
+}
+
+// errShareNotFound is returned for every share link that does not work,
+// whatever the reason, so a link's holder learns nothing about the list.
+var errShareNotFound = errors.ErrNotFound.Wrap("share link not found or expired")
+
+type ShoppingListShareService interface {
+	// Create shares the user's list and returns the share with its link.
+	Create(ctx context.Context, userID string, listID string, req *domain.CreateShareRequest) (*domain.ShoppingListShare, error)
+	// List returns the list's shares with their links, newest first.
+	List(ctx context.Context, userID string, listID string) ([]domain.ShoppingListShare, error)
+	Revoke(ctx context.Context, userID string, listID string, shareID string) error
+	// GetShared returns the list a share link's token is for.
+	GetShared(ctx context.Context, token string, languages []string) (*domain.SharedShoppingList, error)
+	// ToggleSharedItem checks an item of a shared list on or off, for links
+	// with check access.
+	ToggleSharedItem(ctx context.Context, token string, itemID string, checked bool) error
+	ExportShared(ctx context.Context, token string, format string, languages []string) (*domain.ShoppingListExport, error)
+}
+
+type shoppingListShareService struct {
+	shareRepo     shoppingListShareRepository
+	shoppingLists sharedShoppingLists
+	signer        *signedurl.Signer
+	frontendURL   string
+	auditLog      *AuditLog
+	logger        *zap.Logger
+}
+
+func NewShoppingListShareService(shareRepo shoppingListShareRepository, shoppingLists sharedShoppingLists, signingSecret string, frontendURL string, auditLog *AuditLog, logger *zap.Logger) ShoppingListShareService {
+	if !strings.Contains(frontendURL, "://") {
+		frontendURL = "https://" + frontendURL
+	}
+	return &shoppingListShareService{
+		shareRepo:     shareRepo,
+		shoppingLists: shoppingLists,
+		// Share tokens do not expire by signature; shares keep their own
+		// expiry so they can be revoked.
+		signer:      signedurl.NewSigner(signingSecret, 0),
+		frontendURL: strings.TrimSuffix(frontendURL, "/"),
+		auditLog:    auditLog,
+		logger:      logger,
+	}
+}
+
+func (s *shoppingListShareService) Create(ctx context.Context, userID string, listID string, req *domain.CreateShareRequest) (*domain.ShoppingListShare, error) {
+	list, err := s.shoppingLists.GetByID(ctx, userID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future", "INVALID_INPUT")
+	}
+
+	share := &domain.ShoppingListShare{
+		ListID:    list.ID,
+		UserID:    userID,
+		Access:    req.Access,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if share.Access == "" {
+		share.Access = domain.ShareAccessView
+	}
+	if err := s.shareRepo.Create(ctx, share); err != nil {
+		return nil, err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditShoppingListShared,
+		TargetType: domain.AuditTargetShoppingList,
+		TargetID:   list.ID,
+		Changes:    domain.AuditChanges{"access": {To: share.Access}},
+	})
+
+	s.withLink(share)
+	return share, nil
+}
+
+func (s *shoppingListShareService) List(ctx context.Context, userID string, listID string) ([]domain.ShoppingListShare, error) {
+	if _, err := s.shoppingLists.GetByID(ctx, userID, listID); err != nil {
+		return nil, err
+	}
+	shares, err := s.shareRepo.ListByListID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		if shares[i].RevokedAt == nil {
+			s.withLink(&shares[i])
+		}
+	}
+	return shares, nil
+}
+
+func (s *shoppingListShareService) Revoke(ctx context.Context, userID string, listID string, shareID string) error {
+	if _, err := s.shoppingLists.GetByID(ctx, userID, listID); err != nil {
+		return err
+	}
+	share, err := s.shareRepo.GetByID(ctx, shareID)
+	if err != nil {
+		return err
+	}
+	if share.ListID != listID {
+		return errors.ErrNotFound.Wrap("share not found")
+	}
+
+	revoked, err := s.shareRepo.Revoke(ctx, shareID, time.Now())
+	if err != nil || !revoked {
+		return err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditShoppingListShareRevoked,
+		TargetType: domain.AuditTargetShoppingList,
+		TargetID:   listID,
+	})
+	return nil
+}
+
+func (s *shoppingListShareService) GetShared(ctx context.Context, token string, languages []string) (*domain.SharedShoppingList, error) {
+	share, err := s.resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	list, sections, err := s.shoppingLists.GetSections(ctx, share.UserID, share.ListID, languages)
+	if err != nil {
+		return nil, s.sharedListError(err)
+	}
+	return &domain.SharedShoppingList{
+		Name:      list.Name,
+		Access:    share.Access,
+		ExpiresAt: share.ExpiresAt,
+		Sections:  sections,
+	}, nil
+}
+
+func (s *shoppingListShareService) ToggleSharedItem(ctx context.Context, token string, itemID string, checked bool) error {
+	share, err := s.resolve(ctx, token)
+	if err != nil {
+		return err
+	}
+	if share.Access != domain.ShareAccessCheck {
+		return errors.ErrUnauthorized.Wrap("this link can only view the list")
+	}
+
+	list, err := s.shoppingLists.GetByID(ctx, share.UserID, share.ListID)
+	if err != nil {
+		return s.sharedListError(err)
+	}
+	for _, item := range list.Items {
+		if item.ID == itemID {
+			return s.shoppingLists.ToggleItem(ctx, share.UserID, itemID, checked)
+		}
+	}
+	return errors.ErrNotFound.Wrap("item not found")
+}
+
+func (s *shoppingListShareService) ExportShared(ctx context.Context, token string, format string, languages []string) (*domain.ShoppingListExport, error) {
+	share, err := s.resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	export, err := s.shoppingLists.Export(ctx, share.UserID, share.ListID, format, languages)
+	if err != nil {
+		return nil, s.sharedListError(err)
+	}
+	return export, nil
+}
+
+// resolve returns the active share token was signed for.
+func (s *shoppingListShareService) resolve(ctx context.Context, token string) (*domain.ShoppingListShare, error) {
+	id, err := s.signer.VerifyToken(token)
+	if err != nil {
+		return nil, errShareNotFound
+	}
+	share, err := s.shareRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errShareNotFound
+		}
+		return nil, err
+	}
+	if !share.Active(time.Now()) {
+		return nil, errShareNotFound
+	}
+	return share, nil
+}
+
+// sharedListError hides why a shared list could not be read, such as it
+// being in the trash, behind errShareNotFound.
+func (s *shoppingListShareService) sharedListError(err error) error {
+	if errors.IsNotFound(err) || errors.IsUnauthorized(err) {
+		return errShareNotFound
+	}
+	return err
+}
+
+// withLink fills in the token and link the share is reached with.
+func (s *shoppingListShareService) withLink(share *domain.ShoppingListShare) {
+	share.Token = s.signer.SignToken(share.ID)
+	share.URL = s.frontendURL + "/shared/shopping-list?token=" + url.QueryEscape(share.Token)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/signedurl"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockShareRepo struct {
+	mock.Mock
+}
+
+func (m *mockShareRepo) Create(ctx context.Context, share *domain.ShoppingListShare) error {
+	args := m.Called(ctx, share)
+	share.ID = "share-new"
+	return args.Error(0)
+}
+
+func (m *mockShareRepo) GetByID(ctx context.Context, id string) (*domain.ShoppingListShare, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.ShoppingListShare)
+	return v, args.Error(1)
+}
+
+func (m *mockShareRepo) ListByListID(ctx context.Context, listID string) ([]domain.ShoppingListShare, error) {
+	args := m.Called(ctx, listID)
+	v, _ := args.Get(0).([]domain.ShoppingListShare)
+	return v, args.Error(1)
+}
+
+func (m *mockShareRepo) ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListShare, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.ShoppingListShare)
+	return v, args.Error(1)
+}
+
+func (m *mockShareRepo) Revoke(ctx context.Context, id string, revokedAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockSharedShoppingLists struct {
+	mock.Mock
+}
+
+func (m *mockSharedShoppingLists) GetByID(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error) {
+	args := m.Called(ctx, userID, listID)
+	v, _ := args.Get(0).(*domain.ShoppingList)
+	return v, args.Error(1)
+}
+
+func (m *mockSharedShoppingLists) GetSections(ctx context.Context, userID string, listID string, languages []string) (*domain.ShoppingList, []domain.ListSection, error) {
+	args := m.Called(ctx, userID, listID, languages)
+	list, _ := args.Get(0).(*domain.ShoppingList)
+	sections, _ := args.Get(1).([]domain.ListSection)
+	return list, sections, args.Error(2)
+}
+
+func (m *mockSharedShoppingLists) ToggleItem(ctx context.Context, userID string, itemID string, checked bool) error {
+	args := m.Called(ctx, userID, itemID, checked)
+	return args.Error(0)
+}
+
+func (m *mockSharedShoppingLists) Export(ctx context.Context, userID string, listID string, format string, languages []string) (*domain.ShoppingListExport, error) {
+	args := m.Called(ctx, userID, listID, format, languages)
+	v, _ := args.Get(0).(*domain.ShoppingListExport)
+	return v, args.Error(1)
+}
+
+const testShareSecret = "share-secret"
+
+func newTestShareService(repo *mockShareRepo, lists *mockSharedShoppingLists) ShoppingListShareService {
+	return NewShoppingListShareService(repo, lists, testShareSecret, "app.example.com", nil, zap.NewNop())
+}
+
+func TestShoppingListShareService_Create(t *testing.T) {
+	t.Run("defaults to view access and links the token", func(t *testing.T) {
+		repo, lists := new(mockShareRepo), new(mockSharedShoppingLists)
+		lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(share *domain.ShoppingListShare) bool {
+			return share.ListID == "list-1" && share.UserID == "user-1" && share.Access == domain.ShareAccessView
+		})).Return(nil).Once()
+
+		share, err := newTestShareService(repo, lists).Create(context.Background(), "user-1", "list-1", &domain.CreateShareRequest{})
+
+		require.NoError(t, err)
+		id, err := signedurl.NewSigner(testShareSecret, 0).VerifyToken(share.Token)
+		require.NoError(t, err)
+		require.Equal(t, "share-new", id)
+		require.True(t, strings.HasPrefix(share.URL, "https://app.example.com/shared/shopping-list?token="))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an expiry in the past", func(t *testing.T) {
+		repo, lists := new(mockShareRepo), new(mockSharedShoppingLists)
+		lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+		past := time.Now().Add(-time.Hour)
+
+		_, err := newTestShareService(repo, lists).Create(context.Background(), "user-1", "list-1", &domain.CreateShareRequest{ExpiresAt: &past})
+
+		require.True(t, apperrors.IsInvalidInput(err))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestShoppingListShareService_Revoke_OnlyTheListsShares(t *testing.T) {
+	repo, lists := new(mockShareRepo), new(mockSharedShoppingLists)
+	lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+	repo.On("GetByID", mock.Anything, "share-2").Return(&domain.ShoppingListShare{ID: "share-2", ListID: "list-2"}, nil).Once()
+
+	err := newTestShareService(repo, lists).Revoke(context.Background(), "user-1", "list-1", "share-2")
+
+	require.ErrorIs(t, err, apperrors.ErrNotFound)
+	repo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShoppingListShareService_GetShared_RejectsDeadLinks(t *testing.T) {
+	signer := signedurl.NewSigner(testShareSecret, 0)
+	past := time.Now().Add(-time.Minute)
+	repo := new(mockShareRepo)
+	repo.On("GetByID", mock.Anything, "revoked").Return(&domain.ShoppingListShare{ID: "revoked", RevokedAt: &past}, nil)
+	repo.On("GetByID", mock.Anything, "expired").Return(&domain.ShoppingListShare{ID: "expired", ExpiresAt: &past}, nil)
+	repo.On("GetByID", mock.Anything, "gone").Return(nil, gorm.ErrRecordNotFound)
+	srv := newTestShareService(repo, new(mockSharedShoppingLists))
+
+	tokens := map[string]string{
+		"revoked":     signer.SignToken("revoked"),
+		"expired":     signer.SignToken("expired"),
+		"deleted":     signer.SignToken("gone"),
+		"other key":   signedurl.NewSigner("other-secret", 0).SignToken("revoked"),
+		"tampered id": "expired" + signer.SignToken("revoked")[len("revoked"):],
+		"malformed":   "not-a-token",
+	}
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			_, err := srv.GetShared(context.Background(), token, nil)
+			require.ErrorIs(t, err, apperrors.ErrNotFound)
+		})
+	}
+}
+
+func TestShoppingListShareService_GetShared(t *testing.T) {
+	repo, lists := new(mockShareRepo), new(mockSharedShoppingLists)
+	repo.On("GetByID", mock.Anything, "share-1").Return(&domain.ShoppingListShare{ID: "share-1", ListID: "list-1", UserID: "user-1", Access: domain.ShareAccessCheck}, nil).Once()
+	sections := []domain.ListSection{{Name: "Dairy", Items: []domain.ShoppingListItem{{ID: "item-1", Name: "Milk"}}}}
+	lists.On("GetSections", mock.Anything, "user-1", "list-1", []string{"de"}).Return(&domain.ShoppingList{ID: "list-1", Name: "Weekend"}, sections, nil).Once()
+
+	token := signedurl.NewSigner(testShareSecret, 0).SignToken("share-1")
+	shared, err := newTestShareService(repo, lists).GetShared(context.Background(), token, []string{"de"})
+
+	require.NoError(t, err)
+	require.Equal(t, "Weekend", shared.Name)
+	require.Equal(t, domain.ShareAccessCheck, shared.Access)
+	require.Equal(t, sections, shared.Sections)
+}
+
+func TestShoppingListShareService_ToggleSharedItem(t *testing.T) {
+	signer := signedurl.NewSigner(testShareSecret, 0)
+	list := &domain.ShoppingList{ID: "list-1", UserID: "user-1", Items: []domain.ShoppingListItem{{ID: "item-1"}}}
+
+	t.Run("checks off an item on the list", func(t *testing.T) {
+		repo, lists := new(mockShareRepo), new(mockSharedShoppingLists)
+		repo.On("GetByID", mock.Anything, "share-1").Return(&domain.ShoppingListShare{ID: "share-1", ListID: "list-1", UserID: "user-1", Access: domain.ShareAccessCheck}, nil).Once()
+		lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(list, nil).Once()
+		lists.On("ToggleItem", mock.Anything, "user-1", "item-1", true).Return(nil).Once()
+
+		err := newTestShareService(repo, lists).ToggleSharedItem(context.Background(), signer.SignToken("share-1"), "item-1", true)
+
+		require.NoError(t, err)
+		lists.AssertExpectations(t)
+	})
+
+	t.Run("refuses items on other lists", func(t *testing.T) {
+		repo, lists := new(mockShareRepo), new(mockSharedShoppingLists)
+		repo.On("GetByID", mock.Anything, "share-1").Return(&domain.ShoppingListShare{ID: "share-1", ListID: "list-1", UserID: "user-1", Access: domain.ShareAccessCheck}, nil).Once()
+		lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(list, nil).Once()
+
+		err := newTestShareService(repo, lists).ToggleSharedItem(context.Background(), signer.SignToken("share-1"), "item-9", true)
+
+		require.ErrorIs(t, err, apperrors.ErrNotFound)
+		lists.AssertNotCalled(t, "ToggleItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses view-only links", func(t *testing.T) {
+		repo, lists := new(mockShareRepo), new(mockSharedShoppingLists)
+		repo.On("GetByID", mock.Anything, "share-1").Return(&domain.ShoppingListShare{ID: "share-1", ListID: "list-1", UserID: "user-1", Access: domain.ShareAccessView}, nil).Once()
+
+		err := newTestShareService(repo, lists).ToggleSharedItem(context.Background(), signer.SignToken("share-1"), "item-1", true)
+
+		require.ErrorIs(t, err, apperrors.ErrUnauthorized)
+		lists.AssertNotCalled(t, "ToggleItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGroupSections(t *testing.T) {
+	layout := []domain.StoreSection{
+		{Name: "Fresh", Categories: []domain.Category{domain.CategoryProduce}},
+		{Name: "Cooler", Categories: []domain.Category{domain.CategoryDairy}},
+	}
+	items := []domain.ShoppingListItem{
+		{Name: "Apples", Category: domain.CategoryProduce},
+		{Name: "Milk", Category: domain.CategoryDairy},
+		{Name: "Cheese", Category: domain.CategoryDairy},
+		{Name: "Bread", Category: domain.CategoryBakery},
+	}
+
+	sections := groupSections(items, layout, nil, nil)
+
+	require.Len(t, sections, 3)
+	require.Equal(t, "Fresh", sections[0].Name)
+	require.Equal(t, "Cooler", sections[1].Name)
+	require.Len(t, sections[1].Items, 2)
+	require.Equal(t, string(domain.CategoryBakery), sections[2].Name)
+}
+
+func TestRenderShoppingList_EscapesItemNames(t *testing.T) {
+	sections := []domain.ListSection{{Name: "Dairy", Items: []domain.ShoppingListItem{
+		{Name: "<script>x</script> *Milk*", Amount: 2, Unit: "l"},
+		{Name: "Eggs", IsChecked: true},
+	}}}
+
+	markdown, err := renderShoppingList("Weekend", sections, domain.ExportFormatMarkdown)
+	require.NoError(t, err)
+	require.Equal(t, "Weekend.md", markdown.Filename)
+	require.Contains(t, string(markdown.Body), `- [ ] 2 l &lt;script&gt;x&lt;/script&gt; \*Milk\*`)
+	require.Contains(t, string(markdown.Body), "- [x] Eggs")
+
+	page, err := renderShoppingList("Weekend", sections, domain.ExportFormatHTML)
+	require.NoError(t, err)
+	require.NotContains(t, string(page.Body), "<script>")
+	require.Contains(t, string(page.Body), `<li class="checked">Eggs</li>`)
+
+	text, err := renderShoppingList("Weekend", sections, "")
+	require.NoError(t, err)
+	require.Equal(t, "Weekend\n\nDairy\n[ ] 2 l <script>x</script> *Milk*\n[x] Eggs\n", string(text.Body))
+
+	_, err = renderShoppingList("Weekend", sections, "pdf")
+	require.True(t, apperrors.IsInvalidInput(err))
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// sortKey is one key of a sort order such as "checked,section,-name".
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// parseSortOrder returns the keys of order it knows, in order, and the parts
+// of it that are not keys.
+func parseSortOrder(order string) (keys []sortKey, unknown []string) {
+	for _, part := range strings.Split(order, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := sortKey{field: strings.TrimPrefix(part, "-"), desc: strings.HasPrefix(part, "-")}
+		if !slices.Contains(domain.ItemSortKeys, key.field) {
+			unknown = append(unknown, part)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, unknown
+}
+
+// normalizeSortOrder checks a sort order a list is saved with and returns it
+// without spaces or empty keys.
+func normalizeSortOrder(order string) (string, error) {
+	keys, unknown := parseSortOrder(order)
+	if len(unknown) > 0 {
+		return "", errors.New(fmt.Sprintf("unknown sort key %q; sort by %s", unknown[0], strings.Join(domain.ItemSortKeys, ", ")), "INVALID_INPUT")
+	}
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key.field
+		if key.desc {
+			parts[i] = "-" + key.field
+		}
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// itemSorter sorts items by its keys in turn, then by when they were added,
+// so items that tie on every key always come in the same order.
+type itemSorter struct {
+	keys     []sortKey
+	collator *collate.Collator
+	// sectionOf places categories in the list's store; nil when the list has
+	// no store or chain.
+	sectionOf  func(domain.Category) (int, bool)
+	recipeRank map[string]int
+}
+
+// newItemSorter returns a sorter comparing names as the first of languages
+// does ("Äpfel" next to "Apfel" in German), ignoring case and with numbers
+// in names compared by value ("Eggs 6" before "Eggs 10").
+func newItemSorter(keys []sortKey, languages []string) *itemSorter {
+	return &itemSorter{
+		keys:     keys,
+		collator: collate.New(collationTag(languages), collate.IgnoreCase, collate.Numeric),
+	}
+}
+
+func (s *itemSorter) sort(items []domain.ShoppingListItem) {
+	s.recipeRank = recipeRanks(items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return s.compare(&items[i], &items[j]) < 0
+	})
+}
+
+func (s *itemSorter) compare(a *domain.ShoppingListItem, b *domain.ShoppingListItem) int {
+	for _, key := range s.keys {
+		c := s.compareBy(key.field, a, b)
+		if key.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+func (s *itemSorter) compareBy(field string, a *domain.ShoppingListItem, b *domain.ShoppingListItem) int {
+	switch field {
+	case domain.SortKeyChecked:
+		return compareBool(a.IsChecked, b.IsChecked)
+	case domain.SortKeySection:
+		return cmp.Compare(s.section(a.Category), s.section(b.Category))
+	case domain.SortKeyCategory:
+		return strings.Compare(string(a.Category), string(b.Category))
+	case domain.SortKeyName:
+		return s.collator.CompareString(a.Name, b.Name)
+	case domain.SortKeyRecipe:
+		return cmp.Compare(s.recipeRank[derefString(a.RecipeID)], s.recipeRank[derefString(b.RecipeID)])
+	case domain.SortKeyAmount:
+		return cmp.Compare(a.Amount, b.Amount)
+	case domain.SortKeyCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case domain.SortKeyPosition:
+		return comparePositions(a.Position, b.Position)
+	}
+	return 0
+}
+
+// comparePositions puts items the user placed by hand in their place and
+// items never placed after them.
+func comparePositions(a, b *int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return cmp.Compare(*a, *b)
+}
+
+// section returns the position of the store section items of category are
+// in, with categories the store does not place after all others.
+func (s *itemSorter) section(category domain.Category) int {
+	if s.sectionOf == nil {
+		return 0
+	}
+	if i, ok := s.sectionOf(category); ok {
+		return i
+	}
+	return math.MaxInt
+}
+
+// storeSections places categories in the sections of the list's store or,
+// failing that, its chain. It returns nil when the list has neither.
+func (s *shoppingListService) storeSections(ctx context.Context, userID string, list *domain.ShoppingList) (func(domain.Category) (int, bool), error) {
+	var layout []domain.StoreSection
+	switch {
+	case list.StoreID != nil:
+		store, err := s.storeService.Get(ctx, userID, *list.StoreID)
+		if err != nil {
+			return nil, err
+		}
+		layout = store.Layout
+	case list.StoreChainID != nil:
+		chain, err := s.storeChainService.GetChain(ctx, *list.StoreChainID)
+		if err != nil {
+			return nil, err
+		}
+		layout = chain.Layout
+	default:
+		return nil, nil
+	}
+
+	taxonomy, err := s.categoryService.Taxonomy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sectionLookup(layoutIndex(layout), taxonomy), nil
+}
+
+// layoutIndex maps each category layout places to its section's position.
+func layoutIndex(layout []domain.StoreSection) map[domain.Category]int {
+	index := make(map[domain.Category]int)
+	for i, section := range layout {
+		for _, category := range section.Categories {
+			index[category] = i
+		}
+	}
+	return index
+}
+
+// recipeRanks numbers the recipes items come from in the order their first
+// item was added. Items added by hand rank before all recipes.
+func recipeRanks(items []domain.ShoppingListItem) map[string]int {
+	first := make(map[string]time.Time)
+	for _, item := range items {
+		if item.RecipeID == nil {
+			continue
+		}
+		if added, ok := first[*item.RecipeID]; !ok || item.CreatedAt.Before(added) {
+			first[*item.RecipeID] = item.CreatedAt
+		}
+	}
+
+	recipes := make([]string, 0, len(first))
+	for id := range first {
+		recipes = append(recipes, id)
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		if c := first[recipes[i]].Compare(first[recipes[j]]); c != 0 {
+			return c < 0
+		}
+		return recipes[i] < recipes[j]
+	})
+
+	ranks := make(map[string]int, len(recipes))
+	for i, id := range recipes {
+		ranks[id] = i + 1
+	}
+	return ranks
+}
+
+// collationTag returns the first of languages that parses, or the root
+// collation's tag.
+func collationTag(languages []string) language.Tag {
+	for _, l := range languages {
+		if tag, err := language.Parse(l); err == nil {
+			return tag
+		}
+	}
+	return language.Und
+}
+
+func compareBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
+
+// sortItems sorts items by sortBy, a sort order such as "category" or
+// "checked,-name", without store sections and in the root collation.
+// sortDirection "desc" reverses every key. Orders without a known key sort by
+// name — callers should validate before invoking.
+func sortItems(items []domain.ShoppingListItem, sortBy string, sortDirection string) {
+	keys, _ := parseSortOrder(sortBy)
+	newItemSorter(directedKeys(keys, sortDirection), nil).sort(items)
+}
+
+// directedKeys returns keys, or a sort by name when there are none, reversed
+// when sortDirection is "desc".
+func directedKeys(keys []sortKey, sortDirection string) []sortKey {
+	if len(keys) == 0 {
+		keys = []sortKey{{field: domain.SortKeyName}}
+	}
+	if sortDirection == "desc" {
+		reversed := make([]sortKey, len(keys))
+		for i, key := range keys {
+			reversed[i] = sortKey{field: key.field, desc: !key.desc}
+		}
+		keys = reversed
+	}
+	return keys
+}
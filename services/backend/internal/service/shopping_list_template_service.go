@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"go.uber.org/zap"
+)
+
+// templateScheduleInterval is how often RunScheduler looks for templates that
+// are due.
+const templateScheduleInterval = 5 * time.Minute
+
+type shoppingListTemplateRepository interface {
+	Create(ctx context.Context, template *domain.ShoppingListTemplate) error
+	GetByID(ctx context.Context, id string) (*domain.ShoppingListTemplate, error)
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListTemplate, error)
+	Replace(ctx context.Context, template *domain.ShoppingListTemplate) error
+	Delete(ctx context.Context, id string) error
+	ListDue(ctx context.Context, now time.Time) ([]domain.ShoppingListTemplate, error)
+	ClaimRun(ctx context.Context, id string, scheduledAt time.Time, next *time.Time, ranAt time.Time) (bool, error)
+}
+
+type templateShoppingListRepository interface {
+	GetByID(ctx context.Context, listID string) (*domain.ShoppingList, error)
+	AddItems(ctx context.Context, items []domain.ShoppingListItem) error
+}
+
+type ShoppingListTemplateService interface {
+	List(ctx context.Context, userID string) ([]domain.ShoppingListTemplate, error)
+	Get(ctx context.Context, userID string, templateID string) (*domain.ShoppingListTemplate, error)
+	Create(ctx context.Context, userID string, req *domain.ShoppingListTemplateRequest) (*domain.ShoppingListTemplate, error)
+	// Update replaces the template, items included. Its schedule carries on
+	// unless the request changes when it starts.
+	Update(ctx context.Context, userID string, templateID string, req *domain.ShoppingListTemplateRequest) (*domain.ShoppingListTemplate, error)
+	Delete(ctx context.Context, userID string, templateID string) error
+	// Apply adds the template's staples to the list with listID, or to its
+	// target list, skipping those already on the list and not checked off.
+	Apply(ctx context.Context, userID string, templateID string, listID string) (*domain.ShoppingList, error)
+	// RunDue applies every template whose scheduled run is due to its target
+	// list and returns how many were applied. Each run is claimed before it
+	// is applied, so a run is never applied twice.
+	RunDue(ctx context.Context) (int, error)
+	// RunScheduler applies due templates once at start and then every few
+	// minutes until ctx is done.
+	RunScheduler(ctx context.Context)
+}
+
+type shoppingListTemplateService struct {
+	templateRepo     shoppingListTemplateRepository
+	shoppingListRepo templateShoppingListRepository
+	categoryService  CategoryService
+	logger           *zap.Logger
+}
+
+func NewShoppingListTemplateService(templateRepo shoppingListTemplateRepository, shoppingListRepo templateShoppingListRepository, categoryService CategoryService, logger *zap.Logger) ShoppingListTemplateService {
+	return &shoppingListTemplateService{
+		templateRepo:     templateRepo,
+		shoppingListRepo: shoppingListRepo,
+		categoryService:  categoryService,
+		logger:           logger,
+	}
+}
+
+func (s *shoppingListTemplateService) List(ctx context.Context, userID string) ([]domain.ShoppingListTemplate, error) {
+	return s.templateRepo.ListByUserID(ctx, userID)
+}
+
+func (s *shoppingListTemplateService) Get(ctx context.Context, userID string, templateID string) (*domain.ShoppingListTemplate, error) {
+	return s.verifyTemplateOwnership(ctx, userID, templateID)
+}
+
+func (s *shoppingListTemplateService) Create(ctx context.Context, userID string, req *domain.ShoppingListTemplateRequest) (*domain.ShoppingListTemplate, error) {
+	template := &domain.ShoppingListTemplate{UserID: userID}
+	if err := s.applyRequest(ctx, userID, template, req); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *shoppingListTemplateService) Update(ctx context.Context, userID string, templateID string, req *domain.ShoppingListTemplateRequest) (*domain.ShoppingListTemplate, error) {
+	template, err := s.verifyTemplateOwnership(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(ctx, userID, template, req); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Replace(ctx, template); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.GetByID(ctx, templateID)
+}
+
+// applyRequest sets template up as req describes it: its target list must be
+// the user's, and staples without a category are categorized.
+func (s *shoppingListTemplateService) applyRequest(ctx context.Context, userID string, template *domain.ShoppingListTemplate, req *domain.ShoppingListTemplateRequest) error {
+	if req.Frequency != "" && req.TargetListID == "" {
+		return errors.New("a recurring template needs a target list", "INVALID_INPUT")
+	}
+	template.TargetListID = nil
+	if req.TargetListID != "" {
+		list, err := s.shoppingListRepo.GetByID(ctx, req.TargetListID)
+		if err != nil {
+			return err
+		}
+		if list.UserID != userID {
+			return errors.ErrUnauthorized
+		}
+		template.TargetListID = &list.ID
+	}
+
+	wasRecurring := template.Frequency != ""
+	template.Name = req.Name
+	template.Frequency = req.Frequency
+	template.Every = req.Every
+	if template.Every == 0 {
+		template.Every = 1
+	}
+	switch {
+	case template.Frequency == "":
+		template.NextRunAt = nil
+	case req.StartsAt != nil:
+		startsAt := *req.StartsAt
+		template.NextRunAt = &startsAt
+	case !wasRecurring || template.NextRunAt == nil:
+		now := time.Now()
+		template.NextRunAt = &now
+	}
+
+	names := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		if item.Category == "" {
+			names = append(names, item.Name)
+		}
+	}
+	var categories map[string]domain.Category
+	if len(names) > 0 {
+		categories = s.categoryService.Categorize(ctx, names)
+	}
+
+	template.Items = make([]domain.ShoppingListTemplateItem, len(req.Items))
+	for i, item := range req.Items {
+		category := item.Category
+		if category == "" {
+			category = categories[item.Name]
+		}
+		template.Items[i] = domain.ShoppingListTemplateItem{
+			Name:     item.Name,
+			Amount:   item.Amount,
+			Unit:     item.Unit,
+			Category: category,
+			Notes:    item.Notes,
+			Position: i,
+		}
+	}
+	return nil
+}
+
+func (s *shoppingListTemplateService) Delete(ctx context.Context, userID string, templateID string) error {
+	if _, err := s.verifyTemplateOwnership(ctx, userID, templateID); err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(ctx, templateID)
+}
+
+func (s *shoppingListTemplateService) Apply(ctx context.Context, userID string, templateID string, listID string) (*domain.ShoppingList, error) {
+	template, err := s.verifyTemplateOwnership(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if listID == "" {
+		if template.TargetListID == nil {
+			return nil, errors.New("list_id is required for a template without a target list", "INVALID_INPUT")
+		}
+		listID = *template.TargetListID
+	}
+
+	list, err := s.shoppingListRepo.GetByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
+	if _, err := s.addStaples(ctx, list, template); err != nil {
+		return nil, err
+	}
+	return s.shoppingListRepo.GetByID(ctx, listID)
+}
+
+// addStaples adds the template's items to list, except those it already has
+// unchecked, and returns how many were added.
+func (s *shoppingListTemplateService) addStaples(ctx context.Context, list *domain.ShoppingList, template *domain.ShoppingListTemplate) (int, error) {
+	present := make(map[string]bool, len(list.Items))
+	for _, item := range list.Items {
+		if !item.IsChecked {
+			present[itemKey(item.Name)] = true
+		}
+	}
+
+	var items []domain.ShoppingListItem
+	for _, staple := range template.Items {
+		key := itemKey(staple.Name)
+		if present[key] {
+			continue
+		}
+		present[key] = true
+		items = append(items, domain.ShoppingListItem{
+			ListID:   list.ID,
+			Name:     staple.Name,
+			Amount:   staple.Amount,
+			Unit:     staple.Unit,
+			Category: staple.Category,
+			Notes:    staple.Notes,
+		})
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+	return len(items), s.shoppingListRepo.AddItems(ctx, items)
+}
+
+func (s *shoppingListTemplateService) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := s.templateRepo.ListDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	var ran int
+	for i := range due {
+		template := &due[i]
+		claimed, err := s.templateRepo.ClaimRun(ctx, template.ID, *template.NextRunAt, template.NextRunAfter(*template.NextRunAt, now), now)
+		if err != nil {
+			return ran, err
+		}
+		if !claimed {
+			continue
+		}
+
+		// A target list in the trash, or no longer the owner's, skips the
+		// run; the template carries on with the next.
+		list, err := s.shoppingListRepo.GetByID(ctx, *template.TargetListID)
+		if err != nil || list.UserID != template.UserID {
+			s.logger.Warn("skipped template run without its target list", zap.String("templateID", template.ID), zap.Error(err))
+			continue
+		}
+		added, err := s.addStaples(ctx, list, template)
+		if err != nil {
+			s.logger.Error("failed to add staples to list", zap.String("templateID", template.ID), zap.Error(err))
+			continue
+		}
+		s.logger.Debug("applied template", zap.String("templateID", template.ID), zap.Int("added", added))
+		ran++
+	}
+	return ran, nil
+}
+
+func (s *shoppingListTemplateService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(templateScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := s.RunDue(ctx)
+		if err != nil {
+			s.logger.Error("failed to run due shopping list templates", zap.Error(err))
+		} else if ran > 0 {
+			s.logger.Info("ran due shopping list templates", zap.Int("ran", ran))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *shoppingListTemplateService) verifyTemplateOwnership(ctx context.Context, userID string, templateID string) (*domain.ShoppingListTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
+	return template, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockTemplateRepo struct {
+	mock.Mock
+}
+
+func (m *mockTemplateRepo) Create(ctx context.Context, template *domain.ShoppingListTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *mockTemplateRepo) GetByID(ctx context.Context, id string) (*domain.ShoppingListTemplate, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.ShoppingListTemplate)
+	return v, args.Error(1)
+}
+
+func (m *mockTemplateRepo) ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingListTemplate, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.ShoppingListTemplate)
+	return v, args.Error(1)
+}
+
+func (m *mockTemplateRepo) Replace(ctx context.Context, template *domain.ShoppingListTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *mockTemplateRepo) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockTemplateRepo) ListDue(ctx context.Context, now time.Time) ([]domain.ShoppingListTemplate, error) {
+	args := m.Called(ctx, now)
+	v, _ := args.Get(0).([]domain.ShoppingListTemplate)
+	return v, args.Error(1)
+}
+
+func (m *mockTemplateRepo) ClaimRun(ctx context.Context, id string, scheduledAt time.Time, next *time.Time, ranAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, scheduledAt, next, ranAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestShoppingListTemplateService_Create(t *testing.T) {
+	t.Run("categorizes staples and schedules the first run", func(t *testing.T) {
+		repo, lists := new(mockTemplateRepo), new(mockShoppingListRepository)
+		lists.On("GetByID", mock.Anything, "list-1").Return(&domain.ShoppingList{ID: "list-1", UserID: "user-1"}, nil).Once()
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewShoppingListTemplateService(repo, lists, newTestCategoryService(nil), zap.NewNop())
+		template, err := srv.Create(context.Background(), "user-1", &domain.ShoppingListTemplateRequest{
+			Name: "Weekly", TargetListID: "list-1", Frequency: domain.FrequencyWeekly,
+			Items: []domain.TemplateItemRequest{{Name: "Eggs"}, {Name: "Bread", Category: domain.CategoryBakery}},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 1, template.Every)
+		require.NotNil(t, template.NextRunAt)
+		require.Equal(t, domain.Category("DAIRY_EGGS"), template.Items[0].Category)
+		require.Equal(t, domain.CategoryBakery, template.Items[1].Category)
+		require.Equal(t, 1, template.Items[1].Position)
+	})
+
+	t.Run("needs a target list to recur", func(t *testing.T) {
+		repo := new(mockTemplateRepo)
+
+		srv := NewShoppingListTemplateService(repo, new(mockShoppingListRepository), newTestCategoryService(nil), zap.NewNop())
+		_, err := srv.Create(context.Background(), "user-1", &domain.ShoppingListTemplateRequest{
+			Name: "Weekly", Frequency: domain.FrequencyDaily, Every: 3, Items: []domain.TemplateItemRequest{{Name: "Milk"}},
+		})
+
+		require.True(t, apperrors.IsInvalidInput(err))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects another user's target list", func(t *testing.T) {
+		lists := new(mockShoppingListRepository)
+		lists.On("GetByID", mock.Anything, "list-2").Return(&domain.ShoppingList{ID: "list-2", UserID: "someone-else"}, nil).Once()
+
+		srv := NewShoppingListTemplateService(new(mockTemplateRepo), lists, newTestCategoryService(nil), zap.NewNop())
+		_, err := srv.Create(context.Background(), "user-1", &domain.ShoppingListTemplateRequest{
+			Name: "Weekly", TargetListID: "list-2", Items: []domain.TemplateItemRequest{{Name: "Milk"}},
+		})
+
+		require.ErrorIs(t, err, apperrors.ErrUnauthorized)
+	})
+}
+
+func TestShoppingListTemplateService_Apply_SkipsItemsStillOnTheList(t *testing.T) {
+	repo, lists := new(mockTemplateRepo), new(mockShoppingListRepository)
+	repo.On("GetByID", mock.Anything, "tmpl-1").Return(&domain.ShoppingListTemplate{ID: "tmpl-1", UserID: "user-1", Items: []domain.ShoppingListTemplateItem{
+		{Name: "Milk", Category: domain.CategoryDairy},
+		{Name: "Bread", Category: domain.CategoryBakery},
+		{Name: "Eggs", Amount: 10, Category: domain.CategoryDairy},
+	}}, nil).Once()
+	list := &domain.ShoppingList{ID: "list-1", UserID: "user-1", Items: []domain.ShoppingListItem{
+		{Name: "milk ", Category: domain.CategoryDairy},
+		{Name: "Bread", Category: domain.CategoryBakery, IsChecked: true},
+	}}
+	lists.On("GetByID", mock.Anything, "list-1").Return(list, nil).Twice()
+	lists.On("AddItems", mock.Anything, mock.MatchedBy(func(items []domain.ShoppingListItem) bool {
+		return len(items) == 2 && items[0].Name == "Bread" && items[1].Name == "Eggs" && items[1].Amount == 10 && items[1].ListID == "list-1"
+	})).Return(nil).Once()
+
+	srv := NewShoppingListTemplateService(repo, lists, newTestCategoryService(nil), zap.NewNop())
+	_, err := srv.Apply(context.Background(), "user-1", "tmpl-1", "list-1")
+
+	require.NoError(t, err)
+	lists.AssertExpectations(t)
+}
+
+func TestShoppingListTemplateService_Apply_NeedsAList(t *testing.T) {
+	repo := new(mockTemplateRepo)
+	repo.On("GetByID", mock.Anything, "tmpl-1").Return(&domain.ShoppingListTemplate{ID: "tmpl-1", UserID: "user-1"}, nil).Once()
+
+	srv := NewShoppingListTemplateService(repo, new(mockShoppingListRepository), newTestCategoryService(nil), zap.NewNop())
+	_, err := srv.Apply(context.Background(), "user-1", "tmpl-1", "")
+
+	require.True(t, apperrors.IsInvalidInput(err))
+}
+
+func TestShoppingListTemplateService_RunDue(t *testing.T) {
+	scheduled := time.Now().Add(-time.Minute)
+	target, trashed := "list-1", "list-2"
+	staples := []domain.ShoppingListTemplateItem{{Name: "Milk", Category: domain.CategoryDairy}}
+	repo, lists := new(mockTemplateRepo), new(mockShoppingListRepository)
+	repo.On("ListDue", mock.Anything, mock.Anything).Return([]domain.ShoppingListTemplate{
+		{ID: "mine", UserID: "user-1", TargetListID: &target, Frequency: domain.FrequencyWeekly, Every: 1, NextRunAt: &scheduled, Items: staples},
+		{ID: "taken", UserID: "user-1", TargetListID: &target, Frequency: domain.FrequencyDaily, Every: 1, NextRunAt: &scheduled, Items: staples},
+		{ID: "orphaned", UserID: "user-1", TargetListID: &trashed, Frequency: domain.FrequencyDaily, Every: 2, NextRunAt: &scheduled, Items: staples},
+	}, nil).Once()
+	nextWeek := mock.MatchedBy(func(next *time.Time) bool { return next.Equal(scheduled.AddDate(0, 0, 7)) })
+	repo.On("ClaimRun", mock.Anything, "mine", scheduled, nextWeek, mock.Anything).Return(true, nil).Once()
+	repo.On("ClaimRun", mock.Anything, "taken", scheduled, mock.Anything, mock.Anything).Return(false, nil).Once()
+	repo.On("ClaimRun", mock.Anything, "orphaned", scheduled, mock.Anything, mock.Anything).Return(true, nil).Once()
+	lists.On("GetByID", mock.Anything, target).Return(&domain.ShoppingList{ID: target, UserID: "user-1"}, nil).Once()
+	lists.On("GetByID", mock.Anything, trashed).Return(nil, gorm.ErrRecordNotFound).Once()
+	lists.On("AddItems", mock.Anything, mock.Anything).Return(nil).Once()
+
+	srv := NewShoppingListTemplateService(repo, lists, newTestCategoryService(nil), zap.NewNop())
+	ran, err := srv.RunDue(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 1, ran)
+	repo.AssertExpectations(t)
+	lists.AssertExpectations(t)
+}
+
+func TestShoppingListTemplate_NextRunAfter_SkipsMissedRuns(t *testing.T) {
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+
+	weekly := &domain.ShoppingListTemplate{Frequency: domain.FrequencyWeekly, Every: 1}
+	require.Equal(t, time.Date(2026, 3, 23, 8, 0, 0, 0, time.UTC), *weekly.NextRunAfter(start, now))
+
+	everyFiveDays := &domain.ShoppingListTemplate{Frequency: domain.FrequencyDaily, Every: 5}
+	require.Equal(t, time.Date(2026, 3, 22, 8, 0, 0, 0, time.UTC), *everyFiveDays.NextRunAfter(start, now))
+
+	require.Nil(t, (&domain.ShoppingListTemplate{Every: 1}).NextRunAfter(start, now))
+}
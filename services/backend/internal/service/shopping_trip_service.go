@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"go.uber.org/zap"
+)
+
+type shoppingTripRepository interface {
+	Create(ctx context.Context, trip *domain.ShoppingTrip) error
+	GetByID(ctx context.Context, id string) (*domain.ShoppingTrip, error)
+	GetOpenByListID(ctx context.Context, listID string) (*domain.ShoppingTrip, error)
+	ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingTrip, error)
+	Delete(ctx context.Context, id string) error
+	SaveItem(ctx context.Context, item *domain.ShoppingTripItem) error
+	DeleteItem(ctx context.Context, tripID string, itemID string) (bool, error)
+	Finish(ctx context.Context, tripID string, finishedAt time.Time) (bool, error)
+	ListFinished(ctx context.Context, userID string, from time.Time, to time.Time) ([]domain.ShoppingTrip, error)
+	ListPrices(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.PricePoint, error)
+}
+
+// tripShoppingLists is the part of ShoppingListService a trip shops from.
+type tripShoppingLists interface {
+	GetByID(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error)
+	ToggleItem(ctx context.Context, userID string, itemID string, checked bool) error
+}
+
+type ShoppingTripService interface {
+	Start(ctx context.Context, userID string, req *domain.StartTripRequest) (*domain.ShoppingTrip, error)
+	Get(ctx context.Context, userID string, tripID string) (*domain.ShoppingTrip, error)
+	List(ctx context.Context, userID string) ([]domain.ShoppingTrip, error)
+	// RecordItem records what was paid for an item on an open trip and checks
+	// it off the trip's list.
+	RecordItem(ctx context.Context, userID string, tripID string, req *domain.RecordTripItemRequest) (*domain.ShoppingTripItem, error)
+	RemoveItem(ctx context.Context, userID string, tripID string, itemID string) error
+	// Finish closes the trip and fixes its totals.
+	Finish(ctx context.Context, userID string, tripID string) (*domain.ShoppingTrip, error)
+	Delete(ctx context.Context, userID string, tripID string) error
+	Spending(ctx context.Context, userID string, filter domain.SpendingFilter) ([]domain.SpendingBucket, error)
+	PriceHistory(ctx context.Context, userID string, name string) ([]domain.PricePoint, error)
+}
+
+type shoppingTripService struct {
+	tripRepo        shoppingTripRepository
+	shoppingLists   tripShoppingLists
+	storeService    StoreService
+	categoryService CategoryService
+	logger          *zap.Logger
+}
+
+func NewShoppingTripService(tripRepo shoppingTripRepository, shoppingLists tripShoppingLists, storeService StoreService, categoryService CategoryService, logger *zap.Logger) ShoppingTripService {
+	return &shoppingTripService{
+		tripRepo:        tripRepo,
+		shoppingLists:   shoppingLists,
+		storeService:    storeService,
+		categoryService: categoryService,
+		logger:          logger,
+	}
+}
+
+func (s *shoppingTripService) Start(ctx context.Context, userID string, req *domain.StartTripRequest) (*domain.ShoppingTrip, error) {
+	list, err := s.shoppingLists.GetByID(ctx, userID, req.ListID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.tripRepo.GetOpenByListID(ctx, list.ID); err == nil {
+		return nil, errors.New("list already has an open trip", "CONFLICT")
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	trip := &domain.ShoppingTrip{
+		UserID:    userID,
+		ListID:    &list.ID,
+		Currency:  strings.ToUpper(req.Currency),
+		StartedAt: time.Now(),
+	}
+	if trip.Currency == "" {
+		trip.Currency = domain.DefaultTripCurrency
+	}
+
+	storeID := req.StoreID
+	if storeID == "" && list.StoreID != nil {
+		storeID = *list.StoreID
+	}
+	if storeID != "" {
+		store, err := s.storeService.Get(ctx, userID, storeID)
+		if err != nil {
+			return nil, err
+		}
+		trip.StoreID = &store.ID
+		trip.StoreName = store.Name
+	}
+
+	if err := s.tripRepo.Create(ctx, trip); err != nil {
+		return nil, err
+	}
+	return trip, nil
+}
+
+func (s *shoppingTripService) Get(ctx context.Context, userID string, tripID string) (*domain.ShoppingTrip, error) {
+	return s.verifyTripOwnership(ctx, userID, tripID)
+}
+
+func (s *shoppingTripService) List(ctx context.Context, userID string) ([]domain.ShoppingTrip, error) {
+	return s.tripRepo.ListByUserID(ctx, userID)
+}
+
+func (s *shoppingTripService) RecordItem(ctx context.Context, userID string, tripID string, req *domain.RecordTripItemRequest) (*domain.ShoppingTripItem, error) {
+	trip, err := s.verifyOpenTrip(ctx, userID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &domain.ShoppingTripItem{
+		TripID:     trip.ID,
+		Name:       strings.TrimSpace(req.Name),
+		Category:   req.Category,
+		Quantity:   req.Quantity,
+		Unit:       req.Unit,
+		PriceCents: req.PriceCents,
+	}
+
+	var listItem *domain.ShoppingListItem
+	if req.ListItemID != "" {
+		if listItem, err = s.tripListItem(ctx, userID, trip, req.ListItemID); err != nil {
+			return nil, err
+		}
+		item.ListItemID = &listItem.ID
+		item.Name = listItem.Name
+		item.Category = listItem.Category
+		if item.Quantity == 0 {
+			item.Quantity, item.Unit = listItem.Amount, listItem.Unit
+		}
+	}
+
+	if item.Name == "" {
+		return nil, errors.New("an item needs a name or a list item", "INVALID_INPUT")
+	}
+	if item.Category == "" {
+		item.Category = s.categoryService.Categorize(ctx, []string{item.Name})[item.Name]
+	}
+	item.ItemKey = itemKey(item.Name)
+
+	if err := s.tripRepo.SaveItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if listItem != nil && !listItem.IsChecked {
+		if err := s.shoppingLists.ToggleItem(ctx, userID, listItem.ID, true); err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
+}
+
+// tripListItem finds the item with itemID on the list trip is shopping.
+func (s *shoppingTripService) tripListItem(ctx context.Context, userID string, trip *domain.ShoppingTrip, itemID string) (*domain.ShoppingListItem, error) {
+	if trip.ListID == nil {
+		return nil, errors.New("trip's list no longer exists", "INVALID_INPUT")
+	}
+	list, err := s.shoppingLists.GetByID(ctx, userID, *trip.ListID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		if list.Items[i].ID == itemID {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, errors.ErrNotFound.Wrap("list item not found on the trip's list")
+}
+
+func (s *shoppingTripService) RemoveItem(ctx context.Context, userID string, tripID string, itemID string) error {
+	if _, err := s.verifyOpenTrip(ctx, userID, tripID); err != nil {
+		return err
+	}
+
+	removed, err := s.tripRepo.DeleteItem(ctx, tripID, itemID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.ErrNotFound.Wrap("trip item not found")
+	}
+	return nil
+}
+
+func (s *shoppingTripService) Finish(ctx context.Context, userID string, tripID string) (*domain.ShoppingTrip, error) {
+	if _, err := s.verifyOpenTrip(ctx, userID, tripID); err != nil {
+		return nil, err
+	}
+
+	finished, err := s.tripRepo.Finish(ctx, tripID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !finished {
+		return nil, errors.New("trip is already finished", "CONFLICT")
+	}
+	return s.tripRepo.GetByID(ctx, tripID)
+}
+
+func (s *shoppingTripService) Delete(ctx context.Context, userID string, tripID string) error {
+	if _, err := s.verifyTripOwnership(ctx, userID, tripID); err != nil {
+		return err
+	}
+	return s.tripRepo.Delete(ctx, tripID)
+}
+
+func (s *shoppingTripService) Spending(ctx context.Context, userID string, filter domain.SpendingFilter) ([]domain.SpendingBucket, error) {
+	var taxonomy *domain.CategoryTaxonomy
+	switch filter.GroupBy {
+	case domain.SpendingByMonth, domain.SpendingByStore:
+	case domain.SpendingByCategory:
+		var err error
+		if taxonomy, err = s.categoryService.Taxonomy(ctx); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("group_by must be month, category or store", "INVALID_INPUT")
+	}
+
+	trips, err := s.tripRepo.ListFinished(ctx, userID, filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	return groupSpending(trips, filter.GroupBy, taxonomy), nil
+}
+
+func (s *shoppingTripService) PriceHistory(ctx context.Context, userID string, name string) ([]domain.PricePoint, error) {
+	key := itemKey(name)
+	if key == "" {
+		return nil, errors.New("name is required", "INVALID_INPUT")
+	}
+	return s.tripRepo.ListPrices(ctx, userID, "", []string{key})
+}
+
+// groupSpending adds up trips into buckets by groupBy, one per key and
+// currency, ordered by key. Spending by category rolls subcategories up into
+// their top-level category.
+func groupSpending(trips []domain.ShoppingTrip, groupBy string, taxonomy *domain.CategoryTaxonomy) []domain.SpendingBucket {
+	type bucketKey struct{ key, currency string }
+	buckets := make(map[bucketKey]*domain.SpendingBucket)
+	bucket := func(key, currency string) *domain.SpendingBucket {
+		k := bucketKey{key, currency}
+		if buckets[k] == nil {
+			buckets[k] = &domain.SpendingBucket{Key: key, Currency: currency}
+		}
+		return buckets[k]
+	}
+
+	for _, trip := range trips {
+		switch groupBy {
+		case domain.SpendingByCategory:
+			counted := make(map[string]bool)
+			for _, item := range trip.Items {
+				key := string(topLevelCategory(item.Category, taxonomy))
+				b := bucket(key, trip.Currency)
+				b.TotalCents += item.PriceCents
+				b.ItemCount++
+				if !counted[key] {
+					counted[key] = true
+					b.TripCount++
+				}
+			}
+			continue
+		case domain.SpendingByStore:
+			b := bucket(trip.StoreName, trip.Currency)
+			b.TotalCents, b.TripCount, b.ItemCount = b.TotalCents+trip.TotalCents, b.TripCount+1, b.ItemCount+trip.ItemCount
+		default:
+			b := bucket(trip.FinishedAt.Format("2006-01"), trip.Currency)
+			b.TotalCents, b.TripCount, b.ItemCount = b.TotalCents+trip.TotalCents, b.TripCount+1, b.ItemCount+trip.ItemCount
+		}
+	}
+
+	result := make([]domain.SpendingBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Key != result[j].Key {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result
+}
+
+func topLevelCategory(category domain.Category, taxonomy *domain.CategoryTaxonomy) domain.Category {
+	if lineage := taxonomy.Lineage(category); len(lineage) > 0 {
+		return lineage[len(lineage)-1]
+	}
+	return category
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func roundCents(cents float64) int64 {
+	return int64(cents + 0.5)
+}
+
+func (s *shoppingTripService) verifyTripOwnership(ctx context.Context, userID string, tripID string) (*domain.ShoppingTrip, error) {
+	trip, err := s.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.UserID != userID {
+		return nil, errors.ErrUnauthorized
+	}
+	return trip, nil
+}
+
+func (s *shoppingTripService) verifyOpenTrip(ctx context.Context, userID string, tripID string) (*domain.ShoppingTrip, error) {
+	trip, err := s.verifyTripOwnership(ctx, userID, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if !trip.IsOpen() {
+		return nil, errors.New("trip is already finished", "CONFLICT")
+	}
+	return trip, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockShoppingTripRepo struct {
+	mock.Mock
+}
+
+func (m *mockShoppingTripRepo) Create(ctx context.Context, trip *domain.ShoppingTrip) error {
+	args := m.Called(ctx, trip)
+	return args.Error(0)
+}
+
+func (m *mockShoppingTripRepo) GetByID(ctx context.Context, id string) (*domain.ShoppingTrip, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.ShoppingTrip)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingTripRepo) GetOpenByListID(ctx context.Context, listID string) (*domain.ShoppingTrip, error) {
+	args := m.Called(ctx, listID)
+	v, _ := args.Get(0).(*domain.ShoppingTrip)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingTripRepo) ListByUserID(ctx context.Context, userID string) ([]domain.ShoppingTrip, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.ShoppingTrip)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingTripRepo) ListWithItems(ctx context.Context, userID string) ([]domain.ShoppingTrip, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.ShoppingTrip)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingTripRepo) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockShoppingTripRepo) SaveItem(ctx context.Context, item *domain.ShoppingTripItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *mockShoppingTripRepo) DeleteItem(ctx context.Context, tripID string, itemID string) (bool, error) {
+	args := m.Called(ctx, tripID, itemID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockShoppingTripRepo) Finish(ctx context.Context, tripID string, finishedAt time.Time) (bool, error) {
+	args := m.Called(ctx, tripID, finishedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockShoppingTripRepo) ListFinished(ctx context.Context, userID string, from time.Time, to time.Time) ([]domain.ShoppingTrip, error) {
+	args := m.Called(ctx, userID, from, to)
+	v, _ := args.Get(0).([]domain.ShoppingTrip)
+	return v, args.Error(1)
+}
+
+func (m *mockShoppingTripRepo) ListPrices(ctx context.Context, userID string, storeChainID string, itemKeys []string) ([]domain.PricePoint, error) {
+	args := m.Called(ctx, userID, storeChainID, itemKeys)
+	v, _ := args.Get(0).([]domain.PricePoint)
+	return v, args.Error(1)
+}
+
+type mockTripShoppingLists struct {
+	mock.Mock
+}
+
+func (m *mockTripShoppingLists) GetByID(ctx context.Context, userID string, listID string) (*domain.ShoppingList, error) {
+	args := m.Called(ctx, userID, listID)
+	v, _ := args.Get(0).(*domain.ShoppingList)
+	return v, args.Error(1)
+}
+
+func (m *mockTripShoppingLists) ToggleItem(ctx context.Context, userID string, itemID string, checked bool) error {
+	args := m.Called(ctx, userID, itemID, checked)
+	return args.Error(0)
+}
+
+func newTestShoppingTripService(repo *mockShoppingTripRepo, lists *mockTripShoppingLists, stores *mockStoreService) ShoppingTripService {
+	return NewShoppingTripService(repo, lists, stores, newTestCategoryService(nil), zap.NewNop())
+}
+
+func TestShoppingTripService_Start(t *testing.T) {
+	storeID := "store-1"
+	list := &domain.ShoppingList{ID: "list-1", UserID: "user-1", StoreID: &storeID}
+
+	t.Run("shops the list's store in the default currency", func(t *testing.T) {
+		repo, lists, stores := new(mockShoppingTripRepo), new(mockTripShoppingLists), new(mockStoreService)
+		lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(list, nil).Once()
+		repo.On("GetOpenByListID", mock.Anything, "list-1").Return(nil, gorm.ErrRecordNotFound).Once()
+		stores.On("Get", mock.Anything, "user-1", storeID).Return(&domain.Store{ID: storeID, Name: "Corner"}, nil).Once()
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := newTestShoppingTripService(repo, lists, stores)
+		trip, err := srv.Start(context.Background(), "user-1", &domain.StartTripRequest{ListID: "list-1"})
+
+		require.NoError(t, err)
+		require.Equal(t, "Corner", trip.StoreName)
+		require.Equal(t, domain.DefaultTripCurrency, trip.Currency)
+		require.True(t, trip.IsOpen())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a second open trip for the list", func(t *testing.T) {
+		repo, lists := new(mockShoppingTripRepo), new(mockTripShoppingLists)
+		lists.On("GetByID", mock.Anything, "user-1", "list-1").Return(list, nil).Once()
+		repo.On("GetOpenByListID", mock.Anything, "list-1").Return(&domain.ShoppingTrip{ID: "trip-0"}, nil).Once()
+
+		srv := newTestShoppingTripService(repo, lists, new(mockStoreService))
+		_, err := srv.Start(context.Background(), "user-1", &domain.StartTripRequest{ListID: "list-1", Currency: "usd"})
+
+		require.True(t, apperrors.IsConflict(err))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestShoppingTripService_RecordItem(t *testing.T) {
+	listID := "list-1"
+	trip := &domain.ShoppingTrip{ID: "trip-1", UserID: "user-1", ListID: &listID, Currency: "EUR"}
+	list := &domain.ShoppingList{ID: listID, UserID: "user-1", Items: []domain.ShoppingListItem{
+		{ID: "item-1", Name: "Milk", Amount: 2, Unit: "l", Category: domain.CategoryDairy},
+	}}
+
+	t.Run("prices the list item and checks it off", func(t *testing.T) {
+		repo, lists := new(mockShoppingTripRepo), new(mockTripShoppingLists)
+		repo.On("GetByID", mock.Anything, "trip-1").Return(trip, nil).Once()
+		lists.On("GetByID", mock.Anything, "user-1", listID).Return(list, nil).Once()
+		repo.On("SaveItem", mock.Anything, mock.MatchedBy(func(item *domain.ShoppingTripItem) bool {
+			return *item.ListItemID == "item-1" && item.ItemKey == "milk" && item.Quantity == 2 && item.Unit == "l" && item.PriceCents == 198
+		})).Return(nil).Once()
+		lists.On("ToggleItem", mock.Anything, "user-1", "item-1", true).Return(nil).Once()
+
+		srv := newTestShoppingTripService(repo, lists, nil)
+		_, err := srv.RecordItem(context.Background(), "user-1", "trip-1", &domain.RecordTripItemRequest{ListItemID: "item-1", PriceCents: 198})
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+		lists.AssertExpectations(t)
+	})
+
+	t.Run("categorizes purchases that were not on the list", func(t *testing.T) {
+		repo := new(mockShoppingTripRepo)
+		repo.On("GetByID", mock.Anything, "trip-1").Return(trip, nil).Once()
+		repo.On("SaveItem", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := newTestShoppingTripService(repo, new(mockTripShoppingLists), nil)
+		item, err := srv.RecordItem(context.Background(), "user-1", "trip-1", &domain.RecordTripItemRequest{Name: " Onions ", PriceCents: 80})
+
+		require.NoError(t, err)
+		require.Equal(t, domain.Category("PRODUCE_VEGETABLES"), item.Category)
+		require.Equal(t, "onions", item.ItemKey)
+	})
+
+	t.Run("rejects finished trips", func(t *testing.T) {
+		finishedAt := time.Now()
+		repo := new(mockShoppingTripRepo)
+		repo.On("GetByID", mock.Anything, "trip-2").Return(&domain.ShoppingTrip{ID: "trip-2", UserID: "user-1", FinishedAt: &finishedAt}, nil).Once()
+
+		srv := newTestShoppingTripService(repo, new(mockTripShoppingLists), nil)
+		_, err := srv.RecordItem(context.Background(), "user-1", "trip-2", &domain.RecordTripItemRequest{Name: "Gum"})
+
+		require.True(t, apperrors.IsConflict(err))
+	})
+
+	t.Run("rejects other users' trips", func(t *testing.T) {
+		repo := new(mockShoppingTripRepo)
+		repo.On("GetByID", mock.Anything, "trip-1").Return(trip, nil).Once()
+
+		srv := newTestShoppingTripService(repo, new(mockTripShoppingLists), nil)
+		_, err := srv.RecordItem(context.Background(), "someone-else", "trip-1", &domain.RecordTripItemRequest{Name: "Gum"})
+
+		require.ErrorIs(t, err, apperrors.ErrUnauthorized)
+	})
+}
+
+func TestShoppingTripService_Spending(t *testing.T) {
+	jan, feb := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	trips := []domain.ShoppingTrip{
+		{StoreName: "Corner", Currency: "EUR", TotalCents: 500, ItemCount: 2, FinishedAt: &jan, Items: []domain.ShoppingTripItem{
+			{Category: "PRODUCE_VEGETABLES", PriceCents: 200},
+			{Category: domain.CategoryProduce, PriceCents: 300},
+		}},
+		{StoreName: "Market", Currency: "EUR", TotalCents: 700, ItemCount: 1, FinishedAt: &feb, Items: []domain.ShoppingTripItem{
+			{Category: "DAIRY_EGGS", PriceCents: 700},
+		}},
+		{StoreName: "Corner", Currency: "USD", TotalCents: 100, ItemCount: 1, FinishedAt: &feb, Items: []domain.ShoppingTripItem{
+			{Category: "DAIRY_EGGS", PriceCents: 100},
+		}},
+	}
+	spending := func(groupBy string) []domain.SpendingBucket {
+		repo := new(mockShoppingTripRepo)
+		repo.On("ListFinished", mock.Anything, "user-1", time.Time{}, time.Time{}).Return(trips, nil).Once()
+		srv := newTestShoppingTripService(repo, nil, nil)
+		buckets, err := srv.Spending(context.Background(), "user-1", domain.SpendingFilter{GroupBy: groupBy})
+		require.NoError(t, err)
+		return buckets
+	}
+
+	require.Equal(t, []domain.SpendingBucket{
+		{Key: "2026-01", Currency: "EUR", TotalCents: 500, TripCount: 1, ItemCount: 2},
+		{Key: "2026-02", Currency: "EUR", TotalCents: 700, TripCount: 1, ItemCount: 1},
+		{Key: "2026-02", Currency: "USD", TotalCents: 100, TripCount: 1, ItemCount: 1},
+	}, spending(domain.SpendingByMonth))
+
+	// Subcategories are rolled up into their top-level category.
+	require.Equal(t, []domain.SpendingBucket{
+		{Key: "DAIRY", Currency: "EUR", TotalCents: 700, TripCount: 1, ItemCount: 1},
+		{Key: "DAIRY", Currency: "USD", TotalCents: 100, TripCount: 1, ItemCount: 1},
+		{Key: "PRODUCE", Currency: "EUR", TotalCents: 500, TripCount: 1, ItemCount: 2},
+	}, spending(domain.SpendingByCategory))
+
+	require.Equal(t, []domain.SpendingBucket{
+		{Key: "Corner", Currency: "EUR", TotalCents: 500, TripCount: 1, ItemCount: 2},
+		{Key: "Corner", Currency: "USD", TotalCents: 100, TripCount: 1, ItemCount: 1},
+		{Key: "Market", Currency: "EUR", TotalCents: 700, TripCount: 1, ItemCount: 1},
+	}, spending(domain.SpendingByStore))
+}
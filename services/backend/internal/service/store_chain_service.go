@@ -22,14 +22,16 @@ type StoreChainService interface {
 }
 
 type storeChainService struct {
-	storeChainRepo storeChainRepository
-	logger         *zap.Logger
+	storeChainRepo  storeChainRepository
+	categoryService CategoryService
+	logger          *zap.Logger
 }
 
-func NewStoreChainService(storeChainRepo storeChainRepository, logger *zap.Logger) StoreChainService {
+func NewStoreChainService(storeChainRepo storeChainRepository, categoryService CategoryService, logger *zap.Logger) StoreChainService {
 	return &storeChainService{
-		storeChainRepo: storeChainRepo,
-		logger:         logger,
+		storeChainRepo:  storeChainRepo,
+		categoryService: categoryService,
+		logger:          logger,
 	}
 }
 
@@ -51,23 +53,29 @@ func (s *storeChainService) OrganizeShoppingList(ctx context.Context, list *doma
 		return err
 	}
 
-	organizeByChain(list, chain)
+	taxonomy, err := s.categoryService.Taxonomy(ctx)
+	if err != nil {
+		return err
+	}
+
+	organizeByChain(list, chain, taxonomy)
 	return nil
 }
 
 // organizeByChain sorts list.Items in-place according to the store chain's section layout.
-// Items whose category is not found in the layout are moved to the end.
-func organizeByChain(list *domain.ShoppingList, chain *domain.StoreChain) {
+// Items whose category and its parents are all missing from the layout are moved to the end.
+func organizeByChain(list *domain.ShoppingList, chain *domain.StoreChain, taxonomy *domain.CategoryTaxonomy) {
 	sectionOrder := make(map[domain.Category]int)
 	for _, section := range chain.Layout {
 		for _, category := range section.Categories {
 			sectionOrder[category] = section.Order
 		}
 	}
+	orderOf := sectionLookup(sectionOrder, taxonomy)
 
 	sort.SliceStable(list.Items, func(i, j int) bool {
-		orderI, okI := sectionOrder[list.Items[i].Category]
-		orderJ, okJ := sectionOrder[list.Items[j].Category]
+		orderI, okI := orderOf(list.Items[i].Category)
+		orderJ, okJ := orderOf(list.Items[j].Category)
 
 		if !okI {
 			orderI = math.MaxInt
@@ -83,3 +91,18 @@ func organizeByChain(list *domain.ShoppingList, chain *domain.StoreChain) {
 		return i < j
 	})
 }
+
+// sectionLookup returns a lookup of the section value a layout assigns a
+// category. A category the layout does not list falls into the section of its
+// nearest listed parent, so a layout of top-level categories also places
+// their subcategories.
+func sectionLookup(sections map[domain.Category]int, taxonomy *domain.CategoryTaxonomy) func(domain.Category) (int, bool) {
+	return func(category domain.Category) (int, bool) {
+		for _, c := range taxonomy.Lineage(category) {
+			if v, ok := sections[c]; ok {
+				return v, true
+			}
+		}
+		return 0, false
+	}
+}
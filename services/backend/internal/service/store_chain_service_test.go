@@ -32,12 +32,27 @@ func (m *mockStoreChainRepo) ListChains(ctx context.Context, country string) ([]
 	return v, args.Error(1)
 }
 
+func (m *mockStoreChainRepo) CreateChain(ctx context.Context, chain *domain.StoreChain) error {
+	args := m.Called(ctx, chain)
+	return args.Error(0)
+}
+
+func (m *mockStoreChainRepo) UpdateChain(ctx context.Context, chain *domain.StoreChain) error {
+	args := m.Called(ctx, chain)
+	return args.Error(0)
+}
+
+func (m *mockStoreChainRepo) DeleteChain(ctx context.Context, chainID string) (bool, error) {
+	args := m.Called(ctx, chainID)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestStoreChainService_GetChain_Success(t *testing.T) {
 	storeChain := domain.StoreChain{ID: "1_foo"}
 	m := new(mockStoreChainRepo)
 	m.On("GetChain", mock.Anything, storeChain.ID).Return(&storeChain, nil).Once()
 
-	srv := NewStoreChainService(m, zap.NewNop())
+	srv := NewStoreChainService(m, newTestCategoryService(nil), zap.NewNop())
 	v, err := srv.GetChain(context.Background(), storeChain.ID)
 
 	require.NoError(t, err)
@@ -51,7 +66,7 @@ func TestStoreChainService_GetChain_Error(t *testing.T) {
 	m := new(mockStoreChainRepo)
 	m.On("GetChain", mock.Anything, storeChainID).Return(nil, expectedErr).Once()
 
-	srv := NewStoreChainService(m, zap.NewNop())
+	srv := NewStoreChainService(m, newTestCategoryService(nil), zap.NewNop())
 	v, err := srv.GetChain(context.Background(), storeChainID)
 
 	require.ErrorIs(t, err, expectedErr)
@@ -66,7 +81,7 @@ func TestStoreChainService_GetChainByName_Success(t *testing.T) {
 	m := new(mockStoreChainRepo)
 	m.On("GetChainByName", mock.Anything, name, country).Return(&storeChain, nil).Once()
 
-	srv := NewStoreChainService(m, zap.NewNop())
+	srv := NewStoreChainService(m, newTestCategoryService(nil), zap.NewNop())
 	v, err := srv.GetChainByName(context.Background(), name, country)
 
 	require.NoError(t, err)
@@ -81,7 +96,7 @@ func TestStoreChainService_GetChainByName_Error(t *testing.T) {
 	m := new(mockStoreChainRepo)
 	m.On("GetChainByName", mock.Anything, name, country).Return(nil, expectedErr).Once()
 
-	srv := NewStoreChainService(m, zap.NewNop())
+	srv := NewStoreChainService(m, newTestCategoryService(nil), zap.NewNop())
 	v, err := srv.GetChainByName(context.Background(), name, country)
 
 	require.ErrorIs(t, err, expectedErr)
@@ -95,7 +110,7 @@ func TestStoreChainService_ListChains_Success(t *testing.T) {
 	m := new(mockStoreChainRepo)
 	m.On("ListChains", mock.Anything, country).Return(storeChains, nil).Once()
 
-	srv := NewStoreChainService(m, zap.NewNop())
+	srv := NewStoreChainService(m, newTestCategoryService(nil), zap.NewNop())
 	v, err := srv.ListChains(context.Background(), country)
 
 	require.NoError(t, err)
@@ -109,7 +124,7 @@ func TestStoreChainService_ListChains_Error(t *testing.T) {
 	m := new(mockStoreChainRepo)
 	m.On("ListChains", mock.Anything, country).Return(nil, expectedErr).Once()
 
-	srv := NewStoreChainService(m, zap.NewNop())
+	srv := NewStoreChainService(m, newTestCategoryService(nil), zap.NewNop())
 	v, err := srv.ListChains(context.Background(), country)
 
 	require.ErrorIs(t, err, expectedErr)
@@ -167,7 +182,7 @@ func TestStoreChainService_OrganizeShoppingList(t *testing.T) {
 			tt.mockMethod(m)
 			shoppingList := domain.ShoppingList{ID: "1_foo", Items: tt.items}
 
-			srv := NewStoreChainService(m, zap.NewNop())
+			srv := NewStoreChainService(m, newTestCategoryService(nil), zap.NewNop())
 			err := srv.OrganizeShoppingList(context.Background(), &shoppingList, chainID)
 
 			if tt.expectedErr != nil {
@@ -181,3 +196,25 @@ func TestStoreChainService_OrganizeShoppingList(t *testing.T) {
 		})
 	}
 }
+
+func TestOrganizeByChain_PlacesSubcategoriesWithTheirParent(t *testing.T) {
+	chain := &domain.StoreChain{Layout: []domain.StoreSection{
+		{Order: 0, Name: "Fresh", Categories: []domain.Category{domain.CategoryProduce}},
+		{Order: 1, Name: "Spices", Categories: []domain.Category{"PANTRY_SPICES"}},
+		{Order: 2, Name: "Aisles", Categories: []domain.Category{domain.CategoryPantry}},
+	}}
+	list := &domain.ShoppingList{Items: []domain.ShoppingListItem{
+		{Name: "chickpeas", Category: "PANTRY_CANNED"},
+		{Name: "eggs", Category: "DAIRY_EGGS"},
+		{Name: "cumin", Category: "PANTRY_SPICES"},
+		{Name: "onion", Category: "PRODUCE_VEGETABLES"},
+	}}
+
+	organizeByChain(list, chain, domain.NewCategoryTaxonomy(testCategoryTaxonomy()))
+
+	names := make([]string, len(list.Items))
+	for i, item := range list.Items {
+		names[i] = item.Name
+	}
+	require.Equal(t, []string{"onion", "cumin", "chickpeas", "eggs"}, names)
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"go.uber.org/zap"
+)
+
+type storeRepository interface {
+	Create(ctx context.Context, store *domain.Store) error
+	GetByID(ctx context.Context, id string) (*domain.Store, error)
+	ListByUserID(ctx context.Context, userID string) ([]domain.Store, error)
+	Update(ctx context.Context, store *domain.Store) error
+	Delete(ctx context.Context, id string) error
+	ListPositions(ctx context.Context, storeID string) ([]domain.StorePosition, error)
+	UpdatePosition(ctx context.Context, storeID, kind, key string, update func(*domain.StorePosition)) error
+}
+
+const (
+	// storeLearningWindow is how many recent check-offs a learned position
+	// averages over, so it follows a store that gets rearranged.
+	storeLearningWindow = 10
+	// storeLayoutWeight is how many check-offs the edited layout is worth
+	// when it is blended with learned category positions.
+	storeLayoutWeight = 3
+	// itemKeyMax matches the store_positions and shopping_trip_items key
+	// columns.
+	itemKeyMax = 255
+)
+
+type StoreService interface {
+	List(ctx context.Context, userID string) ([]domain.Store, error)
+	Get(ctx context.Context, userID string, storeID string) (*domain.Store, error)
+	Create(ctx context.Context, userID string, req *domain.CreateStoreRequest) (*domain.Store, error)
+	Update(ctx context.Context, userID string, storeID string, req *domain.UpdateStoreRequest) (*domain.Store, error)
+	Delete(ctx context.Context, userID string, storeID string) error
+	// OrganizeShoppingList sorts list.Items in-place in the order they are
+	// found in the store: its layout, refined by what was learned from
+	// earlier trips.
+	OrganizeShoppingList(ctx context.Context, userID string, list *domain.ShoppingList, storeID string) error
+	// RecordCheckOff learns from item being checked off list, which is
+	// shopped at list.StoreID. How far through the list the item was picked
+	// up says how far into the store it is.
+	RecordCheckOff(ctx context.Context, list *domain.ShoppingList, item *domain.ShoppingListItem) error
+}
+
+type storeService struct {
+	storeRepo       storeRepository
+	storeChainRepo  storeChainRepository
+	categoryService CategoryService
+	auditLog        *AuditLog
+	logger          *zap.Logger
+}
+
+func NewStoreService(storeRepo storeRepository, storeChainRepo storeChainRepository, categoryService CategoryService, auditLog *AuditLog, logger *zap.Logger) StoreService {
+	return &storeService{
+		storeRepo:       storeRepo,
+		storeChainRepo:  storeChainRepo,
+		categoryService: categoryService,
+		auditLog:        auditLog,
+		logger:          logger,
+	}
+}
+
+func (s *storeService) List(ctx context.Context, userID string) ([]domain.Store, error) {
+	return s.storeRepo.ListByUserID(ctx, userID)
+}
+
+func (s *storeService) Get(ctx context.Context, userID string, storeID string) (*domain.Store, error) {
+	store, err := s.storeRepo.GetByID(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	if store.UserID != userID {
+		return nil, apperrors.ErrUnauthorized
+	}
+	return store, nil
+}
+
+func (s *storeService) Create(ctx context.Context, userID string, req *domain.CreateStoreRequest) (*domain.Store, error) {
+	store := &domain.Store{
+		UserID: userID,
+		Name:   req.Name,
+		Layout: req.Layout,
+	}
+
+	if req.StoreChainID != "" {
+		chain, err := s.storeChainRepo.GetChain(ctx, req.StoreChainID)
+		if err != nil {
+			if apperrors.IsNotFound(err) {
+				return nil, apperrors.ErrNotFound.Wrap("store chain not found")
+			}
+			return nil, err
+		}
+		store.StoreChainID = &chain.ID
+		if len(store.Layout) == 0 {
+			store.Layout = chain.Layout
+		}
+	}
+	if store.Layout == nil {
+		store.Layout = []domain.StoreSection{}
+	}
+
+	if err := s.storeRepo.Create(ctx, store); err != nil {
+		return nil, err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditStoreCreated,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID,
+		Changes:    domain.AuditChanges{"name": {To: store.Name}},
+	})
+	return store, nil
+}
+
+func (s *storeService) Update(ctx context.Context, userID string, storeID string, req *domain.UpdateStoreRequest) (*domain.Store, error) {
+	store, err := s.Get(ctx, userID, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := domain.AuditChanges{}
+	if req.Name != nil && *req.Name != store.Name {
+		changes["name"] = domain.AuditChange{From: store.Name, To: *req.Name}
+		store.Name = *req.Name
+	}
+	if req.Layout != nil {
+		// The layout is too large to diff usefully; record that it changed.
+		changes["layout"] = domain.AuditChange{From: len(store.Layout), To: len(req.Layout)}
+		store.Layout = req.Layout
+	}
+	if len(changes) == 0 {
+		return store, nil
+	}
+
+	if err := s.storeRepo.Update(ctx, store); err != nil {
+		return nil, err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditStoreUpdated,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID,
+		Changes:    changes,
+	})
+	return store, nil
+}
+
+func (s *storeService) Delete(ctx context.Context, userID string, storeID string) error {
+	store, err := s.Get(ctx, userID, storeID)
+	if err != nil {
+		return err
+	}
+	if err := s.storeRepo.Delete(ctx, store.ID); err != nil {
+		return err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditStoreDeleted,
+		TargetType: domain.AuditTargetStore,
+		TargetID:   store.ID,
+		Changes:    domain.AuditChanges{"name": {From: store.Name}},
+	})
+	return nil
+}
+
+func (s *storeService) OrganizeShoppingList(ctx context.Context, userID string, list *domain.ShoppingList, storeID string) error {
+	store, err := s.Get(ctx, userID, storeID)
+	if err != nil {
+		return err
+	}
+	positions, err := s.storeRepo.ListPositions(ctx, store.ID)
+	if err != nil {
+		return err
+	}
+	taxonomy, err := s.categoryService.Taxonomy(ctx)
+	if err != nil {
+		return err
+	}
+
+	organizeByStore(list, store, positions, taxonomy)
+	return nil
+}
+
+func (s *storeService) RecordCheckOff(ctx context.Context, list *domain.ShoppingList, item *domain.ShoppingListItem) error {
+	if list.StoreID == nil || len(list.Items) < 2 {
+		return nil
+	}
+
+	checkedBefore := 0
+	for _, other := range list.Items {
+		if other.ID != item.ID && other.IsChecked {
+			checkedBefore++
+		}
+	}
+	rank := float64(checkedBefore) / float64(len(list.Items)-1)
+	observe := func(p *domain.StorePosition) {
+		if p.Observations < storeLearningWindow {
+			p.Observations++
+		}
+		p.Position += (rank - p.Position) / float64(p.Observations)
+	}
+
+	if key := itemKey(item.Name); key != "" {
+		if err := s.storeRepo.UpdatePosition(ctx, *list.StoreID, domain.StorePositionItem, key, observe); err != nil {
+			return err
+		}
+	}
+	return s.storeRepo.UpdatePosition(ctx, *list.StoreID, domain.StorePositionCategory, string(item.Category), observe)
+}
+
+// itemKey is the name an item's learned positions and price history are
+// kept under, so "Milk" and "milk " count as the same item.
+func itemKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	for len(key) > itemKeyMax {
+		_, size := utf8.DecodeLastRuneInString(key)
+		key = key[:len(key)-size]
+	}
+	return key
+}
+
+// organizeByStore sorts list.Items in-place by where they are found in store.
+// Sections are spread over the trip in layout order, with categories missing
+// from the layout at the end; a subcategory belongs to the section of its
+// nearest listed parent. Learned category positions then pull each section
+// towards where its items were actually picked up. Within a section, items
+// with a learned position follow it and the rest keep the section's.
+func organizeByStore(list *domain.ShoppingList, store *domain.Store, positions []domain.StorePosition, taxonomy *domain.CategoryTaxonomy) {
+	sections := make([]domain.StoreSection, len(store.Layout))
+	copy(sections, store.Layout)
+	sort.SliceStable(sections, func(i, j int) bool { return sections[i].Order < sections[j].Order })
+
+	sectionIndex := make(map[domain.Category]int)
+	for i, section := range sections {
+		for _, category := range section.Categories {
+			if _, ok := sectionIndex[category]; !ok {
+				sectionIndex[category] = i
+			}
+		}
+	}
+	sectionOf := sectionLookup(sectionIndex, taxonomy)
+
+	// Blend each section's layout position with the learned positions of the
+	// categories it holds, weighting the layout as storeLayoutWeight
+	// check-offs. Unlisted categories start at the end of the trip.
+	type blend struct{ sum, n float64 }
+	sectionBlends := make([]blend, len(sections))
+	for i := range sections {
+		sectionBlends[i] = blend{sum: float64(i) / float64(len(sections)) * storeLayoutWeight, n: storeLayoutWeight}
+	}
+	unlistedBlends := make(map[domain.Category]blend)
+	learnedItems := make(map[string]domain.StorePosition)
+	for _, p := range positions {
+		switch p.Kind {
+		case domain.StorePositionItem:
+			learnedItems[p.Key] = p
+		case domain.StorePositionCategory:
+			learned := blend{sum: p.Position * float64(p.Observations), n: float64(p.Observations)}
+			if i, ok := sectionOf(domain.Category(p.Key)); ok {
+				sectionBlends[i].sum += learned.sum
+				sectionBlends[i].n += learned.n
+			} else {
+				unlistedBlends[domain.Category(p.Key)] = blend{sum: storeLayoutWeight + learned.sum, n: storeLayoutWeight + learned.n}
+			}
+		}
+	}
+
+	type group struct {
+		index int
+		score float64
+	}
+	unlisted := make(map[domain.Category]group)
+	groupOf := func(category domain.Category) group {
+		if i, ok := sectionOf(category); ok {
+			return group{index: i, score: sectionBlends[i].sum / sectionBlends[i].n}
+		}
+		if g, ok := unlisted[category]; ok {
+			return g
+		}
+		b, ok := unlistedBlends[category]
+		if !ok {
+			b = blend{sum: storeLayoutWeight, n: storeLayoutWeight}
+		}
+		g := group{index: len(sections) + len(unlisted), score: b.sum / b.n}
+		unlisted[category] = g
+		return g
+	}
+
+	type entry struct {
+		item  domain.ShoppingListItem
+		group group
+		score float64
+	}
+	entries := make([]entry, len(list.Items))
+	for i, item := range list.Items {
+		g := groupOf(item.Category)
+		score := g.score
+		if p, ok := learnedItems[itemKey(item.Name)]; ok {
+			score = p.Position
+		}
+		entries[i] = entry{item: item, group: g, score: score}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].group.score != entries[j].group.score {
+			return entries[i].group.score < entries[j].group.score
+		}
+		if entries[i].group.index != entries[j].group.index {
+			return entries[i].group.index < entries[j].group.index
+		}
+		return entries[i].score < entries[j].score
+	})
+	for i := range entries {
+		list.Items[i] = entries[i].item
+	}
+}
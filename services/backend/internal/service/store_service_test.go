@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockStoreRepo struct {
+	mock.Mock
+}
+
+func (m *mockStoreRepo) Create(ctx context.Context, store *domain.Store) error {
+	args := m.Called(ctx, store)
+	return args.Error(0)
+}
+
+func (m *mockStoreRepo) GetByID(ctx context.Context, id string) (*domain.Store, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.Store)
+	return v, args.Error(1)
+}
+
+func (m *mockStoreRepo) ListByUserID(ctx context.Context, userID string) ([]domain.Store, error) {
+	args := m.Called(ctx, userID)
+	v, _ := args.Get(0).([]domain.Store)
+	return v, args.Error(1)
+}
+
+func (m *mockStoreRepo) Update(ctx context.Context, store *domain.Store) error {
+	args := m.Called(ctx, store)
+	return args.Error(0)
+}
+
+func (m *mockStoreRepo) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockStoreRepo) ListPositions(ctx context.Context, storeID string) ([]domain.StorePosition, error) {
+	args := m.Called(ctx, storeID)
+	v, _ := args.Get(0).([]domain.StorePosition)
+	return v, args.Error(1)
+}
+
+func (m *mockStoreRepo) UpdatePosition(ctx context.Context, storeID, kind, key string, update func(*domain.StorePosition)) error {
+	args := m.Called(ctx, storeID, kind, key, update)
+	return args.Error(0)
+}
+
+func TestStoreService_Create(t *testing.T) {
+	chainLayout := []domain.StoreSection{{Order: 0, Name: "Fresh", Categories: []domain.Category{domain.CategoryProduce}}}
+
+	t.Run("copies the chain layout when none is given", func(t *testing.T) {
+		repo, chains := new(mockStoreRepo), new(mockStoreChainRepo)
+		chains.On("GetChain", mock.Anything, "chain-1").Return(&domain.StoreChain{ID: "chain-1", Layout: chainLayout}, nil).Once()
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(s *domain.Store) bool {
+			return s.UserID == "user-1" && *s.StoreChainID == "chain-1" && len(s.Layout) == 1
+		})).Return(nil).Once()
+
+		srv := NewStoreService(repo, chains, newTestCategoryService(nil), nil, zap.NewNop())
+		store, err := srv.Create(context.Background(), "user-1", &domain.CreateStoreRequest{Name: "Corner", StoreChainID: "chain-1"})
+
+		require.NoError(t, err)
+		require.Equal(t, chainLayout, store.Layout)
+		repo.AssertExpectations(t)
+		chains.AssertExpectations(t)
+	})
+
+	t.Run("keeps its own layout over the chain's", func(t *testing.T) {
+		own := []domain.StoreSection{{Order: 0, Name: "Drinks", Categories: []domain.Category{domain.CategoryBeverages}}}
+		repo, chains := new(mockStoreRepo), new(mockStoreChainRepo)
+		chains.On("GetChain", mock.Anything, "chain-1").Return(&domain.StoreChain{ID: "chain-1", Layout: chainLayout}, nil).Once()
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewStoreService(repo, chains, newTestCategoryService(nil), nil, zap.NewNop())
+		store, err := srv.Create(context.Background(), "user-1", &domain.CreateStoreRequest{Name: "Corner", StoreChainID: "chain-1", Layout: own})
+
+		require.NoError(t, err)
+		require.Equal(t, own, store.Layout)
+	})
+
+	t.Run("returns not found for an unknown chain", func(t *testing.T) {
+		repo, chains := new(mockStoreRepo), new(mockStoreChainRepo)
+		chains.On("GetChain", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewStoreService(repo, chains, newTestCategoryService(nil), nil, zap.NewNop())
+		_, err := srv.Create(context.Background(), "user-1", &domain.CreateStoreRequest{Name: "Corner", StoreChainID: "missing"})
+
+		require.True(t, apperrors.IsNotFound(err))
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoreService_Get_RejectsOtherUsersStore(t *testing.T) {
+	repo := new(mockStoreRepo)
+	repo.On("GetByID", mock.Anything, "store-1").Return(&domain.Store{ID: "store-1", UserID: "owner"}, nil).Once()
+
+	srv := NewStoreService(repo, new(mockStoreChainRepo), newTestCategoryService(nil), nil, zap.NewNop())
+	_, err := srv.Get(context.Background(), "someone-else", "store-1")
+
+	require.ErrorIs(t, err, apperrors.ErrUnauthorized)
+}
+
+func TestStoreService_RecordCheckOff(t *testing.T) {
+	storeID := "store-1"
+	list := &domain.ShoppingList{ID: "list-1", StoreID: &storeID, Items: []domain.ShoppingListItem{
+		{ID: "a", Name: "Apples", Category: domain.CategoryProduce, IsChecked: true},
+		{ID: "b", Name: " Milk", Category: domain.CategoryDairy},
+		{ID: "c", Name: "Bread", Category: domain.CategoryBakery},
+	}}
+
+	repo := new(mockStoreRepo)
+	var updates []func(*domain.StorePosition)
+	capture := func(args mock.Arguments) { updates = append(updates, args.Get(4).(func(*domain.StorePosition))) }
+	repo.On("UpdatePosition", mock.Anything, storeID, domain.StorePositionItem, "milk", mock.Anything).Run(capture).Return(nil).Once()
+	repo.On("UpdatePosition", mock.Anything, storeID, domain.StorePositionCategory, "DAIRY", mock.Anything).Run(capture).Return(nil).Once()
+
+	srv := NewStoreService(repo, new(mockStoreChainRepo), newTestCategoryService(nil), nil, zap.NewNop())
+	require.NoError(t, srv.RecordCheckOff(context.Background(), list, &list.Items[1]))
+	repo.AssertExpectations(t)
+	require.Len(t, updates, 2)
+
+	// One of the two other items was picked up first: half-way through.
+	first := domain.StorePosition{}
+	updates[0](&first)
+	require.Equal(t, 1, first.Observations)
+	require.InDelta(t, 0.5, first.Position, 1e-9)
+
+	// Once the window is full, each check-off moves the mean by a tenth.
+	settled := domain.StorePosition{Position: 0, Observations: storeLearningWindow}
+	updates[1](&settled)
+	require.Equal(t, storeLearningWindow, settled.Observations)
+	require.InDelta(t, 0.05, settled.Position, 1e-9)
+}
+
+func TestStoreService_RecordCheckOff_SkipsListsWithoutStore(t *testing.T) {
+	repo := new(mockStoreRepo)
+	list := &domain.ShoppingList{Items: []domain.ShoppingListItem{{ID: "a"}, {ID: "b"}}}
+
+	srv := NewStoreService(repo, new(mockStoreChainRepo), newTestCategoryService(nil), nil, zap.NewNop())
+	require.NoError(t, srv.RecordCheckOff(context.Background(), list, &list.Items[0]))
+	repo.AssertNotCalled(t, "UpdatePosition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrganizeByStore(t *testing.T) {
+	store := &domain.Store{Layout: []domain.StoreSection{
+		{Order: 2, Name: "Bread", Categories: []domain.Category{domain.CategoryBakery}},
+		{Order: 0, Name: "Fresh", Categories: []domain.Category{domain.CategoryProduce}},
+		{Order: 1, Name: "Chilled", Categories: []domain.Category{domain.CategoryDairy}},
+	}}
+	newList := func() *domain.ShoppingList {
+		return &domain.ShoppingList{Items: []domain.ShoppingListItem{
+			{Name: "Soap", Category: domain.CategoryHousehold},
+			{Name: "Milk", Category: domain.CategoryDairy},
+			{Name: "Rolls", Category: domain.CategoryBakery},
+			{Name: "Yogurt", Category: domain.CategoryDairy},
+			{Name: "Apples", Category: domain.CategoryProduce},
+		}}
+	}
+	names := func(list *domain.ShoppingList) []string {
+		out := make([]string, len(list.Items))
+		for i, item := range list.Items {
+			out[i] = item.Name
+		}
+		return out
+	}
+
+	t.Run("follows the layout with unlisted categories last", func(t *testing.T) {
+		list := newList()
+		organizeByStore(list, store, nil, nil)
+		require.Equal(t, []string{"Apples", "Milk", "Yogurt", "Rolls", "Soap"}, names(list))
+	})
+
+	t.Run("learned positions refine the layout", func(t *testing.T) {
+		list := newList()
+		organizeByStore(list, store, []domain.StorePosition{
+			// The bakery is really by the entrance in this branch.
+			{Kind: domain.StorePositionCategory, Key: "BAKERY", Position: 0, Observations: storeLearningWindow},
+			// Milk is at the back of the chilled section.
+			{Kind: domain.StorePositionItem, Key: "milk", Position: 0.9, Observations: 4},
+		}, nil)
+		require.Equal(t, []string{"Apples", "Rolls", "Yogurt", "Milk", "Soap"}, names(list))
+	})
+	t.Run("subcategories fall into their parent's section", func(t *testing.T) {
+		list := &domain.ShoppingList{Items: []domain.ShoppingListItem{
+			{Name: "Eggs", Category: "DAIRY_EGGS"},
+			{Name: "Rolls", Category: domain.CategoryBakery},
+			{Name: "Onions", Category: "PRODUCE_VEGETABLES"},
+			{Name: "Cumin", Category: "PANTRY_SPICES"},
+		}}
+		organizeByStore(list, store, []domain.StorePosition{
+			// Eggs are picked up last, which moves the whole chilled section
+			// behind the bread.
+			{Kind: domain.StorePositionCategory, Key: "DAIRY_EGGS", Position: 1, Observations: storeLearningWindow},
+		}, domain.NewCategoryTaxonomy(testCategoryTaxonomy()))
+		require.Equal(t, []string{"Onions", "Rolls", "Eggs", "Cumin"}, names(list))
+	})
+}
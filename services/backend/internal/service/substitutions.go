@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+)
+
+// curatedSubstitute is a well-known replacement. Ratio scales the original
+// amount; an empty Unit keeps the original unit.
+type curatedSubstitute struct {
+	Name  string
+	Ratio float64
+	Unit  string
+	Notes string
+}
+
+// curatedSubstitutes is checked before asking the AI model. Keys are
+// lower-cased ingredient names; entries are ordered best fit first.
+var curatedSubstitutes = map[string][]curatedSubstitute{
+	"buttermilk": {
+		{Name: "milk", Ratio: 1, Notes: "Stir 1 tablespoon lemon juice or white vinegar into each cup of milk and let it stand for 5 minutes."},
+		{Name: "plain yogurt", Ratio: 0.75, Notes: "Thin with milk to make up the remaining quarter."},
+		{Name: "sour cream", Ratio: 0.75, Notes: "Thin with water or milk to make up the remaining quarter."},
+	},
+	"butter": {
+		{Name: "vegetable oil", Ratio: 0.75, Notes: "Works for cooking and most batters; not for laminated doughs or frostings."},
+		{Name: "coconut oil", Ratio: 1, Notes: "Use solid for baking where butter is creamed."},
+	},
+	"egg": {
+		{Name: "ground flaxseed", Ratio: 1, Unit: "tablespoon", Notes: "Mix each tablespoon with 3 tablespoons water and let it gel for 5 minutes. Best for binding, not for leavening."},
+		{Name: "unsweetened applesauce", Ratio: 0.25, Unit: "cup", Notes: "Adds moisture and a little sweetness."},
+	},
+	"eggs": {
+		{Name: "ground flaxseed", Ratio: 1, Unit: "tablespoon", Notes: "Mix each tablespoon with 3 tablespoons water and let it gel for 5 minutes. Best for binding, not for leavening."},
+		{Name: "unsweetened applesauce", Ratio: 0.25, Unit: "cup", Notes: "Adds moisture and a little sweetness."},
+	},
+	"heavy cream": {
+		{Name: "whole milk", Ratio: 0.75, Notes: "Melt in butter to make up the remaining quarter. Will not whip."},
+		{Name: "full-fat coconut milk", Ratio: 1, Notes: "Adds a light coconut flavor."},
+	},
+	"sour cream": {
+		{Name: "plain Greek yogurt", Ratio: 1},
+		{Name: "crème fraîche", Ratio: 1},
+	},
+	"baking powder": {
+		{Name: "baking soda", Ratio: 0.25, Notes: "Add ½ teaspoon cream of tartar for every ¼ teaspoon baking soda."},
+	},
+	"brown sugar": {
+		{Name: "white sugar", Ratio: 1, Notes: "Add 1 tablespoon molasses per cup for the same flavor and moisture."},
+	},
+	"cornstarch": {
+		{Name: "all-purpose flour", Ratio: 2, Notes: "Cook a little longer to remove the raw flour taste."},
+		{Name: "arrowroot", Ratio: 1},
+	},
+	"lemon juice": {
+		{Name: "lime juice", Ratio: 1},
+		{Name: "white wine vinegar", Ratio: 0.5, Notes: "Sharper than lemon; taste and adjust."},
+	},
+	"white wine": {
+		{Name: "chicken or vegetable stock", Ratio: 1, Notes: "Add a splash of white wine vinegar for acidity."},
+	},
+	"fresh herbs": {
+		{Name: "dried herbs", Ratio: 1.0 / 3, Notes: "Add earlier in cooking so they can rehydrate."},
+	},
+}
+
+// curatedSubstitutesFor returns the curated replacements for an ingredient with
+// amounts scaled to the original, or nil when the table has no entry.
+func curatedSubstitutesFor(ingredient domain.RecipeIngredient) []domain.IngredientSubstitute {
+	entries, ok := curatedSubstitutes[strings.ToLower(strings.TrimSpace(ingredient.Name))]
+	if !ok {
+		return nil
+	}
+
+	substitutes := make([]domain.IngredientSubstitute, len(entries))
+	for i, e := range entries {
+		unit := e.Unit
+		if unit == "" {
+			unit = ingredient.Unit
+		}
+		substitutes[i] = domain.IngredientSubstitute{
+			Name:   e.Name,
+			Amount: ingredient.Amount * e.Ratio,
+			Unit:   unit,
+			Notes:  e.Notes,
+			Source: domain.SubstituteSourceCurated,
+		}
+	}
+	return substitutes
+}
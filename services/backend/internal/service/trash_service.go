@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	"github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/pkg/storage"
+	"go.uber.org/zap"
+)
+
+type trashRecipeRepository interface {
+	ListTrashed(ctx context.Context, userID string) ([]domain.Recipe, error)
+	GetTrashedByID(ctx context.Context, id string) (*domain.Recipe, error)
+	Restore(ctx context.Context, id string) error
+	ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]domain.Recipe, error)
+	Purge(ctx context.Context, id string) error
+}
+
+type trashShoppingListRepository interface {
+	GetByID(ctx context.Context, listID string) (*domain.ShoppingList, error)
+	ListTrashed(ctx context.Context, userID string) ([]domain.ShoppingList, error)
+	ListTrashedItems(ctx context.Context, userID string) ([]domain.ShoppingListItem, error)
+	GetTrashedByID(ctx context.Context, id string) (*domain.ShoppingList, error)
+	GetTrashedItemByID(ctx context.Context, id string) (*domain.ShoppingListItem, error)
+	Restore(ctx context.Context, id string) error
+	RestoreItem(ctx context.Context, id string) error
+	PurgeTrashedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// defaultTrashRetention applies when trash.retention_days is not set.
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// trashPurgeInterval is how often RunPurge empties expired trash.
+const trashPurgeInterval = time.Hour
+
+type TrashService interface {
+	// List returns everything the user has deleted that can still be
+	// restored, most recently deleted first.
+	List(ctx context.Context, userID string) ([]domain.TrashEntry, error)
+	RestoreRecipe(ctx context.Context, userID string, recipeID string) error
+	RestoreShoppingList(ctx context.Context, userID string, listID string) error
+	// RestoreShoppingListItem puts an item back on its list. An item whose
+	// list is itself in the trash comes back with the list instead.
+	RestoreShoppingListItem(ctx context.Context, userID string, itemID string) error
+	// PurgeExpired permanently deletes everything that has been in the trash
+	// longer than the retention period, recipe images included.
+	PurgeExpired(ctx context.Context) (int64, error)
+	// RunPurge purges expired trash once at start and then hourly until ctx
+	// is done.
+	RunPurge(ctx context.Context)
+}
+
+type trashService struct {
+	recipeRepo       trashRecipeRepository
+	shoppingListRepo trashShoppingListRepository
+	fileStorage      storage.FileStore
+	retention        time.Duration
+	auditLog         *AuditLog
+	logger           *zap.Logger
+}
+
+func NewTrashService(recipeRepo trashRecipeRepository, shoppingListRepo trashShoppingListRepository, fileStorage storage.FileStore, retentionDays int, auditLog *AuditLog, logger *zap.Logger) TrashService {
+	retention := defaultTrashRetention
+	if retentionDays > 0 {
+		retention = time.Duration(retentionDays) * 24 * time.Hour
+	}
+	return &trashService{
+		recipeRepo:       recipeRepo,
+		shoppingListRepo: shoppingListRepo,
+		fileStorage:      fileStorage,
+		retention:        retention,
+		auditLog:         auditLog,
+		logger:           logger,
+	}
+}
+
+func (s *trashService) List(ctx context.Context, userID string) ([]domain.TrashEntry, error) {
+	recipes, err := s.recipeRepo.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := s.shoppingListRepo.ListTrashed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.shoppingListRepo.ListTrashedItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.TrashEntry, 0, len(recipes)+len(lists)+len(items))
+	for _, recipe := range recipes {
+		entries = append(entries, s.entry(domain.TrashTypeRecipe, recipe.ID, recipe.Title, "", recipe.DeletedAt.Time))
+	}
+	for _, list := range lists {
+		entries = append(entries, s.entry(domain.TrashTypeShoppingList, list.ID, list.Name, "", list.DeletedAt.Time))
+	}
+	for _, item := range items {
+		entries = append(entries, s.entry(domain.TrashTypeShoppingListItem, item.ID, item.Name, item.ListID, item.DeletedAt.Time))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+func (s *trashService) entry(entryType, id, name, listID string, deletedAt time.Time) domain.TrashEntry {
+	return domain.TrashEntry{
+		Type:      entryType,
+		ID:        id,
+		Name:      name,
+		ListID:    listID,
+		DeletedAt: deletedAt,
+		PurgeAt:   deletedAt.Add(s.retention),
+	}
+}
+
+func (s *trashService) RestoreRecipe(ctx context.Context, userID string, recipeID string) error {
+	recipe, err := s.recipeRepo.GetTrashedByID(ctx, recipeID)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err != nil || recipe.UserID != userID {
+		return errors.ErrNotFound.Wrap("recipe not found in trash")
+	}
+
+	if err := s.recipeRepo.Restore(ctx, recipeID); err != nil {
+		return err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditRecipeRestored,
+		TargetType: domain.AuditTargetRecipe,
+		TargetID:   recipeID,
+		Changes:    domain.AuditChanges{"title": {To: recipe.Title}},
+	})
+	return nil
+}
+
+func (s *trashService) RestoreShoppingList(ctx context.Context, userID string, listID string) error {
+	list, err := s.shoppingListRepo.GetTrashedByID(ctx, listID)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err != nil || list.UserID != userID {
+		return errors.ErrNotFound.Wrap("shopping list not found in trash")
+	}
+
+	if err := s.shoppingListRepo.Restore(ctx, listID); err != nil {
+		return err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditShoppingListRestored,
+		TargetType: domain.AuditTargetShoppingList,
+		TargetID:   listID,
+		Changes:    domain.AuditChanges{"name": {To: list.Name}},
+	})
+	return nil
+}
+
+func (s *trashService) RestoreShoppingListItem(ctx context.Context, userID string, itemID string) error {
+	notFound := errors.ErrNotFound.Wrap("shopping list item not found in trash")
+
+	item, err := s.shoppingListRepo.GetTrashedItemByID(ctx, itemID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return notFound
+		}
+		return err
+	}
+
+	list, err := s.shoppingListRepo.GetByID(ctx, item.ListID)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		trashed, err := s.shoppingListRepo.GetTrashedByID(ctx, item.ListID)
+		if err != nil || trashed.UserID != userID {
+			return notFound
+		}
+		return errors.ErrConflict.Wrap("the item's shopping list is in the trash; restore the list first")
+	}
+	if list.UserID != userID {
+		return notFound
+	}
+
+	return s.shoppingListRepo.RestoreItem(ctx, itemID)
+}
+
+func (s *trashService) PurgeExpired(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-s.retention)
+
+	recipes, err := s.recipeRepo.ListTrashedBefore(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	var purged int64
+	for _, recipe := range recipes {
+		if err := s.recipeRepo.Purge(ctx, recipe.ID); err != nil {
+			return purged, err
+		}
+		purged++
+
+		// The image stays while the recipe can still be restored.
+		if recipe.ImageURL != "" {
+			if err := s.fileStorage.DeleteFile(ctx, recipe.ImageURL); err != nil {
+				s.logger.Warn("failed to delete image of purged recipe",
+					zap.Error(err),
+					zap.String("imageURL", recipe.ImageURL))
+			}
+		}
+	}
+
+	lists, err := s.shoppingListRepo.PurgeTrashedBefore(ctx, cutoff)
+	return purged + lists, err
+}
+
+func (s *trashService) RunPurge(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpired(ctx)
+		if err != nil {
+			s.logger.Error("failed to purge expired trash", zap.Error(err))
+		} else if purged > 0 {
+			s.logger.Info("purged expired trash", zap.Int64("purged", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
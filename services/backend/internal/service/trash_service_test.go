@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newTestTrashService(recipes *mockRecipeRepo, lists *mockShoppingListRepository, files *mockFileStore) TrashService {
+	return NewTrashService(recipes, lists, files, 30, nil, zap.NewNop())
+}
+
+func deletedAt(t time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: t, Valid: true}
+}
+
+func TestTrashService_List_MergesNewestFirst(t *testing.T) {
+	recipes, lists := new(mockRecipeRepo), new(mockShoppingListRepository)
+	now := time.Now()
+
+	recipes.On("ListTrashed", mock.Anything, "user-1").Return([]domain.Recipe{
+		{ID: "r1", Title: "Pancakes", DeletedAt: deletedAt(now.Add(-2 * time.Hour))},
+	}, nil).Once()
+	lists.On("ListTrashed", mock.Anything, "user-1").Return([]domain.ShoppingList{
+		{ID: "l1", Name: "Weekly", DeletedAt: deletedAt(now.Add(-time.Hour))},
+	}, nil).Once()
+	lists.On("ListTrashedItems", mock.Anything, "user-1").Return([]domain.ShoppingListItem{
+		{ID: "i1", ListID: "l2", Name: "Milk", DeletedAt: deletedAt(now.Add(-3 * time.Hour))},
+	}, nil).Once()
+
+	entries, err := newTestTrashService(recipes, lists, new(mockFileStore)).List(context.Background(), "user-1")
+
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, []string{"l1", "r1", "i1"}, []string{entries[0].ID, entries[1].ID, entries[2].ID})
+	require.Equal(t, domain.TrashTypeShoppingListItem, entries[2].Type)
+	require.Equal(t, "l2", entries[2].ListID)
+	require.WithinDuration(t, now.Add(-time.Hour).Add(30*24*time.Hour), entries[0].PurgeAt, time.Second)
+}
+
+func TestTrashService_RestoreRecipe(t *testing.T) {
+	t.Run("restores the owner's recipe", func(t *testing.T) {
+		recipes := new(mockRecipeRepo)
+		recipes.On("GetTrashedByID", mock.Anything, "r1").Return(&domain.Recipe{ID: "r1", UserID: "user-1"}, nil).Once()
+		recipes.On("Restore", mock.Anything, "r1").Return(nil).Once()
+
+		err := newTestTrashService(recipes, new(mockShoppingListRepository), new(mockFileStore)).RestoreRecipe(context.Background(), "user-1", "r1")
+
+		require.NoError(t, err)
+		recipes.AssertExpectations(t)
+	})
+
+	t.Run("someone else's recipe is not found", func(t *testing.T) {
+		recipes := new(mockRecipeRepo)
+		recipes.On("GetTrashedByID", mock.Anything, "r1").Return(&domain.Recipe{ID: "r1", UserID: "other"}, nil).Once()
+
+		err := newTestTrashService(recipes, new(mockShoppingListRepository), new(mockFileStore)).RestoreRecipe(context.Background(), "user-1", "r1")
+
+		require.True(t, apperrors.IsNotFound(err))
+		recipes.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrashService_RestoreShoppingListItem(t *testing.T) {
+	t.Run("restores onto a live list", func(t *testing.T) {
+		lists := new(mockShoppingListRepository)
+		lists.On("GetTrashedItemByID", mock.Anything, "i1").Return(&domain.ShoppingListItem{ID: "i1", ListID: "l1"}, nil).Once()
+		lists.On("GetByID", mock.Anything, "l1").Return(&domain.ShoppingList{ID: "l1", UserID: "user-1"}, nil).Once()
+		lists.On("RestoreItem", mock.Anything, "i1").Return(nil).Once()
+
+		err := newTestTrashService(new(mockRecipeRepo), lists, new(mockFileStore)).RestoreShoppingListItem(context.Background(), "user-1", "i1")
+
+		require.NoError(t, err)
+		lists.AssertExpectations(t)
+	})
+
+	t.Run("conflicts while its list is in the trash", func(t *testing.T) {
+		lists := new(mockShoppingListRepository)
+		lists.On("GetTrashedItemByID", mock.Anything, "i1").Return(&domain.ShoppingListItem{ID: "i1", ListID: "l1"}, nil).Once()
+		lists.On("GetByID", mock.Anything, "l1").Return(nil, gorm.ErrRecordNotFound).Once()
+		lists.On("GetTrashedByID", mock.Anything, "l1").Return(&domain.ShoppingList{ID: "l1", UserID: "user-1"}, nil).Once()
+
+		err := newTestTrashService(new(mockRecipeRepo), lists, new(mockFileStore)).RestoreShoppingListItem(context.Background(), "user-1", "i1")
+
+		require.True(t, apperrors.IsConflict(err))
+		lists.AssertNotCalled(t, "RestoreItem", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrashService_PurgeExpired_DeletesRecipeImages(t *testing.T) {
+	recipes, lists, files := new(mockRecipeRepo), new(mockShoppingListRepository), new(mockFileStore)
+	recipes.On("ListTrashedBefore", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) > 29*24*time.Hour
+	})).Return([]domain.Recipe{
+		{ID: "r1", ImageURL: "https://storage/r1.jpg"},
+		{ID: "r2"},
+	}, nil).Once()
+	recipes.On("Purge", mock.Anything, "r1").Return(nil).Once()
+	recipes.On("Purge", mock.Anything, "r2").Return(nil).Once()
+	files.On("DeleteFile", mock.Anything, "https://storage/r1.jpg").Return(nil).Once()
+	lists.On("PurgeTrashedBefore", mock.Anything, mock.Anything).Return(int64(3), nil).Once()
+
+	purged, err := newTestTrashService(recipes, lists, files).PurgeExpired(context.Background())
+
+	require.NoError(t, err)
+	require.EqualValues(t, 5, purged)
+	recipes.AssertExpectations(t)
+	files.AssertExpectations(t)
+	lists.AssertExpectations(t)
+}
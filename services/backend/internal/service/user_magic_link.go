@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/repository"
+	"go.uber.org/zap"
+)
+
+// magicLinkTTL is how long an emailed sign-in link stays valid. It is short
+// because the link alone signs the user in.
+const magicLinkTTL = 15 * time.Minute
+
+// errInvalidMagicLink is returned for every failed redemption so the response
+// does not reveal whether a link was unknown, expired or already used.
+var errInvalidMagicLink = apperrors.New("invalid or expired link")
+
+// RequestMagicLink emails a single-use sign-in link. Like ForgotPassword, an
+// unknown email returns nil so the response does not confirm which addresses
+// are registered.
+func (s *userService) RequestMagicLink(ctx context.Context, req *domain.MagicLinkRequest) error {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if !apperrors.IsNotFound(err) {
+			s.logger.Warn("failed to lookup user for magic link", zap.Error(err))
+		}
+		return nil
+	}
+
+	secret, hash, err := newRefreshSecret()
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.CreateMagicLinkToken(ctx, &domain.MagicLinkToken{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(magicLinkTTL),
+	}); err != nil {
+		return err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditMagicLinkRequested,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+	})
+
+	return s.emailService.SendMagicLinkEmail(ctx, user.Email, secret)
+}
+
+// MagicLinkLogin redeems a sign-in link. Opening it proves control of the
+// mailbox, so an unverified account becomes verified; the password lockout
+// does not apply since no password is involved. Two-factor authentication
+// still does: the link only stands in for the password.
+func (s *userService) MagicLinkLogin(ctx context.Context, req *domain.MagicLinkLoginRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
+	token, err := s.userRepo.ConsumeMagicLinkToken(ctx, hashRefreshSecret(req.Token), time.Now())
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, errInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, errInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		err := s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+			return claimUnverifiedAccount(ctx, txRepo, user)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.firstFactorPassed(ctx, user, client)
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/repository"
+	"github.com/H3nSte1n/recipe/pkg/config"
+	"github.com/H3nSte1n/recipe/pkg/oidc"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// oidcStateTTL is how long the user has to complete sign-in at the provider.
+const oidcStateTTL = 10 * time.Minute
+
+// oidcProvider is the part of oidc.Provider the sign-in flow uses.
+type oidcProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Identity, error)
+}
+
+var (
+	// errInvalidOIDCState covers unknown, expired, reused and mismatched state
+	// parameters alike.
+	errInvalidOIDCState = apperrors.New("invalid or expired sign-in state")
+	// errOIDCEmailUnverified is returned when an identity cannot be matched to
+	// an account because the provider does not vouch for its email address.
+	errOIDCEmailUnverified = apperrors.New("identity provider did not confirm the email address")
+)
+
+func newOIDCProviders(cfg config.OIDCConfig) (map[string]oidcProvider, []domain.OIDCProvider) {
+	providers := make(map[string]oidcProvider, len(cfg.Providers))
+	options := make([]domain.OIDCProvider, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			IssuerURL:    p.IssuerURL,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+			AuthURL:      p.AuthURL,
+			TokenURL:     p.TokenURL,
+			UserInfoURL:  p.UserInfoURL,
+			EmailsURL:    p.EmailsURL,
+		}, nil)
+
+		displayName := p.DisplayName
+		if displayName == "" {
+			displayName = p.Name
+		}
+		options = append(options, domain.OIDCProvider{Name: p.Name, DisplayName: displayName})
+	}
+	return providers, options
+}
+
+func (s *userService) ListOIDCProviders() []domain.OIDCProvider {
+	return s.oidcOptions
+}
+
+// StartOIDCLogin returns the provider URL to send the browser to. The state,
+// nonce and PKCE verifier generated here are stored server-side and checked
+// when the browser comes back, together with a browser binding only the
+// caller is given: without it, someone could start a sign-in and get a victim
+// to finish it, signing the victim in as them.
+func (s *userService) StartOIDCLogin(ctx context.Context, providerName string) (*domain.OIDCStartResponse, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return nil, apperrors.ErrNotFound.Wrap("unknown identity provider")
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	binding, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.PKCEChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.CreateOIDCState(ctx, &domain.OIDCLoginState{
+		ID:           uuid.New().String(),
+		StateHash:    hashRefreshSecret(state),
+		BindingHash:  hashRefreshSecret(binding),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.OIDCStartResponse{AuthorizationURL: authURL, BrowserBinding: binding}, nil
+}
+
+// CompleteOIDCLogin handles the provider's redirect back. The identity is
+// matched to a user by its provider subject, then by verified email, and a
+// new account is created when neither matches. Two-factor authentication
+// still applies to accounts that have it enabled.
+func (s *userService) CompleteOIDCLogin(ctx context.Context, providerName string, req *domain.OIDCCallbackRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return nil, apperrors.ErrNotFound.Wrap("unknown identity provider")
+	}
+
+	state, err := s.userRepo.ConsumeOIDCState(ctx, hashRefreshSecret(req.State), time.Now())
+	if err != nil {
+		if apperrors.IsNotFound(err) {
+			return nil, errInvalidOIDCState
+		}
+		return nil, err
+	}
+	if state.Provider != providerName ||
+		subtle.ConstantTimeCompare([]byte(hashRefreshSecret(req.BrowserBinding)), []byte(state.BindingHash)) != 1 {
+		return nil, errInvalidOIDCState
+	}
+
+	identity, err := provider.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		s.logger.Warn("external sign-in failed", zap.String("provider", providerName), zap.Error(err))
+		return nil, apperrors.ErrUnauthorized.Wrap("external sign-in failed")
+	}
+
+	user, err := s.userForIdentity(ctx, providerName, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.firstFactorPassed(ctx, user, client)
+}
+
+// userForIdentity finds or creates the user an external identity signs in as.
+func (s *userService) userForIdentity(ctx context.Context, providerName string, identity *oidc.Identity) (*domain.User, error) {
+	linked, err := s.userRepo.GetIdentity(ctx, providerName, identity.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(ctx, linked.UserID)
+	}
+	if !apperrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	// Matching by email is only safe when the provider vouches for it;
+	// otherwise anyone could claim an address at a lax provider and take
+	// over the account registered with it.
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, errOIDCEmailUnverified
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, identity.Email)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	link := &domain.UserIdentity{
+		ID:       uuid.New().String(),
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	if user != nil {
+		link.UserID = user.ID
+		if err := s.linkIdentity(ctx, user, link); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	return s.createUserForIdentity(ctx, identity, link)
+}
+
+// linkIdentity attaches an identity to an existing account. If that account
+// never verified its email, whoever registered it has not proven they own
+// the address the provider just confirmed, so their password and sessions
+// are discarded rather than inherited.
+func (s *userService) linkIdentity(ctx context.Context, user *domain.User, link *domain.UserIdentity) error {
+	return s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+		if err := txRepo.CreateIdentity(ctx, link); err != nil {
+			return err
+		}
+		if user.IsEmailVerified() {
+			return nil
+		}
+		return claimUnverifiedAccount(ctx, txRepo, user)
+	})
+}
+
+// claimUnverifiedAccount marks user's email verified on behalf of someone who
+// just proved they own it. Whoever registered the account never did, so
+// their password, second factor and sessions are discarded.
+func claimUnverifiedAccount(ctx context.Context, txRepo repository.UserRepository, user *domain.User) error {
+	if err := txRepo.UpdatePassword(ctx, user.ID, ""); err != nil {
+		return err
+	}
+	if err := txRepo.DisableTOTP(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := txRepo.RevokeUserSessions(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := txRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return err
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return nil
+}
+
+// createUserForIdentity creates an account without a password for someone
+// signing in through a provider. They can still set one with the
+// forgot-password flow.
+func (s *userService) createUserForIdentity(ctx context.Context, identity *oidc.Identity, link *domain.UserIdentity) (*domain.User, error) {
+	now := time.Now()
+	user := &domain.User{
+		ID:              uuid.New().String(),
+		Email:           identity.Email,
+		FirstName:       identity.GivenName,
+		LastName:        identity.FamilyName,
+		EmailVerifiedAt: &now,
+		Role:            domain.RoleMember,
+	}
+	link.UserID = user.ID
+
+	err := s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+		if err := txRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		profile := &domain.Profile{
+			ID:        uuid.New().String(),
+			UserID:    user.ID,
+			Bio:       fmt.Sprintf("Hello, I'm %s %s", user.FirstName, user.LastName),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := txRepo.CreateProfile(ctx, profile); err != nil {
+			return err
+		}
+		return txRepo.CreateIdentity(ctx, link)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
 	"github.com/H3nSte1n/recipe/internal/domain"
@@ -13,6 +15,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"strings"
 	"time"
 )
 
@@ -34,8 +37,27 @@ type userRepository interface {
 	GetVerificationTokenByToken(ctx context.Context, token string) (*domain.EmailVerificationToken, error)
 	MarkVerificationTokenUsed(ctx context.Context, tokenID string) error
 	GetLatestVerificationToken(ctx context.Context, userID string) (*domain.EmailVerificationToken, error)
+	CreateMagicLinkToken(ctx context.Context, token *domain.MagicLinkToken) error
+	ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLinkToken, error)
 	MarkEmailVerified(ctx context.Context, userID string) error
 	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+	GetTokenRevokedAt(ctx context.Context, userID string) (*time.Time, error)
+	CreateSession(ctx context.Context, session *domain.Session) error
+	GetSessionByID(ctx context.Context, id string) (*domain.Session, error)
+	RotateSessionToken(ctx context.Context, id, oldHash, newHash string, client domain.SessionClient, seenAt, expiresAt time.Time) (bool, error)
+	ListActiveSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) (bool, error)
+	RevokeUserSessions(ctx context.Context, userID string) error
+	RevokeUserAPITokens(ctx context.Context, userID string, revokedAt time.Time) error
+	SetTOTPSecret(ctx context.Context, userID string, encryptedSecret string) error
+	EnableTOTP(ctx context.Context, userID string, enabledAt time.Time, step int64) error
+	DisableTOTP(ctx context.Context, userID string) error
+	AdvanceTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error)
+	GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	CreateOIDCState(ctx context.Context, state *domain.OIDCLoginState) error
+	ConsumeOIDCState(ctx context.Context, stateHash string, now time.Time) (*domain.OIDCLoginState, error)
 	WithTypedTransaction(ctx context.Context, fn func(repository.UserRepository) error) error
 }
 
@@ -52,7 +74,19 @@ const (
 
 type UserService interface {
 	Register(ctx context.Context, req *domain.RegisterRequest) (*domain.User, error)
-	Login(ctx context.Context, req *domain.LoginRequest) (*domain.LoginResponse, error)
+	Login(ctx context.Context, req *domain.LoginRequest, client domain.SessionClient) (*domain.LoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string, client domain.SessionClient) (*domain.TokenPair, error)
+	ListSessions(ctx context.Context, userID string, currentSessionID string) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID string, sessionID string) error
+	CompleteTwoFactorLogin(ctx context.Context, req *domain.TwoFactorLoginRequest, client domain.SessionClient) (*domain.LoginResponse, error)
+	EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID string, code string) (*domain.RecoveryCodesResponse, error)
+	DisableTOTP(ctx context.Context, userID string, req *domain.DisableTOTPRequest, client domain.SessionClient) error
+	ListOIDCProviders() []domain.OIDCProvider
+	StartOIDCLogin(ctx context.Context, provider string) (*domain.OIDCStartResponse, error)
+	CompleteOIDCLogin(ctx context.Context, provider string, req *domain.OIDCCallbackRequest, client domain.SessionClient) (*domain.LoginResponse, error)
+	RequestMagicLink(ctx context.Context, req *domain.MagicLinkRequest) error
+	MagicLinkLogin(ctx context.Context, req *domain.MagicLinkLoginRequest, client domain.SessionClient) (*domain.LoginResponse, error)
 	ValidateToken(token string) (*jwt.Token, error)
 	ForgotPassword(ctx context.Context, req *domain.ForgotPasswordRequest) error
 	ResetPassword(ctx context.Context, req *domain.ResetPasswordRequest) error
@@ -61,6 +95,7 @@ type UserService interface {
 	VerifyEmail(ctx context.Context, req *domain.VerifyEmailRequest) error
 	ResendVerification(ctx context.Context, req *domain.ResendVerificationRequest) error
 	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+	GetRole(ctx context.Context, userID string) (string, error)
 }
 
 // verificationTokenTTL is how long a freshly issued email-verification token
@@ -73,25 +108,47 @@ const verificationTokenTTL = 24 * time.Hour
 // middleware/infra, since that infra doesn't exist yet in this codebase.
 const resendVerificationCooldown = 60 * time.Second
 
+// maxSessionUserAgentLen bounds the client-supplied User-Agent kept on a
+// session; it is only shown back to the owner to recognise the device.
+const maxSessionUserAgentLen = 512
+
+// errInvalidRefreshToken is returned for every refresh failure so the response
+// does not reveal whether a session exists, expired or was revoked.
+var errInvalidRefreshToken = apperrors.New("invalid refresh token")
+
+// errAdminLocked rejects sign-in to an account an administrator has locked.
+var errAdminLocked = apperrors.ErrAccountLocked.Wrap("account locked by an administrator")
+
 type userService struct {
-	userRepo     userRepository
-	jwtSecret    []byte
-	jwtDuration  time.Duration
-	jwtIssuer    string
-	jwtAudience  string
-	emailService email.EmailService
-	logger       *zap.Logger
+	userRepo        userRepository
+	jwtSecret       []byte
+	jwtDuration     time.Duration
+	refreshDuration time.Duration
+	jwtIssuer       string
+	jwtAudience     string
+	emailService    email.EmailService
+	cipher          APIKeyCipher
+	oidcProviders   map[string]oidcProvider
+	oidcOptions     []domain.OIDCProvider
+	auditLog        *AuditLog
+	logger          *zap.Logger
 }
 
-func NewUserService(userRepo userRepository, jwtSecret string, config config.Config, emailService email.EmailService, logger *zap.Logger) UserService {
+func NewUserService(userRepo userRepository, jwtSecret string, config config.Config, emailService email.EmailService, cipher APIKeyCipher, auditLog *AuditLog, logger *zap.Logger) UserService {
+	providers, options := newOIDCProviders(config.OIDC)
 	return &userService{
-		userRepo:     userRepo,
-		jwtSecret:    []byte(jwtSecret),
-		jwtDuration:  config.JWT.Duration,
-		jwtIssuer:    config.JWT.Issuer,
-		jwtAudience:  config.JWT.Audience,
-		emailService: emailService,
-		logger:       logger,
+		userRepo:        userRepo,
+		jwtSecret:       []byte(jwtSecret),
+		jwtDuration:     config.JWT.Duration,
+		refreshDuration: config.JWT.RefreshDuration,
+		jwtIssuer:       config.JWT.Issuer,
+		jwtAudience:     config.JWT.Audience,
+		emailService:    emailService,
+		cipher:          cipher,
+		oidcProviders:   providers,
+		oidcOptions:     options,
+		auditLog:        auditLog,
+		logger:          logger,
 	}
 }
 
@@ -117,6 +174,7 @@ func (s *userService) Register(ctx context.Context, req *domain.RegisterRequest)
 			PasswordHash: hashedPassword,
 			FirstName:    req.FirstName,
 			LastName:     req.LastName,
+			Role:         domain.RoleMember,
 		}
 
 		if err := txRepo.Create(ctx, user); err != nil {
@@ -139,15 +197,15 @@ func (s *userService) Register(ctx context.Context, req *domain.RegisterRequest)
 		return nil, err
 	}
 
-	// Issue a verification token and email it. Sending is best-effort: a mail
-	// outage or unconfigured SMTP provider must not block account creation
-	// (unlike ForgotPassword, where the user is actively waiting on the
-	// email). The token is already persisted, so ResendVerification can
-	// recover from a failed send.
+	// Issue a verification token and queue the email. Delivery happens in the
+	// background, so a mail outage no longer delays sign-up; failing to queue
+	// it is logged rather than failing account creation (unlike
+	// ForgotPassword, where the user is actively waiting on the email). The
+	// token is already persisted, so ResendVerification can recover.
 	tokenString, err := s.createVerificationToken(ctx, user.ID)
 	if err != nil {
 		s.logger.Warn("failed to create email verification token", zap.String("user_id", user.ID), zap.Error(err))
-	} else if err := s.emailService.SendVerificationEmail(user.Email, tokenString); err != nil {
+	} else if err := s.emailService.SendVerificationEmail(ctx, user.Email, tokenString); err != nil {
 		s.logger.Warn("failed to send verification email", zap.String("user_id", user.ID), zap.Error(err))
 	}
 
@@ -176,7 +234,7 @@ func (s *userService) createVerificationToken(ctx context.Context, userID string
 	return tokenString, nil
 }
 
-func (s *userService) Login(ctx context.Context, req *domain.LoginRequest) (*domain.LoginResponse, error) {
+func (s *userService) Login(ctx context.Context, req *domain.LoginRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, apperrors.New("invalid credentials")
@@ -196,27 +254,231 @@ func (s *userService) Login(ctx context.Context, req *domain.LoginRequest) (*dom
 	}
 
 	if !domain.CheckPasswordHash(req.Password, user.PasswordHash) {
-		s.recordFailedLogin(ctx, user)
+		s.recordFailedLogin(ctx, user, domain.AuditLoginFailed, client)
 		return nil, apperrors.New("invalid credentials")
 	}
 
+	return s.firstFactorPassed(ctx, user, client)
+}
+
+// firstFactorPassed signs the user in, unless they have two-factor
+// authentication: then the first factor alone does not sign in, and the
+// lockout counter keeps running until the second factor is passed.
+func (s *userService) firstFactorPassed(ctx context.Context, user *domain.User, client domain.SessionClient) (*domain.LoginResponse, error) {
+	if user.IsAdminLocked() {
+		return nil, errAdminLocked
+	}
+
+	if user.TwoFactorEnabled() {
+		challenge, err := s.generateChallengeToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+// completeLogin clears any failed attempts and opens a session for a user who
+// has passed every factor.
+func (s *userService) completeLogin(ctx context.Context, user *domain.User, client domain.SessionClient) (*domain.LoginResponse, error) {
+	// Checked again here for the second factor: the account may have been
+	// locked after the challenge was issued.
+	if user.IsAdminLocked() {
+		return nil, errAdminLocked
+	}
+
 	if user.FailedLoginAttempts > 0 {
 		if err := s.userRepo.ResetLoginLockout(ctx, user.ID); err != nil {
 			s.logger.Warn("failed to reset login lockout state after successful login", zap.Error(err))
 		}
 	}
 
-	token, err := s.generateToken(user)
+	tokens, err := s.openSession(ctx, user, client)
 	if err != nil {
 		return nil, err
 	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(user.ID),
+		Action:     domain.AuditLoginSucceeded,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+	})
 
 	return &domain.LoginResponse{
-		Token: token,
-		User:  *user,
+		TokenPair: *tokens,
+		User:      user,
+	}, nil
+}
+
+// openSession records a new signed-in device for user and issues its first
+// token pair.
+func (s *userService) openSession(ctx context.Context, user *domain.User, client domain.SessionClient) (*domain.TokenPair, error) {
+	secret, hash, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: hash,
+		UserAgent:        truncateUserAgent(client.UserAgent),
+		IPAddress:        client.IPAddress,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(s.refreshDuration),
+	}
+	if err := s.userRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session.ID, secret, now)
+}
+
+// Refresh exchanges a refresh token for a new token pair. The presented token
+// is single-use: it is rotated out on success, and presenting it again is
+// taken as a sign it was stolen, so the whole session is revoked. Any other
+// wrong secret is only rejected, since session IDs are not secret.
+func (s *userService) Refresh(ctx context.Context, refreshToken string, client domain.SessionClient) (*domain.TokenPair, error) {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return nil, errInvalidRefreshToken
+	}
+
+	session, err := s.userRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if !apperrors.IsNotFound(err) {
+			s.logger.Warn("failed to load session for refresh", zap.Error(err))
+		}
+		return nil, errInvalidRefreshToken
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, errInvalidRefreshToken
+	}
+
+	presentedHash := hashRefreshSecret(secret)
+	if subtle.ConstantTimeCompare([]byte(presentedHash), []byte(session.RefreshTokenHash)) != 1 {
+		if session.PreviousRefreshTokenHash != "" &&
+			subtle.ConstantTimeCompare([]byte(presentedHash), []byte(session.PreviousRefreshTokenHash)) == 1 {
+			s.revokeReusedSession(ctx, session)
+		}
+		return nil, errInvalidRefreshToken
+	}
+
+	// A password reset or account deletion revokes every token issued before
+	// it; a session opened before then must not mint fresh ones.
+	revokedAt, err := s.userRepo.GetTokenRevokedAt(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt != nil && session.CreatedAt.Before(*revokedAt) {
+		return nil, errInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil || user.IsAdminLocked() {
+		return nil, errInvalidRefreshToken
+	}
+
+	newSecret, newHash, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+	client.UserAgent = truncateUserAgent(client.UserAgent)
+	rotated, err := s.userRepo.RotateSessionToken(ctx, session.ID, presentedHash, newHash, client, now, now.Add(s.refreshDuration))
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request rotated this token first: the same token was used twice.
+		s.revokeReusedSession(ctx, session)
+		return nil, errInvalidRefreshToken
+	}
+
+	return s.issueTokens(user, session.ID, newSecret, now)
+}
+
+// revokeReusedSession ends a session whose refresh token was presented after
+// it had already been rotated out.
+func (s *userService) revokeReusedSession(ctx context.Context, session *domain.Session) {
+	s.logger.Warn("refresh token reuse detected, revoking session",
+		zap.String("user_id", session.UserID),
+		zap.String("session_id", session.ID))
+	if _, err := s.userRepo.RevokeSession(ctx, session.UserID, session.ID); err != nil {
+		s.logger.Error("failed to revoke session after refresh token reuse", zap.Error(err))
+	}
+}
+
+func (s *userService) ListSessions(ctx context.Context, userID string, currentSessionID string) ([]domain.Session, error) {
+	sessions, err := s.userRepo.ListActiveSessions(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one device out. Its refresh token stops working at once
+// and its access tokens are rejected by the auth middleware.
+func (s *userService) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	revoked, err := s.userRepo.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return apperrors.ErrNotFound.Wrap("session not found")
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(userID),
+		Action:     domain.AuditSessionRevoked,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   sessionID,
+	})
+	return nil
+}
+
+func (s *userService) issueTokens(user *domain.User, sessionID, secret string, now time.Time) (*domain.TokenPair, error) {
+	token, err := s.generateToken(user, sessionID, now)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TokenPair{
+		Token:        token,
+		RefreshToken: sessionID + "." + secret,
+		ExpiresAt:    now.Add(s.jwtDuration),
 	}, nil
 }
 
+// newRefreshSecret returns a random refresh secret and the hash stored for it.
+// Only the hash is persisted, so a database leak does not yield usable tokens.
+func newRefreshSecret() (secret, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret = hex.EncodeToString(b)
+	return secret, hashRefreshSecret(secret), nil
+}
+
+func hashRefreshSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) <= maxSessionUserAgentLen {
+		return userAgent
+	}
+	return strings.ToValidUTF8(userAgent[:maxSessionUserAgentLen], "")
+}
+
 // recordFailedLogin increments the account's failed-login counter and, once
 // maxFailedLoginAttempts is reached, sets a locked-until cooldown. The increment happens
 // atomically in the repository (a single UPDATE, not a read-then-write here) so concurrent
@@ -224,10 +486,35 @@ func (s *userService) Login(ctx context.Context, req *domain.LoginRequest) (*dom
 // RecordFailedLogin's doc comment. Persistence failures are logged but not surfaced to the
 // caller — Login already returns "invalid credentials" for the bad password itself, and a
 // lockout-bookkeeping error shouldn't turn into a 500 on top of that.
-func (s *userService) recordFailedLogin(ctx context.Context, user *domain.User) {
+//
+// The attempt is audited as action, with no actor since whoever tried has not
+// proven who they are, and a second event is audited if it locked the account.
+func (s *userService) recordFailedLogin(ctx context.Context, user *domain.User, action string, client domain.SessionClient) {
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		Action:     action,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+	})
+
 	lockUntil := time.Now().Add(accountLockDuration)
-	if _, _, err := s.userRepo.RecordFailedLogin(ctx, user.ID, maxFailedLoginAttempts, lockUntil); err != nil {
+	_, lockedUntil, err := s.userRepo.RecordFailedLogin(ctx, user.ID, maxFailedLoginAttempts, lockUntil)
+	if err != nil {
 		s.logger.Warn("failed to record failed login attempt", zap.Error(err))
+		return
+	}
+	// An account that was already locked is rejected before the password is
+	// checked, so a lock returned here was set by this attempt.
+	if lockedUntil != nil {
+		s.auditLog.Record(ctx, domain.AuditEvent{
+			Action:     domain.AuditAccountLocked,
+			TargetType: domain.AuditTargetUser,
+			TargetID:   user.ID,
+			IPAddress:  client.IPAddress,
+			UserAgent:  client.UserAgent,
+			Changes:    domain.AuditChanges{"locked_until": {From: nil, To: lockedUntil.UTC()}},
+		})
 	}
 }
 
@@ -255,8 +542,13 @@ func (s *userService) ForgotPassword(ctx context.Context, req *domain.ForgotPass
 	if err := s.userRepo.CreateResetToken(ctx, resetToken); err != nil {
 		return err
 	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditPasswordResetRequested,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+	})
 
-	return s.emailService.SendPasswordResetEmail(user.Email, tokenString)
+	return s.emailService.SendPasswordResetEmail(ctx, user.Email, tokenString)
 }
 
 func (s *userService) ResetPassword(ctx context.Context, req *domain.ResetPasswordRequest) error {
@@ -279,17 +571,39 @@ func (s *userService) ResetPassword(ctx context.Context, req *domain.ResetPasswo
 		return err
 	}
 
-	return s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+	err = s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
 		if err := txRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
 			return err
 		}
 		if err := txRepo.MarkResetTokenUsed(ctx, resetToken.ID); err != nil {
 			return err
 		}
-		// Invalidate any JWT issued before this moment so a token obtained
-		// prior to the reset (e.g. by an attacker) can't keep working.
-		return txRepo.SetTokenRevocation(ctx, user.ID, time.Now())
+		// Sign every device out so a refresh token obtained before the reset
+		// can't mint new access tokens.
+		if err := txRepo.RevokeUserSessions(ctx, user.ID); err != nil {
+			return err
+		}
+		// Invalidate any JWT issued before this moment, and every personal
+		// access token, so a token obtained prior to the reset (e.g. by an
+		// attacker) can't keep working.
+		now := time.Now()
+		if err := txRepo.RevokeUserAPITokens(ctx, user.ID, now); err != nil {
+			return err
+		}
+		return txRepo.SetTokenRevocation(ctx, user.ID, now)
 	})
+	if err != nil {
+		return err
+	}
+
+	// Holding the emailed token is the only proof of identity here, so the
+	// reset has no actor.
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditPasswordReset,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+	})
+	return nil
 }
 
 // VerifyEmail redeems a verification token and marks the owning user as
@@ -350,7 +664,7 @@ func (s *userService) ResendVerification(ctx context.Context, req *domain.Resend
 		return nil
 	}
 
-	if err := s.emailService.SendVerificationEmail(user.Email, tokenString); err != nil {
+	if err := s.emailService.SendVerificationEmail(ctx, user.Email, tokenString); err != nil {
 		s.logger.Warn("failed to send verification email for resend", zap.String("user_id", user.ID), zap.Error(err))
 	}
 	return nil
@@ -362,11 +676,21 @@ func (s *userService) IsEmailVerified(ctx context.Context, userID string) (bool,
 	return s.userRepo.IsEmailVerified(ctx, userID)
 }
 
-func (s *userService) generateToken(user *domain.User) (string, error) {
-	now := time.Now()
+func (s *userService) GetRole(ctx context.Context, userID string) (string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// generateToken issues an access token bound to sessionID via the "sid"
+// claim, so revoking the session also rejects its outstanding access tokens.
+func (s *userService) generateToken(user *domain.User, sessionID string, now time.Time) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": user.ID,
 		"email":   user.Email,
+		"sid":     sessionID,
 		"iss":     s.jwtIssuer,
 		"aud":     s.jwtAudience,
 		"iat":     now.Unix(),
@@ -399,7 +723,7 @@ func (s *userService) Delete(ctx context.Context, userID string) error {
 		}
 		return err
 	}
-	return s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+	err = s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
 		// Revoke first: token_revocations has no FK to users, so it survives
 		// the delete and keeps rejecting any JWT issued before this moment.
 		if err := txRepo.SetTokenRevocation(ctx, user.ID, time.Now()); err != nil {
@@ -407,6 +731,18 @@ func (s *userService) Delete(ctx context.Context, userID string) error {
 		}
 		return txRepo.Delete(ctx, user.ID)
 	})
+	if err != nil {
+		return err
+	}
+
+	// audit_events has no FK to users either, so the trail outlives the account.
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(user.ID),
+		Action:     domain.AuditAccountDeleted,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+	})
+	return nil
 }
 
 func (s *userService) ListAll(ctx context.Context) ([]domain.UserSummary, error) {
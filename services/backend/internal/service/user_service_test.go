@@ -8,11 +8,15 @@ import (
 	apperrors "github.com/H3nSte1n/recipe/internal/errors"
 	"github.com/H3nSte1n/recipe/internal/repository"
 	"github.com/H3nSte1n/recipe/pkg/config"
+	"github.com/H3nSte1n/recipe/pkg/crypto"
+	"github.com/H3nSte1n/recipe/pkg/oidc/oidctest"
+	"github.com/H3nSte1n/recipe/pkg/totp"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"strings"
 	"testing"
 	"time"
 )
@@ -108,6 +112,17 @@ func (m *mockUserRepository) GetLatestVerificationToken(ctx context.Context, use
 	return v, args.Error(1)
 }
 
+func (m *mockUserRepository) CreateMagicLinkToken(ctx context.Context, token *domain.MagicLinkToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*domain.MagicLinkToken, error) {
+	args := m.Called(ctx, tokenHash, now)
+	v, _ := args.Get(0).(*domain.MagicLinkToken)
+	return v, args.Error(1)
+}
+
 func (m *mockUserRepository) MarkEmailVerified(ctx context.Context, userID string) error {
 	args := m.Called(ctx, userID)
 	return args.Error(0)
@@ -140,6 +155,110 @@ func (m *mockUserRepository) ResetLoginLockout(ctx context.Context, userID strin
 	return args.Error(0)
 }
 
+func (m *mockUserRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) GetSessionByID(ctx context.Context, id string) (*domain.Session, error) {
+	args := m.Called(ctx, id)
+	v, _ := args.Get(0).(*domain.Session)
+	return v, args.Error(1)
+}
+
+func (m *mockUserRepository) RotateSessionToken(ctx context.Context, id, oldHash, newHash string, client domain.SessionClient, seenAt, expiresAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, oldHash, newHash, client, seenAt, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) ListActiveSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error) {
+	args := m.Called(ctx, userID, now)
+	v, _ := args.Get(0).([]domain.Session)
+	return v, args.Error(1)
+}
+
+func (m *mockUserRepository) RevokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) RevokeUserAPITokens(ctx context.Context, userID string, revokedAt time.Time) error {
+	args := m.Called(ctx, userID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) SetTOTPSecret(ctx context.Context, userID string, encryptedSecret string) error {
+	args := m.Called(ctx, userID, encryptedSecret)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) EnableTOTP(ctx context.Context, userID string, enabledAt time.Time, step int64) error {
+	args := m.Called(ctx, userID, enabledAt, step)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) DisableTOTP(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) AdvanceTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	args := m.Called(ctx, userID, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	args := m.Called(ctx, userID, codeHashes)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	args := m.Called(ctx, provider, subject)
+	v, _ := args.Get(0).(*domain.UserIdentity)
+	return v, args.Error(1)
+}
+
+func (m *mockUserRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) CreateOIDCState(ctx context.Context, state *domain.OIDCLoginState) error {
+	args := m.Called(ctx, state)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) ConsumeOIDCState(ctx context.Context, stateHash string, now time.Time) (*domain.OIDCLoginState, error) {
+	args := m.Called(ctx, stateHash, now)
+	v, _ := args.Get(0).(*domain.OIDCLoginState)
+	return v, args.Error(1)
+}
+
+func (m *mockUserRepository) SetRole(ctx context.Context, userID, role string) error {
+	args := m.Called(ctx, userID, role)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) SetAdminLock(ctx context.Context, userID string, lockedAt *time.Time) error {
+	args := m.Called(ctx, userID, lockedAt)
+	return args.Error(0)
+}
+
 func (m *mockUserRepository) WithTypedTransaction(ctx context.Context, fn func(repository.UserRepository) error) error {
 	args := m.Called(ctx, fn)
 	if args.Error(0) != nil {
@@ -152,13 +271,23 @@ type mockEmailService struct {
 	mock.Mock
 }
 
-func (m *mockEmailService) SendPasswordResetEmail(to, resetToken string) error {
-	args := m.Called(to, resetToken)
+func (m *mockEmailService) SendPasswordResetEmail(ctx context.Context, to, resetToken string) error {
+	args := m.Called(ctx, to, resetToken)
+	return args.Error(0)
+}
+
+func (m *mockEmailService) SendVerificationEmail(ctx context.Context, to, verificationToken string) error {
+	args := m.Called(ctx, to, verificationToken)
+	return args.Error(0)
+}
+
+func (m *mockEmailService) SendMagicLinkEmail(ctx context.Context, to, loginToken string) error {
+	args := m.Called(ctx, to, loginToken)
 	return args.Error(0)
 }
 
-func (m *mockEmailService) SendVerificationEmail(to, verificationToken string) error {
-	args := m.Called(to, verificationToken)
+func (m *mockEmailService) SendDataExportEmail(ctx context.Context, to, downloadURL string, expiresAt time.Time) error {
+	args := m.Called(ctx, to, downloadURL, expiresAt)
 	return args.Error(0)
 }
 
@@ -268,9 +397,9 @@ func TestUserService_Register(t *testing.T) {
 			m.On("CreateVerificationToken", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 			mEmail := new(mockEmailService)
-			mEmail.On("SendVerificationEmail", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mEmail.On("SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 
-			srv := NewUserService(m, "foobarJWT", config.Config{}, mEmail, zap.NewNop())
+			srv := NewUserService(m, "foobarJWT", config.Config{}, mEmail, nil, nil, zap.NewNop())
 			u, err := srv.Register(context.Background(), &req)
 
 			if tt.expectedErr != "" {
@@ -303,9 +432,9 @@ func TestUserService_Register_SucceedsDespiteVerificationEmailFailure(t *testing
 		m.On("CreateVerificationToken", mock.Anything, mock.Anything).Return(nil).Once()
 
 		mEmail := new(mockEmailService)
-		mEmail.On("SendVerificationEmail", user.Email, mock.AnythingOfType("string")).Return(errors.New("smtp unreachable")).Once()
+		mEmail.On("SendVerificationEmail", mock.Anything, user.Email, mock.AnythingOfType("string")).Return(errors.New("smtp unreachable")).Once()
 
-		srv := NewUserService(m, "foobarJWT", config.Config{}, mEmail, zap.NewNop())
+		srv := NewUserService(m, "foobarJWT", config.Config{}, mEmail, nil, nil, zap.NewNop())
 		u, err := srv.Register(context.Background(), &req)
 
 		require.NoError(t, err)
@@ -325,14 +454,14 @@ func TestUserService_Register_SucceedsDespiteVerificationEmailFailure(t *testing
 		mEmail := new(mockEmailService)
 		// Never called: token creation failed, so there's nothing to send.
 
-		srv := NewUserService(m, "foobarJWT", config.Config{}, mEmail, zap.NewNop())
+		srv := NewUserService(m, "foobarJWT", config.Config{}, mEmail, nil, nil, zap.NewNop())
 		u, err := srv.Register(context.Background(), &req)
 
 		require.NoError(t, err)
 		require.NotNil(t, u)
 		m.AssertExpectations(t)
 		mEmail.AssertExpectations(t)
-		mEmail.AssertNotCalled(t, "SendVerificationEmail", mock.Anything, mock.Anything)
+		mEmail.AssertNotCalled(t, "SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
@@ -379,6 +508,16 @@ func TestUserService_Login(t *testing.T) {
 				m.On("GetByEmail", mock.Anything, req.Email).Return(&lockedUser, nil).Once()
 			},
 		},
+		{
+			name:        "rejects a correct password for an account locked by an administrator",
+			expectedErr: "account locked by an administrator",
+			mockMethod: func(m *mockUserRepository) {
+				adminLockedUser := user
+				lockedAt := time.Now().Add(-time.Hour)
+				adminLockedUser.AdminLockedAt = &lockedAt
+				m.On("GetByEmail", mock.Anything, req.Email).Return(&adminLockedUser, nil).Once()
+			},
+		},
 		{
 			name:        "locks account once the failed-attempt threshold is reached",
 			expectedErr: "invalid credentials",
@@ -418,8 +557,16 @@ func TestUserService_Login(t *testing.T) {
 			m := new(mockUserRepository)
 			tt.mockMethod(m)
 
-			srv := NewUserService(m, "foobarJWT", config.Config{}, nil, zap.NewNop())
-			resp, err := srv.Login(context.Background(), &req)
+			if tt.expectedErr == "" {
+				m.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *domain.Session) bool {
+					return s.UserID == user.ID && s.RefreshTokenHash != "" && s.UserAgent == "test-agent"
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*domain.Session).ID = "session-1"
+				}).Return(nil).Once()
+			}
+
+			srv := NewUserService(m, "foobarJWT", config.Config{}, nil, nil, nil, zap.NewNop())
+			resp, err := srv.Login(context.Background(), &req, domain.SessionClient{UserAgent: "test-agent", IPAddress: "203.0.113.7"})
 
 			if tt.expectedErr != "" {
 				require.ErrorContains(t, err, tt.expectedErr)
@@ -428,6 +575,7 @@ func TestUserService_Login(t *testing.T) {
 				require.NoError(t, err)
 				require.NotNil(t, resp)
 				require.NotEmpty(t, resp.Token)
+				require.True(t, strings.HasPrefix(resp.RefreshToken, "session-1."))
 				require.Equal(t, user.ID, resp.User.ID)
 				require.Equal(t, user.Email, resp.User.Email)
 			}
@@ -473,7 +621,7 @@ func TestUserService_ForgotPassword(t *testing.T) {
 				})).Return(nil).Once()
 			},
 			mockEmail: func(m *mockEmailService) {
-				m.On("SendPasswordResetEmail", user.Email, mock.AnythingOfType("string")).Return(nil).Once()
+				m.On("SendPasswordResetEmail", mock.Anything, user.Email, mock.AnythingOfType("string")).Return(nil).Once()
 			},
 		},
 		{
@@ -484,7 +632,7 @@ func TestUserService_ForgotPassword(t *testing.T) {
 				m.On("CreateResetToken", mock.Anything, mock.Anything).Return(nil).Once()
 			},
 			mockEmail: func(m *mockEmailService) {
-				m.On("SendPasswordResetEmail", user.Email, mock.MatchedBy(func(token string) bool {
+				m.On("SendPasswordResetEmail", mock.Anything, user.Email, mock.MatchedBy(func(token string) bool {
 					return len(token) == 64
 				})).Return(nil).Once()
 			},
@@ -498,7 +646,7 @@ func TestUserService_ForgotPassword(t *testing.T) {
 				m.On("CreateResetToken", mock.Anything, mock.Anything).Return(nil).Once()
 			},
 			mockEmail: func(m *mockEmailService) {
-				m.On("SendPasswordResetEmail", user.Email, mock.MatchedBy(func(token string) bool {
+				m.On("SendPasswordResetEmail", mock.Anything, user.Email, mock.MatchedBy(func(token string) bool {
 					return len(token) == 64
 				})).Return(errors.New("failed to send password reset email")).Once()
 			},
@@ -516,7 +664,7 @@ func TestUserService_ForgotPassword(t *testing.T) {
 				tt.mockEmail(mEmail)
 			}
 
-			srv := NewUserService(mRepo, "foobar", config.Config{}, mEmail, zap.NewNop())
+			srv := NewUserService(mRepo, "foobar", config.Config{}, mEmail, nil, nil, zap.NewNop())
 			err := srv.ForgotPassword(context.Background(), &req)
 
 			if tt.expectedErr != "" {
@@ -528,7 +676,7 @@ func TestUserService_ForgotPassword(t *testing.T) {
 			mEmail.AssertExpectations(t)
 
 			if !tt.shouldSendEmail {
-				mEmail.AssertNotCalled(t, "SendPasswordResetEmail", mock.Anything, mock.Anything)
+				mEmail.AssertNotCalled(t, "SendPasswordResetEmail", mock.Anything, mock.Anything, mock.Anything)
 			}
 		})
 	}
@@ -612,6 +760,8 @@ func TestUserService_ResetPassword(t *testing.T) {
 				m.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
 				m.On("UpdatePassword", mock.Anything, user.ID, mock.AnythingOfType("string")).Return(nil).Once()
 				m.On("MarkResetTokenUsed", mock.Anything, resetTokenValid.ID).Return(nil).Once()
+				m.On("RevokeUserSessions", mock.Anything, user.ID).Return(nil).Once()
+				m.On("RevokeUserAPITokens", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
 				m.On("SetTokenRevocation", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
 			},
 		},
@@ -622,7 +772,7 @@ func TestUserService_ResetPassword(t *testing.T) {
 			m := new(mockUserRepository)
 			tt.mockMethod(m)
 
-			srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), zap.NewNop())
+			srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), nil, nil, zap.NewNop())
 			err := srv.ResetPassword(context.Background(), &req)
 
 			if tt.expectedErr != "" {
@@ -683,7 +833,7 @@ func TestUserService_Delete(t *testing.T) {
 			m := new(mockUserRepository)
 			tt.mockMethod(m)
 
-			srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), zap.NewNop())
+			srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), nil, nil, zap.NewNop())
 			err := srv.Delete(context.Background(), user.ID)
 
 			if tt.expectedErr != "" {
@@ -705,7 +855,7 @@ func TestUserService_ListAll(t *testing.T) {
 		m := new(mockUserRepository)
 		m.On("ListAll", mock.Anything).Return(users, nil).Once()
 
-		srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), zap.NewNop())
+		srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), nil, nil, zap.NewNop())
 		userList, err := srv.ListAll(context.Background())
 
 		require.NoError(t, err)
@@ -722,7 +872,7 @@ func TestUserService_ListAll(t *testing.T) {
 		m := new(mockUserRepository)
 		m.On("ListAll", mock.Anything).Return(nil, expectedErr).Once()
 
-		srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), zap.NewNop())
+		srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), nil, nil, zap.NewNop())
 		_, err := srv.ListAll(context.Background())
 
 		require.ErrorIs(t, err, expectedErr)
@@ -793,7 +943,7 @@ func TestUserService_VerifyEmail(t *testing.T) {
 			m := new(mockUserRepository)
 			tt.mockMethod(m)
 
-			srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), zap.NewNop())
+			srv := NewUserService(m, "foobar", config.Config{}, new(mockEmailService), nil, nil, zap.NewNop())
 			err := srv.VerifyEmail(context.Background(), &req)
 
 			if tt.expectedErr != "" {
@@ -884,10 +1034,10 @@ func TestUserService_ResendVerification(t *testing.T) {
 			mEmail := new(mockEmailService)
 			tt.mockRepo(mRepo)
 			if tt.shouldSendEmail {
-				mEmail.On("SendVerificationEmail", req.Email, mock.AnythingOfType("string")).Return(tt.sendEmailErr).Once()
+				mEmail.On("SendVerificationEmail", mock.Anything, req.Email, mock.AnythingOfType("string")).Return(tt.sendEmailErr).Once()
 			}
 
-			srv := NewUserService(mRepo, "foobar", config.Config{}, mEmail, zap.NewNop())
+			srv := NewUserService(mRepo, "foobar", config.Config{}, mEmail, nil, nil, zap.NewNop())
 			err := srv.ResendVerification(context.Background(), &req)
 
 			if tt.expectedErr != "" {
@@ -900,3 +1050,640 @@ func TestUserService_ResendVerification(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_Refresh(t *testing.T) {
+	const secret = "secret-1"
+	user := domain.User{ID: "1_foo", Email: "foo@bar.com"}
+	activeSession := func() *domain.Session {
+		return &domain.Session{
+			ID:               "session-1",
+			UserID:           user.ID,
+			RefreshTokenHash: hashRefreshSecret(secret),
+			ExpiresAt:        time.Now().Add(time.Hour),
+			CreatedAt:        time.Now().Add(-time.Hour),
+		}
+	}
+	client := domain.SessionClient{UserAgent: "test-agent", IPAddress: "203.0.113.7"}
+
+	t.Run("rotates the refresh token", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetSessionByID", mock.Anything, "session-1").Return(activeSession(), nil).Once()
+		m.On("GetTokenRevokedAt", mock.Anything, user.ID).Return(nil, nil).Once()
+		m.On("GetByID", mock.Anything, user.ID).Return(&user, nil).Once()
+		m.On("RotateSessionToken", mock.Anything, "session-1", hashRefreshSecret(secret), mock.AnythingOfType("string"), client,
+			mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		tokens, err := srv.Refresh(context.Background(), "session-1."+secret, client)
+
+		require.NoError(t, err)
+		require.NotEmpty(t, tokens.Token)
+		require.True(t, strings.HasPrefix(tokens.RefreshToken, "session-1."))
+		require.NotEqual(t, "session-1."+secret, tokens.RefreshToken)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("revokes the session when a rotated-out token is reused", func(t *testing.T) {
+		session := activeSession()
+		session.PreviousRefreshTokenHash = hashRefreshSecret("old-secret")
+		m := new(mockUserRepository)
+		m.On("GetSessionByID", mock.Anything, "session-1").Return(session, nil).Once()
+		m.On("RevokeSession", mock.Anything, user.ID, "session-1").Return(true, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		_, err := srv.Refresh(context.Background(), "session-1.old-secret", client)
+
+		require.ErrorIs(t, err, errInvalidRefreshToken)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("only rejects a secret the session never issued", func(t *testing.T) {
+		session := activeSession()
+		session.PreviousRefreshTokenHash = hashRefreshSecret("old-secret")
+		m := new(mockUserRepository)
+		m.On("GetSessionByID", mock.Anything, "session-1").Return(session, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		_, err := srv.Refresh(context.Background(), "session-1.x", client)
+
+		require.ErrorIs(t, err, errInvalidRefreshToken)
+		m.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything, mock.Anything)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("revokes the session when a concurrent refresh won the rotation", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetSessionByID", mock.Anything, "session-1").Return(activeSession(), nil).Once()
+		m.On("GetTokenRevokedAt", mock.Anything, user.ID).Return(nil, nil).Once()
+		m.On("GetByID", mock.Anything, user.ID).Return(&user, nil).Once()
+		m.On("RotateSessionToken", mock.Anything, "session-1", hashRefreshSecret(secret), mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything).Return(false, nil).Once()
+		m.On("RevokeSession", mock.Anything, user.ID, "session-1").Return(true, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		_, err := srv.Refresh(context.Background(), "session-1."+secret, client)
+
+		require.ErrorIs(t, err, errInvalidRefreshToken)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects a session opened before a user-wide revocation", func(t *testing.T) {
+		revokedAt := time.Now()
+		m := new(mockUserRepository)
+		m.On("GetSessionByID", mock.Anything, "session-1").Return(activeSession(), nil).Once()
+		m.On("GetTokenRevokedAt", mock.Anything, user.ID).Return(&revokedAt, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		_, err := srv.Refresh(context.Background(), "session-1."+secret, client)
+
+		require.ErrorIs(t, err, errInvalidRefreshToken)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("rejects revoked, expired and malformed tokens", func(t *testing.T) {
+		revoked := activeSession()
+		now := time.Now()
+		revoked.RevokedAt = &now
+		expired := activeSession()
+		expired.ExpiresAt = now.Add(-time.Minute)
+
+		m := new(mockUserRepository)
+		m.On("GetSessionByID", mock.Anything, "session-1").Return(revoked, nil).Once()
+		m.On("GetSessionByID", mock.Anything, "session-1").Return(expired, nil).Once()
+		m.On("GetSessionByID", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		for _, token := range []string{"session-1." + secret, "session-1." + secret, "missing." + secret, "no-separator"} {
+			_, err := srv.Refresh(context.Background(), token, client)
+			require.ErrorIs(t, err, errInvalidRefreshToken, token)
+		}
+		m.AssertExpectations(t)
+	})
+}
+
+func TestUserService_Sessions(t *testing.T) {
+	t.Run("marks the current session", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("ListActiveSessions", mock.Anything, "1_foo", mock.AnythingOfType("time.Time")).
+			Return([]domain.Session{{ID: "session-1"}, {ID: "session-2"}}, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		sessions, err := srv.ListSessions(context.Background(), "1_foo", "session-2")
+
+		require.NoError(t, err)
+		require.False(t, sessions[0].Current)
+		require.True(t, sessions[1].Current)
+	})
+
+	t.Run("revoking another user's session reports not found", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("RevokeSession", mock.Anything, "1_foo", "session-9").Return(false, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		err := srv.RevokeSession(context.Background(), "1_foo", "session-9")
+
+		require.True(t, apperrors.IsNotFound(err))
+	})
+}
+
+func TestUserService_TwoFactor(t *testing.T) {
+	cipher, err := crypto.NewCipher("test-encryption-key")
+	require.NoError(t, err)
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	encrypted, err := cipher.Encrypt(secret)
+	require.NoError(t, err)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	enabledUser := func() *domain.User {
+		enabledAt := time.Now().Add(-time.Hour)
+		return &domain.User{ID: "1_foo", Email: "foo@bar.com", PasswordHash: string(hash), TOTPSecret: encrypted, TOTPEnabledAt: &enabledAt}
+	}
+	currentCode := func(t *testing.T) string {
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		return code
+	}
+
+	t.Run("login with 2FA enabled returns only a challenge", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByEmail", mock.Anything, "foo@bar.com").Return(enabledUser(), nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		resp, err := srv.Login(context.Background(), &domain.LoginRequest{Email: "foo@bar.com", Password: "password"}, domain.SessionClient{})
+
+		require.NoError(t, err)
+		require.True(t, resp.TwoFactorRequired)
+		require.NotEmpty(t, resp.ChallengeToken)
+		require.Empty(t, resp.Token)
+		require.Nil(t, resp.User)
+		m.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("challenge and TOTP code complete the login", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByEmail", mock.Anything, "foo@bar.com").Return(enabledUser(), nil).Once()
+		m.On("GetByID", mock.Anything, "1_foo").Return(enabledUser(), nil).Once()
+		m.On("AdvanceTOTPStep", mock.Anything, "1_foo", mock.AnythingOfType("int64")).Return(true, nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		challenge, err := srv.Login(context.Background(), &domain.LoginRequest{Email: "foo@bar.com", Password: "password"}, domain.SessionClient{})
+		require.NoError(t, err)
+
+		resp, err := srv.CompleteTwoFactorLogin(context.Background(), &domain.TwoFactorLoginRequest{
+			ChallengeToken: challenge.ChallengeToken,
+			Code:           currentCode(t),
+		}, domain.SessionClient{})
+
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Token)
+		require.Equal(t, "1_foo", resp.User.ID)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("challenge token is not accepted as an access token", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByEmail", mock.Anything, "foo@bar.com").Return(enabledUser(), nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		resp, err := srv.Login(context.Background(), &domain.LoginRequest{Email: "foo@bar.com", Password: "password"}, domain.SessionClient{})
+		require.NoError(t, err)
+
+		_, err = srv.ValidateToken(resp.ChallengeToken)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong code counts as a failed login", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(enabledUser(), nil).Once()
+		m.On("UseRecoveryCode", mock.Anything, "1_foo", mock.Anything).Return(false, nil).Once()
+		m.On("RecordFailedLogin", mock.Anything, "1_foo", maxFailedLoginAttempts, mock.AnythingOfType("time.Time")).Return(1, nil, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		challenge, err := srv.(*userService).generateChallengeToken(enabledUser())
+		require.NoError(t, err)
+
+		_, err = srv.CompleteTwoFactorLogin(context.Background(), &domain.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "000000"}, domain.SessionClient{})
+
+		require.True(t, apperrors.IsInvalid2FACode(err))
+		m.AssertExpectations(t)
+	})
+
+	t.Run("a replayed TOTP step is rejected", func(t *testing.T) {
+		user := enabledUser()
+		user.TOTPLastStep = totp.Step(time.Now()) + totp.Skew
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(user, nil).Once()
+		m.On("RecordFailedLogin", mock.Anything, "1_foo", maxFailedLoginAttempts, mock.AnythingOfType("time.Time")).Return(1, nil, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		challenge, err := srv.(*userService).generateChallengeToken(user)
+		require.NoError(t, err)
+
+		_, err = srv.CompleteTwoFactorLogin(context.Background(), &domain.TwoFactorLoginRequest{ChallengeToken: challenge, Code: currentCode(t)}, domain.SessionClient{})
+
+		require.True(t, apperrors.IsInvalid2FACode(err))
+		m.AssertNotCalled(t, "AdvanceTOTPStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("recovery code is accepted in any case and spacing", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(enabledUser(), nil).Once()
+		m.On("UseRecoveryCode", mock.Anything, "1_foo", hashRefreshSecret("1a2b3c4d5e")).Return(true, nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		challenge, err := srv.(*userService).generateChallengeToken(enabledUser())
+		require.NoError(t, err)
+
+		resp, err := srv.CompleteTwoFactorLogin(context.Background(), &domain.TwoFactorLoginRequest{ChallengeToken: challenge, Code: " 1A2B3-C4D5E "}, domain.SessionClient{})
+
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Token)
+	})
+
+	t.Run("enroll then confirm enables 2FA and returns recovery codes", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(&domain.User{ID: "1_foo", Email: "foo@bar.com", PasswordHash: string(hash)}, nil).Once()
+
+		var stored string
+		m.On("SetTOTPSecret", mock.Anything, "1_foo", mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { stored = args.String(2) }).Return(nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{JWT: config.JWTConfig{Issuer: "recipe"}}, nil, cipher, nil, zap.NewNop())
+		enrollment, err := srv.EnrollTOTP(context.Background(), "1_foo")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/recipe:foo@bar.com?"))
+		require.NotEqual(t, enrollment.Secret, stored, "secret must be stored encrypted")
+
+		m.On("GetByID", mock.Anything, "1_foo").Return(&domain.User{ID: "1_foo", TOTPSecret: stored}, nil).Once()
+		m.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("ReplaceRecoveryCodes", mock.Anything, "1_foo", mock.MatchedBy(func(h []string) bool { return len(h) == recoveryCodeCount })).Return(nil).Once()
+		m.On("EnableTOTP", mock.Anything, "1_foo", mock.AnythingOfType("time.Time"), mock.AnythingOfType("int64")).Return(nil).Once()
+
+		code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		codes, err := srv.ConfirmTOTP(context.Background(), "1_foo", code)
+
+		require.NoError(t, err)
+		require.Len(t, codes.RecoveryCodes, recoveryCodeCount)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("enroll is refused when 2FA is already enabled", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(enabledUser(), nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		_, err := srv.EnrollTOTP(context.Background(), "1_foo")
+
+		require.True(t, apperrors.IsConflict(err))
+	})
+
+	t.Run("enroll is refused without a password to disable it with", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(&domain.User{ID: "1_foo", Email: "foo@bar.com"}, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		_, err := srv.EnrollTOTP(context.Background(), "1_foo")
+
+		require.True(t, apperrors.IsInvalidInput(err))
+		m.AssertNotCalled(t, "SetTOTPSecret", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("disable counts a wrong password or code towards the lockout", func(t *testing.T) {
+		for name, req := range map[string]*domain.DisableTOTPRequest{
+			"password": {Password: "wrong", Code: currentCode(t)},
+			"code":     {Password: "password", Code: "000000"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				m := new(mockUserRepository)
+				m.On("GetByID", mock.Anything, "1_foo").Return(enabledUser(), nil).Once()
+				m.On("UseRecoveryCode", mock.Anything, "1_foo", mock.Anything).Return(false, nil).Maybe()
+				m.On("RecordFailedLogin", mock.Anything, "1_foo", maxFailedLoginAttempts, mock.AnythingOfType("time.Time")).Return(1, nil, nil).Once()
+
+				srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+				err := srv.DisableTOTP(context.Background(), "1_foo", req, domain.SessionClient{})
+
+				require.ErrorIs(t, err, apperrors.ErrInvalid2FACode)
+				m.AssertExpectations(t)
+				m.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("disable is refused while the account is locked", func(t *testing.T) {
+		user := enabledUser()
+		lockedUntil := time.Now().Add(time.Minute)
+		user.LockedUntil = &lockedUntil
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(user, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		err := srv.DisableTOTP(context.Background(), "1_foo", &domain.DisableTOTPRequest{Password: "password", Code: currentCode(t)}, domain.SessionClient{})
+
+		require.True(t, apperrors.IsInvalid2FACode(err))
+		m.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+	})
+
+	t.Run("disable with password and code", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("GetByID", mock.Anything, "1_foo").Return(enabledUser(), nil).Once()
+		m.On("AdvanceTOTPStep", mock.Anything, "1_foo", mock.AnythingOfType("int64")).Return(true, nil).Once()
+		m.On("DisableTOTP", mock.Anything, "1_foo").Return(nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, cipher, nil, zap.NewNop())
+		err := srv.DisableTOTP(context.Background(), "1_foo", &domain.DisableTOTPRequest{Password: "password", Code: currentCode(t)}, domain.SessionClient{})
+
+		require.NoError(t, err)
+		m.AssertExpectations(t)
+	})
+}
+
+func TestUserService_OIDCLogin(t *testing.T) {
+	idp, err := oidctest.NewProvider("recipe-client")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	cfg := config.Config{OIDC: config.OIDCConfig{Providers: []config.OIDCProviderConfig{{
+		Name:        "keycloak",
+		DisplayName: "Company SSO",
+		IssuerURL:   idp.URL,
+		ClientID:    "recipe-client",
+		RedirectURL: "https://app.example/auth/oidc/keycloak/callback",
+	}}}}
+	ctx := context.Background()
+
+	// signIn runs the browser leg against the stub IdP and returns the
+	// callback request plus the state row StartOIDCLogin stored.
+	signIn := func(t *testing.T, m *mockUserRepository, srv UserService, user oidctest.User) (*domain.OIDCCallbackRequest, *domain.OIDCLoginState) {
+		var stored *domain.OIDCLoginState
+		m.On("CreateOIDCState", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.OIDCLoginState) }).Return(nil).Once()
+
+		start, err := srv.StartOIDCLogin(ctx, "keycloak")
+		require.NoError(t, err)
+		code, state, err := idp.Authorize(start.AuthorizationURL, user)
+		require.NoError(t, err)
+		require.Equal(t, hashRefreshSecret(state), stored.StateHash)
+		require.Equal(t, hashRefreshSecret(start.BrowserBinding), stored.BindingHash)
+
+		m.On("ConsumeOIDCState", mock.Anything, stored.StateHash, mock.AnythingOfType("time.Time")).Return(stored, nil).Once()
+		return &domain.OIDCCallbackRequest{Code: code, State: state, BrowserBinding: start.BrowserBinding}, stored
+	}
+	verified := oidctest.User{Subject: "kc-1", Email: "foo@bar.com", EmailVerified: true, GivenName: "Foo", FamilyName: "Bar"}
+
+	t.Run("lists configured providers", func(t *testing.T) {
+		srv := NewUserService(new(mockUserRepository), "foobar", cfg, nil, nil, nil, zap.NewNop())
+		require.Equal(t, []domain.OIDCProvider{{Name: "keycloak", DisplayName: "Company SSO"}}, srv.ListOIDCProviders())
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		srv := NewUserService(new(mockUserRepository), "foobar", cfg, nil, nil, nil, zap.NewNop())
+		_, err := srv.StartOIDCLogin(ctx, "nope")
+		require.True(t, apperrors.IsNotFound(err))
+	})
+
+	t.Run("linked identity signs in", func(t *testing.T) {
+		m := new(mockUserRepository)
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		req, _ := signIn(t, m, srv, verified)
+
+		m.On("GetIdentity", mock.Anything, "keycloak", "kc-1").Return(&domain.UserIdentity{UserID: "1_foo"}, nil).Once()
+		m.On("GetByID", mock.Anything, "1_foo").Return(&domain.User{ID: "1_foo"}, nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := srv.CompleteOIDCLogin(ctx, "keycloak", req, domain.SessionClient{})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Token)
+		require.Equal(t, "1_foo", resp.User.ID)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("verified email links to the existing account", func(t *testing.T) {
+		m := new(mockUserRepository)
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		req, _ := signIn(t, m, srv, verified)
+
+		verifiedAt := time.Now()
+		m.On("GetIdentity", mock.Anything, "keycloak", "kc-1").Return(nil, gorm.ErrRecordNotFound).Once()
+		m.On("GetByEmail", mock.Anything, "foo@bar.com").Return(&domain.User{ID: "1_foo", EmailVerifiedAt: &verifiedAt}, nil).Once()
+		m.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("CreateIdentity", mock.Anything, mock.MatchedBy(func(i *domain.UserIdentity) bool {
+			return i.UserID == "1_foo" && i.Provider == "keycloak" && i.Subject == "kc-1"
+		})).Return(nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := srv.CompleteOIDCLogin(ctx, "keycloak", req, domain.SessionClient{})
+		require.NoError(t, err)
+		require.Equal(t, "1_foo", resp.User.ID)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("linking an unverified account discards its password, second factor and sessions", func(t *testing.T) {
+		m := new(mockUserRepository)
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		req, _ := signIn(t, m, srv, verified)
+
+		m.On("GetIdentity", mock.Anything, "keycloak", "kc-1").Return(nil, gorm.ErrRecordNotFound).Once()
+		m.On("GetByEmail", mock.Anything, "foo@bar.com").Return(&domain.User{ID: "1_foo"}, nil).Once()
+		m.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("CreateIdentity", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("UpdatePassword", mock.Anything, "1_foo", "").Return(nil).Once()
+		m.On("DisableTOTP", mock.Anything, "1_foo").Return(nil).Once()
+		m.On("RevokeUserSessions", mock.Anything, "1_foo").Return(nil).Once()
+		m.On("MarkEmailVerified", mock.Anything, "1_foo").Return(nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := srv.CompleteOIDCLogin(ctx, "keycloak", req, domain.SessionClient{})
+		require.NoError(t, err)
+		require.True(t, resp.User.IsEmailVerified())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("unknown verified email creates an account", func(t *testing.T) {
+		m := new(mockUserRepository)
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		req, _ := signIn(t, m, srv, verified)
+
+		m.On("GetIdentity", mock.Anything, "keycloak", "kc-1").Return(nil, gorm.ErrRecordNotFound).Once()
+		m.On("GetByEmail", mock.Anything, "foo@bar.com").Return(nil, gorm.ErrRecordNotFound).Once()
+		m.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "foo@bar.com" && u.FirstName == "Foo" && u.IsEmailVerified() && !u.HasPassword()
+		})).Return(nil).Once()
+		m.On("CreateProfile", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("CreateIdentity", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resp, err := srv.CompleteOIDCLogin(ctx, "keycloak", req, domain.SessionClient{})
+		require.NoError(t, err)
+		require.Equal(t, "foo@bar.com", resp.User.Email)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("unverified email is not matched to an account", func(t *testing.T) {
+		m := new(mockUserRepository)
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		unverified := verified
+		unverified.EmailVerified = false
+		req, _ := signIn(t, m, srv, unverified)
+
+		m.On("GetIdentity", mock.Anything, "keycloak", "kc-1").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		_, err := srv.CompleteOIDCLogin(ctx, "keycloak", req, domain.SessionClient{})
+		require.ErrorIs(t, err, errOIDCEmailUnverified)
+		m.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown or reused state is rejected", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("ConsumeOIDCState", mock.Anything, hashRefreshSecret("stale"), mock.AnythingOfType("time.Time")).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		_, err := srv.CompleteOIDCLogin(ctx, "keycloak", &domain.OIDCCallbackRequest{Code: "code", State: "stale"}, domain.SessionClient{})
+		require.ErrorIs(t, err, errInvalidOIDCState)
+	})
+
+	t.Run("a callback from another browser is rejected", func(t *testing.T) {
+		m := new(mockUserRepository)
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		req, _ := signIn(t, m, srv, verified)
+
+		// A victim's browser finishing someone else's sign-in has its own
+		// binding, or none, rather than the one the sign-in was started with.
+		req.BrowserBinding = "victims-binding"
+		_, err := srv.CompleteOIDCLogin(ctx, "keycloak", req, domain.SessionClient{})
+		require.ErrorIs(t, err, errInvalidOIDCState)
+		m.AssertNotCalled(t, "GetIdentity", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("two-factor accounts still get a challenge", func(t *testing.T) {
+		m := new(mockUserRepository)
+		srv := NewUserService(m, "foobar", cfg, nil, nil, nil, zap.NewNop())
+		req, _ := signIn(t, m, srv, verified)
+
+		enabledAt := time.Now()
+		m.On("GetIdentity", mock.Anything, "keycloak", "kc-1").Return(&domain.UserIdentity{UserID: "1_foo"}, nil).Once()
+		m.On("GetByID", mock.Anything, "1_foo").Return(&domain.User{ID: "1_foo", TOTPEnabledAt: &enabledAt}, nil).Once()
+
+		resp, err := srv.CompleteOIDCLogin(ctx, "keycloak", req, domain.SessionClient{})
+		require.NoError(t, err)
+		require.True(t, resp.TwoFactorRequired)
+		m.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_MagicLink(t *testing.T) {
+	verifiedAt := time.Now().Add(-time.Hour)
+	verified := func() *domain.User {
+		return &domain.User{ID: "1_foo", Email: "foo@bar.com", PasswordHash: "hash", EmailVerifiedAt: &verifiedAt}
+	}
+
+	t.Run("emails a link whose token is stored hashed", func(t *testing.T) {
+		m := new(mockUserRepository)
+		e := new(mockEmailService)
+		var stored *domain.MagicLinkToken
+		m.On("GetByEmail", mock.Anything, "foo@bar.com").Return(verified(), nil).Once()
+		m.On("CreateMagicLinkToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.MagicLinkToken)
+		}).Return(nil).Once()
+		var sent string
+		e.On("SendMagicLinkEmail", mock.Anything, "foo@bar.com", mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.String(2)
+		}).Return(nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, e, nil, nil, zap.NewNop())
+		err := srv.RequestMagicLink(context.Background(), &domain.MagicLinkRequest{Email: "foo@bar.com"})
+
+		require.NoError(t, err)
+		require.Equal(t, "1_foo", stored.UserID)
+		require.Equal(t, hashRefreshSecret(sent), stored.TokenHash)
+		require.WithinDuration(t, time.Now().Add(magicLinkTTL), stored.ExpiresAt, time.Minute)
+		m.AssertExpectations(t)
+		e.AssertExpectations(t)
+	})
+
+	t.Run("unknown email sends nothing and does not error", func(t *testing.T) {
+		m := new(mockUserRepository)
+		e := new(mockEmailService)
+		m.On("GetByEmail", mock.Anything, "nobody@bar.com").Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, e, nil, nil, zap.NewNop())
+		err := srv.RequestMagicLink(context.Background(), &domain.MagicLinkRequest{Email: "nobody@bar.com"})
+
+		require.NoError(t, err)
+		m.AssertNotCalled(t, "CreateMagicLinkToken", mock.Anything, mock.Anything)
+		e.AssertNotCalled(t, "SendMagicLinkEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("redeeming the link signs in", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("ConsumeMagicLinkToken", mock.Anything, hashRefreshSecret("secret"), mock.AnythingOfType("time.Time")).
+			Return(&domain.MagicLinkToken{ID: "t1", UserID: "1_foo"}, nil).Once()
+		m.On("GetByID", mock.Anything, "1_foo").Return(verified(), nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		resp, err := srv.MagicLinkLogin(context.Background(), &domain.MagicLinkLoginRequest{Token: "secret"}, domain.SessionClient{})
+
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Token)
+		require.Equal(t, "1_foo", resp.User.ID)
+		m.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("redeeming the link verifies an unverified account", func(t *testing.T) {
+		m := new(mockUserRepository)
+		user := verified()
+		user.EmailVerifiedAt = nil
+		m.On("ConsumeMagicLinkToken", mock.Anything, mock.Anything, mock.Anything).
+			Return(&domain.MagicLinkToken{ID: "t1", UserID: "1_foo"}, nil).Once()
+		m.On("GetByID", mock.Anything, "1_foo").Return(user, nil).Once()
+		m.On("WithTypedTransaction", mock.Anything, mock.Anything).Return(nil).Once()
+		m.On("UpdatePassword", mock.Anything, "1_foo", mock.Anything).Return(nil).Once()
+		m.On("DisableTOTP", mock.Anything, "1_foo").Return(nil).Once()
+		m.On("RevokeUserSessions", mock.Anything, "1_foo").Return(nil).Once()
+		m.On("MarkEmailVerified", mock.Anything, "1_foo").Return(nil).Once()
+		m.On("CreateSession", mock.Anything, mock.Anything).Return(nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		resp, err := srv.MagicLinkLogin(context.Background(), &domain.MagicLinkLoginRequest{Token: "secret"}, domain.SessionClient{})
+
+		require.NoError(t, err)
+		require.True(t, resp.User.IsEmailVerified())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("used or expired link is rejected", func(t *testing.T) {
+		m := new(mockUserRepository)
+		m.On("ConsumeMagicLinkToken", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		_, err := srv.MagicLinkLogin(context.Background(), &domain.MagicLinkLoginRequest{Token: "secret"}, domain.SessionClient{})
+
+		require.ErrorIs(t, err, errInvalidMagicLink)
+		m.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("two-factor accounts still need their code", func(t *testing.T) {
+		m := new(mockUserRepository)
+		user := verified()
+		user.TOTPEnabledAt = &verifiedAt
+		m.On("ConsumeMagicLinkToken", mock.Anything, mock.Anything, mock.Anything).
+			Return(&domain.MagicLinkToken{ID: "t1", UserID: "1_foo"}, nil).Once()
+		m.On("GetByID", mock.Anything, "1_foo").Return(user, nil).Once()
+
+		srv := NewUserService(m, "foobar", config.Config{}, nil, nil, nil, zap.NewNop())
+		resp, err := srv.MagicLinkLogin(context.Background(), &domain.MagicLinkLoginRequest{Token: "secret"}, domain.SessionClient{})
+
+		require.NoError(t, err)
+		require.True(t, resp.TwoFactorRequired)
+		m.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/H3nSte1n/recipe/internal/domain"
+	apperrors "github.com/H3nSte1n/recipe/internal/errors"
+	"github.com/H3nSte1n/recipe/internal/repository"
+	"github.com/H3nSte1n/recipe/pkg/totp"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// twoFactorChallengeTTL is how long the user has to enter a code after
+	// their password was accepted.
+	twoFactorChallengeTTL = 5 * time.Minute
+	// twoFactorAudienceSuffix gives challenge tokens their own audience, so the
+	// auth middleware never accepts one as an access token.
+	twoFactorAudienceSuffix = "/2fa"
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5
+)
+
+// errInvalidChallenge covers every unusable challenge token, so the response
+// does not reveal whether it expired or never existed.
+var errInvalidChallenge = apperrors.New("invalid two-factor challenge")
+
+// generateChallengeToken issues the short-lived token Login returns instead of
+// a session when a second factor is still required.
+func (s *userService) generateChallengeToken(user *domain.User) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"iss":     s.jwtIssuer,
+		"aud":     s.jwtAudience + twoFactorAudienceSuffix,
+		"iat":     now.Unix(),
+		"nbf":     now.Unix(),
+		"exp":     now.Add(twoFactorChallengeTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.jwtSecret)
+}
+
+func (s *userService) parseChallengeToken(tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, apperrors.New("unexpected signing method")
+		}
+		return s.jwtSecret, nil
+	}, jwt.WithIssuer(s.jwtIssuer), jwt.WithAudience(s.jwtAudience+twoFactorAudienceSuffix), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return "", errInvalidChallenge
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", errInvalidChallenge
+	}
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		return "", errInvalidChallenge
+	}
+	return userID, nil
+}
+
+// CompleteTwoFactorLogin redeems a challenge token from Login together with a
+// TOTP or recovery code. Wrong codes count towards the same lockout as wrong
+// passwords, which bounds how many codes one challenge can be used to guess.
+func (s *userService) CompleteTwoFactorLogin(ctx context.Context, req *domain.TwoFactorLoginRequest, client domain.SessionClient) (*domain.LoginResponse, error) {
+	userID, err := s.parseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errInvalidChallenge
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, errInvalidChallenge
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return nil, apperrors.ErrAccountLocked
+	}
+
+	ok, err := s.checkSecondFactor(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.recordFailedLogin(ctx, user, domain.AuditTwoFactorFailed, client)
+		return nil, apperrors.ErrInvalid2FACode
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
+
+// checkSecondFactor accepts either a current TOTP code or an unused recovery
+// code. A TOTP step is accepted at most once, so an observed code cannot be
+// replayed within its validity window.
+func (s *userService) checkSecondFactor(ctx context.Context, user *domain.User, code string) (bool, error) {
+	secret, err := s.cipher.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	if step, ok := totp.Validate(secret, code, time.Now()); ok {
+		if step <= user.TOTPLastStep {
+			return false, nil
+		}
+		return s.userRepo.AdvanceTOTPStep(ctx, user.ID, step)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	return s.userRepo.UseRecoveryCode(ctx, user.ID, hashRefreshSecret(normalized))
+}
+
+// EnrollTOTP starts enrollment with a fresh secret. Nothing is enforced until
+// ConfirmTOTP proves the authenticator app produces matching codes; enrolling
+// again before that simply replaces the pending secret.
+func (s *userService) EnrollTOTP(ctx context.Context, userID string) (*domain.TOTPEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, apperrors.ErrConflict.Wrap("two-factor authentication is already enabled")
+	}
+	// DisableTOTP asks for the password, so an account without one could
+	// never turn two-factor authentication off again.
+	if !user.HasPassword() {
+		return nil, apperrors.New("set a password before enabling two-factor authentication", "INVALID_INPUT")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetTOTPSecret(ctx, user.ID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.jwtIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once code matches the pending
+// secret, and returns the recovery codes. They are only ever shown here.
+func (s *userService) ConfirmTOTP(ctx context.Context, userID string, code string) (*domain.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, apperrors.ErrConflict.Wrap("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, apperrors.ErrNotFound.Wrap("no two-factor enrollment in progress")
+	}
+
+	secret, err := s.cipher.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	step, ok := totp.Validate(secret, code, now)
+	if !ok {
+		return nil, apperrors.ErrInvalid2FACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.WithTypedTransaction(ctx, func(txRepo repository.UserRepository) error {
+		if err := txRepo.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+			return err
+		}
+		return txRepo.EnableTOTP(ctx, user.ID, now, step)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(user.ID),
+		Action:     domain.AuditTwoFactorEnabled,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+	})
+
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP turns two-factor authentication off. A stolen access token is
+// not enough: the caller must present the password and a current code. Both
+// fail with the same error and count towards the login lockout, so neither
+// can be guessed one at a time.
+func (s *userService) DisableTOTP(ctx context.Context, userID string, req *domain.DisableTOTPRequest, client domain.SessionClient) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return apperrors.ErrNotFound.Wrap("two-factor authentication is not enabled")
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return apperrors.ErrInvalid2FACode
+	}
+
+	if !domain.CheckPasswordHash(req.Password, user.PasswordHash) {
+		s.recordFailedLogin(ctx, user, domain.AuditLoginFailed, client)
+		return apperrors.ErrInvalid2FACode
+	}
+	ok, err := s.checkSecondFactor(ctx, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.recordFailedLogin(ctx, user, domain.AuditTwoFactorFailed, client)
+		return apperrors.ErrInvalid2FACode
+	}
+
+	if err := s.userRepo.DisableTOTP(ctx, user.ID); err != nil {
+		return err
+	}
+	s.auditLog.Record(ctx, domain.AuditEvent{
+		ActorID:    auditActor(user.ID),
+		Action:     domain.AuditTwoFactorDisabled,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID,
+	})
+	return nil
+}
+
+// generateRecoveryCodes returns recovery codes formatted for reading aloud
+// ("1a2b3-c4d5e") together with the hashes that get stored.
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, recoveryCodeCount)
+	hashes = make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(b)
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRefreshSecret(raw)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode strips the separator and case so a code typed as
+// "1A2B3 C4D5E" still matches.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
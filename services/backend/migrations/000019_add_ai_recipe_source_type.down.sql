@@ -0,0 +1,5 @@
+UPDATE recipes SET source_type = 'MANUAL' WHERE source_type = 'AI';
+
+ALTER TABLE recipes DROP CONSTRAINT IF EXISTS recipes_source_type_check;
+ALTER TABLE recipes ADD CONSTRAINT recipes_source_type_check
+    CHECK (source_type IN ('URL', 'MANUAL', 'PDF', 'IMAGE'));
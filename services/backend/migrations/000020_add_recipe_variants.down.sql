@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_recipes_variant_of_id;
+
+ALTER TABLE recipes DROP COLUMN IF EXISTS variant_of_id;
@@ -0,0 +1,3 @@
+ALTER TABLE recipes ADD COLUMN variant_of_id UUID NULL REFERENCES recipes(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_recipes_variant_of_id ON recipes(variant_of_id);
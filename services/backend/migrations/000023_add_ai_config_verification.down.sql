@@ -0,0 +1,3 @@
+ALTER TABLE user_ai_configs
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS last_verified_at;
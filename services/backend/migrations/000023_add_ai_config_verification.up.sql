@@ -0,0 +1,3 @@
+ALTER TABLE user_ai_configs
+    ADD COLUMN last_verified_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret TEXT NOT NULL DEFAULT '',
+    ADD COLUMN totp_enabled_at TIMESTAMPTZ,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE user_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
@@ -0,0 +1,28 @@
+-- External identities (OpenID Connect / OAuth2) linked to a local account.
+-- subject is the provider's stable user ID; email is what the provider
+-- asserted at link time and is kept for display only.
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(64) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- In-flight sign-ins: the state parameter (stored hashed) ties the callback
+-- to the nonce and PKCE verifier generated when the sign-in started. Rows are
+-- single-use and short-lived.
+CREATE TABLE oidc_login_states (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    provider VARCHAR(64) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
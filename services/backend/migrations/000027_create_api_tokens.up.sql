@@ -0,0 +1,17 @@
+-- Personal access tokens. token_hash is the SHA-256 of the token; only
+-- token_prefix is kept in the clear so the owner can tell tokens apart.
+-- scopes is a JSON array of "<resource>:read|write" strings.
+CREATE TABLE api_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS audit_events;
+ALTER TABLE users DROP COLUMN IF EXISTS admin_locked_at;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
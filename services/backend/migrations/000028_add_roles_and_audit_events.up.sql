@@ -0,0 +1,28 @@
+-- Roles gate the /admin API. There is no self-service way to become an admin;
+-- promote the first one with:
+--   UPDATE users SET role = 'admin' WHERE email = '...';
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('member', 'admin'));
+
+-- Set when an administrator locks the account. Unlike locked_until it does not
+-- expire; the account stays locked until an administrator unlocks it.
+ALTER TABLE users ADD COLUMN admin_locked_at TIMESTAMPTZ;
+
+-- Append-only audit trail. actor_id and target_id deliberately have no foreign
+-- keys so events outlive the users and records they mention. changes is a JSON
+-- object of field name to {"from": ..., "to": ...}.
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id UUID,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id, created_at);
+CREATE INDEX idx_audit_events_target ON audit_events(target_type, target_id, created_at);
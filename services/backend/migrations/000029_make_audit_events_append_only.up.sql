@@ -0,0 +1,11 @@
+-- audit_events is append-only: rows may be inserted, and deleted by the
+-- retention job once they age out, but never changed.
+CREATE OR REPLACE FUNCTION audit_events_reject_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_reject_update();
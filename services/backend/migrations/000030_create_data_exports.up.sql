@@ -0,0 +1,18 @@
+-- "Download my data" requests. An export is built in the background into an
+-- archive named file_name under the export directory; once ready it can be
+-- downloaded through a signed link until expires_at, after which the archive
+-- is deleted and the row marked expired.
+CREATE TABLE data_exports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'ready', 'failed', 'expired')),
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id, created_at DESC);
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at) WHERE status = 'ready';
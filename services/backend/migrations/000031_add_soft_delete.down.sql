@@ -0,0 +1,13 @@
+-- Rows still in the trash would reappear as live data once the column is
+-- gone, so they are removed first.
+DELETE FROM shopping_list_items WHERE deleted_at IS NOT NULL;
+DELETE FROM shopping_lists WHERE deleted_at IS NOT NULL;
+DELETE FROM recipes WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_shopping_list_items_deleted_at;
+DROP INDEX IF EXISTS idx_shopping_lists_deleted_at;
+DROP INDEX IF EXISTS idx_recipes_deleted_at;
+
+ALTER TABLE shopping_list_items DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE shopping_lists DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE recipes DROP COLUMN IF EXISTS deleted_at;
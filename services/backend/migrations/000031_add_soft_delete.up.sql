@@ -0,0 +1,10 @@
+-- Deleted recipes, shopping lists and items go to the trash: deleted_at is
+-- set instead of removing the row, and rows are purged for good once they
+-- have been in the trash longer than trash.retention_days.
+ALTER TABLE recipes ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE shopping_lists ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE shopping_list_items ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_recipes_deleted_at ON recipes(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_shopping_lists_deleted_at ON shopping_lists(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_shopping_list_items_deleted_at ON shopping_list_items(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE magic_link_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT magic_link_tokens_token_hash_key UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_magic_link_tokens_user_id ON magic_link_tokens(user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE shopping_lists DROP COLUMN IF EXISTS store_id;
+DROP TABLE IF EXISTS store_positions;
+DROP TABLE IF EXISTS stores;
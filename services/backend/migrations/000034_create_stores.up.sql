@@ -0,0 +1,28 @@
+-- Stores are a user's own shops, optionally derived from a chain. Their layout
+-- starts as a copy of the chain's and is edited independently.
+CREATE TABLE stores (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    store_chain_id UUID REFERENCES store_chains(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    layout JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_stores_user_id ON stores(user_id);
+
+-- Where in a store items and categories are found, learned from the order in
+-- which they are checked off. position runs from 0 (start of the trip) to 1
+-- (end).
+CREATE TABLE store_positions (
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('item', 'category')),
+    key VARCHAR(255) NOT NULL,
+    position DOUBLE PRECISION NOT NULL,
+    observations INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (store_id, kind, key)
+);
+
+ALTER TABLE shopping_lists ADD COLUMN store_id UUID REFERENCES stores(id) ON DELETE SET NULL;
@@ -0,0 +1,382 @@
+-- Categories form a two-level taxonomy: the top-level codes items have always
+-- used, refined by subcategories (PANTRY_SPICES under PANTRY). Store sections
+-- may list either level; an item falls into the section of its most specific
+-- listed category.
+CREATE TABLE categories (
+    code VARCHAR(50) PRIMARY KEY,
+    parent_code VARCHAR(50) REFERENCES categories(code) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    names JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_categories_parent_code ON categories(parent_code);
+
+-- Common item names and the category they are assigned without asking an AI
+-- model. Terms are lower-case; item names are also looked up without an
+-- English plural ending.
+CREATE TABLE category_terms (
+    term VARCHAR(255) PRIMARY KEY,
+    locale VARCHAR(10) NOT NULL,
+    category_code VARCHAR(50) NOT NULL REFERENCES categories(code) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_category_terms_category_code ON category_terms(category_code);
+
+INSERT INTO categories (code, parent_code, position, names) VALUES
+    ('PRODUCE', NULL, 1, '{"en": "Produce", "de": "Obst & Gemüse"}'),
+    ('MEAT', NULL, 2, '{"en": "Meat & fish", "de": "Fleisch & Fisch"}'),
+    ('DAIRY', NULL, 3, '{"en": "Dairy & eggs", "de": "Milchprodukte & Eier"}'),
+    ('BAKERY', NULL, 4, '{"en": "Bakery", "de": "Backwaren"}'),
+    ('PANTRY', NULL, 5, '{"en": "Pantry", "de": "Vorratsschrank"}'),
+    ('FROZEN', NULL, 6, '{"en": "Frozen", "de": "Tiefkühl"}'),
+    ('BEVERAGES', NULL, 7, '{"en": "Beverages", "de": "Getränke"}'),
+    ('HOUSEHOLD', NULL, 8, '{"en": "Household", "de": "Haushalt"}'),
+    ('OTHER', NULL, 9, '{"en": "Other", "de": "Sonstiges"}');
+
+INSERT INTO categories (code, parent_code, position, names) VALUES
+    ('PRODUCE_FRUIT', 'PRODUCE', 1, '{"en": "Fruit", "de": "Obst"}'),
+    ('PRODUCE_VEGETABLES', 'PRODUCE', 2, '{"en": "Vegetables", "de": "Gemüse"}'),
+    ('PRODUCE_HERBS', 'PRODUCE', 3, '{"en": "Fresh herbs", "de": "Frische Kräuter"}'),
+    ('MEAT_BEEF_PORK', 'MEAT', 1, '{"en": "Beef & pork", "de": "Rind & Schwein"}'),
+    ('MEAT_POULTRY', 'MEAT', 2, '{"en": "Poultry", "de": "Geflügel"}'),
+    ('MEAT_FISH', 'MEAT', 3, '{"en": "Fish & seafood", "de": "Fisch & Meeresfrüchte"}'),
+    ('MEAT_DELI', 'MEAT', 4, '{"en": "Deli & cold cuts", "de": "Wurst & Aufschnitt"}'),
+    ('DAIRY_MILK', 'DAIRY', 1, '{"en": "Milk, cream & butter", "de": "Milch, Sahne & Butter"}'),
+    ('DAIRY_CHEESE', 'DAIRY', 2, '{"en": "Cheese", "de": "Käse"}'),
+    ('DAIRY_YOGURT', 'DAIRY', 3, '{"en": "Yogurt & desserts", "de": "Joghurt & Desserts"}'),
+    ('DAIRY_EGGS', 'DAIRY', 4, '{"en": "Eggs", "de": "Eier"}'),
+    ('PANTRY_PASTA_RICE', 'PANTRY', 1, '{"en": "Pasta, rice & grains", "de": "Nudeln, Reis & Getreide"}'),
+    ('PANTRY_CANNED', 'PANTRY', 2, '{"en": "Canned & jarred goods", "de": "Konserven"}'),
+    ('PANTRY_BAKING', 'PANTRY', 3, '{"en": "Baking", "de": "Backzutaten"}'),
+    ('PANTRY_SPICES', 'PANTRY', 4, '{"en": "Spices & seasonings", "de": "Gewürze"}'),
+    ('PANTRY_OILS_SAUCES', 'PANTRY', 5, '{"en": "Oils, vinegar & sauces", "de": "Öle, Essig & Saucen"}'),
+    ('PANTRY_BREAKFAST', 'PANTRY', 6, '{"en": "Breakfast & spreads", "de": "Frühstück & Aufstriche"}'),
+    ('PANTRY_SNACKS', 'PANTRY', 7, '{"en": "Snacks & sweets", "de": "Snacks & Süßwaren"}'),
+    ('BEVERAGES_HOT', 'BEVERAGES', 1, '{"en": "Coffee & tea", "de": "Kaffee & Tee"}'),
+    ('BEVERAGES_SOFT', 'BEVERAGES', 2, '{"en": "Water, juice & soft drinks", "de": "Wasser, Saft & Softdrinks"}'),
+    ('BEVERAGES_ALCOHOL', 'BEVERAGES', 3, '{"en": "Beer, wine & spirits", "de": "Bier, Wein & Spirituosen"}'),
+    ('HOUSEHOLD_CLEANING', 'HOUSEHOLD', 1, '{"en": "Cleaning & laundry", "de": "Putzen & Waschen"}'),
+    ('HOUSEHOLD_PAPER', 'HOUSEHOLD', 2, '{"en": "Paper & foil", "de": "Papier & Folien"}'),
+    ('HOUSEHOLD_PERSONAL_CARE', 'HOUSEHOLD', 3, '{"en": "Personal care", "de": "Körperpflege"}');
+
+INSERT INTO category_terms (term, locale, category_code) VALUES
+    ('apple', 'en', 'PRODUCE_FRUIT'),
+    ('banana', 'en', 'PRODUCE_FRUIT'),
+    ('orange', 'en', 'PRODUCE_FRUIT'),
+    ('lemon', 'en', 'PRODUCE_FRUIT'),
+    ('lime', 'en', 'PRODUCE_FRUIT'),
+    ('strawberry', 'en', 'PRODUCE_FRUIT'),
+    ('blueberry', 'en', 'PRODUCE_FRUIT'),
+    ('raspberry', 'en', 'PRODUCE_FRUIT'),
+    ('grape', 'en', 'PRODUCE_FRUIT'),
+    ('pear', 'en', 'PRODUCE_FRUIT'),
+    ('peach', 'en', 'PRODUCE_FRUIT'),
+    ('mango', 'en', 'PRODUCE_FRUIT'),
+    ('pineapple', 'en', 'PRODUCE_FRUIT'),
+    ('avocado', 'en', 'PRODUCE_FRUIT'),
+    ('kiwi', 'en', 'PRODUCE_FRUIT'),
+    ('melon', 'en', 'PRODUCE_FRUIT'),
+    ('watermelon', 'en', 'PRODUCE_FRUIT'),
+    ('apfel', 'de', 'PRODUCE_FRUIT'),
+    ('banane', 'de', 'PRODUCE_FRUIT'),
+    ('zitrone', 'de', 'PRODUCE_FRUIT'),
+    ('limette', 'de', 'PRODUCE_FRUIT'),
+    ('erdbeere', 'de', 'PRODUCE_FRUIT'),
+    ('heidelbeere', 'de', 'PRODUCE_FRUIT'),
+    ('himbeere', 'de', 'PRODUCE_FRUIT'),
+    ('weintraube', 'de', 'PRODUCE_FRUIT'),
+    ('birne', 'de', 'PRODUCE_FRUIT'),
+    ('pfirsich', 'de', 'PRODUCE_FRUIT'),
+    ('ananas', 'de', 'PRODUCE_FRUIT'),
+    ('melone', 'de', 'PRODUCE_FRUIT'),
+    ('wassermelone', 'de', 'PRODUCE_FRUIT'),
+    ('onion', 'en', 'PRODUCE_VEGETABLES'),
+    ('red onion', 'en', 'PRODUCE_VEGETABLES'),
+    ('garlic', 'en', 'PRODUCE_VEGETABLES'),
+    ('potato', 'en', 'PRODUCE_VEGETABLES'),
+    ('tomato', 'en', 'PRODUCE_VEGETABLES'),
+    ('carrot', 'en', 'PRODUCE_VEGETABLES'),
+    ('cucumber', 'en', 'PRODUCE_VEGETABLES'),
+    ('lettuce', 'en', 'PRODUCE_VEGETABLES'),
+    ('spinach', 'en', 'PRODUCE_VEGETABLES'),
+    ('broccoli', 'en', 'PRODUCE_VEGETABLES'),
+    ('cauliflower', 'en', 'PRODUCE_VEGETABLES'),
+    ('zucchini', 'en', 'PRODUCE_VEGETABLES'),
+    ('bell pepper', 'en', 'PRODUCE_VEGETABLES'),
+    ('mushroom', 'en', 'PRODUCE_VEGETABLES'),
+    ('celery', 'en', 'PRODUCE_VEGETABLES'),
+    ('leek', 'en', 'PRODUCE_VEGETABLES'),
+    ('cabbage', 'en', 'PRODUCE_VEGETABLES'),
+    ('sweet potato', 'en', 'PRODUCE_VEGETABLES'),
+    ('ginger', 'en', 'PRODUCE_VEGETABLES'),
+    ('zwiebel', 'de', 'PRODUCE_VEGETABLES'),
+    ('rote zwiebel', 'de', 'PRODUCE_VEGETABLES'),
+    ('knoblauch', 'de', 'PRODUCE_VEGETABLES'),
+    ('kartoffel', 'de', 'PRODUCE_VEGETABLES'),
+    ('tomate', 'de', 'PRODUCE_VEGETABLES'),
+    ('karotte', 'de', 'PRODUCE_VEGETABLES'),
+    ('möhre', 'de', 'PRODUCE_VEGETABLES'),
+    ('gurke', 'de', 'PRODUCE_VEGETABLES'),
+    ('salat', 'de', 'PRODUCE_VEGETABLES'),
+    ('spinat', 'de', 'PRODUCE_VEGETABLES'),
+    ('brokkoli', 'de', 'PRODUCE_VEGETABLES'),
+    ('blumenkohl', 'de', 'PRODUCE_VEGETABLES'),
+    ('paprika', 'de', 'PRODUCE_VEGETABLES'),
+    ('champignon', 'de', 'PRODUCE_VEGETABLES'),
+    ('pilz', 'de', 'PRODUCE_VEGETABLES'),
+    ('sellerie', 'de', 'PRODUCE_VEGETABLES'),
+    ('lauch', 'de', 'PRODUCE_VEGETABLES'),
+    ('porree', 'de', 'PRODUCE_VEGETABLES'),
+    ('kohl', 'de', 'PRODUCE_VEGETABLES'),
+    ('süßkartoffel', 'de', 'PRODUCE_VEGETABLES'),
+    ('ingwer', 'de', 'PRODUCE_VEGETABLES'),
+    ('basil', 'en', 'PRODUCE_HERBS'),
+    ('parsley', 'en', 'PRODUCE_HERBS'),
+    ('cilantro', 'en', 'PRODUCE_HERBS'),
+    ('coriander', 'en', 'PRODUCE_HERBS'),
+    ('mint', 'en', 'PRODUCE_HERBS'),
+    ('dill', 'en', 'PRODUCE_HERBS'),
+    ('chives', 'en', 'PRODUCE_HERBS'),
+    ('rosemary', 'en', 'PRODUCE_HERBS'),
+    ('thyme', 'en', 'PRODUCE_HERBS'),
+    ('basilikum', 'de', 'PRODUCE_HERBS'),
+    ('petersilie', 'de', 'PRODUCE_HERBS'),
+    ('koriander', 'de', 'PRODUCE_HERBS'),
+    ('minze', 'de', 'PRODUCE_HERBS'),
+    ('schnittlauch', 'de', 'PRODUCE_HERBS'),
+    ('rosmarin', 'de', 'PRODUCE_HERBS'),
+    ('thymian', 'de', 'PRODUCE_HERBS'),
+    ('beef', 'en', 'MEAT_BEEF_PORK'),
+    ('ground beef', 'en', 'MEAT_BEEF_PORK'),
+    ('minced meat', 'en', 'MEAT_BEEF_PORK'),
+    ('steak', 'en', 'MEAT_BEEF_PORK'),
+    ('pork', 'en', 'MEAT_BEEF_PORK'),
+    ('pork chop', 'en', 'MEAT_BEEF_PORK'),
+    ('bacon', 'en', 'MEAT_BEEF_PORK'),
+    ('sausage', 'en', 'MEAT_BEEF_PORK'),
+    ('rindfleisch', 'de', 'MEAT_BEEF_PORK'),
+    ('hackfleisch', 'de', 'MEAT_BEEF_PORK'),
+    ('rinderhack', 'de', 'MEAT_BEEF_PORK'),
+    ('schweinefleisch', 'de', 'MEAT_BEEF_PORK'),
+    ('schnitzel', 'de', 'MEAT_BEEF_PORK'),
+    ('speck', 'de', 'MEAT_BEEF_PORK'),
+    ('bratwurst', 'de', 'MEAT_BEEF_PORK'),
+    ('chicken', 'en', 'MEAT_POULTRY'),
+    ('chicken breast', 'en', 'MEAT_POULTRY'),
+    ('chicken thigh', 'en', 'MEAT_POULTRY'),
+    ('turkey', 'en', 'MEAT_POULTRY'),
+    ('duck', 'en', 'MEAT_POULTRY'),
+    ('hähnchen', 'de', 'MEAT_POULTRY'),
+    ('hühnchen', 'de', 'MEAT_POULTRY'),
+    ('hähnchenbrust', 'de', 'MEAT_POULTRY'),
+    ('hähnchenschenkel', 'de', 'MEAT_POULTRY'),
+    ('pute', 'de', 'MEAT_POULTRY'),
+    ('putenbrust', 'de', 'MEAT_POULTRY'),
+    ('ente', 'de', 'MEAT_POULTRY'),
+    ('salmon', 'en', 'MEAT_FISH'),
+    ('tuna', 'en', 'MEAT_FISH'),
+    ('cod', 'en', 'MEAT_FISH'),
+    ('shrimp', 'en', 'MEAT_FISH'),
+    ('prawn', 'en', 'MEAT_FISH'),
+    ('fish', 'en', 'MEAT_FISH'),
+    ('lachs', 'de', 'MEAT_FISH'),
+    ('thunfisch', 'de', 'MEAT_FISH'),
+    ('kabeljau', 'de', 'MEAT_FISH'),
+    ('garnele', 'de', 'MEAT_FISH'),
+    ('garnelen', 'de', 'MEAT_FISH'),
+    ('fisch', 'de', 'MEAT_FISH'),
+    ('ham', 'en', 'MEAT_DELI'),
+    ('salami', 'en', 'MEAT_DELI'),
+    ('prosciutto', 'en', 'MEAT_DELI'),
+    ('schinken', 'de', 'MEAT_DELI'),
+    ('aufschnitt', 'de', 'MEAT_DELI'),
+    ('milk', 'en', 'DAIRY_MILK'),
+    ('cream', 'en', 'DAIRY_MILK'),
+    ('heavy cream', 'en', 'DAIRY_MILK'),
+    ('sour cream', 'en', 'DAIRY_MILK'),
+    ('butter', 'en', 'DAIRY_MILK'),
+    ('buttermilk', 'en', 'DAIRY_MILK'),
+    ('milch', 'de', 'DAIRY_MILK'),
+    ('vollmilch', 'de', 'DAIRY_MILK'),
+    ('sahne', 'de', 'DAIRY_MILK'),
+    ('schlagsahne', 'de', 'DAIRY_MILK'),
+    ('saure sahne', 'de', 'DAIRY_MILK'),
+    ('schmand', 'de', 'DAIRY_MILK'),
+    ('cheese', 'en', 'DAIRY_CHEESE'),
+    ('cheddar', 'en', 'DAIRY_CHEESE'),
+    ('mozzarella', 'en', 'DAIRY_CHEESE'),
+    ('parmesan', 'en', 'DAIRY_CHEESE'),
+    ('feta', 'en', 'DAIRY_CHEESE'),
+    ('gouda', 'en', 'DAIRY_CHEESE'),
+    ('cream cheese', 'en', 'DAIRY_CHEESE'),
+    ('ricotta', 'en', 'DAIRY_CHEESE'),
+    ('käse', 'de', 'DAIRY_CHEESE'),
+    ('frischkäse', 'de', 'DAIRY_CHEESE'),
+    ('quark', 'de', 'DAIRY_CHEESE'),
+    ('yogurt', 'en', 'DAIRY_YOGURT'),
+    ('greek yogurt', 'en', 'DAIRY_YOGURT'),
+    ('pudding', 'en', 'DAIRY_YOGURT'),
+    ('joghurt', 'de', 'DAIRY_YOGURT'),
+    ('griechischer joghurt', 'de', 'DAIRY_YOGURT'),
+    ('egg', 'en', 'DAIRY_EGGS'),
+    ('ei', 'de', 'DAIRY_EGGS'),
+    ('eier', 'de', 'DAIRY_EGGS'),
+    ('bread', 'en', 'BAKERY'),
+    ('baguette', 'en', 'BAKERY'),
+    ('roll', 'en', 'BAKERY'),
+    ('bun', 'en', 'BAKERY'),
+    ('bagel', 'en', 'BAKERY'),
+    ('croissant', 'en', 'BAKERY'),
+    ('tortilla', 'en', 'BAKERY'),
+    ('toast', 'en', 'BAKERY'),
+    ('brot', 'de', 'BAKERY'),
+    ('brötchen', 'de', 'BAKERY'),
+    ('semmel', 'de', 'BAKERY'),
+    ('toastbrot', 'de', 'BAKERY'),
+    ('pasta', 'en', 'PANTRY_PASTA_RICE'),
+    ('spaghetti', 'en', 'PANTRY_PASTA_RICE'),
+    ('penne', 'en', 'PANTRY_PASTA_RICE'),
+    ('noodle', 'en', 'PANTRY_PASTA_RICE'),
+    ('rice', 'en', 'PANTRY_PASTA_RICE'),
+    ('couscous', 'en', 'PANTRY_PASTA_RICE'),
+    ('quinoa', 'en', 'PANTRY_PASTA_RICE'),
+    ('oats', 'en', 'PANTRY_PASTA_RICE'),
+    ('lentil', 'en', 'PANTRY_PASTA_RICE'),
+    ('nudeln', 'de', 'PANTRY_PASTA_RICE'),
+    ('reis', 'de', 'PANTRY_PASTA_RICE'),
+    ('haferflocken', 'de', 'PANTRY_PASTA_RICE'),
+    ('linsen', 'de', 'PANTRY_PASTA_RICE'),
+    ('canned tomatoes', 'en', 'PANTRY_CANNED'),
+    ('chickpeas', 'en', 'PANTRY_CANNED'),
+    ('kidney beans', 'en', 'PANTRY_CANNED'),
+    ('coconut milk', 'en', 'PANTRY_CANNED'),
+    ('tomato paste', 'en', 'PANTRY_CANNED'),
+    ('corn', 'en', 'PANTRY_CANNED'),
+    ('dosentomaten', 'de', 'PANTRY_CANNED'),
+    ('passierte tomaten', 'de', 'PANTRY_CANNED'),
+    ('kichererbsen', 'de', 'PANTRY_CANNED'),
+    ('kidneybohnen', 'de', 'PANTRY_CANNED'),
+    ('kokosmilch', 'de', 'PANTRY_CANNED'),
+    ('tomatenmark', 'de', 'PANTRY_CANNED'),
+    ('mais', 'de', 'PANTRY_CANNED'),
+    ('flour', 'en', 'PANTRY_BAKING'),
+    ('sugar', 'en', 'PANTRY_BAKING'),
+    ('brown sugar', 'en', 'PANTRY_BAKING'),
+    ('baking powder', 'en', 'PANTRY_BAKING'),
+    ('baking soda', 'en', 'PANTRY_BAKING'),
+    ('yeast', 'en', 'PANTRY_BAKING'),
+    ('vanilla extract', 'en', 'PANTRY_BAKING'),
+    ('cocoa powder', 'en', 'PANTRY_BAKING'),
+    ('mehl', 'de', 'PANTRY_BAKING'),
+    ('zucker', 'de', 'PANTRY_BAKING'),
+    ('brauner zucker', 'de', 'PANTRY_BAKING'),
+    ('backpulver', 'de', 'PANTRY_BAKING'),
+    ('natron', 'de', 'PANTRY_BAKING'),
+    ('hefe', 'de', 'PANTRY_BAKING'),
+    ('vanillezucker', 'de', 'PANTRY_BAKING'),
+    ('kakaopulver', 'de', 'PANTRY_BAKING'),
+    ('salt', 'en', 'PANTRY_SPICES'),
+    ('pepper', 'en', 'PANTRY_SPICES'),
+    ('black pepper', 'en', 'PANTRY_SPICES'),
+    ('paprika powder', 'en', 'PANTRY_SPICES'),
+    ('cumin', 'en', 'PANTRY_SPICES'),
+    ('cinnamon', 'en', 'PANTRY_SPICES'),
+    ('oregano', 'en', 'PANTRY_SPICES'),
+    ('curry powder', 'en', 'PANTRY_SPICES'),
+    ('chili flakes', 'en', 'PANTRY_SPICES'),
+    ('nutmeg', 'en', 'PANTRY_SPICES'),
+    ('salz', 'de', 'PANTRY_SPICES'),
+    ('pfeffer', 'de', 'PANTRY_SPICES'),
+    ('schwarzer pfeffer', 'de', 'PANTRY_SPICES'),
+    ('paprikapulver', 'de', 'PANTRY_SPICES'),
+    ('kreuzkümmel', 'de', 'PANTRY_SPICES'),
+    ('zimt', 'de', 'PANTRY_SPICES'),
+    ('currypulver', 'de', 'PANTRY_SPICES'),
+    ('chiliflocken', 'de', 'PANTRY_SPICES'),
+    ('muskatnuss', 'de', 'PANTRY_SPICES'),
+    ('olive oil', 'en', 'PANTRY_OILS_SAUCES'),
+    ('vegetable oil', 'en', 'PANTRY_OILS_SAUCES'),
+    ('vinegar', 'en', 'PANTRY_OILS_SAUCES'),
+    ('balsamic vinegar', 'en', 'PANTRY_OILS_SAUCES'),
+    ('soy sauce', 'en', 'PANTRY_OILS_SAUCES'),
+    ('ketchup', 'en', 'PANTRY_OILS_SAUCES'),
+    ('mustard', 'en', 'PANTRY_OILS_SAUCES'),
+    ('mayonnaise', 'en', 'PANTRY_OILS_SAUCES'),
+    ('honey', 'en', 'PANTRY_OILS_SAUCES'),
+    ('olivenöl', 'de', 'PANTRY_OILS_SAUCES'),
+    ('sonnenblumenöl', 'de', 'PANTRY_OILS_SAUCES'),
+    ('essig', 'de', 'PANTRY_OILS_SAUCES'),
+    ('balsamico', 'de', 'PANTRY_OILS_SAUCES'),
+    ('sojasauce', 'de', 'PANTRY_OILS_SAUCES'),
+    ('senf', 'de', 'PANTRY_OILS_SAUCES'),
+    ('honig', 'de', 'PANTRY_OILS_SAUCES'),
+    ('cereal', 'en', 'PANTRY_BREAKFAST'),
+    ('muesli', 'en', 'PANTRY_BREAKFAST'),
+    ('granola', 'en', 'PANTRY_BREAKFAST'),
+    ('jam', 'en', 'PANTRY_BREAKFAST'),
+    ('peanut butter', 'en', 'PANTRY_BREAKFAST'),
+    ('müsli', 'de', 'PANTRY_BREAKFAST'),
+    ('cornflakes', 'de', 'PANTRY_BREAKFAST'),
+    ('marmelade', 'de', 'PANTRY_BREAKFAST'),
+    ('konfitüre', 'de', 'PANTRY_BREAKFAST'),
+    ('erdnussbutter', 'de', 'PANTRY_BREAKFAST'),
+    ('chocolate', 'en', 'PANTRY_SNACKS'),
+    ('chips', 'en', 'PANTRY_SNACKS'),
+    ('crisps', 'en', 'PANTRY_SNACKS'),
+    ('crackers', 'en', 'PANTRY_SNACKS'),
+    ('nuts', 'en', 'PANTRY_SNACKS'),
+    ('schokolade', 'de', 'PANTRY_SNACKS'),
+    ('nüsse', 'de', 'PANTRY_SNACKS'),
+    ('frozen peas', 'en', 'FROZEN'),
+    ('ice cream', 'en', 'FROZEN'),
+    ('frozen pizza', 'en', 'FROZEN'),
+    ('tiefkühlerbsen', 'de', 'FROZEN'),
+    ('eis', 'de', 'FROZEN'),
+    ('speiseeis', 'de', 'FROZEN'),
+    ('tiefkühlpizza', 'de', 'FROZEN'),
+    ('coffee', 'en', 'BEVERAGES_HOT'),
+    ('tea', 'en', 'BEVERAGES_HOT'),
+    ('kaffee', 'de', 'BEVERAGES_HOT'),
+    ('tee', 'de', 'BEVERAGES_HOT'),
+    ('water', 'en', 'BEVERAGES_SOFT'),
+    ('sparkling water', 'en', 'BEVERAGES_SOFT'),
+    ('juice', 'en', 'BEVERAGES_SOFT'),
+    ('orange juice', 'en', 'BEVERAGES_SOFT'),
+    ('soda', 'en', 'BEVERAGES_SOFT'),
+    ('wasser', 'de', 'BEVERAGES_SOFT'),
+    ('mineralwasser', 'de', 'BEVERAGES_SOFT'),
+    ('saft', 'de', 'BEVERAGES_SOFT'),
+    ('orangensaft', 'de', 'BEVERAGES_SOFT'),
+    ('limonade', 'de', 'BEVERAGES_SOFT'),
+    ('beer', 'en', 'BEVERAGES_ALCOHOL'),
+    ('wine', 'en', 'BEVERAGES_ALCOHOL'),
+    ('red wine', 'en', 'BEVERAGES_ALCOHOL'),
+    ('white wine', 'en', 'BEVERAGES_ALCOHOL'),
+    ('bier', 'de', 'BEVERAGES_ALCOHOL'),
+    ('wein', 'de', 'BEVERAGES_ALCOHOL'),
+    ('rotwein', 'de', 'BEVERAGES_ALCOHOL'),
+    ('weißwein', 'de', 'BEVERAGES_ALCOHOL'),
+    ('dish soap', 'en', 'HOUSEHOLD_CLEANING'),
+    ('detergent', 'en', 'HOUSEHOLD_CLEANING'),
+    ('laundry detergent', 'en', 'HOUSEHOLD_CLEANING'),
+    ('sponge', 'en', 'HOUSEHOLD_CLEANING'),
+    ('spülmittel', 'de', 'HOUSEHOLD_CLEANING'),
+    ('waschmittel', 'de', 'HOUSEHOLD_CLEANING'),
+    ('schwamm', 'de', 'HOUSEHOLD_CLEANING'),
+    ('toilet paper', 'en', 'HOUSEHOLD_PAPER'),
+    ('paper towels', 'en', 'HOUSEHOLD_PAPER'),
+    ('aluminum foil', 'en', 'HOUSEHOLD_PAPER'),
+    ('plastic wrap', 'en', 'HOUSEHOLD_PAPER'),
+    ('toilettenpapier', 'de', 'HOUSEHOLD_PAPER'),
+    ('küchenrolle', 'de', 'HOUSEHOLD_PAPER'),
+    ('alufolie', 'de', 'HOUSEHOLD_PAPER'),
+    ('frischhaltefolie', 'de', 'HOUSEHOLD_PAPER'),
+    ('shampoo', 'en', 'HOUSEHOLD_PERSONAL_CARE'),
+    ('toothpaste', 'en', 'HOUSEHOLD_PERSONAL_CARE'),
+    ('soap', 'en', 'HOUSEHOLD_PERSONAL_CARE'),
+    ('deodorant', 'en', 'HOUSEHOLD_PERSONAL_CARE'),
+    ('zahnpasta', 'de', 'HOUSEHOLD_PERSONAL_CARE'),
+    ('seife', 'de', 'HOUSEHOLD_PERSONAL_CARE'),
+    ('deo', 'de', 'HOUSEHOLD_PERSONAL_CARE');
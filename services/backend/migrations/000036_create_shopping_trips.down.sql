@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS shopping_trip_items;
+DROP TABLE IF EXISTS shopping_trips;
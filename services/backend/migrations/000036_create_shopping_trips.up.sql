@@ -0,0 +1,41 @@
+-- A shopping trip is one visit to a store to shop a list. While it is open,
+-- what was bought is recorded with its price; finishing it archives the trip
+-- with its total. The store's name is copied so history survives the store.
+CREATE TABLE shopping_trips (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    list_id UUID REFERENCES shopping_lists(id) ON DELETE SET NULL,
+    store_id UUID REFERENCES stores(id) ON DELETE SET NULL,
+    store_name VARCHAR(255) NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL,
+    total_cents BIGINT NOT NULL DEFAULT 0,
+    item_count INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shopping_trips_user_id_started_at ON shopping_trips(user_id, started_at DESC);
+-- A list is shopped on at most one open trip at a time.
+CREATE UNIQUE INDEX idx_shopping_trips_open_list ON shopping_trips(list_id) WHERE finished_at IS NULL;
+
+-- What was bought on a trip. Items usually come from the list, but a trip can
+-- also record purchases that were not on it. item_key is the normalized name
+-- price history is looked up by.
+CREATE TABLE shopping_trip_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    trip_id UUID NOT NULL REFERENCES shopping_trips(id) ON DELETE CASCADE,
+    list_item_id UUID REFERENCES shopping_list_items(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    item_key VARCHAR(255) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL DEFAULT 0,
+    unit VARCHAR(50) NOT NULL DEFAULT '',
+    price_cents BIGINT NOT NULL CHECK (price_cents >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (trip_id, list_item_id)
+);
+
+CREATE INDEX idx_shopping_trip_items_item_key ON shopping_trip_items(item_key);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS shopping_list_template_items;
+DROP TABLE IF EXISTS shopping_list_templates;
@@ -0,0 +1,33 @@
+-- Templates are sets of staple items that can be added to a list on demand
+-- or, with a frequency, every `every` days or weeks. next_run_at is when the
+-- scheduler next adds them to target_list_id.
+CREATE TABLE shopping_list_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    target_list_id UUID REFERENCES shopping_lists(id) ON DELETE SET NULL,
+    frequency VARCHAR(16) NOT NULL DEFAULT '' CHECK (frequency IN ('', 'daily', 'weekly')),
+    every INTEGER NOT NULL DEFAULT 1 CHECK (every >= 1),
+    next_run_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shopping_list_templates_user_id ON shopping_list_templates(user_id);
+CREATE INDEX idx_shopping_list_templates_next_run_at ON shopping_list_templates(next_run_at) WHERE next_run_at IS NOT NULL;
+
+CREATE TABLE shopping_list_template_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    template_id UUID NOT NULL REFERENCES shopping_list_templates(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    amount DOUBLE PRECISION NOT NULL DEFAULT 0,
+    unit VARCHAR(50) NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shopping_list_template_items_template_id ON shopping_list_template_items(template_id);
@@ -0,0 +1,15 @@
+-- Share links give people without an account a view of a list, and with
+-- access 'check' let them check items off. The link carries a signed token
+-- for the share's id; revoking or expiring the share disables it.
+CREATE TABLE shopping_list_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    list_id UUID NOT NULL REFERENCES shopping_lists(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    access VARCHAR(16) NOT NULL CHECK (access IN ('view', 'check')),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_shopping_list_shares_list_id ON shopping_list_shares(list_id);
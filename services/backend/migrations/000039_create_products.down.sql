@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_products;
+DROP TABLE IF EXISTS products;
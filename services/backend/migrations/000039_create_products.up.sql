@@ -0,0 +1,36 @@
+-- Products are looked up by barcode when an item is scanned onto a list.
+-- The table is bulk-loaded from an Open Food Facts dump with
+-- cmd/import-products; nutrition is per 100 g or 100 ml.
+CREATE TABLE products (
+    barcode VARCHAR(14) PRIMARY KEY,
+    name TEXT NOT NULL,
+    brand TEXT NOT NULL DEFAULT '',
+    quantity TEXT NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL,
+    energy_kcal DOUBLE PRECISION,
+    fat DOUBLE PRECISION,
+    saturated_fat DOUBLE PRECISION,
+    carbohydrates DOUBLE PRECISION,
+    sugars DOUBLE PRECISION,
+    fiber DOUBLE PRECISION,
+    protein DOUBLE PRECISION,
+    salt DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A user's own products: barcodes the products table does not know, or
+-- that the user wants added differently. They win over products.
+CREATE TABLE user_products (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    barcode VARCHAR(14) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    brand VARCHAR(255) NOT NULL DEFAULT '',
+    quantity VARCHAR(100) NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL,
+    amount DOUBLE PRECISION NOT NULL DEFAULT 0,
+    unit VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, barcode)
+);
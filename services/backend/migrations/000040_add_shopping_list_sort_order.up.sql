@@ -0,0 +1,3 @@
+-- The order a list's items are shown in when none is asked for, as
+-- comma-separated sort keys such as 'checked,section,name'.
+ALTER TABLE shopping_lists ADD COLUMN sort_order VARCHAR(200) NOT NULL DEFAULT '';
@@ -0,0 +1,22 @@
+-- What a user pays for an item at a store chain, kept by hand. item_key is
+-- the normalized name prices are looked up by; unit_price_cents is the price
+-- per per_unit (kg, l, piece, or the unit itself when it does not convert),
+-- so pack sizes compare. Each item has one price per chain.
+CREATE TABLE item_prices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    store_chain_id UUID NOT NULL REFERENCES store_chains(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    item_key VARCHAR(255) NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL CHECK (quantity > 0),
+    unit VARCHAR(50) NOT NULL DEFAULT '',
+    price_cents BIGINT NOT NULL CHECK (price_cents >= 0),
+    currency VARCHAR(3) NOT NULL,
+    unit_price_cents DOUBLE PRECISION NOT NULL,
+    per_unit VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, store_chain_id, item_key)
+);
+
+CREATE INDEX idx_item_prices_user_id_item_key ON item_prices(user_id, item_key);
@@ -0,0 +1,4 @@
+-- The hash of the refresh token a session's current one replaced. Only that
+-- token being presented again counts as reuse; any other wrong secret is
+-- just rejected.
+ALTER TABLE sessions ADD COLUMN previous_refresh_token_hash VARCHAR(64) NOT NULL DEFAULT '';
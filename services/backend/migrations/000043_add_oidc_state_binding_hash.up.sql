@@ -0,0 +1,4 @@
+-- The hash of a secret only the browser that started a sign-in holds. The
+-- callback must present it, so a victim cannot be made to finish a sign-in
+-- someone else started. States created before this have none and fail.
+ALTER TABLE oidc_login_states ADD COLUMN binding_hash VARCHAR(64) NOT NULL DEFAULT '';
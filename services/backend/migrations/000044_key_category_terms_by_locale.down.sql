@@ -0,0 +1,6 @@
+-- Keep one locale's entry for terms spelled the same in several.
+DELETE FROM category_terms a USING category_terms b
+WHERE a.term = b.term AND a.locale > b.locale;
+
+ALTER TABLE category_terms DROP CONSTRAINT category_terms_pkey;
+ALTER TABLE category_terms ADD PRIMARY KEY (term);
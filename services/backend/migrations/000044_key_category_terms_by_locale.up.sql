@@ -0,0 +1,4 @@
+-- The same spelling can mean different things in different languages, so a
+-- term is only unique within its locale.
+ALTER TABLE category_terms DROP CONSTRAINT category_terms_pkey;
+ALTER TABLE category_terms ADD PRIMARY KEY (term, locale);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_shopping_list_items_archived_at;
+ALTER TABLE shopping_list_items DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,6 @@
+-- Checked items cleared off a list are archived rather than trashed: they
+-- leave the list but stay out of the trash and its purge, and keep counting
+-- towards the user's shopping history.
+ALTER TABLE shopping_list_items ADD COLUMN archived_at TIMESTAMPTZ;
+
+CREATE INDEX idx_shopping_list_items_archived_at ON shopping_list_items(archived_at) WHERE archived_at IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE shopping_list_items DROP COLUMN IF EXISTS position;
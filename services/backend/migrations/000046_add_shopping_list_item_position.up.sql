@@ -0,0 +1,4 @@
+-- Where the user put an item by hand with a batch reorder. Items never
+-- placed keep NULL and sort after the placed ones, in the order they were
+-- added.
+ALTER TABLE shopping_list_items ADD COLUMN position INTEGER;
@@ -23,12 +23,12 @@ func NewClaudeModel(modelVersion string, apiKey string, logger *zap.Logger) *Cla
 	}
 }
 
-func (m *ClaudeModel) Parse(ctx context.Context, content string, contentType string) (*domain.Recipe, error) {
-	system, user := buildRecipePrompt(content, contentType)
-
+// complete sends one system+user exchange and returns the text of the first
+// content block.
+func (m *ClaudeModel) complete(ctx context.Context, system, user string, maxTokens int64) (string, error) {
 	message, err := m.client.Messages.New(ctx, anthropic.MessageNewParams{
 		Model:     anthropic.F(anthropic.Model(m.modelVersion)),
-		MaxTokens: anthropic.F(int64(2000)),
+		MaxTokens: anthropic.F(maxTokens),
 		System:    anthropic.F([]anthropic.TextBlockParam{anthropic.NewTextBlock(system)}),
 		Messages: anthropic.F([]anthropic.MessageParam{
 			anthropic.NewUserMessage(anthropic.NewTextBlock(user)),
@@ -36,58 +36,96 @@ func (m *ClaudeModel) Parse(ctx context.Context, content string, contentType str
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("Claude API error: %w", err)
+		return "", fmt.Errorf("Claude API error: %w", classifyError(err))
 	}
 
-	if len(message.Content) > 0 {
-		return parseAIResponse(message.Content[0].Text)
+	if len(message.Content) == 0 {
+		return "", fmt.Errorf("no response content from Claude")
 	}
 
-	return nil, fmt.Errorf("no response content from Claude")
+	return message.Content[0].Text, nil
+}
+
+// Verify makes the smallest possible request to confirm the API key is accepted
+// and the model exists. Failures carry one of the ErrProvider sentinels.
+func (m *ClaudeModel) Verify(ctx context.Context) error {
+	_, err := m.complete(ctx, "Reply with OK.", "ping", 1)
+	return err
+}
+
+func (m *ClaudeModel) Parse(ctx context.Context, content string, contentType string) (*domain.Recipe, error) {
+	system, user := buildRecipePrompt(content, contentType)
+
+	text, err := m.complete(ctx, system, user, 2000)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAIResponse(text)
 }
 
 func (m *ClaudeModel) ParseInstructions(ctx context.Context, content string) (*[]domain.RecipeInstruction, error) {
 	system, user := buildInstructionsPrompt(content)
 
-	message, err := m.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.F(anthropic.Model(m.modelVersion)),
-		MaxTokens: anthropic.F(int64(2000)),
-		System:    anthropic.F([]anthropic.TextBlockParam{anthropic.NewTextBlock(system)}),
-		Messages: anthropic.F([]anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(user)),
-		}),
-	})
+	text, err := m.complete(ctx, system, user, 2000)
+	if err != nil {
+		return nil, err
+	}
 
+	return parseInstructions(text)
+}
+
+func (m *ClaudeModel) CategorizeItems(ctx context.Context, items []string, categories []domain.GroceryCategory) (map[string]string, error) {
+	system, user := buildCategorizePrompt(items, categories)
+
+	text, err := m.complete(ctx, system, user, 2000)
 	if err != nil {
-		return nil, fmt.Errorf("Claude API error: %w", err)
+		return nil, err
 	}
 
-	if len(message.Content) > 0 {
-		return parseInstructions(message.Content[0].Text)
+	return parseCategorizeItemsResponse(text, categories)
+}
+
+func (m *ClaudeModel) GenerateRecipe(ctx context.Context, req RecipeGenerationRequest) (*domain.Recipe, error) {
+	system, user := buildGenerateRecipePrompt(req)
+
+	text, err := m.complete(ctx, system, user, 3000)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("no response content from Claude")
+	return parseAIResponse(text)
 }
 
-func (m *ClaudeModel) CategorizeItems(ctx context.Context, items []string) (map[string]string, error) {
-	system, user := buildCategorizePrompt(items)
+func (m *ClaudeModel) SuggestSubstitutions(ctx context.Context, req SubstitutionRequest) ([]domain.IngredientSubstitute, error) {
+	system, user := buildSubstitutionsPrompt(req)
 
-	message, err := m.client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     anthropic.F(anthropic.Model(m.modelVersion)),
-		MaxTokens: anthropic.F(int64(2000)),
-		System:    anthropic.F([]anthropic.TextBlockParam{anthropic.NewTextBlock(system)}),
-		Messages: anthropic.F([]anthropic.MessageParam{
-			anthropic.NewUserMessage(anthropic.NewTextBlock(user)),
-		}),
-	})
+	text, err := m.complete(ctx, system, user, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSubstitutionsResponse(text)
+}
 
+func (m *ClaudeModel) TranslateRecipe(ctx context.Context, content RecipeTranslationContent, language string) (*RecipeTranslationContent, error) {
+	system, user := buildTranslatePrompt(content, language)
+
+	text, err := m.complete(ctx, system, user, 4000)
 	if err != nil {
-		return nil, fmt.Errorf("Claude API error: %w", err)
+		return nil, err
 	}
 
-	if len(message.Content) > 0 {
-		return parseCategorizeItemsResponse(message.Content[0].Text)
+	return parseTranslationResponse(text, content)
+}
+
+func (m *ClaudeModel) EditRecipe(ctx context.Context, recipe *domain.Recipe, instruction string) (*domain.Recipe, error) {
+	system, user := buildEditRecipePrompt(toAIRecipeResponse(recipe), instruction)
+
+	text, err := m.complete(ctx, system, user, 3000)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("no response content from Claude")
+	return parseAIResponse(text)
 }
@@ -0,0 +1,68 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/sashabaranov/go-openai"
+)
+
+// Provider failures are classified into these sentinels so callers can tell a
+// bad key from an exhausted quota without knowing which SDK produced the error.
+// The original error stays in the chain.
+var (
+	ErrProviderAuth          = errors.New("AI provider rejected the API key")
+	ErrProviderQuota         = errors.New("AI provider quota or rate limit exceeded")
+	ErrProviderModelNotFound = errors.New("AI provider does not offer this model")
+	ErrProviderUnavailable   = errors.New("AI provider is unavailable")
+)
+
+// classifyStatus maps a provider HTTP status to one of the sentinels, or nil
+// for statuses that do not fit a class (such as a malformed request).
+func classifyStatus(status int) error {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrProviderAuth
+	case status == http.StatusPaymentRequired, status == http.StatusTooManyRequests:
+		return ErrProviderQuota
+	case status == http.StatusNotFound:
+		return ErrProviderModelNotFound
+	case status >= http.StatusInternalServerError:
+		// Includes Anthropic's 529 "overloaded".
+		return ErrProviderUnavailable
+	default:
+		return nil
+	}
+}
+
+// classifyError attaches the matching sentinel to a provider SDK error.
+// Transport failures with no HTTP status count as unavailable; context
+// cancellation is left alone so callers still see it as such.
+func classifyError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	status := 0
+	var claudeErr *anthropic.Error
+	var gptErr *openai.APIError
+	var gptReqErr *openai.RequestError
+	switch {
+	case errors.As(err, &claudeErr):
+		status = claudeErr.StatusCode
+	case errors.As(err, &gptErr):
+		status = gptErr.HTTPStatusCode
+	case errors.As(err, &gptReqErr):
+		status = gptReqErr.HTTPStatusCode
+	default:
+		return fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+	}
+
+	if class := classifyStatus(status); class != nil {
+		return fmt.Errorf("%w: %w", class, err)
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"claude unauthorized", &anthropic.Error{StatusCode: http.StatusUnauthorized}, ErrProviderAuth},
+		{"claude overloaded", &anthropic.Error{StatusCode: 529}, ErrProviderUnavailable},
+		{"claude unknown model", &anthropic.Error{StatusCode: http.StatusNotFound}, ErrProviderModelNotFound},
+		{"gpt forbidden", &openai.APIError{HTTPStatusCode: http.StatusForbidden}, ErrProviderAuth},
+		{"gpt insufficient quota", &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests}, ErrProviderQuota},
+		{"gpt request error", &openai.RequestError{HTTPStatusCode: http.StatusBadGateway}, ErrProviderUnavailable},
+		{"transport failure", fmt.Errorf("dial tcp: connection refused"), ErrProviderUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+			assert.ErrorIs(t, got, tt.want)
+			assert.ErrorIs(t, got, tt.err, "original error must stay in the chain")
+		})
+	}
+}
+
+func TestClassifyError_LeavesUnclassifiedAlone(t *testing.T) {
+	badRequest := &openai.APIError{HTTPStatusCode: http.StatusBadRequest}
+	assert.Same(t, error(badRequest), classifyError(badRequest))
+
+	got := classifyError(fmt.Errorf("post: %w", context.Canceled))
+	assert.ErrorIs(t, got, context.Canceled)
+	for _, sentinel := range []error{ErrProviderAuth, ErrProviderQuota, ErrProviderModelNotFound, ErrProviderUnavailable} {
+		assert.False(t, errors.Is(got, sentinel))
+	}
+}
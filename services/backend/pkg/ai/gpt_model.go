@@ -22,58 +22,107 @@ func NewGPTModel(modelType ModelType, apiKey string, logger *zap.Logger) *GPTMod
 	}
 }
 
-func (m *GPTModel) Parse(ctx context.Context, content string, contentType string) (*domain.Recipe, error) {
-	system, user := buildRecipePrompt(content, contentType)
-
+// complete sends one system+user exchange and returns the content of the first
+// choice.
+func (m *GPTModel) complete(ctx context.Context, system, user string, maxTokens int) (string, error) {
 	resp, err := m.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
 		Model: string(m.modelType),
 		Messages: []openai.ChatCompletionMessage{
 			{Role: "system", Content: system},
 			{Role: "user", Content: user},
 		},
-		MaxTokens: 2000,
+		MaxTokens: maxTokens,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("GPT API error: %w", err)
+		return "", fmt.Errorf("GPT API error: %w", classifyError(err))
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("no response content from GPT")
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+// Verify confirms the API key and model with a one-token completion.
+func (m *GPTModel) Verify(ctx context.Context) error {
+	_, err := m.complete(ctx, "Reply with OK.", "ping", 1)
+	return err
+}
+
+func (m *GPTModel) Parse(ctx context.Context, content string, contentType string) (*domain.Recipe, error) {
+	system, user := buildRecipePrompt(content, contentType)
+
+	text, err := m.complete(ctx, system, user, 2000)
+	if err != nil {
+		return nil, err
 	}
 
-	return parseAIResponse(resp.Choices[0].Message.Content)
+	return parseAIResponse(text)
 }
 
 func (m *GPTModel) ParseInstructions(ctx context.Context, content string) (*[]domain.RecipeInstruction, error) {
 	system, user := buildInstructionsPrompt(content)
 
-	resp, err := m.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: string(m.modelType),
-		Messages: []openai.ChatCompletionMessage{
-			{Role: "system", Content: system},
-			{Role: "user", Content: user},
-		},
-		MaxTokens: 2000,
-	})
+	text, err := m.complete(ctx, system, user, 2000)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseInstructions(text)
+}
 
+func (m *GPTModel) CategorizeItems(ctx context.Context, content []string, categories []domain.GroceryCategory) (map[string]string, error) {
+	system, user := buildCategorizePrompt(content, categories)
+
+	text, err := m.complete(ctx, system, user, 2000)
 	if err != nil {
-		return nil, fmt.Errorf("GPT API error: %w", err)
+		return nil, err
 	}
 
-	return parseInstructions(resp.Choices[0].Message.Content)
+	return parseCategorizeItemsResponse(text, categories)
 }
 
-func (m *GPTModel) CategorizeItems(ctx context.Context, content []string) (map[string]string, error) {
-	system, user := buildCategorizePrompt(content)
+func (m *GPTModel) GenerateRecipe(ctx context.Context, req RecipeGenerationRequest) (*domain.Recipe, error) {
+	system, user := buildGenerateRecipePrompt(req)
 
-	resp, err := m.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: string(m.modelType),
-		Messages: []openai.ChatCompletionMessage{
-			{Role: "system", Content: system},
-			{Role: "user", Content: user},
-		},
-		MaxTokens: 2000,
-	})
+	text, err := m.complete(ctx, system, user, 3000)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAIResponse(text)
+}
+
+func (m *GPTModel) SuggestSubstitutions(ctx context.Context, req SubstitutionRequest) ([]domain.IngredientSubstitute, error) {
+	system, user := buildSubstitutionsPrompt(req)
+
+	text, err := m.complete(ctx, system, user, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSubstitutionsResponse(text)
+}
+
+func (m *GPTModel) TranslateRecipe(ctx context.Context, content RecipeTranslationContent, language string) (*RecipeTranslationContent, error) {
+	system, user := buildTranslatePrompt(content, language)
+
+	text, err := m.complete(ctx, system, user, 4000)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTranslationResponse(text, content)
+}
+
+func (m *GPTModel) EditRecipe(ctx context.Context, recipe *domain.Recipe, instruction string) (*domain.Recipe, error) {
+	system, user := buildEditRecipePrompt(toAIRecipeResponse(recipe), instruction)
 
+	text, err := m.complete(ctx, system, user, 3000)
 	if err != nil {
-		return nil, fmt.Errorf("GPT API error: %w", err)
+		return nil, err
 	}
 
-	return parseCategorizeItemsResponse(resp.Choices[0].Message.Content)
+	return parseAIResponse(text)
 }
@@ -25,7 +25,15 @@ const (
 type AIModel interface {
 	Parse(ctx context.Context, content string, contentType string) (*domain.Recipe, error)
 	ParseInstructions(ctx context.Context, content string) (*[]domain.RecipeInstruction, error)
-	CategorizeItems(ctx context.Context, items []string) (map[string]string, error)
+	// CategorizeItems assigns each item one of categories (or OTHER), keyed by
+	// the item as given.
+	CategorizeItems(ctx context.Context, items []string, categories []domain.GroceryCategory) (map[string]string, error)
+	GenerateRecipe(ctx context.Context, req RecipeGenerationRequest) (*domain.Recipe, error)
+	SuggestSubstitutions(ctx context.Context, req SubstitutionRequest) ([]domain.IngredientSubstitute, error)
+	TranslateRecipe(ctx context.Context, content RecipeTranslationContent, language string) (*RecipeTranslationContent, error)
+	EditRecipe(ctx context.Context, recipe *domain.Recipe, instruction string) (*domain.Recipe, error)
+	// Verify checks that the key and model work with a minimal request.
+	Verify(ctx context.Context) error
 }
 
 type ModelFactory struct {
@@ -7,27 +7,25 @@ import (
 	"strings"
 )
 
-// validCategories is the allowlist the LLM's categorization output is checked
-// against. Anything outside it (including an injected value) is normalized to
-// OTHER rather than trusted verbatim.
-var validCategories = map[domain.Category]bool{
-	domain.CategoryProduce:   true,
-	domain.CategoryMeat:      true,
-	domain.CategoryDairy:     true,
-	domain.CategoryBakery:    true,
-	domain.CategoryPantry:    true,
-	domain.CategoryFrozen:    true,
-	domain.CategoryBeverages: true,
-	domain.CategoryHousehold: true,
-	domain.CategoryOther:     true,
+// validCategories builds the allowlist the LLM's categorization output is
+// checked against from the categories it was offered. Anything outside it
+// (including an injected value) is normalized to OTHER rather than trusted
+// verbatim.
+func validCategories(categories []domain.GroceryCategory) map[domain.Category]bool {
+	valid := make(map[domain.Category]bool, len(categories)+1)
+	for _, c := range categories {
+		valid[c.Code] = true
+	}
+	valid[domain.CategoryOther] = true
+	return valid
 }
 
 // normalizeCategory upper-cases and validates a category string against the
 // allowlist, falling back to OTHER (matching the CategoryOther fallback used
 // when adding recipe items to a shopping list).
-func normalizeCategory(raw string) string {
+func normalizeCategory(raw string, valid map[domain.Category]bool) string {
 	c := domain.Category(strings.ToUpper(strings.TrimSpace(raw)))
-	if validCategories[c] {
+	if valid[c] {
 		return string(c)
 	}
 	return string(domain.CategoryOther)
@@ -47,6 +45,19 @@ func clampNonNegative(v int) int {
 	return v
 }
 
+// clampNonNegativeFloat is clampNonNegative for LLM-supplied quantities such as
+// ingredient amounts.
+func clampNonNegativeFloat(v float64) float64 {
+	const maxReasonable = 1_000_000
+	if v < 0 {
+		return 0
+	}
+	if v > maxReasonable {
+		return maxReasonable
+	}
+	return v
+}
+
 func parseAIResponse(response string) (*domain.Recipe, error) {
 	startIndex := strings.Index(response, "{")
 	endIndex := strings.LastIndex(response, "}")
@@ -77,7 +88,7 @@ func parseAIResponse(response string) (*domain.Recipe, error) {
 		recipe.Ingredients[i] = domain.RecipeIngredient{
 			Name:        ing.Name,
 			Description: ing.Description,
-			Amount:      ing.Amount,
+			Amount:      clampNonNegativeFloat(ing.Amount),
 			Unit:        ing.Unit,
 			Notes:       ing.Notes,
 		}
@@ -86,8 +97,14 @@ func parseAIResponse(response string) (*domain.Recipe, error) {
 	// Convert instructions
 	recipe.Instructions = make([]domain.RecipeInstruction, len(aiResponse.Instructions))
 	for i, inst := range aiResponse.Instructions {
+		// Step numbers must be positive; renumber rather than trust a missing or
+		// negative value from the model.
+		stepNumber := inst.StepNumber
+		if stepNumber <= 0 {
+			stepNumber = i + 1
+		}
 		recipe.Instructions[i] = domain.RecipeInstruction{
-			StepNumber:  inst.StepNumber,
+			StepNumber:  stepNumber,
 			Instruction: inst.Description,
 		}
 	}
@@ -156,7 +173,7 @@ func parseInstructions(content string) (*[]domain.RecipeInstruction, error) {
 	return &instructions, nil
 }
 
-func parseCategorizeItemsResponse(content string) (map[string]string, error) {
+func parseCategorizeItemsResponse(content string, categories []domain.GroceryCategory) (map[string]string, error) {
 	content = strings.TrimSpace(content)
 	content = stripMarkdownFences(content)
 
@@ -172,9 +189,140 @@ func parseCategorizeItemsResponse(content string) (map[string]string, error) {
 
 	// Validate every category against the allowlist so an injected/unknown value
 	// cannot reach the domain model.
+	valid := validCategories(categories)
 	for item, category := range result {
-		result[item] = normalizeCategory(category)
+		result[item] = normalizeCategory(category, valid)
 	}
 
 	return result, nil
 }
+
+// maxSubstitutes caps how many suggestions are kept from a single response.
+const maxSubstitutes = 5
+
+func parseSubstitutionsResponse(content string) ([]domain.IngredientSubstitute, error) {
+	content = strings.TrimSpace(content)
+	content = stripMarkdownFences(content)
+
+	if !strings.HasPrefix(content, "{") || !strings.HasSuffix(content, "}") {
+		return nil, fmt.Errorf("invalid JSON object format")
+	}
+
+	var aiResponse AISubstitutesResponse
+	if err := json.Unmarshal([]byte(content), &aiResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	substitutes := make([]domain.IngredientSubstitute, 0, len(aiResponse.Substitutes))
+	for _, sub := range aiResponse.Substitutes {
+		name := strings.TrimSpace(sub.Name)
+		if name == "" {
+			continue
+		}
+		substitutes = append(substitutes, domain.IngredientSubstitute{
+			Name:   name,
+			Amount: clampNonNegativeFloat(sub.Amount),
+			Unit:   strings.TrimSpace(sub.Unit),
+			Notes:  sub.Notes,
+			Source: domain.SubstituteSourceAI,
+		})
+		if len(substitutes) == maxSubstitutes {
+			break
+		}
+	}
+
+	return substitutes, nil
+}
+
+// parseTranslationResponse decodes a translated recipe and keeps only the
+// ingredients and instructions whose IDs were in the original, so the model
+// cannot add or retarget rows.
+func parseTranslationResponse(response string, original RecipeTranslationContent) (*RecipeTranslationContent, error) {
+	response = strings.TrimSpace(response)
+	response = stripMarkdownFences(response)
+
+	if !strings.HasPrefix(response, "{") || !strings.HasSuffix(response, "}") {
+		return nil, fmt.Errorf("invalid JSON object format")
+	}
+
+	var translated RecipeTranslationContent
+	if err := json.Unmarshal([]byte(response), &translated); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	if strings.TrimSpace(translated.Title) == "" {
+		return nil, fmt.Errorf("translated recipe missing title")
+	}
+
+	ingredientIDs := make(map[string]bool, len(original.Ingredients))
+	for _, ing := range original.Ingredients {
+		ingredientIDs[ing.ID] = true
+	}
+	ingredients := translated.Ingredients[:0]
+	for _, ing := range translated.Ingredients {
+		if ingredientIDs[ing.ID] {
+			ingredients = append(ingredients, ing)
+			delete(ingredientIDs, ing.ID)
+		}
+	}
+	translated.Ingredients = ingredients
+
+	instructionIDs := make(map[string]bool, len(original.Instructions))
+	for _, inst := range original.Instructions {
+		instructionIDs[inst.ID] = true
+	}
+	instructions := translated.Instructions[:0]
+	for _, inst := range translated.Instructions {
+		if instructionIDs[inst.ID] {
+			instructions = append(instructions, inst)
+			delete(instructionIDs, inst.ID)
+		}
+	}
+	translated.Instructions = instructions
+
+	return &translated, nil
+}
+
+// toAIRecipeResponse serializes a recipe into the shape the model returns, so
+// an edit prompt can hand the current recipe over in the same format it expects
+// back.
+func toAIRecipeResponse(recipe *domain.Recipe) AIRecipeResponse {
+	resp := AIRecipeResponse{
+		Title:        recipe.Title,
+		Description:  recipe.Description,
+		Servings:     recipe.Servings,
+		PrepTime:     recipe.PrepTime,
+		CookTime:     recipe.CookTime,
+		Notes:        recipe.Notes,
+		Ingredients:  make([]AIRecipeIngredient, len(recipe.Ingredients)),
+		Instructions: make([]AIRecipeStep, len(recipe.Instructions)),
+	}
+	for i, ing := range recipe.Ingredients {
+		resp.Ingredients[i] = AIRecipeIngredient{
+			Name:        ing.Name,
+			Description: ing.Description,
+			Amount:      ing.Amount,
+			Unit:        ing.Unit,
+			Notes:       ing.Notes,
+		}
+	}
+	for i, inst := range recipe.Instructions {
+		resp.Instructions[i] = AIRecipeStep{
+			StepNumber:  inst.StepNumber,
+			Description: inst.Instruction,
+		}
+	}
+	if n := recipe.Nutrition; n != nil {
+		resp.Nutrition = &AIRecipeNutrition{
+			Calories:     n.Calories,
+			Protein:      n.Protein,
+			Carbs:        n.Carbs,
+			Fat:          n.Fat,
+			Fiber:        n.Fiber,
+			Sugar:        n.Sugar,
+			SaturatedFat: n.SaturatedFat,
+			Cholesterol:  n.Cholesterol,
+			Sodium:       n.Sodium,
+		}
+	}
+	return resp
+}
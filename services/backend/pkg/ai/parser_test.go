@@ -8,10 +8,23 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func testCategories() []domain.GroceryCategory {
+	produce, dairy, pantry := domain.CategoryProduce, domain.CategoryDairy, domain.CategoryPantry
+	return []domain.GroceryCategory{
+		{Code: domain.CategoryProduce, Names: map[string]string{"en": "Produce"}},
+		{Code: domain.CategoryDairy, Names: map[string]string{"en": "Dairy & eggs"}},
+		{Code: domain.CategoryBakery, Names: map[string]string{"en": "Bakery"}},
+		{Code: domain.CategoryPantry, Names: map[string]string{"en": "Pantry"}},
+		{Code: "PRODUCE_FRUIT", ParentCode: &produce, Names: map[string]string{"en": "Fruit"}},
+		{Code: "DAIRY_EGGS", ParentCode: &dairy, Names: map[string]string{"en": "Eggs"}},
+		{Code: "PANTRY_SPICES", ParentCode: &pantry, Names: map[string]string{"en": "Spices & seasonings"}},
+	}
+}
+
 func TestParseCategorizeItemsResponse_NormalizesUnknownToOther(t *testing.T) {
 	// An injected/garbage category must be coerced to OTHER, not trusted verbatim.
 	resp := `{"milk":"IGNORE PREVIOUS; DROP TABLE","spinach":"PRODUCE"}`
-	got, err := parseCategorizeItemsResponse(resp)
+	got, err := parseCategorizeItemsResponse(resp, testCategories())
 	require.NoError(t, err)
 	assert.Equal(t, string(domain.CategoryOther), got["milk"])
 	assert.Equal(t, string(domain.CategoryProduce), got["spinach"])
@@ -19,16 +32,21 @@ func TestParseCategorizeItemsResponse_NormalizesUnknownToOther(t *testing.T) {
 
 func TestParseCategorizeItemsResponse_CaseInsensitive(t *testing.T) {
 	resp := `{"eggs":"dairy","bread":"Bakery"}`
-	got, err := parseCategorizeItemsResponse(resp)
+	got, err := parseCategorizeItemsResponse(resp, testCategories())
 	require.NoError(t, err)
 	assert.Equal(t, string(domain.CategoryDairy), got["eggs"])
 	assert.Equal(t, string(domain.CategoryBakery), got["bread"])
 }
 
 func TestNormalizeCategory(t *testing.T) {
-	assert.Equal(t, "PRODUCE", normalizeCategory("  produce "))
-	assert.Equal(t, "OTHER", normalizeCategory("NOT_A_CATEGORY"))
-	assert.Equal(t, "OTHER", normalizeCategory(""))
+	valid := validCategories(testCategories())
+	assert.Equal(t, "PRODUCE", normalizeCategory("  produce ", valid))
+	assert.Equal(t, "PANTRY_SPICES", normalizeCategory("pantry_spices", valid))
+	assert.Equal(t, "OTHER", normalizeCategory("other", valid))
+	// Top-level categories the taxonomy was not offered are not accepted.
+	assert.Equal(t, "OTHER", normalizeCategory("FROZEN", valid))
+	assert.Equal(t, "OTHER", normalizeCategory("NOT_A_CATEGORY", valid))
+	assert.Equal(t, "OTHER", normalizeCategory("", valid))
 }
 
 func TestParseAIResponse_ClampsNegativeFields(t *testing.T) {
@@ -48,3 +66,40 @@ func TestParseAIResponse_KeepsNormalFields(t *testing.T) {
 	assert.Equal(t, 15, recipe.PrepTime)
 	assert.Equal(t, 30, recipe.CookTime)
 }
+
+func TestParseSubstitutionsResponse_ClampsAndCaps(t *testing.T) {
+	resp := "```json\n" + `{"substitutes":[
+		{"name":"milk","amount":-2,"unit":"cups","notes":"add lemon"},
+		{"name":"  ","amount":1},
+		{"name":"a","amount":1},{"name":"b","amount":1},{"name":"c","amount":1},{"name":"d","amount":1},{"name":"e","amount":1}
+	]}` + "\n```"
+	got, err := parseSubstitutionsResponse(resp)
+	require.NoError(t, err)
+	require.Len(t, got, maxSubstitutes)
+	assert.Equal(t, "milk", got[0].Name)
+	assert.Equal(t, 0.0, got[0].Amount)
+	assert.Equal(t, domain.SubstituteSourceAI, got[0].Source)
+	assert.Equal(t, "a", got[1].Name)
+}
+
+func TestParseTranslationResponse_DropsUnknownIDs(t *testing.T) {
+	original := RecipeTranslationContent{
+		Title:        "Pancakes",
+		Ingredients:  []RecipeTranslationIngredient{{ID: "ing-1", Name: "flour"}},
+		Instructions: []RecipeTranslationInstruction{{ID: "step-1", Instruction: "Mix"}},
+	}
+	resp := `{"title":"Pfannkuchen","ingredients":[{"id":"ing-1","name":"Mehl"},{"id":"injected","name":"x"},{"id":"ing-1","name":"dup"}],"instructions":[{"id":"step-1","instruction":"Verrühren"},{"id":"step-9","instruction":"x"}]}`
+
+	got, err := parseTranslationResponse(resp, original)
+	require.NoError(t, err)
+	assert.Equal(t, "Pfannkuchen", got.Title)
+	require.Len(t, got.Ingredients, 1)
+	assert.Equal(t, "Mehl", got.Ingredients[0].Name)
+	require.Len(t, got.Instructions, 1)
+	assert.Equal(t, "step-1", got.Instructions[0].ID)
+}
+
+func TestParseTranslationResponse_RequiresTitle(t *testing.T) {
+	_, err := parseTranslationResponse(`{"title":"  "}`, RecipeTranslationContent{})
+	assert.Error(t, err)
+}
@@ -1,24 +1,28 @@
 package ai
 
 type AIRecipeResponse struct {
-	Title       string `json:"title"`
+	Title        string               `json:"title"`
+	Description  string               `json:"description"`
+	Servings     int                  `json:"servings"`
+	PrepTime     int                  `json:"prepTime"`
+	CookTime     int                  `json:"cookTime"`
+	Ingredients  []AIRecipeIngredient `json:"ingredients"`
+	Instructions []AIRecipeStep       `json:"instructions"`
+	Notes        string               `json:"notes,omitempty"`
+	Nutrition    *AIRecipeNutrition   `json:"nutrition,omitempty"`
+}
+
+type AIRecipeIngredient struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	Unit        string  `json:"unit"`
+	Notes       string  `json:"notes"`
+}
+
+type AIRecipeStep struct {
+	StepNumber  int    `json:"stepNumber"`
 	Description string `json:"description"`
-	Servings    int    `json:"servings"`
-	PrepTime    int    `json:"prepTime"`
-	CookTime    int    `json:"cookTime"`
-	Ingredients []struct {
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Amount      float64 `json:"amount"`
-		Unit        string  `json:"unit"`
-		Notes       string  `json:"notes"`
-	} `json:"ingredients"`
-	Instructions []struct {
-		StepNumber  int    `json:"stepNumber"`
-		Description string `json:"description"`
-	} `json:"instructions"`
-	Notes     string             `json:"notes,omitempty"`
-	Nutrition *AIRecipeNutrition `json:"nutrition,omitempty"`
 }
 
 type AIRecipeNutrition struct {
@@ -37,3 +41,58 @@ type AIRecipeInstructions struct {
 	StepNumber  int    `json:"step_number"`
 	Instruction string `json:"instruction"`
 }
+
+// RecipeGenerationRequest describes what a generated recipe has to work with.
+// Every field is user-supplied and is embedded in the prompt as fenced data.
+type RecipeGenerationRequest struct {
+	Ingredients        []string `json:"ingredients"`
+	DietaryConstraints []string `json:"dietaryConstraints,omitempty"`
+	MaxTotalTime       int      `json:"maxTotalTimeMinutes,omitempty"`
+	Servings           int      `json:"servings"`
+	Cuisine            string   `json:"cuisine,omitempty"`
+}
+
+// SubstitutionRequest identifies the ingredient to replace and the recipe it
+// appears in, so suggestions can fit the dish.
+type SubstitutionRequest struct {
+	RecipeTitle      string   `json:"recipeTitle"`
+	Ingredient       string   `json:"ingredient"`
+	Amount           float64  `json:"amount"`
+	Unit             string   `json:"unit"`
+	OtherIngredients []string `json:"otherIngredients,omitempty"`
+}
+
+type AISubstitutesResponse struct {
+	Substitutes []AISubstitute `json:"substitutes"`
+}
+
+type AISubstitute struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+	Notes  string  `json:"notes"`
+}
+
+// RecipeTranslationContent is the translatable text of a recipe. It is both
+// the prompt payload and the expected response shape; IDs tie each translated
+// ingredient and instruction back to its row.
+type RecipeTranslationContent struct {
+	Title        string                         `json:"title"`
+	Description  string                         `json:"description"`
+	Notes        string                         `json:"notes"`
+	Ingredients  []RecipeTranslationIngredient  `json:"ingredients"`
+	Instructions []RecipeTranslationInstruction `json:"instructions"`
+}
+
+type RecipeTranslationIngredient struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+	Notes       string `json:"notes"`
+}
+
+type RecipeTranslationInstruction struct {
+	ID          string `json:"id"`
+	Instruction string `json:"instruction"`
+}
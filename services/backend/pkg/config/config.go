@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"os"
 	"strings"
 	"time"
 )
@@ -13,10 +14,15 @@ type Config struct {
 	DB       DBConfig       `mapstructure:"db"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	SMTP     SMTPConfig     `mapstructure:"smtp"`
+	Email    EmailConfig    `mapstructure:"email"`
 	Storage  StorageConfig  `mapstructure:"storage"`
 	AI       AIConfig       `mapstructure:"ai"`
 	Security SecurityConfig `mapstructure:"security"`
 	CORS     CORSConfig     `mapstructure:"cors"`
+	OIDC     OIDCConfig     `mapstructure:"oidc"`
+	Audit    AuditConfig    `mapstructure:"audit"`
+	Export   ExportConfig   `mapstructure:"export"`
+	Trash    TrashConfig    `mapstructure:"trash"`
 	LogLevel string         `mapstructure:"log_level"`
 }
 
@@ -30,6 +36,51 @@ type CORSConfig struct {
 	AllowedOrigins []string `mapstructure:"allowed_origins"`
 }
 
+// AuditConfig controls the audit trail. Events older than RetentionDays are
+// purged daily; zero keeps the default of a year.
+type AuditConfig struct {
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
+// ExportConfig controls "download my data" archives. They are written to Dir
+// and linked as BaseURL/<file>; a link stays valid for LinkTTL, after which
+// the archive is deleted.
+type ExportConfig struct {
+	Dir     string        `mapstructure:"dir"`
+	BaseURL string        `mapstructure:"base_url"`
+	LinkTTL time.Duration `mapstructure:"link_ttl"`
+}
+
+// TrashConfig controls the trash. Deleted recipes and shopping lists can be
+// restored for RetentionDays before they are purged; zero keeps the default
+// of 30 days.
+type TrashConfig struct {
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
+// OIDCConfig lists the external identity providers users can sign in with.
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig configures one provider. IssuerURL enables OpenID
+// Connect discovery; plain OAuth2 providers such as GitHub set the endpoint
+// URLs instead. ClientSecret falls back to the OIDC_<NAME>_CLIENT_SECRET
+// environment variable so it need not be committed.
+type OIDCProviderConfig struct {
+	Name         string   `mapstructure:"name"`
+	DisplayName  string   `mapstructure:"display_name"`
+	IssuerURL    string   `mapstructure:"issuer_url"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	AuthURL      string   `mapstructure:"auth_url"`
+	TokenURL     string   `mapstructure:"token_url"`
+	UserInfoURL  string   `mapstructure:"userinfo_url"`
+	EmailsURL    string   `mapstructure:"emails_url"`
+}
+
 type AppConfig struct {
 	Name string `mapstructure:"name"`
 	Env  string `mapstructure:"env"`
@@ -53,6 +104,9 @@ type JWTConfig struct {
 	Secret        string        `mapstructure:"secret"`
 	Duration      time.Duration `mapstructure:"duration"`
 	ExpirationHrs time.Duration `mapstructure:"expiration_hours"`
+	// RefreshDuration is how long a session's refresh token stays valid after
+	// login or its last refresh. Defaults to defaultRefreshDuration when unset.
+	RefreshDuration time.Duration `mapstructure:"refresh_duration"`
 	// Issuer and Audience are set as the "iss"/"aud" claims on issued tokens
 	// and are required to match on every token the auth middleware accepts.
 	// Fall back to defaultJWTIssuer/defaultJWTAudience when unset.
@@ -68,6 +122,14 @@ type SMTPConfig struct {
 	From     string `mapstructure:"from"`
 }
 
+// EmailConfig selects how queued email is delivered: "smtp" (the default)
+// through the smtp settings, or "file", which writes each message into a
+// maildir at Dir for development.
+type EmailConfig struct {
+	Transport string `mapstructure:"transport"`
+	Dir       string `mapstructure:"dir"`
+}
+
 type StorageConfig struct {
 	Type      string    `mapstructure:"type"`
 	LocalPath string    `mapstructure:"local_path"`
@@ -141,6 +203,23 @@ func LoadConfig(env string) (config Config, err error) {
 	if strings.TrimSpace(config.JWT.Audience) == "" {
 		config.JWT.Audience = defaultJWTAudience
 	}
+	if config.JWT.RefreshDuration <= 0 {
+		config.JWT.RefreshDuration = defaultRefreshDuration
+	}
+	if config.Email.Transport == "file" && strings.TrimSpace(config.Email.Dir) == "" {
+		config.Email.Dir = defaultEmailDir
+	}
+	if strings.TrimSpace(config.Export.Dir) == "" {
+		config.Export.Dir = defaultExportDir
+	}
+	if config.Export.LinkTTL <= 0 {
+		config.Export.LinkTTL = defaultExportLinkTTL
+	}
+	for i, provider := range config.OIDC.Providers {
+		if provider.ClientSecret == "" {
+			config.OIDC.Providers[i].ClientSecret = os.Getenv(oidcClientSecretEnv(provider.Name))
+		}
+	}
 
 	return config, nil
 }
@@ -154,6 +233,26 @@ const (
 	defaultJWTAudience = "recipe-app-api"
 )
 
+// oidcClientSecretEnv names the environment variable holding a provider's
+// client secret, e.g. OIDC_GOOGLE_CLIENT_SECRET.
+func oidcClientSecretEnv(name string) string {
+	return "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_CLIENT_SECRET"
+}
+
+// defaultRefreshDuration is used when jwt.refresh_duration is not set.
+const defaultRefreshDuration = 30 * 24 * time.Hour
+
+// defaultEmailDir is the maildir of the file email transport when email.dir
+// is not set.
+const defaultEmailDir = "./mail"
+
+// defaultExportDir and defaultExportLinkTTL are used when export.dir and
+// export.link_ttl are not set.
+const (
+	defaultExportDir     = "./exports"
+	defaultExportLinkTTL = 7 * 24 * time.Hour
+)
+
 const minJWTSecretBytes = 32
 
 // knownWeakJWTSecrets are placeholder values shipped in sample configs. Using
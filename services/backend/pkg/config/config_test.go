@@ -55,6 +55,29 @@ func TestLoadConfig_EnvOverridesSecrets(t *testing.T) {
 	assert.Equal(t, "file_secret", cfg.JWT.Secret)
 }
 
+func TestLoadConfig_OIDCClientSecretFromEnv(t *testing.T) {
+	writeTempConfig(t, "test", sampleYAML+`
+oidc:
+  providers:
+    - name: google
+      issuer_url: https://accounts.google.com
+      client_id: google-client
+    - name: keycloak
+      issuer_url: https://sso.example/realms/recipe
+      client_id: keycloak-client
+      client_secret: file_client_secret
+`)
+
+	t.Setenv("OIDC_GOOGLE_CLIENT_SECRET", "env_client_secret")
+	t.Setenv("OIDC_KEYCLOAK_CLIENT_SECRET", "ignored")
+
+	cfg, err := LoadConfig("test")
+	require.NoError(t, err)
+	require.Len(t, cfg.OIDC.Providers, 2)
+	assert.Equal(t, "env_client_secret", cfg.OIDC.Providers[0].ClientSecret)
+	assert.Equal(t, "file_client_secret", cfg.OIDC.Providers[1].ClientSecret)
+}
+
 func TestConfig_Validate_JWTSecret(t *testing.T) {
 	strong := "a-sufficiently-long-random-jwt-secret-value-1234"
 	require.GreaterOrEqual(t, len(strong), minJWTSecretBytes)
@@ -1,97 +1,80 @@
 package email
 
 import (
-	"fmt"
-	"net/smtp"
+	"context"
+	"net/url"
+	"strings"
+	"time"
 )
 
 type EmailService interface {
-	SendPasswordResetEmail(to, resetToken string) error
-	SendVerificationEmail(to, verificationToken string) error
+	SendPasswordResetEmail(ctx context.Context, to, resetToken string) error
+	SendVerificationEmail(ctx context.Context, to, verificationToken string) error
+	SendMagicLinkEmail(ctx context.Context, to, loginToken string) error
+	SendDataExportEmail(ctx context.Context, to, downloadURL string, expiresAt time.Time) error
+}
+
+// Queue accepts rendered messages for delivery in the background, so a slow
+// or unavailable mail server never holds up the request that sends one.
+type Queue interface {
+	Enqueue(ctx context.Context, msg Message) error
 }
 
 type emailService struct {
-	from        string
-	password    string
-	host        string
-	port        string
-	frontendUrl string
+	queue       Queue
+	frontendURL string
 }
 
-func NewEmailService(from, password, host, port, frontendUrl string) EmailService {
+// NewEmailService renders emails in the recipient's language (see
+// ContextWithLanguage) and hands them to queue. Links point at frontendURL,
+// which is assumed to be https when it has no scheme.
+func NewEmailService(queue Queue, frontendURL string) EmailService {
+	if !strings.Contains(frontendURL, "://") {
+		frontendURL = "https://" + frontendURL
+	}
 	return &emailService{
-		from:        from,
-		password:    password,
-		host:        host,
-		port:        port,
-		frontendUrl: frontendUrl,
+		queue:       queue,
+		frontendURL: strings.TrimSuffix(frontendURL, "/"),
 	}
 }
 
-func (s *emailService) SendPasswordResetEmail(to, resetToken string) error {
-	subject := "Password Reset Request"
-	resetLink := fmt.Sprintf("http://%s/reset-password?token=%s", s.frontendUrl, resetToken)
-	body := fmt.Sprintf(`
-        Hello,
-        
-        You have requested to reset your password. Click the link below to reset it:
-        
-        %s
-        
-        If you didn't request this, please ignore this email.
-        
-        The link will expire in 1 hour.
-        
-        Best regards,
-        Your App Team
-    `, resetLink)
-
-	msg := fmt.Sprintf("To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"Content-Type: text/plain; charset=UTF-8\r\n"+
-		"\r\n"+
-		"%s", to, subject, body)
-
-	auth := smtp.PlainAuth("", s.from, s.password, s.host)
-	return smtp.SendMail(
-		s.host+":"+s.port,
-		auth,
-		s.from,
-		[]string{to},
-		[]byte(msg),
-	)
+func (s *emailService) SendPasswordResetEmail(ctx context.Context, to, resetToken string) error {
+	return s.send(ctx, to, templatePasswordReset, struct{ Link string }{
+		Link: s.frontendLink("/reset-password", resetToken),
+	})
 }
 
-func (s *emailService) SendVerificationEmail(to, verificationToken string) error {
-	subject := "Verify Your Email Address"
-	verifyLink := fmt.Sprintf("http://%s/verify-email?token=%s", s.frontendUrl, verificationToken)
-	body := fmt.Sprintf(`
-        Hello,
-
-        Thanks for signing up. Please verify your email address by clicking the link below:
-
-        %s
-
-        If you didn't create this account, please ignore this email.
+func (s *emailService) SendVerificationEmail(ctx context.Context, to, verificationToken string) error {
+	return s.send(ctx, to, templateVerification, struct{ Link string }{
+		Link: s.frontendLink("/verify-email", verificationToken),
+	})
+}
 
-        The link will expire in 24 hours.
+func (s *emailService) SendMagicLinkEmail(ctx context.Context, to, loginToken string) error {
+	return s.send(ctx, to, templateMagicLink, struct{ Link string }{
+		Link: s.frontendLink("/magic-link", loginToken),
+	})
+}
 
-        Best regards,
-        Your App Team
-    `, verifyLink)
+func (s *emailService) SendDataExportEmail(ctx context.Context, to, downloadURL string, expiresAt time.Time) error {
+	return s.send(ctx, to, templateDataExport, struct {
+		Link      string
+		ExpiresAt time.Time
+	}{
+		Link:      downloadURL,
+		ExpiresAt: expiresAt.UTC(),
+	})
+}
 
-	msg := fmt.Sprintf("To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"Content-Type: text/plain; charset=UTF-8\r\n"+
-		"\r\n"+
-		"%s", to, subject, body)
+func (s *emailService) frontendLink(path, token string) string {
+	return s.frontendURL + path + "?token=" + url.QueryEscape(token)
+}
 
-	auth := smtp.PlainAuth("", s.from, s.password, s.host)
-	return smtp.SendMail(
-		s.host+":"+s.port,
-		auth,
-		s.from,
-		[]string{to},
-		[]byte(msg),
-	)
+func (s *emailService) send(ctx context.Context, to, template string, data any) error {
+	msg, err := render(ctx, template, data)
+	if err != nil {
+		return err
+	}
+	msg.To = to
+	return s.queue.Enqueue(ctx, msg)
 }
@@ -0,0 +1,141 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueue struct {
+	messages []Message
+	err      error
+}
+
+func (q *fakeQueue) Enqueue(_ context.Context, msg Message) error {
+	if q.err != nil {
+		return q.err
+	}
+	q.messages = append(q.messages, msg)
+	return nil
+}
+
+func TestParseTemplates_EveryLocaleHasEveryEmail(t *testing.T) {
+	parsed, err := parseTemplates()
+	require.NoError(t, err)
+	for _, tag := range supportedLocales {
+		for _, name := range []string{templateVerification, templatePasswordReset, templateMagicLink, templateDataExport} {
+			require.Contains(t, parsed[tag.String()], name)
+		}
+	}
+}
+
+func TestEmailService_SendVerificationEmail(t *testing.T) {
+	queue := &fakeQueue{}
+	svc := NewEmailService(queue, "recipe.example.com")
+
+	require.NoError(t, svc.SendVerificationEmail(context.Background(), "foo@bar.com", "tok en&x"))
+
+	require.Len(t, queue.messages, 1)
+	msg := queue.messages[0]
+	require.Equal(t, "foo@bar.com", msg.To)
+	require.Equal(t, "Verify your email address", msg.Subject)
+	// A frontend URL without a scheme is served over https, and the token is
+	// query-escaped.
+	link := "https://recipe.example.com/verify-email?token=tok+en%26x"
+	require.Contains(t, msg.Text, link)
+	require.Contains(t, msg.HTML, `href="https://recipe.example.com/verify-email?token=tok&#43;en%26x"`)
+}
+
+func TestEmailService_Localization(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		subject        string
+	}{
+		{name: "german", acceptLanguage: "de-AT,de;q=0.9,en;q=0.5", subject: "Setze dein Passwort zurück"},
+		{name: "unsupported falls back to english", acceptLanguage: "fr-FR", subject: "Reset your password"},
+		{name: "no header", acceptLanguage: "", subject: "Reset your password"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := &fakeQueue{}
+			svc := NewEmailService(queue, "http://localhost:5173/")
+			ctx := ContextWithLanguage(context.Background(), tt.acceptLanguage)
+
+			require.NoError(t, svc.SendPasswordResetEmail(ctx, "foo@bar.com", "abc"))
+
+			require.Equal(t, tt.subject, queue.messages[0].Subject)
+			require.Contains(t, queue.messages[0].Text, "http://localhost:5173/reset-password?token=abc")
+		})
+	}
+}
+
+func TestEmailService_SendDataExportEmail_EscapesHTML(t *testing.T) {
+	queue := &fakeQueue{}
+	svc := NewEmailService(queue, "https://recipe.example.com")
+	expiresAt := time.Date(2026, 3, 4, 17, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	require.NoError(t, svc.SendDataExportEmail(context.Background(), "foo@bar.com", `https://x.test/a.zip?exp=1&sig="><script>`, expiresAt))
+
+	msg := queue.messages[0]
+	require.Contains(t, msg.Text, "March 4, 2026 at 16:30 UTC")
+	require.NotContains(t, msg.HTML, "<script>")
+}
+
+func TestEmailService_ReturnsQueueErrors(t *testing.T) {
+	svc := NewEmailService(&fakeQueue{err: errors.New("db down")}, "https://recipe.example.com")
+	require.Error(t, svc.SendVerificationEmail(context.Background(), "foo@bar.com", "abc"))
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	msg := Message{To: "foo@bar.com", Subject: "Grüße", Text: "plain", HTML: "<p>html</p>"}
+
+	raw, err := msg.Bytes("noreply@recipe.example.com", time.Now())
+	require.NoError(t, err)
+	s := string(raw)
+	require.Contains(t, s, "To: foo@bar.com\r\n")
+	require.Contains(t, s, "Subject: =?utf-8?q?Gr=C3=BC=C3=9Fe?=\r\n")
+	require.Contains(t, s, "Content-Type: multipart/alternative; boundary=")
+	require.Contains(t, s, "Content-Type: text/plain; charset=UTF-8")
+	require.Contains(t, s, "Content-Type: text/html; charset=UTF-8")
+	require.Contains(t, s, "@recipe.example.com>\r\n")
+
+	msg.Subject = "hi\r\nBcc: victim@example.com"
+	_, err = msg.Bytes("noreply@recipe.example.com", time.Now())
+	require.Error(t, err)
+}
+
+func TestFileTransport_DeliversIntoMaildir(t *testing.T) {
+	dir := t.TempDir()
+	transport, err := NewFileTransport(dir, "noreply@recipe.example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, transport.Send(context.Background(), Message{To: "foo@bar.com", Subject: "Hi", Text: "hello"}))
+
+	delivered, err := os.ReadDir(filepath.Join(dir, "new"))
+	require.NoError(t, err)
+	require.Len(t, delivered, 1)
+	pending, err := os.ReadDir(filepath.Join(dir, "tmp"))
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	raw, err := os.ReadFile(filepath.Join(dir, "new", delivered[0].Name()))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(raw), "From: noreply@recipe.example.com\r\n"))
+}
+
+func TestCaptureTransport(t *testing.T) {
+	transport := &CaptureTransport{}
+	require.NoError(t, transport.Send(context.Background(), Message{To: "a@b.c"}))
+	require.NoError(t, transport.Send(context.Background(), Message{To: "d@e.f"}))
+
+	messages := transport.Messages()
+	require.Len(t, messages, 2)
+	require.Equal(t, "d@e.f", messages[1].To)
+}
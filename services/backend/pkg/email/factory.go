@@ -0,0 +1,18 @@
+package email
+
+import (
+	"fmt"
+
+	"github.com/H3nSte1n/recipe/pkg/config"
+)
+
+func NewTransport(cfg *config.Config) (Transport, error) {
+	switch cfg.Email.Transport {
+	case "", "smtp":
+		return NewSMTPTransport(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.User, cfg.SMTP.Password, cfg.SMTP.From), nil
+	case "file":
+		return NewFileTransport(cfg.Email.Dir, cfg.SMTP.From)
+	default:
+		return nil, fmt.Errorf("unsupported email transport: %s", cfg.Email.Transport)
+	}
+}
@@ -0,0 +1,147 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates are named after the email they render, e.g. verification. Each
+// locale directory has <name>.txt.tmpl, defining "subject" and "text", and
+// <name>.html.tmpl, defining "content" for the shared HTML layout.
+const (
+	templateVerification  = "verification"
+	templatePasswordReset = "password_reset"
+	templateMagicLink     = "magic_link"
+	templateDataExport    = "data_export"
+)
+
+// supportedLocales lists the locales with templates. The first one is used
+// when none of the recipient's languages match.
+var supportedLocales = []language.Tag{language.English, language.German}
+
+var localeMatcher = language.NewMatcher(supportedLocales)
+
+type localizedTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates maps locale, then template name, to the parsed template. The
+// templates are embedded, so a parse error is a bug and fails at start-up.
+var templates = mustParseTemplates()
+
+// buttonLink is the argument of the layout's "button" template.
+type buttonLink struct {
+	URL   string
+	Label string
+}
+
+var htmlFuncs = htmltemplate.FuncMap{
+	"button": func(url, label string) buttonLink { return buttonLink{URL: url, Label: label} },
+}
+
+func mustParseTemplates() map[string]map[string]localizedTemplate {
+	parsed, err := parseTemplates()
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func parseTemplates() (map[string]map[string]localizedTemplate, error) {
+	layout, err := htmltemplate.New("layout").Funcs(htmlFuncs).ParseFS(templateFS, "templates/layout.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(map[string]map[string]localizedTemplate, len(supportedLocales))
+	for _, tag := range supportedLocales {
+		locale := tag.String()
+		textFiles, err := fs.Glob(templateFS, path.Join("templates", locale, "*.txt.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+
+		parsed[locale] = make(map[string]localizedTemplate, len(textFiles))
+		for _, textFile := range textFiles {
+			name := strings.TrimSuffix(path.Base(textFile), ".txt.tmpl")
+			text, err := texttemplate.ParseFS(templateFS, textFile)
+			if err != nil {
+				return nil, err
+			}
+			base, err := layout.Clone()
+			if err != nil {
+				return nil, err
+			}
+			html, err := base.ParseFS(templateFS, path.Join("templates", locale, name+".html.tmpl"))
+			if err != nil {
+				return nil, err
+			}
+			parsed[locale][name] = localizedTemplate{text: text, html: html}
+		}
+	}
+
+	// Every locale must be able to render every email.
+	for _, tag := range supportedLocales {
+		for name := range parsed[supportedLocales[0].String()] {
+			if _, ok := parsed[tag.String()][name]; !ok {
+				return nil, fmt.Errorf("email template %s missing for locale %s", name, tag)
+			}
+		}
+	}
+	return parsed, nil
+}
+
+type languageKey struct{}
+
+// ContextWithLanguage records the languages the recipient reads, as an
+// Accept-Language header value, for emails rendered on ctx.
+func ContextWithLanguage(ctx context.Context, acceptLanguage string) context.Context {
+	return context.WithValue(ctx, languageKey{}, acceptLanguage)
+}
+
+// localeFromContext picks the supported locale that best matches the
+// languages attached by ContextWithLanguage.
+func localeFromContext(ctx context.Context) string {
+	acceptLanguage, _ := ctx.Value(languageKey{}).(string)
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, index, _ := localeMatcher.Match(tags...)
+	return supportedLocales[index].String()
+}
+
+// render renders template name in the locale of ctx.
+func render(ctx context.Context, name string, data any) (Message, error) {
+	tmpl, ok := templates[localeFromContext(ctx)][name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %s", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,160 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Transport delivers a rendered message.
+type Transport interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// smtpDialTimeout bounds connecting to the SMTP server when ctx has no
+// deadline of its own.
+const smtpDialTimeout = 30 * time.Second
+
+// SMTPTransport sends through an SMTP server. The connection must be upgraded
+// with STARTTLS before credentials or mail are sent; a server that does not
+// offer it is an error rather than a plain-text fallback.
+type SMTPTransport struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPTransport authenticates as username, or as from when username is
+// empty.
+func NewSMTPTransport(host, port, username, password, from string) *SMTPTransport {
+	if username == "" {
+		username = from
+	}
+	return &SMTPTransport{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (t *SMTPTransport) Send(ctx context.Context, msg Message) error {
+	raw, err := msg.Bytes(t.from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(t.host, t.port)
+	dialer := net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connect to smtp server: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpDialTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, t.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); !ok {
+		return fmt.Errorf("smtp server %s does not offer STARTTLS", addr)
+	}
+	if err := client.StartTLS(&tls.Config{ServerName: t.host, MinVersion: tls.VersionTLS12}); err != nil {
+		return fmt.Errorf("smtp starttls: %w", err)
+	}
+	if t.password != "" {
+		if err := client.Auth(smtp.PlainAuth("", t.username, t.password, t.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(t.from); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+// FileTransport writes each message into a maildir for development, where it
+// can be read with any mail client that opens maildirs.
+type FileTransport struct {
+	dir  string
+	from string
+}
+
+// NewFileTransport creates the tmp, new and cur subdirectories of dir.
+func NewFileTransport(dir, from string) (*FileTransport, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o750); err != nil {
+			return nil, fmt.Errorf("create maildir: %w", err)
+		}
+	}
+	return &FileTransport{dir: dir, from: from}, nil
+}
+
+func (t *FileTransport) Send(_ context.Context, msg Message) error {
+	raw, err := msg.Bytes(t.from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Maildir delivery: write under tmp, then rename into new so readers
+	// never see a partial message.
+	name := fmt.Sprintf("%d.%s.recipe", time.Now().UnixNano(), randomID())
+	tmp := filepath.Join(t.dir, "tmp", name)
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(t.dir, "new", name))
+}
+
+// CaptureTransport keeps sent messages in memory, for tests.
+type CaptureTransport struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (t *CaptureTransport) Send(_ context.Context, msg Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = append(t.messages, msg)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (t *CaptureTransport) Messages() []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Message(nil), t.messages...)
+}
@@ -0,0 +1,56 @@
+// Package gtin checks and normalizes the barcodes printed on products:
+// EAN-8, UPC-A, EAN-13 and GTIN-14.
+package gtin
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("barcode must be an EAN-8, UPC-A, EAN-13 or GTIN-14 code with a valid check digit")
+
+// Normalize strips spaces and dashes from code, checks its length and check
+// digit and returns it in the form it is stored in: UPC-A codes become EAN-13
+// codes with a leading zero and GTIN-14 codes with a leading zero become
+// EAN-13, so a product is found however its code was scanned or typed.
+func Normalize(code string) (string, error) {
+	code = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, code)
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", ErrInvalid
+		}
+	}
+
+	switch len(code) {
+	case 8, 13:
+	case 12:
+		code = "0" + code
+	case 14:
+		code = strings.TrimPrefix(code, "0")
+	default:
+		return "", ErrInvalid
+	}
+	if !validCheckDigit(code) {
+		return "", ErrInvalid
+	}
+	return code, nil
+}
+
+// validCheckDigit reports whether code's last digit is the GS1 check digit
+// of the digits before it.
+func validCheckDigit(code string) bool {
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
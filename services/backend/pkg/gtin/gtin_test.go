@@ -0,0 +1,35 @@
+package gtin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{name: "EAN-13", code: "3017620422003", want: "3017620422003"},
+		{name: "EAN-8", code: "96385074", want: "96385074"},
+		{name: "UPC-A", code: "036000291452", want: "0036000291452"},
+		{name: "GTIN-14", code: "00036000291452", want: "0036000291452"},
+		{name: "spaces and dashes", code: " 3 017620-422003 ", want: "3017620422003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.code)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalize_Rejects(t *testing.T) {
+	for _, code := range []string{"", "3017620422004", "301762042200", "30176204220a3", "123"} {
+		_, err := Normalize(code)
+		require.ErrorIs(t, err, ErrInvalid, code)
+	}
+}
@@ -0,0 +1,461 @@
+// Package oidc is a small OpenID Connect relying party: discovery, the
+// authorization code flow with PKCE, and ID token verification against the
+// provider's published keys. Providers that only speak plain OAuth2 (such as
+// GitHub) are supported by configuring their endpoints explicitly, in which
+// case the identity comes from the userinfo endpoint instead of an ID token.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// maxResponseBytes bounds every response read from a provider.
+const maxResponseBytes = 1 << 20
+
+// keyRefreshInterval limits how often an unknown key ID triggers a JWKS
+// refetch, so forged tokens cannot be used to hammer the provider.
+const keyRefreshInterval = time.Minute
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+var (
+	// ErrInvalidIDToken is returned when an ID token fails signature, issuer,
+	// audience, expiry or nonce checks.
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	// ErrProvider is returned when the provider answers with an error or a
+	// response that cannot be used.
+	ErrProvider = errors.New("identity provider error")
+)
+
+// Config describes one provider. IssuerURL enables discovery; the explicit
+// endpoints override discovered values and are required for providers
+// without discovery.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	// EmailsURL lists the user's addresses with primary and verified flags,
+	// for providers whose userinfo does not say whether the email is verified
+	// (GitHub's /user/emails).
+	EmailsURL string
+}
+
+// Identity is what the provider asserts about the signed-in user.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider talks to one identity provider. It is safe for concurrent use;
+// discovery and keys are fetched lazily and cached.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu          sync.Mutex
+	meta        *metadata
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = defaultScopes
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// AuthCodeURL returns the URL to send the browser to. state and nonce are
+// echoed back by the provider and checked on return; codeChallenge is the
+// S256 PKCE challenge for the verifier later passed to Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the verified identity.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	meta, err := p.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tokens struct {
+		AccessToken      string `json:"access_token"`
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := p.doJSON(req, &tokens, true); err != nil {
+		return nil, err
+	}
+	if tokens.Error != "" {
+		return nil, fmt.Errorf("%w: token endpoint: %s %s", ErrProvider, tokens.Error, tokens.ErrorDescription)
+	}
+
+	if tokens.IDToken != "" {
+		return p.verifyIDToken(ctx, meta, tokens.IDToken, nonce)
+	}
+	if tokens.AccessToken == "" {
+		return nil, fmt.Errorf("%w: token endpoint returned no token", ErrProvider)
+	}
+	return p.userInfo(ctx, meta, tokens.AccessToken)
+}
+
+// metadata returns the provider endpoints, running discovery on first use.
+func (p *Provider) metadata(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	meta := &metadata{}
+	if p.cfg.IssuerURL != "" {
+		issuer := strings.TrimSuffix(p.cfg.IssuerURL, "/")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.doJSON(req, meta); err != nil {
+			return nil, fmt.Errorf("discovery: %w", err)
+		}
+		// The document must describe the issuer we asked about, otherwise a
+		// misconfigured or hostile host could vouch for tokens it did not sign.
+		if strings.TrimSuffix(meta.Issuer, "/") != issuer {
+			return nil, fmt.Errorf("%w: discovery issuer %q does not match %q", ErrProvider, meta.Issuer, p.cfg.IssuerURL)
+		}
+	}
+
+	if p.cfg.AuthURL != "" {
+		meta.AuthorizationEndpoint = p.cfg.AuthURL
+	}
+	if p.cfg.TokenURL != "" {
+		meta.TokenEndpoint = p.cfg.TokenURL
+	}
+	if p.cfg.UserInfoURL != "" {
+		meta.UserinfoEndpoint = p.cfg.UserInfoURL
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" {
+		return nil, fmt.Errorf("%w: authorization and token endpoints are required", ErrProvider)
+	}
+
+	p.meta = meta
+	return meta, nil
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, meta *metadata, rawToken, nonce string) (*Identity, error) {
+	if meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: provider publishes no signing keys", ErrProvider)
+	}
+
+	var claims struct {
+		jwt.RegisteredClaims
+		Nonce         string          `json:"nonce"`
+		AuthorizedBy  string          `json:"azp"`
+		Email         string          `json:"email"`
+		EmailVerified json.RawMessage `json:"email_verified"`
+		GivenName     string          `json:"given_name"`
+		FamilyName    string          `json:"family_name"`
+	}
+	_, err := jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, meta.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: token was issued to another party", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: parseBool(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
+
+// userInfo builds the identity from the userinfo endpoint, for providers that
+// return no ID token.
+func (p *Provider) userInfo(ctx context.Context, meta *metadata, accessToken string) (*Identity, error) {
+	if meta.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("%w: no ID token and no userinfo endpoint", ErrProvider)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, meta.UserinfoEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	var info struct {
+		Subject       string          `json:"sub"`
+		ID            json.Number     `json:"id"`
+		Email         string          `json:"email"`
+		EmailVerified json.RawMessage `json:"email_verified"`
+		GivenName     string          `json:"given_name"`
+		FamilyName    string          `json:"family_name"`
+		Name          string          `json:"name"`
+	}
+	if err := p.doJSON(req, &info); err != nil {
+		return nil, fmt.Errorf("userinfo: %w", err)
+	}
+
+	identity := &Identity{
+		Subject:       info.Subject,
+		Email:         info.Email,
+		EmailVerified: parseBool(info.EmailVerified),
+		GivenName:     info.GivenName,
+		FamilyName:    info.FamilyName,
+	}
+	if identity.Subject == "" {
+		// GitHub identifies users by a numeric id instead of "sub".
+		identity.Subject = info.ID.String()
+	}
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: userinfo has no subject", ErrProvider)
+	}
+	if identity.GivenName == "" && identity.FamilyName == "" && info.Name != "" {
+		identity.GivenName, identity.FamilyName, _ = strings.Cut(info.Name, " ")
+	}
+
+	if p.cfg.EmailsURL != "" {
+		email, err := p.primaryVerifiedEmail(ctx, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		identity.Email = email
+		identity.EmailVerified = email != ""
+	}
+	return identity, nil
+}
+
+func (p *Provider) primaryVerifiedEmail(ctx context.Context, accessToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.EmailsURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.doJSON(req, &emails); err != nil {
+		return "", fmt.Errorf("emails: %w", err)
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			return e.Email, nil
+		}
+	}
+	return "", nil
+}
+
+// key returns the verification key for kid, refetching the key set when kid
+// is unknown (providers rotate keys) but no more than once per
+// keyRefreshInterval.
+func (p *Provider) key(ctx context.Context, jwksURI, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysFetched) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := p.fetchKeys(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds kid in the cached set. A token without kid is accepted only
+// when the provider publishes exactly one key.
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip key types we do not verify with rather than failing the
+			// whole set.
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+// doJSON sends req and decodes the JSON response into v. Non-2xx responses
+// are failures, except that tolerateBadRequest lets the token endpoint's 400
+// through so the caller can read the OAuth error in its body.
+func (p *Provider) doJSON(req *http.Request, v interface{}, tolerateBadRequest ...bool) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrProvider, err)
+	}
+	defer resp.Body.Close()
+
+	badRequestOK := len(tolerateBadRequest) > 0 && tolerateBadRequest[0] && resp.StatusCode == http.StatusBadRequest
+	if resp.StatusCode >= 300 && !badRequestOK {
+		return fmt.Errorf("%w: %s returned %d", ErrProvider, req.URL.Path, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrProvider, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrProvider, err)
+	}
+	return nil
+}
+
+// parseBool accepts email_verified as a JSON boolean or, as some providers
+// send it, a string.
+func parseBool(raw json.RawMessage) bool {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		b, _ = strconv.ParseBool(s)
+	}
+	return b
+}
+
+// RandomString returns a URL-safe random value for state, nonce and PKCE
+// verifiers.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// PKCEChallenge derives the S256 code challenge for verifier (RFC 7636).
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
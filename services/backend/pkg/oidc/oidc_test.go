@@ -0,0 +1,146 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/H3nSte1n/recipe/pkg/oidc"
+	"github.com/H3nSte1n/recipe/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "https://app.example/auth/callback"
+
+func newProvider(t *testing.T) (*oidctest.Provider, *oidc.Provider) {
+	t.Helper()
+	idp, err := oidctest.NewProvider("recipe-client")
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	return idp, oidc.NewProvider(oidc.Config{
+		IssuerURL:    idp.URL,
+		ClientID:     "recipe-client",
+		ClientSecret: "secret",
+		RedirectURL:  redirectURL,
+	}, nil)
+}
+
+func TestProvider_CodeFlow(t *testing.T) {
+	ctx := context.Background()
+	user := oidctest.User{Subject: "sub-1", Email: "foo@bar.com", EmailVerified: true, GivenName: "Foo", FamilyName: "Bar"}
+
+	t.Run("authorization URL carries state, nonce and PKCE", func(t *testing.T) {
+		idp, provider := newProvider(t)
+
+		authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.PKCEChallenge("verifier"))
+		require.NoError(t, err)
+
+		u, err := url.Parse(authURL)
+		require.NoError(t, err)
+		require.Equal(t, idp.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+		q := u.Query()
+		require.Equal(t, "state-1", q.Get("state"))
+		require.Equal(t, "nonce-1", q.Get("nonce"))
+		require.Equal(t, "S256", q.Get("code_challenge_method"))
+		require.Equal(t, redirectURL, q.Get("redirect_uri"))
+		require.Equal(t, "openid email profile", q.Get("scope"))
+	})
+
+	t.Run("exchange returns the verified identity", func(t *testing.T) {
+		idp, provider := newProvider(t)
+		verifier, err := oidc.RandomString()
+		require.NoError(t, err)
+
+		authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.PKCEChallenge(verifier))
+		require.NoError(t, err)
+		code, state, err := idp.Authorize(authURL, user)
+		require.NoError(t, err)
+		require.Equal(t, "state-1", state)
+
+		identity, err := provider.Exchange(ctx, code, verifier, "nonce-1")
+		require.NoError(t, err)
+		require.Equal(t, &oidc.Identity{Subject: "sub-1", Email: "foo@bar.com", EmailVerified: true, GivenName: "Foo", FamilyName: "Bar"}, identity)
+	})
+
+	t.Run("wrong PKCE verifier is rejected by the provider", func(t *testing.T) {
+		idp, provider := newProvider(t)
+
+		authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.PKCEChallenge("verifier"))
+		require.NoError(t, err)
+		code, _, err := idp.Authorize(authURL, user)
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(ctx, code, "another-verifier", "nonce-1")
+		require.True(t, errors.Is(err, oidc.ErrProvider))
+	})
+
+	t.Run("nonce mismatch is rejected", func(t *testing.T) {
+		idp, provider := newProvider(t)
+		idp.Nonce = "replayed"
+
+		authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.PKCEChallenge("verifier"))
+		require.NoError(t, err)
+		code, _, err := idp.Authorize(authURL, user)
+		require.NoError(t, err)
+
+		_, err = provider.Exchange(ctx, code, "verifier", "nonce-1")
+		require.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+	})
+
+	t.Run("token for another client is rejected", func(t *testing.T) {
+		idp, _ := newProvider(t)
+		other := oidc.NewProvider(oidc.Config{IssuerURL: idp.URL, ClientID: "other-client", RedirectURL: redirectURL}, nil)
+
+		authURL, err := other.AuthCodeURL(ctx, "state-1", "nonce-1", oidc.PKCEChallenge("verifier"))
+		require.NoError(t, err)
+		code, _, err := idp.Authorize(authURL, user)
+		require.NoError(t, err)
+
+		_, err = other.Exchange(ctx, code, "verifier", "nonce-1")
+		require.True(t, errors.Is(err, oidc.ErrInvalidIDToken))
+	})
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issuer":"https://evil.example","authorization_endpoint":"https://evil.example/a","token_endpoint":"https://evil.example/t"}`))
+	}))
+	defer server.Close()
+
+	provider := oidc.NewProvider(oidc.Config{IssuerURL: server.URL, ClientID: "recipe-client"}, nil)
+	_, err := provider.AuthCodeURL(context.Background(), "s", "n", "c")
+	require.True(t, errors.Is(err, oidc.ErrProvider))
+}
+
+func TestProvider_UserInfoWithEmailsEndpoint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"gh-token","token_type":"bearer"}`))
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer gh-token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id":4242,"name":"Foo Bar","email":null}`))
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"email":"old@bar.com","primary":false,"verified":true},{"email":"foo@bar.com","primary":true,"verified":true}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider := oidc.NewProvider(oidc.Config{
+		ClientID:    "recipe-client",
+		AuthURL:     server.URL + "/authorize",
+		TokenURL:    server.URL + "/token",
+		UserInfoURL: server.URL + "/user",
+		EmailsURL:   server.URL + "/user/emails",
+	}, nil)
+
+	identity, err := provider.Exchange(context.Background(), "code", "verifier", "nonce")
+	require.NoError(t, err)
+	require.Equal(t, &oidc.Identity{Subject: "4242", Email: "foo@bar.com", EmailVerified: true, GivenName: "Foo", FamilyName: "Bar"}, identity)
+}
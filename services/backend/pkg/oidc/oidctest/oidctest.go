@@ -0,0 +1,172 @@
+// Package oidctest runs a minimal in-process OpenID provider for tests. It
+// serves discovery, a JWKS with one RSA key and a token endpoint that checks
+// PKCE and returns signed ID tokens for codes issued through Authorize.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "test-key"
+
+// User is the identity the provider vouches for when a code is redeemed.
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+type grant struct {
+	user          User
+	nonce         string
+	codeChallenge string
+}
+
+// Provider is a running stub IdP. URL is its issuer.
+type Provider struct {
+	URL      string
+	ClientID string
+
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]grant
+	// Nonce, when set, replaces the nonce echoed in ID tokens, to simulate a
+	// replayed or forged token.
+	Nonce string
+}
+
+// NewProvider starts a stub IdP for clientID. Call Close when done.
+func NewProvider(clientID string) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{ClientID: clientID, key: key, grants: make(map[string]grant)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+	p.server = httptest.NewServer(mux)
+	p.URL = p.server.URL
+	return p, nil
+}
+
+func (p *Provider) Close() {
+	p.server.Close()
+}
+
+// Authorize plays the browser leg of the flow: it reads the nonce and PKCE
+// challenge from an authorization URL and returns a code that signs in user.
+func (p *Provider) Authorize(authURL string, user User) (code, state string, err error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	q := u.Query()
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	code = base64.RawURLEncoding.EncodeToString(b)
+
+	p.mu.Lock()
+	p.grants[code] = grant{user: user, nonce: q.Get("nonce"), codeChallenge: q.Get("code_challenge")}
+	p.mu.Unlock()
+	return code, q.Get("state"), nil
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.URL,
+		"authorization_endpoint": p.URL + "/authorize",
+		"token_endpoint":         p.URL + "/token",
+		"jwks_uri":               p.URL + "/jwks",
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	p.mu.Lock()
+	g, ok := p.grants[r.PostForm.Get("code")]
+	delete(p.grants, r.PostForm.Get("code"))
+	nonceOverride := p.Nonce
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != g.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	nonce := g.nonce
+	if nonceOverride != "" {
+		nonce = nonceOverride
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.URL,
+		"aud":            p.ClientID,
+		"sub":            g.user.Subject,
+		"email":          g.user.Email,
+		"email_verified": g.user.EmailVerified,
+		"given_name":     g.user.GivenName,
+		"family_name":    g.user.FamilyName,
+		"nonce":          nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = keyID
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "stub-access-token",
+		"token_type":   "Bearer",
+		"id_token":     signed,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
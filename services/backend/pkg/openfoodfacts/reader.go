@@ -0,0 +1,173 @@
+// Package openfoodfacts reads products from the Open Food Facts JSONL data
+// dump (openfoodfacts-products.jsonl.gz), gzipped or not, one product at a
+// time so a full dump never has to fit in memory.
+package openfoodfacts
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Product is the part of an Open Food Facts product a shopping list needs.
+type Product struct {
+	Code     string
+	Name     string
+	Brand    string
+	Quantity string
+	// CategoryTags are the product's categories, most general first, as
+	// Open Food Facts tags them ("en:dairies", "en:milks").
+	CategoryTags []string
+	Nutrition    Nutrition
+}
+
+// Nutrition is per 100 g or 100 ml; values the product does not give are nil.
+type Nutrition struct {
+	EnergyKcal    *float64
+	Fat           *float64
+	SaturatedFat  *float64
+	Carbohydrates *float64
+	Sugars        *float64
+	Fiber         *float64
+	Protein       *float64
+	Salt          *float64
+}
+
+type record struct {
+	Code           flexString     `json:"code"`
+	ProductName    string         `json:"product_name"`
+	ProductNameEN  string         `json:"product_name_en"`
+	GenericName    string         `json:"generic_name"`
+	Brands         string         `json:"brands"`
+	Quantity       string         `json:"quantity"`
+	CategoriesTags []string       `json:"categories_tags"`
+	Nutriments     map[string]any `json:"nutriments"`
+}
+
+// flexString accepts a JSON string or number; older dump entries have
+// numeric codes.
+type flexString string
+
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = flexString(str)
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+	*s = flexString(num.String())
+	return nil
+}
+
+type Reader struct {
+	r       *bufio.Reader
+	skipped int
+}
+
+// NewReader reads the dump from r, unzipping it if it is gzipped.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReaderSize(r, 1<<20)
+	magic, err := br.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReaderSize(zr, 1<<20)
+	}
+	return &Reader{r: br}, nil
+}
+
+// Next returns the next product, or io.EOF after the last. Lines that are
+// not a JSON object are skipped and counted in Skipped.
+func (r *Reader) Next() (*Product, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var rec record
+			if jsonErr := json.Unmarshal(line, &rec); jsonErr != nil {
+				r.skipped++
+			} else {
+				return rec.product(), nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Skipped is how many lines so far could not be read as a product.
+func (r *Reader) Skipped() int {
+	return r.skipped
+}
+
+func (rec *record) product() *Product {
+	name := strings.TrimSpace(rec.ProductName)
+	if name == "" {
+		name = strings.TrimSpace(rec.ProductNameEN)
+	}
+	if name == "" {
+		name = strings.TrimSpace(rec.GenericName)
+	}
+	brand, _, _ := strings.Cut(rec.Brands, ",")
+
+	return &Product{
+		Code:         strings.TrimSpace(string(rec.Code)),
+		Name:         name,
+		Brand:        strings.TrimSpace(brand),
+		Quantity:     strings.TrimSpace(rec.Quantity),
+		CategoryTags: rec.CategoriesTags,
+		Nutrition: Nutrition{
+			EnergyKcal:    nutriment(rec.Nutriments, "energy-kcal_100g"),
+			Fat:           nutriment(rec.Nutriments, "fat_100g"),
+			SaturatedFat:  nutriment(rec.Nutriments, "saturated-fat_100g"),
+			Carbohydrates: nutriment(rec.Nutriments, "carbohydrates_100g"),
+			Sugars:        nutriment(rec.Nutriments, "sugars_100g"),
+			Fiber:         nutriment(rec.Nutriments, "fiber_100g"),
+			Protein:       nutriment(rec.Nutriments, "proteins_100g"),
+			Salt:          nutriment(rec.Nutriments, "salt_100g"),
+		},
+	}
+}
+
+// nutriment returns the value under key, which the dump gives as a number or
+// a numeric string.
+func nutriment(nutriments map[string]any, key string) *float64 {
+	var value float64
+	switch v := nutriments[key].(type) {
+	case float64:
+		value = v
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil
+		}
+		value = parsed
+	default:
+		return nil
+	}
+	return &value
+}
+
+// CategoryTerm turns a category tag into the words it stands for:
+// "en:plant-based-milks" becomes "plant based milks". Tags in languages other
+// than English are returned empty.
+func CategoryTerm(tag string) string {
+	lang, term, ok := strings.Cut(tag, ":")
+	if !ok || lang != "en" {
+		return ""
+	}
+	return strings.ReplaceAll(term, "-", " ")
+}
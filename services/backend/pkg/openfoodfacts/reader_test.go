@@ -0,0 +1,70 @@
+package openfoodfacts
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const dump = `{"code":"3017620422003","product_name":"Nutella","brands":"Ferrero, Nutella","quantity":"400 g","categories_tags":["en:spreads","en:hazelnut-spreads"],"nutriments":{"energy-kcal_100g":539,"fat_100g":"30.9","proteins_100g":6.3}}
+not json
+
+{"code":737628064502,"product_name":"","product_name_en":"Thai Peanut Noodle Kit"}
+`
+
+func readAll(t *testing.T, r io.Reader) ([]*Product, int) {
+	t.Helper()
+	reader, err := NewReader(r)
+	require.NoError(t, err)
+
+	var products []*Product
+	for {
+		product, err := reader.Next()
+		if err == io.EOF {
+			return products, reader.Skipped()
+		}
+		require.NoError(t, err)
+		products = append(products, product)
+	}
+}
+
+func TestReader(t *testing.T) {
+	products, skipped := readAll(t, strings.NewReader(dump))
+
+	require.Equal(t, 1, skipped)
+	require.Len(t, products, 2)
+
+	nutella := products[0]
+	require.Equal(t, "3017620422003", nutella.Code)
+	require.Equal(t, "Nutella", nutella.Name)
+	require.Equal(t, "Ferrero", nutella.Brand)
+	require.Equal(t, "400 g", nutella.Quantity)
+	require.Equal(t, []string{"en:spreads", "en:hazelnut-spreads"}, nutella.CategoryTags)
+	require.Equal(t, 539.0, *nutella.Nutrition.EnergyKcal)
+	require.Equal(t, 30.9, *nutella.Nutrition.Fat)
+	require.Nil(t, nutella.Nutrition.Salt)
+
+	require.Equal(t, "737628064502", products[1].Code)
+	require.Equal(t, "Thai Peanut Noodle Kit", products[1].Name)
+}
+
+func TestReader_Gzipped(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(dump))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	products, _ := readAll(t, &buf)
+	require.Len(t, products, 2)
+}
+
+func TestCategoryTerm(t *testing.T) {
+	require.Equal(t, "plant based milks", CategoryTerm("en:plant-based-milks"))
+	require.Empty(t, CategoryTerm("fr:laits"))
+	require.Empty(t, CategoryTerm("milks"))
+}
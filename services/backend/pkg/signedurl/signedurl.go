@@ -47,14 +47,27 @@ func (s *Signer) Sign(rawURL string) string {
 	if !strings.Contains(u.Path, "/uploads/") {
 		return rawURL
 	}
-	exp := time.Now().Add(s.ttl).Unix()
-	sig := s.compute(path.Base(u.Path), exp)
+	s.sign(u, path.Base(u.Path))
+	return u.String()
+}
+
+// SignFile returns a link to filename under baseURL, signed like Sign. It is
+// for files served from somewhere other than /uploads/, such as data exports.
+func (s *Signer) SignFile(baseURL, filename string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/" + url.PathEscape(filename))
+	if err != nil {
+		return "", err
+	}
+	s.sign(u, filename)
+	return u.String(), nil
+}
 
+func (s *Signer) sign(u *url.URL, filename string) {
+	exp := time.Now().Add(s.ttl).Unix()
 	q := u.Query()
 	q.Set("exp", strconv.FormatInt(exp, 10))
-	q.Set("sig", sig)
+	q.Set("sig", s.compute(filename, exp))
 	u.RawQuery = q.Encode()
-	return u.String()
 }
 
 // Verify checks that sig is a valid, unexpired signature for filename.
@@ -74,6 +87,34 @@ func (s *Signer) Verify(filename, expStr, sig string) error {
 	return nil
 }
 
+// SignToken returns a token for id: id with an HMAC signature appended. It
+// does not expire; the caller keeps track of how long id is good for and
+// whether it was revoked, as with share links.
+func (s *Signer) SignToken(id string) string {
+	return id + "." + s.computeToken(id)
+}
+
+// VerifyToken checks token's signature and returns the id it was signed for.
+func (s *Signer) VerifyToken(token string) (string, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i <= 0 {
+		return "", fmt.Errorf("malformed token")
+	}
+	id, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.computeToken(id))) {
+		return "", fmt.Errorf("invalid signature")
+	}
+	return id, nil
+}
+
+// computeToken signs id apart from filenames, so a token signature can never
+// pass for a file link's or the other way round.
+func (s *Signer) computeToken(id string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "token|%s", id)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func (s *Signer) compute(filename string, exp int64) string {
 	mac := hmac.New(sha256.New, s.secret)
 	fmt.Fprintf(mac, "%s|%d", filename, exp)
@@ -67,3 +67,30 @@ func TestSigner_EmptyURLUnchanged(t *testing.T) {
 	s := NewSigner("test-secret", time.Hour)
 	assert.Equal(t, "", s.Sign(""))
 }
+
+func TestSigner_SignFile(t *testing.T) {
+	s := NewSigner("test-secret", time.Hour)
+	signed, err := s.SignFile("http://localhost:8080/exports/", "export.zip")
+	require.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/exports/export.zip", u.Path)
+	assert.NoError(t, s.Verify("export.zip", u.Query().Get("exp"), u.Query().Get("sig")))
+}
+
+func TestSigner_TokenRoundTrip(t *testing.T) {
+	s := NewSigner("test-secret", time.Hour)
+	token := s.SignToken("share-1")
+
+	id, err := s.VerifyToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "share-1", id)
+
+	_, err = s.VerifyToken("share-2" + token[len("share-1"):])
+	assert.Error(t, err, "a signature is only good for its own id")
+	_, err = NewSigner("other-secret", time.Hour).VerifyToken(token)
+	assert.Error(t, err)
+	_, err = s.VerifyToken("share-1")
+	assert.Error(t, err)
+}
@@ -2,10 +2,17 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"io"
 	"mime/multipart"
 )
 
+// ErrNotStored is returned by OpenFile for URLs that do not point into the
+// store, such as an external image on an imported recipe.
+var ErrNotStored = errors.New("file is not in this store")
+
 type FileStore interface {
 	UploadFile(ctx context.Context, file *multipart.FileHeader) (string, error)
 	DeleteFile(ctx context.Context, fileURL string) error
+	OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error)
 }
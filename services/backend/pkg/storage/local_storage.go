@@ -8,6 +8,7 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type localFileStore struct {
@@ -69,3 +70,19 @@ func (s *localFileStore) DeleteFile(ctx context.Context, fileURL string) error {
 
 	return nil
 }
+
+func (s *localFileStore) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(fileURL, s.baseURL+"/") {
+		return nil, ErrNotStored
+	}
+	filePath := filepath.Join(s.uploadDir, filepath.Base(fileURL))
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotStored
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return f, nil
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"bytes"
 	"context"
+	"io"
 	"mime/multipart"
 	"path/filepath"
 	"testing"
@@ -51,3 +52,22 @@ func TestLocalStore_UploadFile_NormalizesExtension(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, ".png", filepath.Ext(url), "stored extension must match detected type")
 }
+
+func TestLocalStore_OpenFile(t *testing.T) {
+	store, err := NewLocalFileStore(t.TempDir(), "http://localhost:8080/uploads")
+	require.NoError(t, err)
+
+	pngBytes := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	url, err := store.UploadFile(context.Background(), makeFileHeader(t, "photo.png", pngBytes))
+	require.NoError(t, err)
+
+	f, err := store.OpenFile(context.Background(), url)
+	require.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, pngBytes, content)
+
+	_, err = store.OpenFile(context.Background(), "https://example.com/uploads/photo.png")
+	assert.ErrorIs(t, err, ErrNotStored)
+}
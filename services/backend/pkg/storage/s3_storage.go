@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"io"
 	"mime/multipart"
 )
 
@@ -29,3 +30,8 @@ func (s *s3FileStore) DeleteFile(ctx context.Context, fileURL string) error {
 	// Implementation for S3
 	return nil
 }
+
+func (s *s3FileStore) OpenFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	// Implementation for S3
+	return nil, ErrNotStored
+}
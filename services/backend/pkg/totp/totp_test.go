@@ -0,0 +1,70 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 test key from RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// Appendix B lists 8-digit codes; the 6-digit code is their last 6 digits.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		got, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "t=%d", unix)
+	}
+}
+
+func TestValidate_AllowsOneStepOfDrift(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	prev, err := Code(rfcSecret, Step(now)-1)
+	require.NoError(t, err)
+	old, err := Code(rfcSecret, Step(now)-2)
+	require.NoError(t, err)
+
+	step, ok := Validate(rfcSecret, prev, now)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Validate(rfcSecret, old, now)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret_RoundTrips(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	code, err := Code(secret, Step(time.Now()))
+	require.NoError(t, err)
+	_, ok := Validate(secret, code, time.Now())
+	assert.True(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("recipe-app", "cook@example.com", "ABC")
+
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/recipe-app:cook@example.com?"))
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "ABC", u.Query().Get("secret"))
+	assert.Equal(t, "recipe-app", u.Query().Get("issuer"))
+}
@@ -0,0 +1,96 @@
+// Package units converts amounts of groceries to the unit they are priced
+// by: kilograms, litres or pieces.
+package units
+
+import "strings"
+
+// Units prices are compared per.
+const (
+	Kilogram = "kg"
+	Liter    = "l"
+	Piece    = "piece"
+)
+
+// unit is how many of its base unit one of a unit is.
+type unit struct {
+	base   string
+	factor float64
+}
+
+// known maps unit names, lowercase and singular, to their base unit. Spoons
+// and cups are the usual cooking measures; an amount without a unit counts
+// pieces.
+var known = map[string]unit{
+	"mg":         {Kilogram, 0.000001},
+	"g":          {Kilogram, 0.001},
+	"gr":         {Kilogram, 0.001},
+	"gram":       {Kilogram, 0.001},
+	"gramm":      {Kilogram, 0.001},
+	"gramme":     {Kilogram, 0.001},
+	"kg":         {Kilogram, 1},
+	"kilo":       {Kilogram, 1},
+	"kilogram":   {Kilogram, 1},
+	"kilogramm":  {Kilogram, 1},
+	"oz":         {Kilogram, 0.028349523125},
+	"ounce":      {Kilogram, 0.028349523125},
+	"lb":         {Kilogram, 0.45359237},
+	"pound":      {Kilogram, 0.45359237},
+	"pfund":      {Kilogram, 0.5},
+	"ml":         {Liter, 0.001},
+	"milliliter": {Liter, 0.001},
+	"millilitre": {Liter, 0.001},
+	"cl":         {Liter, 0.01},
+	"dl":         {Liter, 0.1},
+	"l":          {Liter, 1},
+	"liter":      {Liter, 1},
+	"litre":      {Liter, 1},
+	"tsp":        {Liter, 0.005},
+	"teaspoon":   {Liter, 0.005},
+	"tl":         {Liter, 0.005},
+	"tbsp":       {Liter, 0.015},
+	"tablespoon": {Liter, 0.015},
+	"el":         {Liter, 0.015},
+	"cup":        {Liter, 0.2365882365},
+	"fl oz":      {Liter, 0.0295735295625},
+	"pint":       {Liter, 0.473176473},
+	"quart":      {Liter, 0.946352946},
+	"gallon":     {Liter, 3.785411784},
+	"":           {Piece, 1},
+	"piece":      {Piece, 1},
+	"pc":         {Piece, 1},
+	"each":       {Piece, 1},
+	"ea":         {Piece, 1},
+	"x":          {Piece, 1},
+	"stück":      {Piece, 1},
+	"stk":        {Piece, 1},
+	"st":         {Piece, 1},
+}
+
+// Normalize converts amount of unit to its base unit, returning the amount
+// and the base unit. Unit names are matched ignoring case, a trailing dot
+// and a plural "s". A unit it does not know, such as "bunch", is its own
+// base, so it only compares with amounts in the same unit; ok reports
+// whether unit was known.
+func Normalize(amount float64, unit string) (base float64, baseUnit string, ok bool) {
+	name := canonical(unit)
+	if u, found := lookup(name); found {
+		return amount * u.factor, u.base, true
+	}
+	return amount, name, false
+}
+
+// canonical returns unit lowercase, trimmed and without a trailing dot.
+func canonical(unit string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.Join(strings.Fields(unit), " ")), ".")
+}
+
+func lookup(name string) (unit, bool) {
+	if u, ok := known[name]; ok {
+		return u, true
+	}
+	if singular, ok := strings.CutSuffix(name, "s"); ok {
+		u, ok := known[singular]
+		return u, ok && singular != ""
+	}
+	return unit{}, false
+}
@@ -0,0 +1,47 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		unit     string
+		want     float64
+		wantUnit string
+	}{
+		{amount: 500, unit: "g", want: 0.5, wantUnit: Kilogram},
+		{amount: 2, unit: "KG", want: 2, wantUnit: Kilogram},
+		{amount: 250, unit: "grams", want: 0.25, wantUnit: Kilogram},
+		{amount: 1, unit: "lbs.", want: 0.45359237, wantUnit: Kilogram},
+		{amount: 330, unit: "ml", want: 0.33, wantUnit: Liter},
+		{amount: 2, unit: "Tbsp", want: 0.03, wantUnit: Liter},
+		{amount: 4, unit: "cups", want: 0.946352946, wantUnit: Liter},
+		{amount: 8, unit: "fl  oz", want: 0.2365882365, wantUnit: Liter},
+		{amount: 6, unit: "", want: 6, wantUnit: Piece},
+		{amount: 3, unit: "Stück", want: 3, wantUnit: Piece},
+		{amount: 2, unit: "pcs", want: 2, wantUnit: Piece},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			got, gotUnit, ok := Normalize(tt.amount, tt.unit)
+			require.True(t, ok)
+			require.InDelta(t, tt.want, got, 1e-9)
+			require.Equal(t, tt.wantUnit, gotUnit)
+		})
+	}
+}
+
+func TestNormalize_UnknownUnitIsItsOwnBase(t *testing.T) {
+	got, unit, ok := Normalize(2, " Bunch. ")
+	require.False(t, ok)
+	require.Equal(t, 2.0, got)
+	require.Equal(t, "bunch", unit)
+
+	_, unit, ok = Normalize(1, "s")
+	require.False(t, ok)
+	require.Equal(t, "s", unit)
+}